@@ -31,6 +31,7 @@ import (
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/lib/tls"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/sdkinternal/pkg/api"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/sdkinternal/pkg/util"
+	factory "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/sdkpatch/cryptosuitebridge"
 	log "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/sdkpatch/logbridge"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	http "gitee.com/zhaochuninhefei/gmgo/gmhttp"
@@ -213,6 +214,33 @@ func (c *Client) GenCSR(req *api.CSRInfo, id string) ([]byte, core.Key, error) {
 	return csrPEM, key, nil
 }
 
+// GenCSRUsingKey generates a CSR (Certificate Signing Request) using the provided key
+func (c *Client) GenCSRUsingKey(req *api.CSRInfo, id string, key core.Key) ([]byte, error) {
+	log.Debugf("GenCSRUsingKey %+v", req)
+
+	err := c.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	cr := c.newCertificateRequest(req)
+	cr.CN = id
+
+	cspSigner, err := factory.NewCspSigner(c.csp, key)
+	if err != nil {
+		log.Debugf("failed initializing CryptoSigner: %s", err)
+		return nil, err
+	}
+
+	csrPEM, err := csr.Generate(cspSigner, cr)
+	if err != nil {
+		log.Debugf("failed generating CSR: %s", err)
+		return nil, err
+	}
+
+	return csrPEM, nil
+}
+
 // Enroll enrolls a new identity
 // @param req The enrollment request
 func (c *Client) Enroll(req *api.EnrollmentRequest) (*EnrollmentResponse, error) {
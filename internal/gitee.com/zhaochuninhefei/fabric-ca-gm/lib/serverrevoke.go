@@ -19,6 +19,10 @@ type revocationResponseNet struct {
 	CRL          string
 }
 
+type genCRLResponseNet struct {
+	CRL string
+}
+
 // CertificateStatus represents status of an enrollment certificate
 type CertificateStatus string
 
@@ -312,8 +312,9 @@ type TimeRange struct {
 
 // KeyRequest encapsulates size and algorithm for the key to be generated
 type KeyRequest struct {
-	Algo string `json:"algo" yaml:"algo" help:"Specify key algorithm"`
-	Size int    `json:"size" yaml:"size" help:"Specify key size"`
+	Algo     string `json:"algo" yaml:"algo" help:"Specify key algorithm"`
+	Size     int    `json:"size" yaml:"size" help:"Specify key size"`
+	ReuseKey bool   `json:"reusekey" yaml:"reusekey" help:"Reuse existing key during reenrollment"`
 }
 
 // Attribute is a name and value pair
@@ -140,6 +140,10 @@ func ImportBCCSPKeyFromPEMBytes(keyBuff []byte, myCSP core.CryptoSuite, temporar
 	// PEM字节数组转为私钥
 	key, err := factory.PEMtoPrivateKey(keyBuff, nil)
 	if err != nil {
+		// 混合模式下尝试作为ecdsa私钥导入
+		if sk, ecdsaErr := importECDSAKeyFromPEMBytes(keyBuff, myCSP, temporary); ecdsaErr == nil {
+			return sk, nil
+		}
 		return nil, errors.WithMessage(err, fmt.Sprintf("Failed parsing private key from %s", keyFile))
 	}
 	switch key := key.(type) {
@@ -161,6 +165,19 @@ func ImportBCCSPKeyFromPEMBytes(keyBuff []byte, myCSP core.CryptoSuite, temporar
 	}
 }
 
+// importECDSAKeyFromPEMBytes 将PEM字节数组作为ecdsa私钥导入CSP，只有混合模式下的CSP支持
+func importECDSAKeyFromPEMBytes(keyBuff []byte, myCSP core.CryptoSuite, temporary bool) (core.Key, error) {
+	block, _ := pem.Decode(keyBuff)
+	if block == nil {
+		return nil, errors.New("Failed decoding PEM. Block must be different from nil")
+	}
+	sk, err := myCSP.KeyImport(block.Bytes, factory.GetECDSAPrivateKeyImportOpts(temporary))
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to import ECDSA private key")
+	}
+	return sk, nil
+}
+
 // LoadX509KeyPair reads and parses a public/private key pair from a pair
 // of files. The files must contain PEM encoded data. The certificate file
 // may contain intermediate certificates following the leaf certificate to
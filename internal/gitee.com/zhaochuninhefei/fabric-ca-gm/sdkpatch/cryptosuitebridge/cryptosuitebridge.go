@@ -70,3 +70,9 @@ func GetGMX509PublicKeyImportOpts(ephemeral bool) core.KeyImportOpts {
 func GetSM2PrivateKeyImportOpts(ephemeral bool) core.KeyImportOpts {
 	return &bccsp.SM2PrivateKeyImportOpts{Temporary: ephemeral}
 }
+
+//GetECDSAPrivateKeyImportOpts options for ECDSA secret key importation in DER format
+// or PKCS#8 format, only supported in hybrid mode.
+func GetECDSAPrivateKeyImportOpts(ephemeral bool) core.KeyImportOpts {
+	return &bccsp.ECDSAPrivateKeyImportOpts{Temporary: ephemeral}
+}
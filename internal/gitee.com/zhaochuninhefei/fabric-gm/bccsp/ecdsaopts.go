@@ -17,39 +17,81 @@ limitations under the License.
 package bccsp
 
 /*
- * bccsp/ecdsaopts.go 提供对接口`bccsp.KeyGenOpts`的一些ecdsa实现:
- * ECDSAP256KeyGenOpts, ECDSAP384KeyGenOpts
- * 国密对应后废弃
+ * bccsp/ecdsaopts.go 提供ecdsa相关的`bccsp.KeyGenOpts`与`bccsp.KeyImportOpts`实现:
+ * ECDSAP256KeyGenOpts, ECDSAPrivateKeyImportOpts, ECDSAPKIXPublicKeyImportOpts, ECDSAGoPublicKeyImportOpts
+ * 国密对应后一度废弃，为支持国密与非国密组织混合的网络(混合模式)重新启用，只支持P-256曲线
  */
 
-// // ECDSAP256KeyGenOpts contains options for ECDSA key generation with curve P-256.
-// type ECDSAP256KeyGenOpts struct {
-// 	Temporary bool
-// }
-
-// // Algorithm returns the key generation algorithm identifier (to be used).
-// func (opts *ECDSAP256KeyGenOpts) Algorithm() string {
-// 	return ECDSAP256
-// }
-
-// // Ephemeral returns true if the key to generate has to be ephemeral,
-// // false otherwise.
-// func (opts *ECDSAP256KeyGenOpts) Ephemeral() bool {
-// 	return opts.Temporary
-// }
-
-// // ECDSAP384KeyGenOpts contains options for ECDSA key generation with curve P-384.
-// type ECDSAP384KeyGenOpts struct {
-// 	Temporary bool
-// }
-
-// // Algorithm returns the key generation algorithm identifier (to be used).
-// func (opts *ECDSAP384KeyGenOpts) Algorithm() string {
-// 	return ECDSAP384
-// }
-
-// // Ephemeral returns true if the key to generate has to be ephemeral,
-// // false otherwise.
-// func (opts *ECDSAP384KeyGenOpts) Ephemeral() bool {
-// 	return opts.Temporary
-// }
+const (
+	// ECDSA Elliptic Curve Digital Signature Algorithm (key gen, import, sign, verify),
+	// at default security level.
+	ECDSA = "ECDSA"
+
+	// ECDSAP256 Elliptic Curve Digital Signature Algorithm over P-256 curve
+	ECDSAP256 = "ECDSAP256"
+)
+
+// ECDSAP256KeyGenOpts contains options for ECDSA key generation with curve P-256.
+type ECDSAP256KeyGenOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (opts *ECDSAP256KeyGenOpts) Algorithm() string {
+	return ECDSAP256
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *ECDSAP256KeyGenOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// ECDSAPrivateKeyImportOpts contains options for ECDSA secret key importation in DER format
+// or PKCS#8 format.
+type ECDSAPrivateKeyImportOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key importation algorithm identifier (to be used).
+func (opts *ECDSAPrivateKeyImportOpts) Algorithm() string {
+	return ECDSA
+}
+
+// Ephemeral returns true if the key generated has to be ephemeral,
+// false otherwise.
+func (opts *ECDSAPrivateKeyImportOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// ECDSAPKIXPublicKeyImportOpts contains options for ECDSA public key importation in PKIX format
+type ECDSAPKIXPublicKeyImportOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key importation algorithm identifier (to be used).
+func (opts *ECDSAPKIXPublicKeyImportOpts) Algorithm() string {
+	return ECDSA
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *ECDSAPKIXPublicKeyImportOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// ECDSAGoPublicKeyImportOpts contains options for ECDSA key importation from ecdsa.PublicKey
+type ECDSAGoPublicKeyImportOpts struct {
+	Temporary bool
+}
+
+// Algorithm returns the key importation algorithm identifier (to be used).
+func (opts *ECDSAGoPublicKeyImportOpts) Algorithm() string {
+	return ECDSA
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *ECDSAGoPublicKeyImportOpts) Ephemeral() bool {
+	return opts.Temporary
+}
@@ -22,6 +22,7 @@ bccsp/factory/opts.go 提供默认 FactoryOpts，并为FactoryOpts绑定方法 F
 
 // GetDefaultOpts offers a default implementation for Opts
 // returns a new instance every time
+// 默认只使用国密，需要同时支持ecdsa(P-256)与SHA256时将HashFamily设置为"SM3+SHA2"(混合模式)
 func GetDefaultOpts() *FactoryOpts {
 	return &FactoryOpts{
 		// 恢复为 SW
@@ -0,0 +1,48 @@
+//go:build pkcs11
+// +build pkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/pkcs11"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+/*
+bccsp/factory/pkcs11factory.go 定义 PKCS11Factory 结构体并为其实现`factory.BCCSPFactory`接口(bccsp/factory/factory.go)
+*/
+
+const (
+	// PKCS11BasedFactoryName is the name of the factory of the hsm-based BCCSP implementation
+	PKCS11BasedFactoryName = "PKCS11"
+)
+
+// PKCS11Factory is the factory of the HSM-based BCCSP.
+type PKCS11Factory struct{}
+
+// Name returns the name of this factory
+func (f *PKCS11Factory) Name() string {
+	return PKCS11BasedFactoryName
+}
+
+// Get returns an instance of BCCSP using Opts.
+func (f *PKCS11Factory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
+	// Validate arguments
+	if config == nil || config.Pkcs11Opts == nil {
+		return nil, errors.New("Invalid config. It must not be nil.")
+	}
+
+	p11Opts := *config.Pkcs11Opts
+	// 公钥等密码模块之外的材料不做持久化
+	ks := sw.NewDummyKeyStore()
+
+	return pkcs11.New(p11Opts, ks)
+}
@@ -52,7 +52,7 @@ func (f *SWFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 	case swOpts.Ephemeral:
 		ks = sw.NewDummyKeyStore()
 	case swOpts.FileKeystore != nil:
-		fks, err := sw.NewFileBasedKeyStore(nil, swOpts.FileKeystore.KeyStorePath, false)
+		fks, err := sw.NewFileBasedKeyStore(swOpts.FileKeystore.Pwd, swOpts.FileKeystore.KeyStorePath, false)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed to initialize software key store")
 		}
@@ -74,7 +74,13 @@ func (f *SWFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 		usingGM = true
 	}
 
-	return sw.NewWithParams(usingGM, swOpts.SecLevel, swOpts.HashFamily, ks)
+	// 国密改造版不支持单独使用非国密算法，UsingGM为N时启用混合模式，同时支持国密与ecdsa(P-256)/SHA256
+	hashFamily := swOpts.HashFamily
+	if !usingGM && hashFamily == bccsp.SM3 {
+		hashFamily = bccsp.SM3SHA2
+	}
+
+	return sw.NewWithParams(usingGM, swOpts.SecLevel, hashFamily, ks)
 }
 
 // SwOpts contains options for the SWFactory
@@ -83,7 +89,7 @@ type SwOpts struct {
 	// Default algorithms when not specified (Deprecated?)
 	// 算法强度，国密对应后只支持256
 	SecLevel int `mapstructure:"security" json:"security" yaml:"Security"`
-	// 散列算法，国密对应后只支持SM3
+	// 散列算法，国密对应后只支持SM3，混合模式下为"SM3+SHA2"
 	HashFamily string `mapstructure:"hash" json:"hash" yaml:"Hash"`
 
 	// Keystore Options
@@ -98,6 +104,8 @@ type SwOpts struct {
 // Pluggable Keystores, could add JKS, P12, etc..
 type FileKeystoreOpts struct {
 	KeyStorePath string `mapstructure:"keystore" yaml:"KeyStore"`
+	// 加密keystore中密钥文件的密码，为空时密钥以明文pem存储
+	Pwd []byte `mapstructure:"-" json:"-" yaml:"-"`
 }
 
 type DummyKeystoreOpts struct{}
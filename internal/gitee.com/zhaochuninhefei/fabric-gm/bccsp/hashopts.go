@@ -19,9 +19,19 @@ package bccsp
 import "fmt"
 
 /*
- * bccsp/hashopts.go 提供了对`bccsp.HashOpts`的SM3实现
+ * bccsp/hashopts.go 提供了对`bccsp.HashOpts`的SM3实现，以及混合模式下使用的SHA256实现
  */
 
+const (
+	// SHA2 is an identifier for SHA2 hash family
+	SHA2 = "SHA2"
+	// SHA256 is an identifier for SHA256 hash function
+	SHA256 = "SHA256"
+	// SM3SHA2 同时启用国密(SM2/SM3/SM4)与非国密(ECDSA P-256/SHA256)两套算法的混合模式，
+	// 签名与验签的算法由密钥或证书的类型决定
+	SM3SHA2 = "SM3+SHA2"
+)
+
 // SM3Opts 国密 SM3.
 type SM3Opts struct {
 }
@@ -31,11 +41,22 @@ func (opts *SM3Opts) Algorithm() string {
 	return SM3
 }
 
+// SHA256Opts contains options relating to SHA-256.
+type SHA256Opts struct {
+}
+
+// Algorithm returns the hash algorithm identifier (to be used).
+func (opts *SHA256Opts) Algorithm() string {
+	return SHA256
+}
+
 // GetHashOpt returns the HashOpts corresponding to the passed hash function
 func GetHashOpt(hashFunction string) (HashOpts, error) {
 	switch hashFunction {
 	case SM3:
 		return &SM3Opts{}, nil
+	case SHA256, SHA2:
+		return &SHA256Opts{}, nil
 	}
 	return nil, fmt.Errorf("hash function not recognized [%s]", hashFunction)
 }
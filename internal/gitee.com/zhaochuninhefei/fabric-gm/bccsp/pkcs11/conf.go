@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"encoding/asn1"
+
+	"github.com/miekg/pkcs11"
+)
+
+/*
+bccsp/pkcs11/conf.go 提供pkcs11 bccsp的配置 PKCS11Opts，以及国密算法在PKCS#11中使用的机制常量。
+PKCS#11标准没有定义国密算法，各厂商都在厂商自定义区间(CKK_VENDOR_DEFINED/CKM_VENDOR_DEFINED)内分配取值，
+这里的默认值只是一组常见取值，与实际使用的密码模块不一致时需要通过 PKCS11Opts.Mechanisms 覆盖。
+*/
+
+// 国密相关的PKCS#11密钥类型与机制默认值
+const (
+	// CKK_SM2 sm2密钥类型
+	CKK_SM2 = pkcs11.CKK_VENDOR_DEFINED + 0x00000001
+	// CKK_SM4 sm4密钥类型
+	CKK_SM4 = pkcs11.CKK_VENDOR_DEFINED + 0x00000002
+	// CKM_SM2_KEY_PAIR_GEN sm2密钥对生成
+	CKM_SM2_KEY_PAIR_GEN = pkcs11.CKM_VENDOR_DEFINED + 0x00008001
+	// CKM_SM2_SM3_SIGN sm2签名，由密码模块计算Z值与SM3摘要，与软件实现的sm2签名一致
+	CKM_SM2_SM3_SIGN = pkcs11.CKM_VENDOR_DEFINED + 0x00008002
+	// CKM_SM3 sm3摘要
+	CKM_SM3 = pkcs11.CKM_VENDOR_DEFINED + 0x00008101
+	// CKM_SM4_KEY_GEN sm4密钥生成
+	CKM_SM4_KEY_GEN = pkcs11.CKM_VENDOR_DEFINED + 0x00008201
+	// CKM_SM4_CBC_PAD sm4 CBC模式加解密，PKCS#7填充
+	CKM_SM4_CBC_PAD = pkcs11.CKM_VENDOR_DEFINED + 0x00008202
+)
+
+// oidNamedCurveSM2 sm2椭圆曲线的OID，作为sm2密钥的CKA_EC_PARAMS
+var oidNamedCurveSM2 = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+
+// PKCS11Opts contains options for the P11Factory
+// 用于 PKCS11Factory 的相关配置
+type PKCS11Opts struct {
+	// Default algorithms when not specified (Deprecated?)
+	// 算法强度，国密对应后只支持256
+	SecLevel int `mapstructure:"security" json:"security"`
+	// 散列算法，国密对应后只支持SM3
+	HashFamily string `mapstructure:"hash" json:"hash"`
+
+	// Keystore options
+	// 密码模块之外的公钥等材料使用的keystore，为true时使用DummyKeystore
+	Ephemeral bool `mapstructure:"tempkeys,omitempty" json:"tempkeys,omitempty"`
+
+	// PKCS11 options
+	// 密码模块的PKCS#11动态库路径
+	Library string `mapstructure:"library" json:"library"`
+	// token标签
+	Label string `mapstructure:"label" json:"label"`
+	// 用户PIN
+	Pin string `mapstructure:"pin" json:"pin"`
+	// 为true时验签在软件中完成，不占用密码模块
+	SoftVerify bool `mapstructure:"softwareverify,omitempty" json:"softwareverify,omitempty"`
+	// 为true时生成的密钥对象设置为不可修改
+	Immutable bool `mapstructure:"immutable,omitempty" json:"immutable,omitempty"`
+
+	// 国密机制常量，为nil时使用默认值
+	Mechanisms *GMMechanisms `mapstructure:"mechanisms,omitempty" json:"mechanisms,omitempty"`
+}
+
+// GMMechanisms 国密算法在密码模块中对应的PKCS#11密钥类型与机制
+type GMMechanisms struct {
+	KeyTypeSM2    uint `mapstructure:"keytypesm2" json:"keytypesm2"`
+	KeyTypeSM4    uint `mapstructure:"keytypesm4" json:"keytypesm4"`
+	SM2KeyPairGen uint `mapstructure:"sm2keypairgen" json:"sm2keypairgen"`
+	SM2SM3Sign    uint `mapstructure:"sm2sm3sign" json:"sm2sm3sign"`
+	SM3           uint `mapstructure:"sm3" json:"sm3"`
+	SM4KeyGen     uint `mapstructure:"sm4keygen" json:"sm4keygen"`
+	SM4CBCPad     uint `mapstructure:"sm4cbcpad" json:"sm4cbcpad"`
+}
+
+// DefaultGMMechanisms 返回国密机制常量的默认值
+func DefaultGMMechanisms() *GMMechanisms {
+	return &GMMechanisms{
+		KeyTypeSM2:    CKK_SM2,
+		KeyTypeSM4:    CKK_SM4,
+		SM2KeyPairGen: CKM_SM2_KEY_PAIR_GEN,
+		SM2SM3Sign:    CKM_SM2_SM3_SIGN,
+		SM3:           CKM_SM3,
+		SM4KeyGen:     CKM_SM4_KEY_GEN,
+		SM4CBCPad:     CKM_SM4_CBC_PAD,
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"crypto/rand"
+	"io"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
+	sdkp11 "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite/common/pkcs11"
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+/*
+bccsp/pkcs11/impl.go 定义基于PKCS#11密码模块的bccsp实现 impl。
+sm2密钥生成、sm2签名验签、sm3摘要、sm4密钥生成与sm4加解密在密码模块中完成，
+其他操作(如密钥导入、密钥派生)交给内嵌的软件实现 sw bccsp 处理。
+*/
+
+// Context 密码模块的PKCS#11会话上下文，`*sdkp11.ContextHandle`实现了该接口
+type Context interface {
+	GetSession() pkcs11.SessionHandle
+	ReturnSession(session pkcs11.SessionHandle)
+	GetAttributeValue(session pkcs11.SessionHandle, objectHandle pkcs11.ObjectHandle, attrs []*pkcs11.Attribute) ([]*pkcs11.Attribute, error)
+	SetAttributeValue(session pkcs11.SessionHandle, objectHandle pkcs11.ObjectHandle, attrs []*pkcs11.Attribute) error
+	GenerateKeyPair(session pkcs11.SessionHandle, m []*pkcs11.Mechanism, public, private []*pkcs11.Attribute) (pkcs11.ObjectHandle, pkcs11.ObjectHandle, error)
+	GenerateKey(session pkcs11.SessionHandle, m []*pkcs11.Mechanism, temp []*pkcs11.Attribute) (pkcs11.ObjectHandle, error)
+	FindObjectsInit(session pkcs11.SessionHandle, temp []*pkcs11.Attribute) error
+	FindObjects(session pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error)
+	FindObjectsFinal(session pkcs11.SessionHandle) error
+	FindKeyPairFromSKI(session pkcs11.SessionHandle, ski []byte, keyType bool) (*pkcs11.ObjectHandle, error)
+	EncryptInit(session pkcs11.SessionHandle, m []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error
+	Encrypt(session pkcs11.SessionHandle, message []byte) ([]byte, error)
+	DecryptInit(session pkcs11.SessionHandle, m []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error
+	Decrypt(session pkcs11.SessionHandle, cypher []byte) ([]byte, error)
+	SignInit(session pkcs11.SessionHandle, m []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error
+	Sign(session pkcs11.SessionHandle, message []byte) ([]byte, error)
+	VerifyInit(session pkcs11.SessionHandle, m []*pkcs11.Mechanism, key pkcs11.ObjectHandle) error
+	Verify(session pkcs11.SessionHandle, data []byte, signature []byte) error
+	DigestInit(session pkcs11.SessionHandle, m []*pkcs11.Mechanism) error
+	Digest(session pkcs11.SessionHandle, message []byte) ([]byte, error)
+}
+
+// New returns a new instance of the PKCS#11-based BCCSP
+// set at the passed security level, hash family and KeyStore.
+func New(opts PKCS11Opts, keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
+	if opts.Library == "" {
+		return nil, errors.New("Invalid config. PKCS11 library path must not be empty")
+	}
+
+	ctx, err := sdkp11.LoadContextAndLogin(opts.Library, opts.Pin, opts.Label)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed initializing PKCS11 library %s %s", opts.Library, opts.Label)
+	}
+
+	return NewWithContext(opts, keyStore, ctx)
+}
+
+// NewWithContext 使用已经登录的PKCS#11会话上下文创建pkcs11 bccsp，opts中的Library、Label与Pin不再使用
+func NewWithContext(opts PKCS11Opts, keyStore bccsp.KeyStore, ctx Context) (bccsp.BCCSP, error) {
+	if ctx == nil {
+		return nil, errors.New("Invalid PKCS11 context. It must not be nil")
+	}
+
+	// 密码模块之外的操作使用软件实现
+	swCSP, err := sw.NewWithParams(true, opts.SecLevel, opts.HashFamily, keyStore)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed initializing fallback SW BCCSP")
+	}
+
+	mechs := opts.Mechanisms
+	if mechs == nil {
+		mechs = DefaultGMMechanisms()
+	}
+
+	return &impl{
+		BCCSP:      swCSP,
+		ctx:        ctx,
+		softVerify: opts.SoftVerify,
+		immutable:  opts.Immutable,
+		mechs:      mechs,
+	}, nil
+}
+
+type impl struct {
+	bccsp.BCCSP
+
+	ctx Context
+
+	softVerify bool
+	// Immutable flag makes object immutable
+	immutable bool
+	mechs     *GMMechanisms
+}
+
+// KeyGen generates a key using opts.
+// sm2与sm4密钥在密码模块中生成，其他类型交给软件实现
+func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	if opts == nil {
+		return nil, errors.New("Invalid Opts parameter. It must not be nil")
+	}
+
+	switch opts.(type) {
+	case *bccsp.SM2KeyGenOpts:
+		ski, pub, err := csp.generateSM2Key(opts.Ephemeral())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed generating SM2 key")
+		}
+		return &sm2PrivateKey{ski: ski, pub: sm2PublicKey{ski: ski, pub: pub}}, nil
+	case *bccsp.SM4KeyGenOpts:
+		ski, err := csp.generateSM4Key(opts.Ephemeral())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed generating SM4 key")
+		}
+		return &sm4Key{ski: ski}, nil
+	default:
+		return csp.BCCSP.KeyGen(opts)
+	}
+}
+
+// GetKey returns the key this CSP associates to
+// the Subject Key Identifier ski.
+// 优先在密码模块中查找，找不到时再从软件实现的keystore中查找
+func (csp *impl) GetKey(ski []byte) (bccsp.Key, error) {
+	pubKey, isPriv, err := csp.getSM2Key(ski)
+	if err == nil {
+		if isPriv {
+			return &sm2PrivateKey{ski: ski, pub: sm2PublicKey{ski: ski, pub: pubKey}}, nil
+		}
+		return &sm2PublicKey{ski: ski, pub: pubKey}, nil
+	}
+	if csp.hasSM4Key(ski) {
+		return &sm4Key{ski: ski}, nil
+	}
+	return csp.BCCSP.GetKey(ski)
+}
+
+// Hash hashes messages msg using options opts.
+// sm3摘要在密码模块中计算
+func (csp *impl) Hash(msg []byte, opts bccsp.HashOpts) ([]byte, error) {
+	if _, ok := opts.(*bccsp.SM3Opts); ok {
+		return csp.digestP11SM3(msg)
+	}
+	return csp.BCCSP.Hash(msg, opts)
+}
+
+// Sign signs digest using key k.
+// 使用密码模块中的sm2私钥时，digest应为消息原文，Z值与SM3摘要由密码模块计算
+func (csp *impl) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil")
+	}
+	if len(digest) == 0 {
+		return nil, errors.New("Invalid digest. Cannot be empty")
+	}
+
+	switch key := k.(type) {
+	case *sm2PrivateKey:
+		return csp.signP11SM2(key.ski, digest)
+	default:
+		return csp.BCCSP.Sign(k, digest, opts)
+	}
+}
+
+// Verify verifies signature against key k and digest
+func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	if k == nil {
+		return false, errors.New("Invalid Key. It must not be nil")
+	}
+	if len(signature) == 0 {
+		return false, errors.New("Invalid signature. Cannot be empty")
+	}
+	if len(digest) == 0 {
+		return false, errors.New("Invalid digest. Cannot be empty")
+	}
+
+	var pub *sm2PublicKey
+	switch key := k.(type) {
+	case *sm2PrivateKey:
+		pub = &key.pub
+	case *sm2PublicKey:
+		pub = key
+	default:
+		return csp.BCCSP.Verify(k, signature, digest, opts)
+	}
+
+	if csp.softVerify {
+		// 将公钥导入软件实现后验签，不占用密码模块
+		swKey, err := csp.BCCSP.KeyImport(pub.pub, &bccsp.SM2GoPublicKeyImportOpts{Temporary: true})
+		if err != nil {
+			return false, errors.Wrapf(err, "Failed importing SM2 public key for software verification")
+		}
+		return csp.BCCSP.Verify(swKey, signature, digest, opts)
+	}
+	return csp.verifyP11SM2(pub.ski, digest, signature)
+}
+
+// Encrypt encrypts plaintext using key k.
+// 使用密码模块中的sm4密钥做CBC模式加密，opts可以是`bccsp.SM4EncrypterDecrypterOpts`以指定IV
+func (csp *impl) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	key, ok := k.(*sm4Key)
+	if !ok {
+		return csp.BCCSP.Encrypt(k, plaintext, opts)
+	}
+
+	iv, err := sm4IV(opts)
+	if err != nil {
+		return nil, err
+	}
+	return csp.encryptP11SM4(key.ski, iv, plaintext)
+}
+
+// Decrypt decrypts ciphertext using key k.
+func (csp *impl) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	key, ok := k.(*sm4Key)
+	if !ok {
+		return csp.BCCSP.Decrypt(k, ciphertext, opts)
+	}
+	switch opts.(type) {
+	case *bccsp.SM4EncrypterDecrypterOpts, bccsp.SM4EncrypterDecrypterOpts:
+		return csp.decryptP11SM4(key.ski, ciphertext)
+	default:
+		return nil, errors.Errorf("mode not recognized [%s]", opts)
+	}
+}
+
+// 根据加密选项获取IV，未指定时随机生成
+func sm4IV(opts bccsp.EncrypterOpts) ([]byte, error) {
+	var prng io.Reader = rand.Reader
+	switch o := opts.(type) {
+	case *bccsp.SM4EncrypterDecrypterOpts:
+		if len(o.IV) != 0 && o.PRNG != nil {
+			return nil, errors.New("Invalid options. Either IV or PRNG should be different from nil, or both nil.")
+		}
+		if len(o.IV) != 0 {
+			if len(o.IV) != sm4BlockSize {
+				return nil, errors.Errorf("Invalid IV. It must have length the block size [%d]", sm4BlockSize)
+			}
+			return o.IV, nil
+		}
+		if o.PRNG != nil {
+			prng = o.PRNG
+		}
+	case bccsp.SM4EncrypterDecrypterOpts:
+		return sm4IV(&o)
+	default:
+		return nil, errors.Errorf("mode not recognized [%s]", opts)
+	}
+
+	iv := make([]byte, sm4BlockSize)
+	if _, err := io.ReadFull(prng, iv); err != nil {
+		return nil, errors.Wrap(err, "failed generating IV")
+	}
+	return iv, nil
+}
@@ -0,0 +1,366 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	"gitee.com/zhaochuninhefei/gmgo/sm3"
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+/*
+bccsp/pkcs11/pkcs11.go 通过PKCS#11接口在密码模块中完成国密算法的各项操作:
+sm2密钥对生成、sm2签名与验签、sm3摘要、sm4密钥生成与sm4加解密
+会话的获取与归还、登录以及异常恢复都由`pkg/core/cryptosuite/common/pkcs11`的ContextHandle负责
+*/
+
+const (
+	privateKeyType = true
+	publicKeyType  = false
+
+	// sm2签名中r与s各自的字节长度
+	sm2ScalarLength = 32
+	// sm4密钥字节长度
+	sm4KeyLength = 16
+	// sm4分组字节长度，即CBC模式的IV长度
+	sm4BlockSize = 16
+	// 密码模块中sm4密钥的CKA_ID长度
+	sm4SKILength = 32
+)
+
+// 在密码模块中生成sm2密钥对，返回以公钥计算的SKI与公钥
+// 密钥对先使用随机的CKA_ID生成，得到公钥后再将公私钥对象的CKA_ID修改为SKI，与软件实现的SKI保持一致
+func (csp *impl) generateSM2Key(ephemeral bool) ([]byte, *sm2.PublicKey, error) {
+	session := csp.ctx.GetSession()
+	defer csp.ctx.ReturnSession(session)
+
+	ecParams, err := asn1.Marshal(oidNamedCurveSM2)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed marshalling SM2 curve OID")
+	}
+
+	tmpID := make([]byte, sm4SKILength)
+	if _, err = io.ReadFull(rand.Reader, tmpID); err != nil {
+		return nil, nil, errors.Wrap(err, "failed generating temporary key id")
+	}
+
+	pubTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, csp.mechs.KeyTypeSM2),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ecParams),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, tmpID),
+	}
+
+	privTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, csp.mechs.KeyTypeSM2),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, tmpID),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+	}
+
+	pubHandle, privHandle, err := csp.ctx.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(csp.mechs.SM2KeyPairGen, nil)},
+		pubTemplate, privTemplate)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "P11: keypair generate failed")
+	}
+
+	pubKey, err := csp.sm2PublicKeyFromHandle(session, pubHandle)
+	if err != nil {
+		return nil, nil, err
+	}
+	ski := sm2SKI(pubKey)
+
+	setAttrs := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+	}
+	if csp.immutable {
+		setAttrs = append(setAttrs, pkcs11.NewAttribute(pkcs11.CKA_MODIFIABLE, false))
+	}
+	for _, handle := range []pkcs11.ObjectHandle{pubHandle, privHandle} {
+		if err = csp.ctx.SetAttributeValue(session, handle, setAttrs); err != nil {
+			return nil, nil, errors.Wrap(err, "P11: set-ID-to-SKI[object] failed")
+		}
+	}
+
+	return ski, pubKey, nil
+}
+
+// 根据SKI从密码模块中读取sm2公钥，同时返回是否存在对应的私钥
+func (csp *impl) getSM2Key(ski []byte) (*sm2.PublicKey, bool, error) {
+	session := csp.ctx.GetSession()
+	defer csp.ctx.ReturnSession(session)
+
+	pubHandle, err := csp.ctx.FindKeyPairFromSKI(session, ski, publicKeyType)
+	if err != nil {
+		return nil, false, fmt.Errorf("public key not found [%s]", err)
+	}
+
+	pubKey, err := csp.sm2PublicKeyFromHandle(session, *pubHandle)
+	if err != nil {
+		return nil, false, err
+	}
+
+	_, err = csp.ctx.FindKeyPairFromSKI(session, ski, privateKeyType)
+	isPriv := err == nil
+
+	return pubKey, isPriv, nil
+}
+
+// 读取公钥对象的CKA_EC_POINT并转为sm2公钥
+func (csp *impl) sm2PublicKeyFromHandle(session pkcs11.SessionHandle, handle pkcs11.ObjectHandle) (*sm2.PublicKey, error) {
+	attrs, err := csp.ctx.GetAttributeValue(session, handle,
+		[]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)})
+	if err != nil {
+		return nil, errors.Wrap(err, "P11: get(EC point) failed")
+	}
+
+	var point []byte
+	for _, a := range attrs {
+		if a.Type == pkcs11.CKA_EC_POINT {
+			point = ecPoint(a.Value)
+		}
+	}
+	if point == nil {
+		return nil, errors.New("P11: EC point not found in public key object")
+	}
+
+	curve := sm2.P256Sm2()
+	x, y := elliptic.Unmarshal(curve, point)
+	if x == nil {
+		return nil, errors.New("P11: failed unmarshalling SM2 public key")
+	}
+	return &sm2.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// 在密码模块中使用sm2私钥签名，密码模块计算Z值与SM3摘要，签名结果转为asn1编码
+func (csp *impl) signP11SM2(ski []byte, msg []byte) ([]byte, error) {
+	session := csp.ctx.GetSession()
+	defer csp.ctx.ReturnSession(session)
+
+	privateKey, err := csp.ctx.FindKeyPairFromSKI(session, ski, privateKeyType)
+	if err != nil {
+		return nil, fmt.Errorf("private key not found [%s]", err)
+	}
+
+	err = csp.ctx.SignInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(csp.mechs.SM2SM3Sign, nil)}, *privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("sign-initialize failed [%s]", err)
+	}
+
+	sig, err := csp.ctx.Sign(session, msg)
+	if err != nil {
+		return nil, fmt.Errorf("P11: sign failed [%s]", err)
+	}
+	if len(sig) != 2*sm2ScalarLength {
+		return nil, fmt.Errorf("P11: unexpected signature length [%d]", len(sig))
+	}
+
+	r := new(big.Int).SetBytes(sig[:sm2ScalarLength])
+	s := new(big.Int).SetBytes(sig[sm2ScalarLength:])
+	return sw.MarshalSM2Signature(r, s)
+}
+
+// 在密码模块中使用sm2公钥验签，signature为asn1编码的签名
+func (csp *impl) verifyP11SM2(ski []byte, msg, signature []byte) (bool, error) {
+	r, s, err := sw.UnmarshalSM2Signature(signature)
+	if err != nil {
+		return false, err
+	}
+
+	session := csp.ctx.GetSession()
+	defer csp.ctx.ReturnSession(session)
+
+	publicKey, err := csp.ctx.FindKeyPairFromSKI(session, ski, publicKeyType)
+	if err != nil {
+		return false, fmt.Errorf("public key not found [%s]", err)
+	}
+
+	sig := make([]byte, 2*sm2ScalarLength)
+	r.FillBytes(sig[:sm2ScalarLength])
+	s.FillBytes(sig[sm2ScalarLength:])
+
+	err = csp.ctx.VerifyInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(csp.mechs.SM2SM3Sign, nil)}, *publicKey)
+	if err != nil {
+		return false, fmt.Errorf("PKCS11: Verify-initialize [%s]", err)
+	}
+	err = csp.ctx.Verify(session, msg, sig)
+	if err != nil {
+		if strings.Contains(err.Error(), "CKR_SIGNATURE_INVALID") {
+			return false, nil
+		}
+		return false, fmt.Errorf("PKCS11: Verify failed [%s]", err)
+	}
+
+	return true, nil
+}
+
+// 在密码模块中计算sm3摘要
+func (csp *impl) digestP11SM3(msg []byte) ([]byte, error) {
+	session := csp.ctx.GetSession()
+	defer csp.ctx.ReturnSession(session)
+
+	err := csp.ctx.DigestInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(csp.mechs.SM3, nil)})
+	if err != nil {
+		return nil, fmt.Errorf("P11: digest-initialize failed [%s]", err)
+	}
+
+	digest, err := csp.ctx.Digest(session, msg)
+	if err != nil {
+		return nil, fmt.Errorf("P11: digest failed [%s]", err)
+	}
+	return digest, nil
+}
+
+// 在密码模块中生成sm4密钥，返回随机生成的SKI(即密钥对象的CKA_ID)
+func (csp *impl) generateSM4Key(ephemeral bool) ([]byte, error) {
+	session := csp.ctx.GetSession()
+	defer csp.ctx.ReturnSession(session)
+
+	ski := make([]byte, sm4SKILength)
+	if _, err := io.ReadFull(rand.Reader, ski); err != nil {
+		return nil, errors.Wrap(err, "failed generating key id")
+	}
+
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, csp.mechs.KeyTypeSM4),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, sm4KeyLength),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+		pkcs11.NewAttribute(pkcs11.CKA_MODIFIABLE, !csp.immutable),
+	}
+
+	_, err := csp.ctx.GenerateKey(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(csp.mechs.SM4KeyGen, nil)}, template)
+	if err != nil {
+		return nil, errors.Wrap(err, "P11: SM4 key generate failed")
+	}
+	return ski, nil
+}
+
+// 根据SKI在密码模块中查找sm4密钥对象
+func (csp *impl) findSM4Key(session pkcs11.SessionHandle, ski []byte) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+	}
+	if err := csp.ctx.FindObjectsInit(session, template); err != nil {
+		return 0, err
+	}
+
+	objs, _, err := csp.ctx.FindObjects(session, 1)
+	if err != nil {
+		return 0, err
+	}
+	if err = csp.ctx.FindObjectsFinal(session); err != nil {
+		return 0, err
+	}
+	if len(objs) == 0 {
+		return 0, fmt.Errorf("secret key not found [%x]", ski)
+	}
+	return objs[0], nil
+}
+
+// 判断密码模块中是否存在SKI对应的sm4密钥
+func (csp *impl) hasSM4Key(ski []byte) bool {
+	session := csp.ctx.GetSession()
+	defer csp.ctx.ReturnSession(session)
+
+	_, err := csp.findSM4Key(session, ski)
+	return err == nil
+}
+
+// 在密码模块中使用sm4密钥做CBC模式加密，与软件实现一致，IV放在密文之前
+func (csp *impl) encryptP11SM4(ski, iv, plaintext []byte) ([]byte, error) {
+	session := csp.ctx.GetSession()
+	defer csp.ctx.ReturnSession(session)
+
+	key, err := csp.findSM4Key(session, ski)
+	if err != nil {
+		return nil, err
+	}
+
+	err = csp.ctx.EncryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(csp.mechs.SM4CBCPad, iv)}, key)
+	if err != nil {
+		return nil, fmt.Errorf("P11: encrypt-initialize failed [%s]", err)
+	}
+	ciphertext, err := csp.ctx.Encrypt(session, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("P11: encrypt failed [%s]", err)
+	}
+
+	return append(append([]byte{}, iv...), ciphertext...), nil
+}
+
+// 在密码模块中使用sm4密钥做CBC模式解密，密文的前16字节为IV
+func (csp *impl) decryptP11SM4(ski, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < sm4BlockSize {
+		return nil, errors.New("invalid ciphertext. It must be a multiple of the block size")
+	}
+
+	session := csp.ctx.GetSession()
+	defer csp.ctx.ReturnSession(session)
+
+	key, err := csp.findSM4Key(session, ski)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := ciphertext[:sm4BlockSize]
+	err = csp.ctx.DecryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(csp.mechs.SM4CBCPad, iv)}, key)
+	if err != nil {
+		return nil, fmt.Errorf("P11: decrypt-initialize failed [%s]", err)
+	}
+	plaintext, err := csp.ctx.Decrypt(session, ciphertext[sm4BlockSize:])
+	if err != nil {
+		return nil, fmt.Errorf("P11: decrypt failed [%s]", err)
+	}
+	return plaintext, nil
+}
+
+// 计算sm2公钥的SKI，与`sw.SM2PublicKey`的SKI计算方式一致
+func sm2SKI(pubKey *sm2.PublicKey) []byte {
+	raw := elliptic.Marshal(pubKey.Curve, pubKey.X, pubKey.Y)
+
+	hash := sm3.New()
+	hash.Write(raw)
+	return hash.Sum(nil)
+}
+
+// CKA_EC_POINT通常是asn1编码的OCTET STRING，部分密码模块直接返回未压缩的点
+func ecPoint(raw []byte) []byte {
+	var point []byte
+	rest, err := asn1.Unmarshal(raw, &point)
+	if err == nil && len(rest) == 0 && len(point) > 0 && point[0] == 0x04 {
+		return point
+	}
+	if len(raw) > 0 && raw[0] == 0x04 {
+		return raw
+	}
+	return nil
+}
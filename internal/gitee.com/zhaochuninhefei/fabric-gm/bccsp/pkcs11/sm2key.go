@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"errors"
+	"fmt"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	gmx509 "gitee.com/zhaochuninhefei/gmgo/x509"
+)
+
+/*
+bccsp/pkcs11/sm2key.go 定义保存在密码模块中的sm2公私钥结构体，并分别实现`bccsp.Key`(bccsp/bccsp.go)接口。
+私钥不离开密码模块，结构体中只保存其SKI(即密码模块中对象的CKA_ID)与对应的公钥。
+*/
+
+type sm2PrivateKey struct {
+	ski []byte
+	pub sm2PublicKey
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+// 私钥保存在密码模块中，不支持导出
+func (k *sm2PrivateKey) Bytes() ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *sm2PrivateKey) SKI() []byte {
+	return k.ski
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *sm2PrivateKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *sm2PrivateKey) Private() bool {
+	return true
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+// This method returns an error in symmetric key schemes.
+func (k *sm2PrivateKey) PublicKey() (bccsp.Key, error) {
+	return &k.pub, nil
+}
+
+// InsideKey 私钥保存在密码模块中，返回对应的sm2公钥
+func (k *sm2PrivateKey) InsideKey() interface{} {
+	return k.pub.pub
+}
+
+type sm2PublicKey struct {
+	ski []byte
+	pub *sm2.PublicKey
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+// 返回sm2公钥的PKIX格式der编码结果
+func (k *sm2PublicKey) Bytes() (raw []byte, err error) {
+	raw, err = gmx509.MarshalPKIXPublicKey(k.pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshalling key [%s]", err)
+	}
+	return
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *sm2PublicKey) SKI() []byte {
+	return k.ski
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *sm2PublicKey) Symmetric() bool {
+	return false
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *sm2PublicKey) Private() bool {
+	return false
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+// This method returns an error in symmetric key schemes.
+func (k *sm2PublicKey) PublicKey() (bccsp.Key, error) {
+	return k, nil
+}
+
+func (k *sm2PublicKey) InsideKey() interface{} {
+	return k.pub
+}
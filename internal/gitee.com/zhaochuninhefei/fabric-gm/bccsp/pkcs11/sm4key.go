@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"errors"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+)
+
+/*
+bccsp/pkcs11/sm4key.go 定义保存在密码模块中的sm4密钥结构体，并实现`bccsp.Key`(bccsp/bccsp.go)接口。
+密钥不离开密码模块，结构体中只保存其SKI(即密码模块中对象的CKA_ID)。
+*/
+
+type sm4Key struct {
+	ski []byte
+}
+
+// Bytes converts this key to its byte representation,
+// if this operation is allowed.
+// 密钥保存在密码模块中，不支持导出
+func (k *sm4Key) Bytes() ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+// SKI returns the subject key identifier of this key.
+func (k *sm4Key) SKI() []byte {
+	return k.ski
+}
+
+// Symmetric returns true if this key is a symmetric key,
+// false if this key is asymmetric
+func (k *sm4Key) Symmetric() bool {
+	return true
+}
+
+// Private returns true if this key is a private key,
+// false otherwise.
+func (k *sm4Key) Private() bool {
+	return true
+}
+
+// PublicKey returns the corresponding public key part of an asymmetric public/private key pair.
+// This method returns an error in symmetric key schemes.
+func (k *sm4Key) PublicKey() (bccsp.Key, error) {
+	return nil, errors.New("cannot call this method on a symmetric key")
+}
+
+// InsideKey 密钥保存在密码模块中，只能返回其SKI
+func (k *sm4Key) InsideKey() interface{} {
+	return k.ski
+}
@@ -8,9 +8,11 @@ package sw
 
 import (
 	"crypto/elliptic"
+	"crypto/sha256"
 	"fmt"
 	"hash"
 
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
 	"gitee.com/zhaochuninhefei/gmgo/sm2"
 	"gitee.com/zhaochuninhefei/gmgo/sm3"
 )
@@ -28,16 +30,27 @@ type config struct {
 	gmFunction func() hash.Hash
 	// 国密密钥位数
 	gmByteLength int
+	// 是否同时启用非国密算法(混合模式)
+	hybrid bool
+	// 混合模式下的ecdsa椭圆曲线
+	ecdsaCurve elliptic.Curve
+	// 混合模式下的非国密散列函数
+	sha2Function func() hash.Hash
 }
 
 // 设置安全级别配置
 func (conf *config) setSecurityLevel(usingGM bool, securityLevel int, hashFamily string) (err error) {
 	// 国密对应，无视 usingGM ，固定使用国密
 	conf.usingGM = true
-	if securityLevel == 256 && hashFamily == "SM3" {
+	switch {
+	case securityLevel == 256 && hashFamily == bccsp.SM3:
 		_ = conf.setSecurityLevelWithSM2SM3()
-	} else {
-		err = fmt.Errorf("bccsp国密改造版目前只支持国密SM3 256位，不支持 [%s] [%d]位", hashFamily, securityLevel)
+	case securityLevel == 256 && hashFamily == bccsp.SM3SHA2:
+		// 混合模式，国密算法之外追加ecdsa P-256与SHA256
+		_ = conf.setSecurityLevelWithSM2SM3()
+		_ = conf.setSecurityLevelWithECDSASHA2()
+	default:
+		err = fmt.Errorf("bccsp国密改造版目前只支持国密SM3 256位或混合模式[%s] 256位，不支持 [%s] [%d]位", bccsp.SM3SHA2, hashFamily, securityLevel)
 	}
 	return
 }
@@ -49,3 +62,11 @@ func (conf *config) setSecurityLevelWithSM2SM3() (err error) {
 	conf.gmByteLength = 16
 	return nil
 }
+
+// 设置混合模式下非国密算法的安全级别配置，只支持P-256曲线与SHA256
+func (conf *config) setSecurityLevelWithECDSASHA2() (err error) {
+	conf.hybrid = true
+	conf.ecdsaCurve = elliptic.P256()
+	conf.sha2Function = sha256.New
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/utils"
+)
+
+/*
+bccsp/sw/ecdsa.go 实现`sw.Signer`接口和`sw.Verifier`接口(bccsp/sw/internals.go)
+仅在混合模式下使用，与原生fabric一样只生成与接受lowS签名
+*/
+
+// ecdsa签名，digest是内容摘要，签名结果转为lowS
+func signECDSA(k *ecdsa.PrivateKey, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err = utils.ToLowS(&k.PublicKey, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.MarshalECDSASignature(r, s)
+}
+
+// ecdsa验签，digest是内容摘要，signature是被验签的签名，非lowS签名视为无效
+func verifyECDSA(k *ecdsa.PublicKey, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	r, s, err := utils.UnmarshalECDSASignature(signature)
+	if err != nil {
+		return false, fmt.Errorf("failed unmashalling signature [%s]", err)
+	}
+
+	lowS, err := utils.IsLowS(k, s)
+	if err != nil {
+		return false, err
+	}
+
+	if !lowS {
+		return false, fmt.Errorf("invalid S. Must be smaller than half the order [%s][%s]", s, utils.GetCurveHalfOrdersAt(k.Curve))
+	}
+
+	return ecdsa.Verify(k, digest, r, s), nil
+}
+
+type ecdsaSigner struct{}
+
+// 在ecdsaSigner上绑定Sign签名方法
+func (s *ecdsaSigner) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	return signECDSA(k.(*ECDSAPrivateKey).privKey, digest, opts)
+}
+
+type ecdsaPrivateKeyVerifier struct{}
+
+// 在ecdsaPrivateKeyVerifier上绑定验签方法
+func (v *ecdsaPrivateKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	return verifyECDSA(&(k.(*ECDSAPrivateKey).privKey.PublicKey), signature, digest, opts)
+}
+
+type ecdsaPublicKeyKeyVerifier struct{}
+
+// 在ecdsaPublicKeyKeyVerifier上绑定验签方法
+func (v *ecdsaPublicKeyKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	return verifyECDSA(k.(*ECDSAPublicKey).pubKey, signature, digest, opts)
+}
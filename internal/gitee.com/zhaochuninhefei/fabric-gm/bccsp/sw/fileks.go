@@ -8,6 +8,7 @@ package sw
 
 import (
 	"bytes"
+	"crypto/ecdsa"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -82,7 +83,7 @@ func (ks *fileBasedKeyStore) Init(pwd []byte, path string, readOnly bool) error
 	ks.path = path
 
 	clone := make([]byte, len(pwd))
-	copy(ks.pwd, pwd)
+	copy(clone, pwd)
 	ks.pwd = clone
 	ks.readOnly = readOnly
 
@@ -146,8 +147,8 @@ func (ks *fileBasedKeyStore) GetKey(ski []byte) (bccsp.Key, error) {
 		}
 
 		switch k := key.(type) {
-		// case *ecdsa.PrivateKey:
-		// 	return &ECDSAPrivateKey{k}, nil
+		case *ecdsa.PrivateKey:
+			return &ECDSAPrivateKey{k}, nil
 		case *sm2.PrivateKey:
 			return &SM2PrivateKey{k}, nil
 		default:
@@ -161,8 +162,8 @@ func (ks *fileBasedKeyStore) GetKey(ski []byte) (bccsp.Key, error) {
 		}
 
 		switch k := key.(type) {
-		// case *ecdsa.PublicKey:
-		// 	return &ECDSAPublicKey{k}, nil
+		case *ecdsa.PublicKey:
+			return &ECDSAPublicKey{k}, nil
 		case *sm2.PublicKey:
 			return &SM2PublicKey{k}, nil
 		default:
@@ -203,17 +204,18 @@ func (ks *fileBasedKeyStore) StoreKey(k bccsp.Key) (err error) {
 			return fmt.Errorf("failed storing SM4 key [%s]", err)
 		}
 
-	// case *ECDSAPrivateKey:
-	// 	err = ks.storePrivateKey("ecdsask", hex.EncodeToString(k.SKI()), kk.privKey)
-	// 	if err != nil {
-	// 		return fmt.Errorf("failed storing ECDSA private key [%s]", err)
-	// 	}
+	// 混合模式下的ecdsa公私钥与sm2公私钥使用相同的后缀存储，读取时根据pem内容区分
+	case *ECDSAPrivateKey:
+		err = ks.storePrivateKey("sk", hex.EncodeToString(k.SKI()), kk.privKey)
+		if err != nil {
+			return fmt.Errorf("failed storing ECDSA private key [%s]", err)
+		}
 
-	// case *ECDSAPublicKey:
-	// 	err = ks.storePublicKey("ecdsapk", hex.EncodeToString(k.SKI()), kk.pubKey)
-	// 	if err != nil {
-	// 		return fmt.Errorf("failed storing ECDSA public key [%s]", err)
-	// 	}
+	case *ECDSAPublicKey:
+		err = ks.storePublicKey("pk", hex.EncodeToString(k.SKI()), kk.pubKey)
+		if err != nil {
+			return fmt.Errorf("failed storing ECDSA public key [%s]", err)
+		}
 
 	// case *AESPrivateKey:
 	// 	err = ks.storeKey("aeskey", hex.EncodeToString(k.SKI()), kk.privKey)
@@ -246,21 +248,20 @@ func (ks *fileBasedKeyStore) searchKeystoreForSKI(ski []byte) (k bccsp.Key, err
 		if err != nil {
 			continue
 		}
-		// 尝试将pem转为sm2私钥
-		key, err := pemToSm2PrivateKey(raw, ks.pwd)
+		// 尝试将pem转为sm2或ecdsa私钥
+		key, err := pemToPrivateKey(raw, ks.pwd)
 		if err != nil {
 			continue
 		}
 
-		// switch kk := key.(type) {
-		// case *ecdsa.PrivateKey:
-		// 	k = &ECDSAPrivateKey{kk}
-		// case *sm2.PrivateKey:
-		// 	k = &SM2PrivateKey{kk}
-		// default:
-		// 	continue
-		// }
-		k := &SM2PrivateKey{key}
+		switch kk := key.(type) {
+		case *ecdsa.PrivateKey:
+			k = &ECDSAPrivateKey{kk}
+		case *sm2.PrivateKey:
+			k = &SM2PrivateKey{kk}
+		default:
+			continue
+		}
 
 		if !bytes.Equal(k.SKI(), ski) {
 			continue
@@ -396,7 +397,8 @@ func (ks *fileBasedKeyStore) loadPrivateKey(suffix, alias string) (interface{},
 
 	// privateKey, err := pemToPrivateKey(raw, ks.pwd)
 	// privateKey, err := x509.ReadPrivateKeyFromMem(raw, ks.pwd)
-	privateKey, err := pemToSm2PrivateKey(raw, ks.pwd)
+	// 混合模式下可能是sm2或ecdsa私钥，由调用方根据类型区分
+	privateKey, err := pemToPrivateKey(raw, ks.pwd)
 	if err != nil {
 		logger.Errorf("Failed parsing private key [%s]: [%s].", alias, err.Error())
 		return nil, err
@@ -426,7 +428,8 @@ func (ks *fileBasedKeyStore) loadPublicKey(suffix, alias string) (interface{}, e
 	// }
 	//privateKey, err := pemToPublicKey(raw, ks.pwd)
 	// privateKey, err := x509.ReadPublicKeyFromMem(raw, ks.pwd)
-	pubKey, err := pemToSm2PublicKey(raw, ks.pwd)
+	// 混合模式下可能是sm2或ecdsa公钥，由调用方根据类型区分
+	pubKey, err := pemToPublicKey(raw, ks.pwd)
 	if err != nil {
 		logger.Errorf("Failed parsing private key [%s]: [%s].", alias, err.Error())
 		return nil, err
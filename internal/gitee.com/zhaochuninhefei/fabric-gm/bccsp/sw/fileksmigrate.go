@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gmx509 "gitee.com/zhaochuninhefei/gmgo/x509"
+)
+
+/*
+bccsp/sw/fileksmigrate.go 提供keystore密钥文件的重新加密功能，
+用于将已有的明文keystore迁移为加密keystore，或更换keystore的密码。
+*/
+
+// ErrNotKeyPEM pem内容不是私钥、公钥或sm4密钥(例如证书)
+var ErrNotKeyPEM = errors.New("PEM block does not contain a key")
+
+// ReEncryptPEM 将pem格式的私钥、公钥或sm4密钥使用oldPwd解密后再使用newPwd加密。
+// oldPwd为空时表示原pem未加密，newPwd为空时输出未加密的pem。
+func ReEncryptPEM(raw, oldPwd, newPwd []byte) ([]byte, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, ErrNotKeyPEM
+	}
+
+	switch {
+	case strings.Contains(block.Type, "SM4"):
+		key, err := pemToSM4(raw, oldPwd)
+		if err != nil {
+			return nil, err
+		}
+		return sm4ToEncryptedPEM(key, newPwd)
+	case strings.Contains(block.Type, "PUBLIC KEY"):
+		key, err := pemToPublicKey(raw, oldPwd)
+		if err != nil {
+			return nil, err
+		}
+		return publicKeyToPEM(key, newPwd)
+	case strings.Contains(block.Type, "PRIVATE KEY"):
+		key, err := pemToPrivateKey(raw, oldPwd)
+		if err != nil {
+			return nil, err
+		}
+		return privateKeyToPEM(key, newPwd)
+	default:
+		return nil, ErrNotKeyPEM
+	}
+}
+
+// ReEncryptFileBasedKeyStore 对path目录下的所有密钥文件执行ReEncryptPEM，返回重新加密的文件数量。
+// 不是密钥pem的文件(如证书)会被跳过；oldPwd为空时已经加密的文件也会被跳过，因此可以对同一目录重复执行明文迁移。
+// 每个文件先写入临时文件再重命名，避免迁移中断时留下损坏的密钥文件。
+func ReEncryptFileBasedKeyStore(path string, oldPwd, newPwd []byte) (int, error) {
+	if len(path) == 0 {
+		return 0, errors.New("an invalid KeyStore path provided. Path cannot be an empty string")
+	}
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, f := range files {
+		if f.IsDir() || f.Size() > (1<<16) {
+			continue
+		}
+
+		filePath := filepath.Join(path, f.Name())
+		raw, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return count, err
+		}
+
+		block, _ := pem.Decode(raw)
+		if block == nil {
+			continue
+		}
+		if len(oldPwd) == 0 && gmx509.IsEncryptedPEMBlock(block) {
+			logger.Debugf("Key file [%s] is already encrypted, skipping", filePath)
+			continue
+		}
+
+		converted, err := ReEncryptPEM(raw, oldPwd, newPwd)
+		if err == ErrNotKeyPEM {
+			continue
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed re-encrypting key file [%s]: [%s]", filePath, err)
+		}
+
+		if err = writeFileAtomic(filePath, converted, f.Mode().Perm()); err != nil {
+			return count, fmt.Errorf("failed writing key file [%s]: [%s]", filePath, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// 先写入同目录下的临时文件再重命名为目标文件
+func writeFileAtomic(filePath string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
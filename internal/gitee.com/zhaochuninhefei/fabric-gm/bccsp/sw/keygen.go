@@ -17,6 +17,8 @@ limitations under the License.
 package sw
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"fmt"
 
@@ -28,7 +30,7 @@ import (
 
 /*
 bccsp/sw/keygen.go 定义各个算法的密钥生成器，实现`sw.KeyGenerator`接口(bccsp/sw/internals.go)
-ecdsaKeyGenerator 国密对应时去除
+ecdsaKeyGenerator 国密对应时去除，混合模式下重新启用
 sm2KeyGenerator
 sm4KeyGenerator
 aesKeyGenerator 国密对应时去除
@@ -47,6 +49,20 @@ func (gm *sm2KeyGenerator) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
 	return &SM2PrivateKey{privKey}, nil
 }
 
+// ecdsa私钥生成器，仅在混合模式下使用
+type ecdsaKeyGenerator struct {
+	curve elliptic.Curve
+}
+
+// 生成ecdsa私钥
+func (kg *ecdsaKeyGenerator) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	privKey, err := ecdsa.GenerateKey(kg.curve, rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating ECDSA key for [%v]: [%s]", kg.curve, err)
+	}
+	return &ECDSAPrivateKey{privKey}, nil
+}
+
 // sm4密钥生成器
 type sm4KeyGenerator struct {
 	length int
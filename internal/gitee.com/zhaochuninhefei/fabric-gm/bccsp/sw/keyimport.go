@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package sw
 
 import (
+	"crypto/ecdsa"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"reflect"
@@ -23,9 +25,9 @@ bccsp/sw/keyimport.go 实现`sw.KeyImporter`接口(bccsp/sw/internals.go)
 实现了以下几个密钥导入器:
 aes256ImportKeyOptsKeyImporter 国密对应时去除
 hmacImportKeyOptsKeyImporter 国密对应时去除
-ecdsaPKIXPublicKeyImportOptsKeyImporter 国密对应时去除
-ecdsaPrivateKeyImportOptsKeyImporter 国密对应时去除
-ecdsaGoPublicKeyImportOptsKeyImporter 国密对应时去除
+ecdsaPKIXPublicKeyImportOptsKeyImporter 国密对应时去除，混合模式下重新启用
+ecdsaPrivateKeyImportOptsKeyImporter 国密对应时去除，混合模式下重新启用
+ecdsaGoPublicKeyImportOptsKeyImporter 国密对应时去除，混合模式下重新启用
 x509PublicKeyImportOptsKeyImporter 国密对应时去除
 sm4ImportKeyOptsKeyImporter
 sm2PrivateKeyOptsKeyImporter
@@ -41,7 +43,7 @@ type gmx509PublicKeyImportOptsKeyImporter struct {
 // 从gmx509证书导入公钥
 // raw : *gmx509.Certificate
 // 支持公钥: *sm2.PublicKey, *ecdsa.PublicKey, *rsa.PublicKey
-// 国密改造后只支持sm2
+// 国密改造后只支持sm2，混合模式下追加支持ecdsa
 func (ki *gmx509PublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
 	cert, ok := raw.(*gmx509.Certificate)
 	if !ok {
@@ -58,8 +60,17 @@ func (ki *gmx509PublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts
 		return ki.bccsp.KeyImporters[reflect.TypeOf(&bccsp.SM2GoPublicKeyImportOpts{})].KeyImport(
 			pk,
 			&bccsp.SM2GoPublicKeyImportOpts{Temporary: opts.Ephemeral()})
+	case *ecdsa.PublicKey:
+		// 只有混合模式下才注册了ecdsa公钥导入器
+		importer, found := ki.bccsp.KeyImporters[reflect.TypeOf(&bccsp.ECDSAGoPublicKeyImportOpts{})]
+		if !found {
+			return nil, errors.New("certificate's public key type [ECDSA] is only supported in hybrid mode")
+		}
+		return importer.KeyImport(
+			pk,
+			&bccsp.ECDSAGoPublicKeyImportOpts{Temporary: opts.Ephemeral()})
 	default:
-		return nil, errors.New("certificate's public key type not recognized. Supported keys: [SM2]")
+		return nil, errors.New("certificate's public key type not recognized. Supported keys: [SM2, ECDSA]")
 	}
 }
 
@@ -147,3 +158,72 @@ func (*sm2GoPublicKeyOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyI
 
 	return &SM2PublicKey{lowLevelKey}, nil
 }
+
+// ecdsa私钥(PKCS#8标准 或 SEC1标准 的der字节流)导入器，仅在混合模式下使用
+type ecdsaPrivateKeyImportOptsKeyImporter struct{}
+
+// ecdsa私钥导入
+// raw : PKCS#8标准 或 SEC1标准 的der字节流
+func (*ecdsaPrivateKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	der, ok := raw.([]byte)
+	if !ok {
+		return nil, errors.New("invalid raw material, Expected byte array")
+	}
+
+	if len(der) == 0 {
+		return nil, errors.New("invalid raw material, It must botbe nil")
+	}
+
+	ecdsaSK, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		ecdsaSK, err = x509.ParseECPrivateKey(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed converting to ECDSA private key [%s]", err)
+		}
+	}
+	privEcdsa, ok := ecdsaSK.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key type is not *ecdsa.PrivateKey")
+	}
+	return &ECDSAPrivateKey{privEcdsa}, nil
+}
+
+// ecdsa公钥(PKIX标准的der字节流)导入器，仅在混合模式下使用
+type ecdsaPKIXPublicKeyImportOptsKeyImporter struct{}
+
+// ecdsa公钥导入
+// raw : PKIX标准的der字节流
+func (*ecdsaPKIXPublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	der, ok := raw.([]byte)
+	if !ok {
+		return nil, errors.New("invalid raw material, Expected byte array")
+	}
+
+	if len(der) == 0 {
+		return nil, errors.New("invalid raw material, It must botbe nil")
+	}
+
+	ecdsaPub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed converting PKIX to ECDSA public key [%s]", err)
+	}
+	pubEcdsa, ok := ecdsaPub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("key type is not *ecdsa.PublicKey")
+	}
+	return &ECDSAPublicKey{pubEcdsa}, nil
+}
+
+// ecdsa公钥(Go结构体*ecdsa.PublicKey)导入器，仅在混合模式下使用
+type ecdsaGoPublicKeyImportOptsKeyImporter struct{}
+
+// ecdsa公钥导入
+// raw : *ecdsa.PublicKey
+func (*ecdsaGoPublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	lowLevelKey, ok := raw.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("invalid raw material. Expected *ecdsa.PublicKey")
+	}
+
+	return &ECDSAPublicKey{lowLevelKey}, nil
+}
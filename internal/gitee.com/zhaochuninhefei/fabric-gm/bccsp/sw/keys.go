@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package sw
 
 import (
+	"crypto/ecdsa"
 	"crypto/rand"
+	"crypto/x509"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -19,10 +21,11 @@ import (
 /*
 bccsp/sw/keys.go 定义PKCS#8标准结构体与椭圆曲线私钥结构体，并提供它们之间相互转换的函数。
 但这些函数并未公开，只能在包内部调用。
+混合模式下追加支持ecdsa公私钥，ecdsa相关的编解码使用标准库x509。
 */
 
 // 将私钥转为der字节流
-//  - privateKey : *sm2.PrivateKey
+//  - privateKey : *sm2.PrivateKey 或 *ecdsa.PrivateKey
 func privateKeyToDER(privateKey interface{}) ([]byte, error) {
 	if privateKey == nil {
 		return nil, errors.New("invalid private key. It must be different from nil")
@@ -31,16 +34,16 @@ func privateKeyToDER(privateKey interface{}) ([]byte, error) {
 	switch k := privateKey.(type) {
 	case *sm2.PrivateKey:
 		return gmx509.MarshalECPrivateKey(k)
-	// case *ecdsa.PrivateKey:
-	// 	return x509.MarshalECPrivateKey(k)
+	case *ecdsa.PrivateKey:
+		return x509.MarshalECPrivateKey(k)
 	default:
-		return nil, errors.New("invalid key type. It must be *sm2.PrivateKey")
+		return nil, errors.New("invalid key type. It must be *sm2.PrivateKey or *ecdsa.PrivateKey")
 	}
 }
 
 // privateKeyToPEM converts the private key to PEM format.
 // EC private keys are converted to PKCS#8 format.
-// 国密对应后只支持sm2，将私钥转为pkcs8格式字节流，根据pwd是否为空决定是否加密，然后包装为pem字节流
+// 国密对应后只支持sm2，混合模式下追加支持ecdsa，将私钥转为pkcs8格式字节流，根据pwd是否为空决定是否加密，然后包装为pem字节流
 func privateKeyToPEM(privateKey interface{}, pwd []byte) ([]byte, error) {
 	// Validate inputs
 	if len(pwd) != 0 {
@@ -56,8 +59,17 @@ func privateKeyToPEM(privateKey interface{}, pwd []byte) ([]byte, error) {
 			return nil, errors.New("invalid sm2 private key. It must be different from nil")
 		}
 		return gmx509.WritePrivateKeyToPem(k, nil)
+	case *ecdsa.PrivateKey:
+		if k == nil {
+			return nil, errors.New("invalid ecdsa private key. It must be different from nil")
+		}
+		pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling ecdsa key to pkcs8: [%s]", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes}), nil
 	default:
-		return nil, errors.New("invalid key type. It must be *sm2.PrivateKey")
+		return nil, errors.New("invalid key type. It must be *sm2.PrivateKey or *ecdsa.PrivateKey")
 	}
 }
 
@@ -73,9 +85,42 @@ func privateKeyToEncryptedPEM(privateKey interface{}, pwd []byte) ([]byte, error
 		if k == nil {
 			return nil, errors.New("invalid sm2 private key. It must be different from nil")
 		}
-		return gmx509.WritePrivateKeyToPem(k, pwd)
+		// 与ecdsa私钥一致，使用带Proc-Type头的sm4加密pem，以便pemToPrivateKey解密
+		raw, err := privateKeyToDER(k)
+		if err != nil {
+			return nil, err
+		}
+		block, err := gmx509.EncryptPEMBlock(
+			rand.Reader,
+			"PRIVATE KEY",
+			raw,
+			pwd,
+			gmx509.PEMCipherSM4)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(block), nil
+	case *ecdsa.PrivateKey:
+		if k == nil {
+			return nil, errors.New("invalid ecdsa private key. It must be different from nil")
+		}
+		raw, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+		// 与sm2私钥一致，使用sm4加密pem
+		block, err := gmx509.EncryptPEMBlock(
+			rand.Reader,
+			"PRIVATE KEY",
+			raw,
+			pwd,
+			gmx509.PEMCipherSM4)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(block), nil
 	default:
-		return nil, errors.New("invalid key type. It must be *sm2.PrivateKey")
+		return nil, errors.New("invalid key type. It must be *sm2.PrivateKey or *ecdsa.PrivateKey")
 	}
 }
 
@@ -84,20 +129,32 @@ func privateKeyToEncryptedPEM(privateKey interface{}, pwd []byte) ([]byte, error
 func derToPrivateKey(der []byte) (key interface{}, err error) {
 	if key, err = gmx509.ParsePKCS8PrivateKey(der); err == nil {
 		switch key.(type) {
-		case *sm2.PrivateKey:
+		case *sm2.PrivateKey, *ecdsa.PrivateKey:
 			return
 		default:
-			return nil, errors.New("found private key type not sm2 in PKCS#8 wrapping")
+			return nil, errors.New("found private key type not sm2 or ecdsa in PKCS#8 wrapping")
 		}
 	}
 	if key, err = gmx509.ParseECPrivateKey(der); err == nil {
 		return
 	}
+	// 混合模式下的ecdsa私钥使用标准库x509解析
+	if key, err = x509.ParsePKCS8PrivateKey(der); err == nil {
+		switch key.(type) {
+		case *ecdsa.PrivateKey:
+			return
+		default:
+			return nil, errors.New("found private key type not sm2 or ecdsa in PKCS#8 wrapping")
+		}
+	}
+	if key, err = x509.ParseECPrivateKey(der); err == nil {
+		return
+	}
 	if key, err = gmx509.ParsePKCS1PrivateKey(der); err == nil {
 		return key, nil
 	}
 
-	return nil, errors.New("invalid key type. The DER must contain an sm2.PrivateKey")
+	return nil, errors.New("invalid key type. The DER must contain an sm2.PrivateKey or ecdsa.PrivateKey")
 }
 
 // 将der字节流转为sm2私钥
@@ -214,8 +271,8 @@ func sm4ToEncryptedPEM(raw []byte, pwd []byte) ([]byte, error) {
 }
 
 // publicKeyToPEM marshals a public key to the pem format. 将公钥转为pem字节流。
-// 对于sm2公钥，转为PKIX格式字节流并包装为pem字节流
-// 国密对应后只支持sm2
+// 对于sm2与ecdsa公钥，转为PKIX格式字节流并包装为pem字节流
+// 国密对应后只支持sm2，混合模式下追加支持ecdsa
 func publicKeyToPEM(publicKey interface{}, pwd []byte) ([]byte, error) {
 	if len(pwd) != 0 {
 		return publicKeyToEncryptedPEM(publicKey, pwd)
@@ -231,13 +288,22 @@ func publicKeyToPEM(publicKey interface{}, pwd []byte) ([]byte, error) {
 			return nil, errors.New("invalid sm2 public key. It must be different from nil")
 		}
 		return gmx509.WritePublicKeyToPem(k)
+	case *ecdsa.PublicKey:
+		if k == nil {
+			return nil, errors.New("invalid ecdsa public key. It must be different from nil")
+		}
+		pubASN1, err := x509.MarshalPKIXPublicKey(k)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubASN1}), nil
 	default:
-		return nil, errors.New("invalid key type. It must be *sm2.PublicKey")
+		return nil, errors.New("invalid key type. It must be *sm2.PublicKey or *ecdsa.PublicKey")
 	}
 }
 
 // publicKeyToDER marshals a public key to the der format. 将公钥转为der字节流
-// 国密对应后只支持sm2
+// 国密对应后只支持sm2，混合模式下追加支持ecdsa
 func publicKeyToDER(publicKey interface{}) ([]byte, error) {
 	if publicKey == nil {
 		return nil, errors.New("invalid public key. It must be different from nil")
@@ -249,14 +315,19 @@ func publicKeyToDER(publicKey interface{}) ([]byte, error) {
 			return nil, errors.New("invalid sm2 public key. It must be different from nil")
 		}
 		return gmx509.MarshalPKIXPublicKey(k)
+	case *ecdsa.PublicKey:
+		if k == nil {
+			return nil, errors.New("invalid ecdsa public key. It must be different from nil")
+		}
+		return x509.MarshalPKIXPublicKey(k)
 	default:
-		return nil, errors.New("invalid key type. It must be *sm2.PublicKey")
+		return nil, errors.New("invalid key type. It must be *sm2.PublicKey or *ecdsa.PublicKey")
 	}
 }
 
 // publicKeyToEncryptedPEM converts a public key to encrypted pem.将公钥转为加密pem字节流。
-// 对于sm2公钥，转为PKIX格式字节流并包装为pem字节流
-// 国密对应后只支持sm2
+// 对于sm2与ecdsa公钥，转为PKIX格式字节流并包装为pem字节流
+// 国密对应后只支持sm2，混合模式下追加支持ecdsa
 func publicKeyToEncryptedPEM(publicKey interface{}, pwd []byte) ([]byte, error) {
 	if publicKey == nil {
 		return nil, errors.New("invalid public key. It must be different from nil")
@@ -264,29 +335,39 @@ func publicKeyToEncryptedPEM(publicKey interface{}, pwd []byte) ([]byte, error)
 	if len(pwd) == 0 {
 		return nil, errors.New("invalid password. It must be different from nil")
 	}
+	var raw []byte
+	var blockType string
+	var err error
 	switch k := publicKey.(type) {
 	case *sm2.PublicKey:
 		if k == nil {
 			return nil, errors.New("invalid sm2 public key. It must be different from nil")
 		}
-		raw, err := gmx509.MarshalPKIXPublicKey(k)
-		if err != nil {
-			return nil, err
-		}
-		block, err := gmx509.EncryptPEMBlock(
-			rand.Reader,
-			"ENCRYPTED SM2 PUBLIC KEY",
-			raw,
-			pwd,
-			gmx509.PEMCipherSM4)
-
-		if err != nil {
-			return nil, err
+		raw, err = gmx509.MarshalPKIXPublicKey(k)
+		blockType = "ENCRYPTED SM2 PUBLIC KEY"
+	case *ecdsa.PublicKey:
+		if k == nil {
+			return nil, errors.New("invalid ecdsa public key. It must be different from nil")
 		}
-		return pem.EncodeToMemory(block), nil
+		raw, err = x509.MarshalPKIXPublicKey(k)
+		blockType = "ENCRYPTED PUBLIC KEY"
 	default:
-		return nil, errors.New("invalid key type. It must be *sm2.PublicKey")
+		return nil, errors.New("invalid key type. It must be *sm2.PublicKey or *ecdsa.PublicKey")
+	}
+	if err != nil {
+		return nil, err
 	}
+	block, err := gmx509.EncryptPEMBlock(
+		rand.Reader,
+		blockType,
+		raw,
+		pwd,
+		gmx509.PEMCipherSM4)
+
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(block), nil
 }
 
 // 将pem字节流转为公钥
@@ -339,12 +420,18 @@ func pemToSm2PublicKey(raw []byte, pwd []byte) (*sm2.PublicKey, error) {
 
 // 将der字节流转为公钥
 // 依次尝试 sm2, ecdsa, rsa
-// 国密对应后只支持sm2
+// 国密对应后只支持sm2，混合模式下追加支持ecdsa
 func derToPublicKey(raw []byte) (pub interface{}, err error) {
 	if len(raw) == 0 {
 		return nil, errors.New("invalid DER. It must be different from nil")
 	}
 	pubkey, err := gmx509.ParsePKIXPublicKey(raw)
+	if err != nil {
+		// 混合模式下的ecdsa公钥使用标准库x509解析
+		if ecdsaPub, ecdsaErr := x509.ParsePKIXPublicKey(raw); ecdsaErr == nil {
+			return ecdsaPub, nil
+		}
+	}
 	return pubkey, err
 }
 
@@ -19,7 +19,7 @@ bccsp/sw/new.go 提供创建`sw.CSP`(bccsp/sw/impl.go)的函数:
 NewDefaultSecurityLevel
 NewDefaultSecurityLevelWithKeystore
 NewWithParams
-默认hashFamily改为SM3
+默认hashFamily改为SM3，hashFamily为"SM3+SHA2"时启用混合模式
 */
 
 // NewDefaultSecurityLevel returns a new instance of the software-based BCCSP
@@ -106,5 +106,34 @@ func NewWithParams(usingGM bool, securityLevel int, hashFamily string, keyStore
 	swbccsp.AddWrapper(reflect.TypeOf(&SM4Key{}), &sm4Decryptor{})
 
 	swbccsp.Algorithms = "sm2-sm3-sm4"
+
+	// 混合模式下追加ecdsa(P-256)与SHA256相关组件，签名与验签的算法由密钥类型决定
+	if conf.hybrid {
+		addECDSAWrappers(swbccsp, conf)
+		swbccsp.Algorithms = "sm2-sm3-sm4+ecdsa-sha256"
+	}
 	return swbccsp, nil
 }
+
+// 添加混合模式下使用的ecdsa与SHA256相关组件
+func addECDSAWrappers(swbccsp *CSP, conf *config) {
+	// ecdsa密钥对构造器
+	swbccsp.AddWrapper(reflect.TypeOf(&bccsp.ECDSAP256KeyGenOpts{}), &ecdsaKeyGenerator{curve: conf.ecdsaCurve})
+
+	// ecdsa私钥导入器
+	swbccsp.AddWrapper(reflect.TypeOf(&bccsp.ECDSAPrivateKeyImportOpts{}), &ecdsaPrivateKeyImportOptsKeyImporter{})
+	// ecdsa公钥导入器
+	swbccsp.AddWrapper(reflect.TypeOf(&bccsp.ECDSAPKIXPublicKeyImportOpts{}), &ecdsaPKIXPublicKeyImportOptsKeyImporter{})
+	swbccsp.AddWrapper(reflect.TypeOf(&bccsp.ECDSAGoPublicKeyImportOpts{}), &ecdsaGoPublicKeyImportOptsKeyImporter{})
+
+	// sha256散列
+	swbccsp.AddWrapper(reflect.TypeOf(&bccsp.SHA256Opts{}), &hasher{hash: conf.sha2Function})
+
+	// ecdsa私钥签名
+	swbccsp.AddWrapper(reflect.TypeOf(&ECDSAPrivateKey{}), &ecdsaSigner{})
+
+	// ecdsa公钥验签
+	swbccsp.AddWrapper(reflect.TypeOf(&ECDSAPublicKey{}), &ecdsaPublicKeyKeyVerifier{})
+	// ecdsa私钥验签，实际还是公钥验签
+	swbccsp.AddWrapper(reflect.TypeOf(&ECDSAPrivateKey{}), &ecdsaPrivateKeyVerifier{})
+}
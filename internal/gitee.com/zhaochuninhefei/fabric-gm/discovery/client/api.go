@@ -56,6 +56,11 @@ type ChannelResponse interface {
 	// The given InvocationChain specifies the chaincode calls (along with collections)
 	// that the client passed during the construction of the request
 	Endorsers(invocationChain InvocationChain, f Filter) (Endorsers, error)
+
+	// EndorsementLayouts returns the layouts of the endorsement descriptor for the given
+	// invocation chain. Each layout maps a group to the number of endorsements required from
+	// the peers of that group; the peers of each group are returned along with the layouts.
+	EndorsementLayouts(invocationChain InvocationChain) ([]map[string]int, map[string][]*Peer, error)
 }
 
 // LocalResponse aggregates responses for a channel-less scope
@@ -210,6 +210,9 @@ func (id *identity) getHashOpt(hashFamily string) (core.HashOpts, error) {
 	switch hashFamily {
 	case bccsp.SM3:
 		return bccsp.GetHashOpt(bccsp.SM3)
+	case bccsp.SHA2:
+		// 混合模式下非国密组织的MSP使用SHA2
+		return bccsp.GetHashOpt(bccsp.SHA256)
 	}
 	return nil, errors.Errorf("hash familiy not recognized [%s]", hashFamily)
 }
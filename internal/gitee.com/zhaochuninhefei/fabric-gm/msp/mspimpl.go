@@ -212,6 +212,10 @@ func (msp *bccspmsp) getSigningIdentityFromConf(sidInfo *m.SigningIdentityInfo)
 		}
 		// TODO: 国密改造
 		privKey, err = msp.bccsp.KeyImport(pemKey.Bytes, factory.GetSM2PrivateKeyImportOpts(true))
+		if err != nil {
+			// 混合模式下尝试作为ecdsa私钥导入
+			privKey, err = msp.bccsp.KeyImport(pemKey.Bytes, factory.GetECDSAPrivateKeyImportOpts(true))
+		}
 		if err != nil {
 			return nil, errors.WithMessage(err, "getIdentityFromBytes error: Failed to import EC private key")
 		}
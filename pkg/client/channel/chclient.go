@@ -20,9 +20,10 @@ import (
 	"time"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/channel/invoke"
-	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/discovery/greylist"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/circuitbreaker"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/filter"
 	selectopts "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/options"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/sorter/healthsorter"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/retry"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
@@ -41,9 +42,15 @@ type Client struct {
 	context         context.Channel
 	membership      fab.ChannelMembership
 	eventService    fab.EventService
-	greylist        *greylist.Filter
+	breaker         *circuitbreaker.Breaker
 	metrics         *metrics.ClientMetrics
 	ChannelProvider context.ChannelProvider
+
+	identityValidator *identityValidatorRef
+	healthSorter      *healthsorter.Sorter
+	ordererSelector   fab.OrdererSelector
+	policyCheck       bool
+	policyProvider    invoke.EndorsementPolicyProvider
 }
 
 // ClientOption describes a functional parameter for the New constructor
@@ -57,7 +64,7 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		return nil, errors.WithMessage(err, "failed to create channel context")
 	}
 
-	greylistProvider := greylist.New(channelContext.EndpointConfig().Timeout(fab.DiscoveryGreylistExpiry))
+	breaker := circuitbreaker.New(circuitbreaker.WithOpenTimeout(channelContext.EndpointConfig().Timeout(fab.DiscoveryGreylistExpiry)))
 
 	if channelContext.ChannelService() == nil {
 		return nil, errors.New("channel service not initialized")
@@ -73,7 +80,7 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		return nil, errors.WithMessage(err, "membership creation failed")
 	}
 
-	channelClient := newClient(channelContext, membership, eventService, greylistProvider)
+	channelClient := newClient(channelContext, membership, eventService, breaker)
 
 	for _, param := range opts {
 		err := param(&channelClient)
@@ -167,8 +174,6 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 					requestContext.Opts.BeforeRetry(err)
 				}
 
-				cc.greylist.Greylist(err)
-
 				// Reset context parameters
 				requestContext.Opts.Targets = txnOpts.Targets
 				requestContext.Error = nil
@@ -208,7 +213,8 @@ func (cc *Client) createReqContext(txnOpts *requestOptions) (reqContext.Context,
 	}
 
 	reqCtx, cancel := contextImpl.NewRequest(cc.context, contextImpl.WithTimeout(txnOpts.Timeouts[fab.Execute]),
-		contextImpl.WithParent(txnOpts.ParentContext))
+		contextImpl.WithParent(txnOpts.ParentContext), contextImpl.WithCircuitBreaker(cc.breaker),
+		contextImpl.WithOrdererSelector(cc.ordererSelector))
 	//Add timeout overrides here as a value so that it can be used by immediate child contexts (in handlers/transactors)
 	reqCtx = reqContext.WithValue(reqCtx, contextImpl.ReqContextTimeoutOverrides, txnOpts.Timeouts)
 
@@ -238,7 +244,7 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 	}
 
 	peerFilter := func(peer fab.Peer) bool {
-		if !cc.greylist.Accept(peer) {
+		if !cc.breaker.Accept(peer) {
 			return false
 		}
 		if o.TargetFilter != nil && !o.TargetFilter.Accept(peer) {
@@ -252,14 +258,28 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 		peerSorter = func(peers []fab.Peer) []fab.Peer {
 			return o.TargetSorter.Sort(peers)
 		}
+	} else if cc.healthSorter != nil {
+		peerSorter = cc.healthSorter.PeerSorter()
 	}
 
 	clientContext := &invoke.ClientContext{
-		Selection:    selection,
-		Discovery:    discovery,
-		Membership:   cc.membership,
-		Transactor:   transactor,
-		EventService: cc.eventService,
+		Selection:           selection,
+		Discovery:           discovery,
+		Membership:          cc.membership,
+		Transactor:          transactor,
+		EventService:        cc.eventService,
+		EndorsementObserver: cc.breaker,
+	}
+
+	if cc.healthSorter != nil {
+		clientContext.EndorsementObserver = endorsementObservers{cc.breaker, cc.healthSorter}
+	}
+
+	if cc.identityValidator != nil {
+		clientContext.IdentityValidator, err = cc.identityValidator.get(cc.context)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	requestContext := &invoke.RequestContext{
@@ -18,6 +18,7 @@ import (
 	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
 	pb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/channel/invoke"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/circuitbreaker"
 	txnmocks "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/mocks"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/staticselection"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/retry"
@@ -496,11 +497,21 @@ func TestDiscoveryGreylist(t *testing.T) {
 	assert.True(t, ok, "expected status error")
 	assert.EqualValues(t, status.NoPeersFound.ToInt32(), s.Code, "expected No Peers Found status on greylist")
 	assert.Equal(t, 1, testPeer1.ProcessProposalCalls, "expected peer 1 to be greylisted")
-	// Wait for greylist expiry
+	assert.Equal(t, circuitbreaker.Open, chClient.breaker.State(testPeer1.URL()))
+
+	// Wait for the open timeout so that a probe is sent
 	time.Sleep(chClient.context.EndpointConfig().Timeout(fab.DiscoveryGreylistExpiry))
 	testPeer1.ProcessProposalCalls = 0
+	testPeer1.Error = nil
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
+		WithRetry(retryOpts))
+	assert.Nil(t, err, "expected probe to succeed")
+	assert.Equal(t, 1, testPeer1.ProcessProposalCalls)
+	assert.Equal(t, circuitbreaker.Closed, chClient.breaker.State(testPeer1.URL()))
+
+	// Endorsement errors open the circuit only once the failure threshold is reached
+	testPeer1.ProcessProposalCalls = 0
 	testPeer1.Error = status.New(status.EndorserServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "test", nil)
-	// Try again
 	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
 		WithRetry(retryOpts))
 	assert.NotNil(t, err, "expected error")
@@ -508,7 +519,47 @@ func TestDiscoveryGreylist(t *testing.T) {
 	assert.True(t, ok, "expected status error")
 	assert.EqualValues(t, int32(common.Status_SERVICE_UNAVAILABLE), s.Code, "expected configured mock error")
 	assert.Equal(t, attempts+1, testPeer1.ProcessProposalCalls, "expected peer 1 not to be greylisted")
+	assert.Equal(t, circuitbreaker.Closed, chClient.breaker.State(testPeer1.URL()))
+}
 
+func TestCircuitBreakerStateChanges(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Error = status.New(status.EndorserClientStatus,
+		status.ConnectionFailed.ToInt32(), "test", []interface{}{testPeer1.URL()})
+
+	discoveryService := txnmocks.NewMockDiscoveryService(nil, testPeer1)
+
+	selectionService, err := staticselection.NewService(discoveryService)
+	assert.Nil(t, err, "Got error %s", err)
+
+	fabCtx := setupCustomTestContext(t, selectionService, discoveryService, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	var events []*circuitbreaker.StateChangeEvent
+	breaker := circuitbreaker.New(
+		circuitbreaker.WithOpenTimeout(time.Millisecond*50),
+		circuitbreaker.WithStateChangeListener(func(evt *circuitbreaker.StateChangeEvent) {
+			events = append(events, evt)
+		}),
+	)
+	chClient, err := New(ctx, WithCircuitBreaker(breaker))
+	assert.Nil(t, err, "Got error %s", err)
+
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	_, err = chClient.Query(request)
+	assert.NotNil(t, err, "expected error")
+	assert.Equal(t, circuitbreaker.Open, breaker.State(testPeer1.URL()))
+
+	time.Sleep(time.Millisecond * 50)
+	testPeer1.Error = nil
+	_, err = chClient.Query(request)
+	assert.Nil(t, err, "expected probe to succeed")
+
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, circuitbreaker.Open, events[0].To)
+		assert.Equal(t, circuitbreaker.HalfOpen, events[1].To)
+		assert.Equal(t, circuitbreaker.Closed, events[2].To)
+	}
 }
 
 func setupTestChannelService(ctx context.Client, orderers []fab.Orderer) (fab.ChannelService, error) {
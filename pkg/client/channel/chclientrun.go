@@ -7,16 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
-	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/discovery/greylist"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/circuitbreaker"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
 )
 
-func newClient(channelContext context.Channel, membership fab.ChannelMembership, eventService fab.EventService, greylistProvider *greylist.Filter) Client {
+func newClient(channelContext context.Channel, membership fab.ChannelMembership, eventService fab.EventService, breaker *circuitbreaker.Breaker) Client {
 	channelClient := Client{
 		membership:   membership,
 		eventService: eventService,
-		greylist:     greylistProvider,
+		breaker:      breaker,
 		context:      channelContext,
 		metrics:      channelContext.GetMetrics(),
 	}
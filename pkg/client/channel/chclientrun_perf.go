@@ -51,7 +51,7 @@ func callExecute(cc *Client, request Request, options ...RequestOption) (Respons
 	}
 	cc.metrics.ExecutionsReceived.With(meterLabels...).Add(1)
 	startTime := time.Now()
-	r, err := cc.InvokeHandler(invoke.NewExecuteHandler(), request, options...)
+	r, err := cc.InvokeHandler(cc.executeHandler(), request, options...)
 	if err != nil {
 		if s, ok := err.(*status.Status); ok {
 			if s.Code == status.Timeout.ToInt32() {
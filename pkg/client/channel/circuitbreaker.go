@@ -0,0 +1,42 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/channel/invoke"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/circuitbreaker"
+	"github.com/pkg/errors"
+)
+
+// WithCircuitBreaker sets the circuit breaker that guards the requests sent by the client to
+// peers and orderers. By default each client has its own breaker whose open timeout is the
+// configured discovery greylist expiry; passing the same breaker to several clients (including
+// ledger and resmgmt clients) lets them share the state of the endpoints.
+func WithCircuitBreaker(breaker *circuitbreaker.Breaker) ClientOption {
+	return func(c *Client) error {
+		if breaker == nil {
+			return errors.New("circuit breaker is required")
+		}
+		c.breaker = breaker
+		return nil
+	}
+}
+
+// endorsementObservers notifies several observers of each proposal sent to an endorser
+type endorsementObservers []invoke.EndorsementObserver
+
+func (o endorsementObservers) Begin(peerURL string) func(err error) {
+	done := make([]func(err error), len(o))
+	for i, observer := range o {
+		done[i] = observer.Begin(peerURL)
+	}
+	return func(err error) {
+		for _, d := range done {
+			d(err)
+		}
+	}
+}
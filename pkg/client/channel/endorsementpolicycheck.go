@@ -0,0 +1,44 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/channel/invoke"
+)
+
+// WithEndorsementPolicyCheck enables checking that the endorsements of a transaction satisfy
+// the endorsement policy of the chaincode before the transaction is sent to the orderer, so that
+// a transaction that would be invalidated (ENDORSEMENT_POLICY_FAILURE) fails fast with status
+// EndorsementPolicyNotSatisfied instead. The policies are obtained from the given provider
+// (e.g. resmgmt.LifecyclePolicyProvider). If the provider is nil, the policies are derived from
+// the selection service if it supports it (Fabric selection), otherwise the check is skipped.
+// The check is also skipped for transactions that invoke other chaincodes, write private data or
+// set key-level endorsement policies, since they may be validated against other policies. Key-level
+// policies set by previous transactions cannot be detected, so the provider should return nil for
+// chaincodes that rely on them.
+func WithEndorsementPolicyCheck(provider invoke.EndorsementPolicyProvider) ClientOption {
+	return func(c *Client) error {
+		c.policyCheck = true
+		c.policyProvider = provider
+		return nil
+	}
+}
+
+// executeHandler returns the handler of Execute requests
+func (cc *Client) executeHandler() invoke.Handler {
+	if !cc.policyCheck {
+		return invoke.NewExecuteHandler()
+	}
+
+	return invoke.NewSelectAndEndorseHandler(
+		invoke.NewEndorsementValidationHandler(
+			invoke.NewSignatureValidationHandler(
+				invoke.NewEndorsementPolicyCheckHandler(cc.policyProvider, invoke.NewCommitHandler()),
+			),
+		),
+	)
+}
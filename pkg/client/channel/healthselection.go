@@ -0,0 +1,29 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/sorter/healthsorter"
+	"github.com/pkg/errors"
+)
+
+// WithHealthScoredSelection enables latency-aware and health-scored endorser selection. The
+// latency and outcome of every proposal sent by the client are recorded in the given sorter,
+// which is then used to sort the candidate endorsers of each request (unless a request specifies
+// its own TargetSorter) so that the fastest healthy peers that satisfy the endorsement policy
+// are chosen. As with the default block height priority sorter, endorsers that lag behind in block
+// height (see healthsorter.WithBlockHeightLagThreshold) are only chosen after the up-to-date ones.
+// The same sorter may be shared by several clients.
+func WithHealthScoredSelection(sorter *healthsorter.Sorter) ClientOption {
+	return func(c *Client) error {
+		if sorter == nil {
+			return errors.New("health sorter is required")
+		}
+		c.healthSorter = sorter
+		return nil
+	}
+}
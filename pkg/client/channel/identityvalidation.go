@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"sync"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/channel/invoke"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/verifier"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
+	"github.com/pkg/errors"
+)
+
+// WithIdentityValidation enables client-side validation of endorser identities against the
+// channel MSPs. Endorsements are rejected if the endorser's certificate has expired, does not
+// chain to the MSP's root certificates, appears in one of the MSP's revocation lists or does
+// not carry the peer role required by the MSP. Each failure results in a status error with a
+// distinct code (CertificateExpired, UntrustedCertificate, CertificateRevoked and IdentityRoleMismatch).
+func WithIdentityValidation() ClientOption {
+	return func(c *Client) error {
+		c.identityValidator = &identityValidatorRef{}
+		return nil
+	}
+}
+
+// identityValidatorRef lazily creates an identity verifier for the current channel config
+// and re-creates it whenever the channel config is updated
+type identityValidatorRef struct {
+	mutex    sync.Mutex
+	blockNum uint64
+	verifier *verifier.Identity
+}
+
+func (r *identityValidatorRef) get(ctx context.Channel) (invoke.IdentityValidator, error) {
+	chConfig, err := ctx.ChannelService().ChannelConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel config")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.verifier == nil || r.blockNum != chConfig.BlockNumber() {
+		v, err := verifier.NewIdentity(chConfig.MSPs())
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create identity verifier")
+		}
+		r.verifier = v
+		r.blockNum = chConfig.BlockNumber()
+	}
+
+	return r.verifier, nil
+}
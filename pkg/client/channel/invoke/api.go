@@ -11,6 +11,7 @@ import (
 	reqContext "context"
 	"time"
 
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
 	pb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
 	selectopts "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/options"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/retry"
@@ -68,6 +69,25 @@ type Handler interface {
 	Handle(context *RequestContext, clientContext *ClientContext)
 }
 
+//IdentityValidator validates the identity of an endorser against the channel MSPs
+type IdentityValidator interface {
+	VerifyEndorsement(response *fab.TransactionProposalResponse) error
+}
+
+//EndorsementObserver is notified of each proposal sent to an endorser, e.g. to track the endorser's health
+type EndorsementObserver interface {
+	// Begin is invoked before the proposal is sent to the peer. The returned function
+	// is invoked with the error returned by the peer (nil on success) once it has responded.
+	Begin(peerURL string) func(err error)
+}
+
+//EndorsementPolicyProvider provides the endorsement policy of a chaincode
+type EndorsementPolicyProvider interface {
+	// GetChaincodePolicy returns the signature policy of the given chaincode, or nil if the
+	// policy cannot be evaluated by the client (e.g. it is a channel config policy)
+	GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error)
+}
+
 //ClientContext contains context parameters for handler execution
 type ClientContext struct {
 	CryptoSuite  core.CryptoSuite
@@ -76,6 +96,12 @@ type ClientContext struct {
 	Membership   fab.ChannelMembership
 	Transactor   fab.Transactor
 	EventService fab.EventService
+	// IdentityValidator is optional. If set, endorser identities are validated
+	// before their signatures are verified.
+	IdentityValidator IdentityValidator
+	// EndorsementObserver is optional. If set, it is notified of the outcome of
+	// each proposal sent to an endorser.
+	EndorsementObserver EndorsementObserver
 }
 
 //RequestContext contains request, opts, response parameters for handler execution
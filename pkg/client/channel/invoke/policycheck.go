@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"fmt"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset/kvrwset"
+	pb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/endorsementpolicy"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+//NewEndorsementPolicyCheckHandler returns a handler that checks that the endorsements satisfy the endorsement
+//policy of the chaincode. If no provider is given, or if the provider cannot provide the policy of the chaincode
+//(e.g. it is a channel config policy or the query failed), the policy is provided by the selection service if it
+//implements EndorsementPolicyProvider (e.g. Fabric selection), otherwise the check is skipped.
+func NewEndorsementPolicyCheckHandler(provider EndorsementPolicyProvider, next ...Handler) *EndorsementPolicyCheckHandler {
+	return &EndorsementPolicyCheckHandler{provider: provider, next: getNext(next)}
+}
+
+//EndorsementPolicyCheckHandler checks the endorsements against the endorsement policy of the chaincode,
+//so that a transaction that would be invalidated by the committing peers is not sent to the orderer.
+//The check is skipped if the committing peers may validate the transaction against other policies, i.e.
+//if it invokes other chaincodes, writes private data (collection-level endorsement policies) or sets
+//key-level (state-based) endorsement policies. It is also skipped, with a warning, if the policy cannot be
+//obtained or evaluated; only endorsements that do not satisfy the policy fail the transaction.
+type EndorsementPolicyCheckHandler struct {
+	provider EndorsementPolicyProvider
+	next     Handler
+}
+
+//Handle checks the endorsements of the proposal responses
+func (h *EndorsementPolicyCheckHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	if err := h.check(requestContext, clientContext); err != nil {
+		requestContext.Error = errors.WithMessage(err, "endorsement policy check failed")
+		return
+	}
+
+	// Delegate to next step if any
+	if h.next != nil {
+		h.next.Handle(requestContext, clientContext)
+	}
+}
+
+func (h *EndorsementPolicyCheckHandler) check(requestContext *RequestContext, clientContext *ClientContext) error {
+	ccID := requestContext.Request.ChaincodeID
+	if reason, err := otherPolicies(requestContext.Request, requestContext.Response.Responses); err != nil {
+		logger.Warnf("Unable to determine the policies that apply to the transaction of chaincode [%s] - skipping endorsement policy check: %s", ccID, err)
+		return nil
+	} else if reason != "" {
+		logger.Debugf("Transaction of chaincode [%s] %s - skipping endorsement policy check", ccID, reason)
+		return nil
+	}
+
+	policy := h.getPolicy(ccID, clientContext)
+	if policy == nil {
+		logger.Debugf("Endorsement policy of chaincode [%s] cannot be evaluated - skipping endorsement policy check", ccID)
+		return nil
+	}
+
+	identities, err := endorsementpolicy.EndorserIdentities(requestContext.Response.Responses)
+	if err != nil {
+		logger.Warnf("Unable to get the endorser identities of chaincode [%s] - skipping endorsement policy check: %s", ccID, err)
+		return nil
+	}
+
+	satisfied, err := endorsementpolicy.Satisfied(policy, identities)
+	if err != nil {
+		logger.Warnf("Unable to evaluate endorsement policy of chaincode [%s] - skipping endorsement policy check: %s", ccID, err)
+		return nil
+	}
+	if !satisfied {
+		return status.New(status.EndorserClientStatus, status.EndorsementPolicyNotSatisfied.ToInt32(),
+			fmt.Sprintf("endorsements from %d endorser(s) do not satisfy the endorsement policy of chaincode [%s]", len(identities), ccID), nil)
+	}
+	return nil
+}
+
+// getPolicy returns the policy of the chaincode from the provider, falling back to the selection service.
+// Errors are logged and nil is returned if the policy cannot be obtained.
+func (h *EndorsementPolicyCheckHandler) getPolicy(ccID string, clientContext *ClientContext) *common.SignaturePolicyEnvelope {
+	if h.provider != nil {
+		policy, err := h.provider.GetChaincodePolicy(ccID)
+		if err != nil {
+			logger.Warnf("Unable to get endorsement policy of chaincode [%s] from provider: %s", ccID, err)
+		} else if policy != nil {
+			return policy
+		}
+	}
+
+	provider, ok := clientContext.Selection.(EndorsementPolicyProvider)
+	if !ok {
+		logger.Debugf("Selection service does not provide endorsement policies")
+		return nil
+	}
+	policy, err := provider.GetChaincodePolicy(ccID)
+	if err != nil {
+		logger.Warnf("Unable to get endorsement policy of chaincode [%s] from selection service: %s", ccID, err)
+		return nil
+	}
+	return policy
+}
+
+// otherPolicies returns why the transaction may be validated against other policies than the endorsement
+// policy of the invoked chaincode, or an empty string if only the chaincode policy applies. Key-level
+// policies that were set by previous transactions cannot be detected by the client.
+func otherPolicies(request Request, responses []*fab.TransactionProposalResponse) (string, error) {
+	for _, call := range request.InvocationChain {
+		if call.ID != request.ChaincodeID {
+			return fmt.Sprintf("invokes chaincode [%s]", call.ID), nil
+		}
+		if len(call.Collections) > 0 {
+			return "involves private data collections", nil
+		}
+	}
+
+	if len(responses) == 0 {
+		return "", nil
+	}
+
+	// The endorsement validation handler has already checked that all the responses are the same
+	txRWSet, err := txReadWriteSet(responses[0].ProposalResponse)
+	if err != nil {
+		return "", err
+	}
+	for _, nsRWSet := range txRWSet.NsRwset {
+		kvRWSet := &kvrwset.KVRWSet{}
+		if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+			return "", errors.Wrapf(err, "failed to unmarshal read-write set of namespace [%s]", nsRWSet.Namespace)
+		}
+		if len(kvRWSet.MetadataWrites) > 0 {
+			return "sets key-level endorsement policies", nil
+		}
+		for _, collRWSet := range nsRWSet.CollectionHashedRwset {
+			hashedRWSet := &kvrwset.HashedRWSet{}
+			if err := proto.Unmarshal(collRWSet.HashedRwset, hashedRWSet); err != nil {
+				return "", errors.Wrapf(err, "failed to unmarshal hashed read-write set of collection [%s]", collRWSet.CollectionName)
+			}
+			if len(hashedRWSet.HashedWrites) > 0 || len(hashedRWSet.MetadataWrites) > 0 {
+				return fmt.Sprintf("writes to private data collection [%s]", collRWSet.CollectionName), nil
+			}
+		}
+		if nsRWSet.Namespace != request.ChaincodeID && len(kvRWSet.Writes) > 0 {
+			return fmt.Sprintf("writes to namespace [%s]", nsRWSet.Namespace), nil
+		}
+	}
+	return "", nil
+}
+
+func txReadWriteSet(response *pb.ProposalResponse) (*rwset.TxReadWriteSet, error) {
+	responsePayload := &pb.ProposalResponsePayload{}
+	if err := proto.Unmarshal(response.GetPayload(), responsePayload); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize proposal response payload")
+	}
+	chaincodeAction := &pb.ChaincodeAction{}
+	if err := proto.Unmarshal(responsePayload.GetExtension(), chaincodeAction); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize chaincode action")
+	}
+	txRWSet := &rwset.TxReadWriteSet{}
+	if err := proto.Unmarshal(chaincodeAction.GetResults(), txRWSet); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize read-write set")
+	}
+	return txRWSet, nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset/kvrwset"
+	mb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	pb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/policydsl"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type policyProviderFunc func(chaincodeID string) (*common.SignaturePolicyEnvelope, error)
+
+func (f policyProviderFunc) GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
+	return f(chaincodeID)
+}
+
+// policySelectionService is a selection service that provides endorsement policies
+type policySelectionService struct {
+	fab.SelectionService
+	policyProviderFunc
+}
+
+type mockNextHandler struct {
+	invoked bool
+}
+
+func (h *mockNextHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	h.invoked = true
+}
+
+func newEndorsedResponse(t *testing.T, endorser, mspID string) *fab.TransactionProposalResponse {
+	identity, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(endorser)})
+	require.NoError(t, err)
+	return &fab.TransactionProposalResponse{
+		Endorser: endorser,
+		ProposalResponse: &pb.ProposalResponse{
+			Response:    &pb.Response{Status: 200},
+			Endorsement: &pb.Endorsement{Endorser: identity},
+		},
+	}
+}
+
+// withRWSet returns a copy of the response whose payload contains the given read-write sets
+func withRWSet(t *testing.T, response *fab.TransactionProposalResponse, nsRWSets ...*rwset.NsReadWriteSet) *fab.TransactionProposalResponse {
+	results, err := proto.Marshal(&rwset.TxReadWriteSet{DataModel: rwset.TxReadWriteSet_KV, NsRwset: nsRWSets})
+	require.NoError(t, err)
+	extension, err := proto.Marshal(&pb.ChaincodeAction{Results: results})
+	require.NoError(t, err)
+	payload, err := proto.Marshal(&pb.ProposalResponsePayload{Extension: extension})
+	require.NoError(t, err)
+
+	proposalResponse := proto.Clone(response.ProposalResponse).(*pb.ProposalResponse)
+	proposalResponse.Payload = payload
+	return &fab.TransactionProposalResponse{Endorser: response.Endorser, ProposalResponse: proposalResponse}
+}
+
+func nsRWSet(t *testing.T, namespace string, kvRWSet *kvrwset.KVRWSet, collections ...*rwset.CollectionHashedReadWriteSet) *rwset.NsReadWriteSet {
+	kvBytes, err := proto.Marshal(kvRWSet)
+	require.NoError(t, err)
+	return &rwset.NsReadWriteSet{Namespace: namespace, Rwset: kvBytes, CollectionHashedRwset: collections}
+}
+
+func TestEndorsementPolicyCheckHandler(t *testing.T) {
+	policy := policydsl.SignedByNOutOfGivenRole(2, mb.MSPRole_MEMBER, []string{"Org1MSP", "Org2MSP"})
+	provider := policyProviderFunc(func(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
+		switch chaincodeID {
+		case "cc1":
+			return policy, nil
+		case "cc2":
+			return nil, nil
+		default:
+			return nil, errors.New("injected policy error")
+		}
+	})
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	org1Response := newEndorsedResponse(t, "peer1.org1", "Org1MSP")
+	org1Response2 := newEndorsedResponse(t, "peer2.org1", "Org1MSP")
+	org2Response := newEndorsedResponse(t, "peer1.org2", "Org2MSP")
+
+	check := func(provider EndorsementPolicyProvider, ccID string, responses ...*fab.TransactionProposalResponse) (*RequestContext, bool) {
+		next := &mockNextHandler{}
+		requestContext := prepareRequestContext(Request{ChaincodeID: ccID}, Opts{}, t)
+		requestContext.Response.Responses = responses
+		NewEndorsementPolicyCheckHandler(provider, next).Handle(requestContext, clientContext)
+		return requestContext, next.invoked
+	}
+
+	t.Run("Satisfied", func(t *testing.T) {
+		requestContext, invoked := check(provider, "cc1", org1Response, org2Response)
+		require.NoError(t, requestContext.Error)
+		assert.True(t, invoked)
+	})
+
+	t.Run("Not satisfied", func(t *testing.T) {
+		requestContext, invoked := check(provider, "cc1", org1Response, org1Response2)
+		require.Error(t, requestContext.Error)
+		assert.False(t, invoked, "transaction must not be committed")
+		s, ok := status.FromError(requestContext.Error)
+		require.True(t, ok)
+		assert.Equal(t, status.EndorserClientStatus, s.Group)
+		assert.EqualValues(t, status.EndorsementPolicyNotSatisfied, s.Code)
+	})
+
+	t.Run("Duplicate endorsements", func(t *testing.T) {
+		requestContext, invoked := check(provider, "cc1", org1Response, org1Response)
+		require.Error(t, requestContext.Error)
+		assert.False(t, invoked)
+	})
+
+	t.Run("No policy", func(t *testing.T) {
+		requestContext, invoked := check(provider, "cc2", org1Response)
+		require.NoError(t, requestContext.Error)
+		assert.True(t, invoked)
+	})
+
+	t.Run("Policy error", func(t *testing.T) {
+		requestContext, invoked := check(provider, "cc3", org1Response)
+		require.NoError(t, requestContext.Error, "expecting the check to be skipped if the policy cannot be obtained")
+		assert.True(t, invoked)
+	})
+
+	t.Run("Writes to chaincode namespace", func(t *testing.T) {
+		writes := nsRWSet(t, "cc1", &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key1", Value: []byte("value1")}}})
+		requestContext, invoked := check(provider, "cc1", withRWSet(t, org1Response, writes), withRWSet(t, org1Response2, writes))
+		require.Error(t, requestContext.Error, "expecting the chaincode policy to be checked")
+		assert.False(t, invoked)
+	})
+
+	t.Run("Other policies", func(t *testing.T) {
+		hashedWrites, err := proto.Marshal(&kvrwset.HashedRWSet{HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("keyhash")}}})
+		require.NoError(t, err)
+
+		for name, nsRWSets := range map[string][]*rwset.NsReadWriteSet{
+			"state-based endorsement": {nsRWSet(t, "cc1", &kvrwset.KVRWSet{MetadataWrites: []*kvrwset.KVMetadataWrite{{Key: "key1"}}})},
+			"private data":            {nsRWSet(t, "cc1", &kvrwset.KVRWSet{}, &rwset.CollectionHashedReadWriteSet{CollectionName: "coll1", HashedRwset: hashedWrites})},
+			"cc-to-cc writes": {
+				nsRWSet(t, "cc1", &kvrwset.KVRWSet{}),
+				nsRWSet(t, "cc4", &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key1", Value: []byte("value1")}}}),
+			},
+		} {
+			requestContext, invoked := check(provider, "cc1", withRWSet(t, org1Response, nsRWSets...), withRWSet(t, org1Response2, nsRWSets...))
+			require.NoError(t, requestContext.Error, "expecting the check to be skipped for %s", name)
+			assert.True(t, invoked)
+		}
+
+		for _, chain := range [][]*fab.ChaincodeCall{
+			{{ID: "cc1"}, {ID: "cc4"}},
+			{{ID: "cc1", Collections: []string{"coll1"}}},
+		} {
+			next := &mockNextHandler{}
+			requestContext := prepareRequestContext(Request{ChaincodeID: "cc1", InvocationChain: chain}, Opts{}, t)
+			requestContext.Response.Responses = []*fab.TransactionProposalResponse{org1Response, org1Response2}
+			NewEndorsementPolicyCheckHandler(provider, next).Handle(requestContext, clientContext)
+			require.NoError(t, requestContext.Error, "expecting the check to be skipped for the invocation chain")
+			assert.True(t, next.invoked)
+		}
+	})
+
+	t.Run("Fallback to selection service", func(t *testing.T) {
+		fallbackContext := *clientContext
+		fallbackContext.Selection = &policySelectionService{
+			SelectionService: clientContext.Selection,
+			policyProviderFunc: func(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
+				return policy, nil
+			},
+		}
+
+		next := &mockNextHandler{}
+		requestContext := prepareRequestContext(Request{ChaincodeID: "cc2"}, Opts{}, t)
+		requestContext.Response.Responses = []*fab.TransactionProposalResponse{org1Response, org1Response2}
+		NewEndorsementPolicyCheckHandler(provider, next).Handle(requestContext, &fallbackContext)
+		require.Error(t, requestContext.Error, "expecting the policy derived by the selection service to be checked")
+		assert.False(t, next.invoked)
+
+		next = &mockNextHandler{}
+		requestContext = prepareRequestContext(Request{ChaincodeID: "cc3"}, Opts{}, t)
+		requestContext.Response.Responses = []*fab.TransactionProposalResponse{org1Response, org1Response2}
+		NewEndorsementPolicyCheckHandler(provider, next).Handle(requestContext, &fallbackContext)
+		require.Error(t, requestContext.Error, "expecting the selection service to provide the policy if the provider fails")
+		assert.False(t, next.invoked)
+	})
+
+	t.Run("No provider", func(t *testing.T) {
+		requestContext, invoked := check(nil, "cc1", org1Response)
+		require.NoError(t, requestContext.Error, "expecting the check to be skipped if the selection service does not provide policies")
+		assert.True(t, invoked)
+	})
+}
@@ -49,6 +49,12 @@ func (f *SignatureValidationHandler) validate(txProposalResponse []*fab.Transact
 }
 
 func verifyProposalResponse(res *fab.TransactionProposalResponse, ctx *ClientContext) error {
+	if ctx.IdentityValidator != nil && res.ProposalResponse.GetEndorsement() != nil {
+		if err := ctx.IdentityValidator.VerifyEndorsement(res); err != nil {
+			return err
+		}
+	}
+
 	sv := &verifier.Signature{Membership: ctx.Membership}
 	return sv.Verify(res)
 }
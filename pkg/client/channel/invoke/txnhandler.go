@@ -8,6 +8,7 @@ package invoke
 
 import (
 	"bytes"
+	reqContext "context"
 	"strings"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
@@ -50,7 +51,7 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(
 		clientContext.Transactor,
 		&requestContext.Request,
-		peer.PeersToTxnProcessors(requestContext.Opts.Targets),
+		peer.PeersToTxnProcessors(observedPeers(requestContext.Opts.Targets, clientContext.EndorsementObserver)),
 		TxnHeaderOpts...,
 	)
 
@@ -80,6 +81,32 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 	}
 }
 
+// observedPeers wraps the peers so that the observer is notified of each proposal sent to them
+func observedPeers(peers []fab.Peer, observer EndorsementObserver) []fab.Peer {
+	if observer == nil {
+		return peers
+	}
+	observed := make([]fab.Peer, len(peers))
+	for i, p := range peers {
+		observed[i] = &observedPeer{Peer: p, observer: observer}
+	}
+	return observed
+}
+
+// observedPeer is a peer that notifies an EndorsementObserver of the proposals it processes
+type observedPeer struct {
+	fab.Peer
+	observer EndorsementObserver
+}
+
+// ProcessTransactionProposal sends the proposal to the peer and notifies the observer of the outcome
+func (p *observedPeer) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	done := p.observer.Begin(p.URL())
+	response, err := p.Peer.ProcessTransactionProposal(ctx, request)
+	done(err)
+	return response, err
+}
+
 func getResultFromProposalResponse(proposalResponse *pb.ProposalResponse) ([]byte, error) {
 	responsePayload := &pb.ProposalResponsePayload{}
 	if err := proto.Unmarshal(proposalResponse.GetPayload(), responsePayload); err != nil {
@@ -10,6 +10,7 @@ import (
 	reqContext "context"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -231,6 +232,25 @@ func TestEndorsementHandler(t *testing.T) {
 		require.EqualError(t, requestContext.Error, errExpected.Error())
 	})
 
+	t.Run("notifies the endorsement observer", func(t *testing.T) {
+		clientContext := setupChannelClientContext(nil, nil, nil, t)
+		observer := &mockEndorsementObserver{results: make(map[string]error)}
+		clientContext.EndorsementObserver = observer
+
+		mockPeer1 := fcmocks.NewMockPeer("p1", "peer1.com")
+		mockPeer2 := fcmocks.NewMockPeer("p2", "peer2.com")
+		mockPeer2.Error = status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+		requestContext := prepareRequestContext(request, Opts{Targets: []fab.Peer{mockPeer1, mockPeer2}}, t)
+		handler := NewEndorsementHandler()
+
+		handler.Handle(requestContext, clientContext)
+
+		require.Error(t, requestContext.Error)
+		require.Len(t, observer.results, 2)
+		require.NoError(t, observer.results["peer1.com"])
+		require.Error(t, observer.results["peer2.com"])
+	})
+
 	t.Run("returns error deserializing proposal response payload", func(t *testing.T) {
 		clientContext := setupChannelClientContext(nil, nil, nil, t)
 		mockPeer := fcmocks.NewMockPeer("p2", "")
@@ -538,3 +558,16 @@ func setupTestContext() context.Client {
 	ctx := fcmocks.NewMockContext(user)
 	return ctx
 }
+
+type mockEndorsementObserver struct {
+	mutex   sync.Mutex
+	results map[string]error
+}
+
+func (o *mockEndorsementObserver) Begin(peerURL string) func(err error) {
+	return func(err error) {
+		o.mutex.Lock()
+		defer o.mutex.Unlock()
+		o.results[peerURL] = err
+	}
+}
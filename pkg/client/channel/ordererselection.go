@@ -0,0 +1,25 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// WithOrdererSelection sets the strategy that determines the order in which the orderers are tried
+// when the client broadcasts a transaction (see package ordererselection for random, round-robin,
+// prefer-own-org and latency-based strategies). By default the orderers are tried in a random order.
+func WithOrdererSelection(selector fab.OrdererSelector) ClientOption {
+	return func(c *Client) error {
+		if selector == nil {
+			return errors.New("orderer selector is required")
+		}
+		c.ordererSelector = selector
+		return nil
+	}
+}
@@ -0,0 +1,474 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package circuitbreaker tracks the outcome of the requests sent to peers and orderers and
+// stops sending requests to endpoints that keep failing until they have had time to recover.
+//
+// Each endpoint has a circuit that is closed (requests are sent), open (requests are rejected)
+// or half-open (a limited number of probe requests are sent to find out whether the endpoint
+// has recovered). A circuit opens after a number of consecutive failures, moves to half-open
+// once the open timeout has elapsed and closes again when the probe requests succeed.
+package circuitbreaker
+
+import (
+	reqContext "context"
+	"sync"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/metrics"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/metrics/disabled"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/logging"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/config/endpoint"
+	sdkmetrics "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fabsdk/metrics"
+	grpcCodes "gitee.com/zhaochuninhefei/gmgo/grpc/codes"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const (
+	defaultFailureThreshold           = 5
+	defaultConnectionFailureThreshold = 1
+	defaultOpenTimeout                = 30 * time.Second
+	defaultHalfOpenProbes             = 1
+)
+
+// State is the state of the circuit of an endpoint
+type State int
+
+const (
+	// Closed means that requests are sent to the endpoint
+	Closed State = iota
+	// Open means that requests to the endpoint are rejected
+	Open
+	// HalfOpen means that a limited number of probe requests are sent to the endpoint
+	HalfOpen
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// StateChangeEvent is emitted each time the circuit of an endpoint changes state
+type StateChangeEvent struct {
+	// URL is the address of the endpoint
+	URL string
+	// From is the previous state of the circuit
+	From State
+	// To is the new state of the circuit
+	To State
+	// Failures is the number of consecutive failures recorded for the endpoint
+	Failures int
+	// Err is the failure that opened the circuit (nil for other state changes)
+	Err error
+	// Time is when the state changed
+	Time time.Time
+}
+
+// Option describes a functional parameter for New
+type Option func(*options)
+
+type options struct {
+	failureThreshold           int
+	connectionFailureThreshold int
+	openTimeout                time.Duration
+	halfOpenProbes             int
+	isFailure                  func(err error) bool
+	listeners                  []func(*StateChangeEvent)
+	metricsProvider            metrics.Provider
+}
+
+// WithFailureThreshold sets the number of consecutive failures (e.g. timeouts and endorsement
+// errors) after which the circuit of an endpoint is opened (default 5)
+func WithFailureThreshold(value int) Option {
+	return func(o *options) {
+		o.failureThreshold = value
+	}
+}
+
+// WithConnectionFailureThreshold sets the number of consecutive failures after which the circuit
+// of an endpoint is opened when the last failure is a connection failure (default 1, i.e. requests
+// are no longer sent to an endpoint as soon as it cannot be reached).
+func WithConnectionFailureThreshold(value int) Option {
+	return func(o *options) {
+		o.connectionFailureThreshold = value
+	}
+}
+
+// WithOpenTimeout sets how long the circuit of an endpoint stays open before probe requests
+// are sent to the endpoint (default 30s)
+func WithOpenTimeout(value time.Duration) Option {
+	return func(o *options) {
+		o.openTimeout = value
+	}
+}
+
+// WithHalfOpenProbes sets the number of probe requests that are sent to an endpoint whose
+// circuit is half-open. The circuit is closed once all of them have succeeded (default 1).
+func WithHalfOpenProbes(value int) Option {
+	return func(o *options) {
+		o.halfOpenProbes = value
+	}
+}
+
+// WithFailurePredicate sets the function that decides whether the error returned by an endpoint
+// counts as a failure. By default all errors count except the errors returned by chaincode.
+func WithFailurePredicate(value func(err error) bool) Option {
+	return func(o *options) {
+		o.isFailure = value
+	}
+}
+
+// WithStateChangeListener registers a callback that is invoked each time the circuit of an endpoint
+// changes state. The callback is invoked synchronously and must not block.
+func WithStateChangeListener(listener func(*StateChangeEvent)) Option {
+	return func(o *options) {
+		o.listeners = append(o.listeners, listener)
+	}
+}
+
+// WithMetricsProvider sets the provider used to create the circuit breaker metrics
+func WithMetricsProvider(p metrics.Provider) Option {
+	return func(o *options) {
+		o.metricsProvider = p
+	}
+}
+
+type circuit struct {
+	state    State
+	failures int
+	openedAt time.Time
+	// probes is the number of probe requests allowed since the circuit became half-open
+	probes int
+	// probeSuccesses is the number of probe requests that succeeded
+	probeSuccesses int
+	lastProbe      time.Time
+}
+
+// Breaker keeps a circuit per endpoint URL. A Breaker may be shared by several clients
+// (e.g. channel, ledger and resource management clients) so that they all avoid the same
+// failing endpoints.
+//
+// Breaker implements fab.CircuitBreaker and fab.TargetFilter.
+type Breaker struct {
+	opts     *options
+	metrics  *sdkmetrics.CircuitBreakerMetrics
+	mutex    sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+// New returns a new circuit breaker
+func New(opts ...Option) *Breaker {
+	o := &options{
+		failureThreshold:           defaultFailureThreshold,
+		connectionFailureThreshold: defaultConnectionFailureThreshold,
+		openTimeout:                defaultOpenTimeout,
+		halfOpenProbes:             defaultHalfOpenProbes,
+		isFailure:                  IsFailure,
+		metricsProvider:            &disabled.Provider{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.failureThreshold < 1 {
+		o.failureThreshold = 1
+	}
+	if o.connectionFailureThreshold < 1 {
+		o.connectionFailureThreshold = 1
+	}
+	if o.halfOpenProbes < 1 {
+		o.halfOpenProbes = 1
+	}
+
+	return &Breaker{
+		opts:     o,
+		metrics:  sdkmetrics.NewCircuitBreakerMetrics(o.metricsProvider),
+		circuits: make(map[string]*circuit),
+		now:      time.Now,
+	}
+}
+
+// Allow returns true if a request may be sent to the endpoint with the given URL. If the circuit
+// is open and the open timeout has elapsed, the circuit becomes half-open and a probe is allowed.
+// Allow takes up a probe slot, so it must only be called right before the request is sent; use
+// Accept to filter candidate endpoints.
+func (b *Breaker) Allow(url string) bool {
+	address := endpoint.ToAddress(url)
+
+	b.mutex.Lock()
+	allowed, evt := b.allow(address)
+	b.mutex.Unlock()
+
+	b.publish(evt)
+
+	if !allowed {
+		b.rejected(url, address)
+	}
+	return allowed
+}
+
+// Accept returns whether or not to accept a peer as a candidate for endorsement. Unlike Allow,
+// Accept does not change the circuit of the peer: the probe slot of a half-open circuit is only
+// taken when a proposal is actually sent to the peer (see Begin).
+func (b *Breaker) Accept(peer fab.Peer) bool {
+	address := endpoint.ToAddress(peer.URL())
+
+	b.mutex.Lock()
+	available := b.available(address)
+	b.mutex.Unlock()
+
+	if !available {
+		b.rejected(peer.URL(), address)
+	}
+	return available
+}
+
+// Record records the outcome of a request sent to the endpoint with the given URL
+func (b *Breaker) Record(url string, err error) {
+	address := endpoint.ToAddress(url)
+	failed := b.opts.isFailure(err)
+
+	b.mutex.Lock()
+	evt := b.record(address, failed, err)
+	b.mutex.Unlock()
+
+	if failed {
+		b.metrics.Failures.With("endpoint", address).Add(1)
+	}
+	b.publish(evt)
+}
+
+// Begin is invoked right before a proposal is sent to the peer with the given URL and takes up a
+// probe slot if the circuit of the peer is half-open. The returned function records the outcome,
+// so that the breaker can be used as an endorsement observer.
+func (b *Breaker) Begin(url string) func(err error) {
+	address := endpoint.ToAddress(url)
+
+	b.mutex.Lock()
+	_, evt := b.allow(address)
+	b.mutex.Unlock()
+
+	b.publish(evt)
+
+	return func(err error) {
+		b.Record(url, err)
+	}
+}
+
+// State returns the state of the circuit of the endpoint with the given URL
+func (b *Breaker) State(url string) State {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, ok := b.circuits[endpoint.ToAddress(url)]
+	if !ok {
+		return Closed
+	}
+	if c.state == Open && !b.now().Before(c.openedAt.Add(b.opts.openTimeout)) {
+		return HalfOpen
+	}
+	return c.state
+}
+
+// Peers wraps the given peers so that the outcome of each proposal sent to them is recorded
+func (b *Breaker) Peers(peers []fab.Peer) []fab.Peer {
+	wrapped := make([]fab.Peer, len(peers))
+	for i, p := range peers {
+		wrapped[i] = &peer{Peer: p, breaker: b}
+	}
+	return wrapped
+}
+
+// available returns true if a request may be sent to the endpoint, without changing its circuit
+func (b *Breaker) available(address string) bool {
+	c, ok := b.circuits[address]
+	if !ok {
+		return true
+	}
+
+	now := b.now()
+	switch c.state {
+	case Closed:
+		return true
+	case Open:
+		// The circuit becomes half-open (with all probe slots free) once the open timeout has elapsed
+		return !now.Before(c.openedAt.Add(b.opts.openTimeout))
+	}
+	return c.probes < b.opts.halfOpenProbes || !now.Before(c.lastProbe.Add(b.opts.openTimeout))
+}
+
+func (b *Breaker) rejected(url, address string) {
+	logger.Debugf("Rejecting endpoint %s since its circuit is open", url)
+	b.metrics.Rejections.With("endpoint", address).Add(1)
+}
+
+func (b *Breaker) allow(address string) (bool, *StateChangeEvent) {
+	c, ok := b.circuits[address]
+	if !ok {
+		return true, nil
+	}
+
+	now := b.now()
+	var evt *StateChangeEvent
+	switch c.state {
+	case Closed:
+		return true, nil
+	case Open:
+		if now.Before(c.openedAt.Add(b.opts.openTimeout)) {
+			return false, nil
+		}
+		evt = b.transition(address, c, HalfOpen, nil)
+		c.probes = 0
+		c.probeSuccesses = 0
+	}
+
+	// A probe that was sent but whose outcome was never recorded must not block the circuit forever
+	if c.probes < b.opts.halfOpenProbes || !now.Before(c.lastProbe.Add(b.opts.openTimeout)) {
+		c.probes++
+		c.lastProbe = now
+		logger.Debugf("Allowing probe %d to endpoint %s", c.probes, address)
+		return true, evt
+	}
+	return false, evt
+}
+
+func (b *Breaker) record(address string, failed bool, err error) *StateChangeEvent {
+	c, ok := b.circuits[address]
+	if !ok {
+		if !failed {
+			return nil
+		}
+		c = &circuit{}
+		b.circuits[address] = c
+	}
+
+	if !failed {
+		switch c.state {
+		case Closed:
+			c.failures = 0
+		case HalfOpen:
+			c.probeSuccesses++
+			if c.probeSuccesses >= b.opts.halfOpenProbes {
+				c.failures = 0
+				return b.transition(address, c, Closed, nil)
+			}
+		}
+		return nil
+	}
+
+	c.failures++
+	switch c.state {
+	case Closed:
+		threshold := b.opts.failureThreshold
+		if IsConnectionFailure(err) {
+			threshold = b.opts.connectionFailureThreshold
+		}
+		if c.failures >= threshold {
+			c.openedAt = b.now()
+			return b.transition(address, c, Open, err)
+		}
+	case HalfOpen:
+		c.openedAt = b.now()
+		return b.transition(address, c, Open, err)
+	case Open:
+		// A request that was sent before the circuit opened has failed
+		c.openedAt = b.now()
+	}
+	return nil
+}
+
+func (b *Breaker) transition(address string, c *circuit, to State, err error) *StateChangeEvent {
+	evt := &StateChangeEvent{
+		URL:      address,
+		From:     c.state,
+		To:       to,
+		Failures: c.failures,
+		Err:      err,
+		Time:     b.now(),
+	}
+	c.state = to
+
+	if to == Open {
+		logger.Warnf("Opening circuit of endpoint %s after %d consecutive failures: %s", address, c.failures, err)
+	} else {
+		logger.Infof("Circuit of endpoint %s changed from %s to %s", address, evt.From, to)
+	}
+
+	b.metrics.StateChanges.With("endpoint", address, "state", to.String()).Add(1)
+	b.metrics.State.With("endpoint", address).Set(float64(to))
+
+	return evt
+}
+
+func (b *Breaker) publish(evt *StateChangeEvent) {
+	if evt == nil {
+		return
+	}
+	for _, listener := range b.opts.listeners {
+		listener(evt)
+	}
+}
+
+// IsFailure returns true if the error returned by an endpoint indicates that the endpoint is not
+// healthy. Connection failures, timeouts and errors returned by the peer or orderer count as
+// failures; errors returned by chaincode mean that the endpoint processed the request and do not.
+func IsFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+	if s.Group == status.ChaincodeStatus {
+		return false
+	}
+	if s.Group == status.EndorserClientStatus && s.Code == status.ChaincodeNameNotFound.ToInt32() {
+		return false
+	}
+	return true
+}
+
+// IsConnectionFailure returns true if the error indicates that the endpoint could not be reached
+func IsConnectionFailure(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch s.Group {
+	case status.EndorserClientStatus, status.OrdererClientStatus:
+		return s.Code == status.ConnectionFailed.ToInt32()
+	case status.GRPCTransportStatus:
+		return s.Code == int32(grpcCodes.Unavailable)
+	}
+	return false
+}
+
+// peer records the outcome of the proposals it processes with the circuit breaker
+type peer struct {
+	fab.Peer
+	breaker *Breaker
+}
+
+// ProcessTransactionProposal sends the proposal to the peer and records the outcome
+func (p *peer) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	done := p.breaker.Begin(p.URL())
+	response, err := p.Peer.ProcessTransactionProposal(ctx, request)
+	done(err)
+	return response, err
+}
@@ -0,0 +1,194 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package circuitbreaker
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const peerURL = "grpcs://peer1.org1.com:7051"
+
+var (
+	connectionFailed = status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", []interface{}{peerURL})
+	timeout          = status.New(status.GRPCTransportStatus, 4, "context deadline exceeded", nil)
+	chaincodeError   = status.New(status.ChaincodeStatus, 500, "chaincode error", nil)
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestBreaker(opts ...Option) (*Breaker, *clock, *[]*StateChangeEvent) {
+	var events []*StateChangeEvent
+	opts = append(opts, WithStateChangeListener(func(evt *StateChangeEvent) {
+		events = append(events, evt)
+	}))
+	b := New(opts...)
+	c := &clock{now: time.Now()}
+	b.now = c.Now
+	return b, c, &events
+}
+
+func TestFailureThreshold(t *testing.T) {
+	b, _, events := newTestBreaker(WithFailureThreshold(3))
+
+	assert.True(t, b.Allow(peerURL))
+	b.Record(peerURL, timeout)
+	b.Record(peerURL, timeout)
+	assert.Equal(t, Closed, b.State(peerURL))
+
+	b.Record(peerURL, nil)
+	b.Record(peerURL, timeout)
+	b.Record(peerURL, timeout)
+	assert.Equal(t, Closed, b.State(peerURL), "a success resets the consecutive failures")
+
+	b.Record(peerURL, timeout)
+	assert.Equal(t, Open, b.State(peerURL))
+	assert.False(t, b.Allow(peerURL))
+	assert.False(t, b.Accept(mocks.NewMockPeer("p1", "peer1.org1.com:7051")), "endpoints are matched by address")
+
+	require.Len(t, *events, 1)
+	evt := (*events)[0]
+	assert.Equal(t, "peer1.org1.com:7051", evt.URL)
+	assert.Equal(t, Closed, evt.From)
+	assert.Equal(t, Open, evt.To)
+	assert.Equal(t, 3, evt.Failures)
+	assert.Equal(t, timeout, evt.Err)
+}
+
+func TestConnectionFailure(t *testing.T) {
+	b, _, _ := newTestBreaker()
+
+	b.Record(peerURL, connectionFailed)
+	assert.Equal(t, Open, b.State(peerURL), "a connection failure opens the circuit by default")
+
+	b, _, _ = newTestBreaker(WithConnectionFailureThreshold(3))
+	b.Record(peerURL, connectionFailed)
+	b.Record(peerURL, connectionFailed)
+	assert.Equal(t, Closed, b.State(peerURL))
+	b.Record(peerURL, connectionFailed)
+	assert.Equal(t, Open, b.State(peerURL), "connection failures open the circuit before the failure threshold is reached")
+}
+
+func TestHalfOpen(t *testing.T) {
+	b, c, events := newTestBreaker(WithOpenTimeout(time.Minute), WithHalfOpenProbes(2), WithConnectionFailureThreshold(1))
+
+	b.Record(peerURL, connectionFailed)
+	assert.False(t, b.Allow(peerURL))
+
+	c.now = c.now.Add(time.Minute)
+	assert.Equal(t, HalfOpen, b.State(peerURL))
+	assert.True(t, b.Allow(peerURL))
+	assert.True(t, b.Allow(peerURL))
+	assert.False(t, b.Allow(peerURL), "only two probes are allowed")
+
+	b.Record(peerURL, nil)
+	assert.Equal(t, HalfOpen, b.State(peerURL))
+	b.Record(peerURL, nil)
+	assert.Equal(t, Closed, b.State(peerURL))
+	assert.True(t, b.Allow(peerURL))
+
+	require.Len(t, *events, 3)
+	assert.Equal(t, HalfOpen, (*events)[1].To)
+	assert.Equal(t, Closed, (*events)[2].To)
+
+	// A failed probe opens the circuit again
+	b.Record(peerURL, connectionFailed)
+	c.now = c.now.Add(time.Minute)
+	assert.True(t, b.Allow(peerURL))
+	b.Record(peerURL, errors.New("probe failed"))
+	assert.Equal(t, Open, b.State(peerURL))
+	assert.False(t, b.Allow(peerURL))
+}
+
+func TestStaleProbe(t *testing.T) {
+	b, c, _ := newTestBreaker(WithOpenTimeout(time.Minute), WithConnectionFailureThreshold(1))
+
+	b.Record(peerURL, connectionFailed)
+	c.now = c.now.Add(time.Minute)
+	assert.True(t, b.Allow(peerURL))
+	assert.False(t, b.Allow(peerURL))
+
+	c.now = c.now.Add(time.Minute)
+	assert.True(t, b.Allow(peerURL), "a probe whose outcome was never recorded is replaced")
+}
+
+func TestAccept(t *testing.T) {
+	b, c, events := newTestBreaker(WithOpenTimeout(time.Minute))
+	p := mocks.NewMockPeer("p1", peerURL)
+
+	b.Record(peerURL, connectionFailed)
+	assert.False(t, b.Accept(p))
+
+	c.now = c.now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.Accept(p), "accepting a candidate does not take up the probe")
+	}
+	require.Len(t, *events, 1, "accepting a candidate does not change the circuit")
+
+	done := b.Begin(peerURL)
+	assert.Equal(t, HalfOpen, b.State(peerURL))
+	assert.False(t, b.Accept(p), "the probe has been sent")
+	require.Len(t, *events, 2)
+	assert.Equal(t, HalfOpen, (*events)[1].To)
+
+	done(nil)
+	assert.Equal(t, Closed, b.State(peerURL))
+	assert.True(t, b.Accept(p))
+}
+
+func TestFailures(t *testing.T) {
+	b, _, _ := newTestBreaker(WithFailureThreshold(1))
+
+	b.Record(peerURL, chaincodeError)
+	assert.Equal(t, Closed, b.State(peerURL), "chaincode errors are not failures")
+
+	b, _, _ = newTestBreaker(WithFailureThreshold(1), WithFailurePredicate(func(err error) bool { return err == timeout }))
+	b.Record(peerURL, errors.New("other error"))
+	assert.Equal(t, Closed, b.State(peerURL))
+	b.Record(peerURL, timeout)
+	assert.Equal(t, Open, b.State(peerURL))
+
+	assert.False(t, IsFailure(nil))
+	assert.True(t, IsFailure(errors.New("some error")))
+	assert.True(t, IsConnectionFailure(errors.Wrap(connectionFailed, "calling orderer failed")))
+	assert.False(t, IsConnectionFailure(timeout))
+}
+
+func TestPeers(t *testing.T) {
+	b, c, _ := newTestBreaker(WithOpenTimeout(time.Minute))
+
+	p := mocks.NewMockPeer("p1", peerURL)
+	p.Error = connectionFailed
+	peers := b.Peers([]fab.Peer{p})
+	require.Len(t, peers, 1)
+	assert.Equal(t, peerURL, peers[0].URL())
+
+	_, err := peers[0].ProcessTransactionProposal(reqContext.Background(), fab.ProcessProposalRequest{})
+	assert.Error(t, err)
+	assert.Equal(t, Open, b.State(peerURL))
+
+	// Sending a proposal takes up the probe of the half-open circuit
+	c.now = c.now.Add(time.Minute)
+	_, err = peers[0].ProcessTransactionProposal(reqContext.Background(), fab.ProcessProposalRequest{})
+	assert.Error(t, err)
+	assert.Equal(t, Open, b.State(peerURL))
+	assert.False(t, b.Accept(p))
+}
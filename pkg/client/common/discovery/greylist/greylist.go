@@ -20,6 +20,9 @@ var logger = logging.NewLogger("fabsdk/client")
 
 // Filter is a discovery filter that greylists certain peers that are
 // known to be down for the configured amount of time
+//
+// Deprecated: the channel client uses circuitbreaker.Breaker, which also reacts to
+// timeouts and endorsement errors and probes peers before accepting them again.
 type Filter struct {
 	// greylistURLs contains a map of peer URLs as keys and timestamps as values
 	// peers are expired from the greylist based on these timestamps
@@ -0,0 +1,182 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsementpolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"github.com/pkg/errors"
+)
+
+// rule is a compiled (sub-)policy. It is evaluated with the given identities and marks the
+// identities that it uses in 'used' so that each identity is only counted once. The candidates
+// of a rule are the sets of identities that may satisfy it; an error is returned if there are
+// more than maxCandidates of them (no limit if maxCandidates is not positive).
+type rule interface {
+	evaluate(identities []*Identity, used []bool) *Explanation
+	candidates(identities []*Identity, maxCandidates int) ([][]int, error)
+	String() string
+}
+
+// Satisfied returns true if the signatures of the given identities satisfy the policy.
+// As on the committing peers, duplicate identities are only counted once and each
+// identity satisfies at most one principal of the policy.
+func Satisfied(policy *common.SignaturePolicyEnvelope, identities []*Identity) (bool, error) {
+	explanation, err := Explain(policy, identities)
+	if err != nil {
+		return false, err
+	}
+	return explanation.Satisfied, nil
+}
+
+// Explain evaluates the policy with the signatures of the given identities and returns
+// which of its sub-policies are satisfied and by which identities.
+func Explain(policy *common.SignaturePolicyEnvelope, identities []*Identity) (*Explanation, error) {
+	root, err := compile(policy)
+	if err != nil {
+		return nil, err
+	}
+	return evaluate(root, identities), nil
+}
+
+func evaluate(root rule, identities []*Identity) *Explanation {
+	identities = deduplicate(identities)
+	return root.evaluate(identities, make([]bool, len(identities)))
+}
+
+func compile(policy *common.SignaturePolicyEnvelope) (rule, error) {
+	if policy == nil || policy.Rule == nil {
+		return nil, errors.New("policy is required")
+	}
+
+	principals, err := parsePrincipals(policy)
+	if err != nil {
+		return nil, err
+	}
+	return compileRule(policy.Rule, principals)
+}
+
+func parsePrincipals(policy *common.SignaturePolicyEnvelope) ([]*principal, error) {
+	principals := make([]*principal, len(policy.Identities))
+	for i, p := range policy.Identities {
+		parsed, err := parsePrincipal(p)
+		if err != nil {
+			return nil, err
+		}
+		principals[i] = parsed
+	}
+	return principals, nil
+}
+
+func compileRule(r *common.SignaturePolicy, principals []*principal) (rule, error) {
+	switch t := r.Type.(type) {
+	case *common.SignaturePolicy_NOutOf_:
+		rules := make([]rule, len(t.NOutOf.Rules))
+		for i, sub := range t.NOutOf.Rules {
+			compiled, err := compileRule(sub, principals)
+			if err != nil {
+				return nil, err
+			}
+			rules[i] = compiled
+		}
+		return &nOutOfRule{n: int(t.NOutOf.N), rules: rules}, nil
+
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(principals) {
+			return nil, errors.Errorf("principal index %d out of range", t.SignedBy)
+		}
+		return &signedByRule{principal: principals[t.SignedBy]}, nil
+
+	default:
+		return nil, errors.Errorf("unsupported signature policy type: %T", t)
+	}
+}
+
+type nOutOfRule struct {
+	n     int
+	rules []rule
+}
+
+func (r *nOutOfRule) evaluate(identities []*Identity, used []bool) *Explanation {
+	explanation := &Explanation{Required: r.n, SubPolicies: make([]*Explanation, 0, len(r.rules))}
+
+	verified := 0
+	ruleUsed := make([]bool, len(used))
+	for _, sub := range r.rules {
+		// An identity that is used by a sub-policy that is not satisfied may be used by another one
+		copy(ruleUsed, used)
+		subExplanation := sub.evaluate(identities, ruleUsed)
+		if subExplanation.Satisfied {
+			verified++
+			copy(used, ruleUsed)
+		}
+		explanation.SubPolicies = append(explanation.SubPolicies, subExplanation)
+	}
+
+	explanation.Satisfied = verified >= r.n
+	explanation.Rule = r.String()
+	return explanation
+}
+
+// String returns the rule in the notation of the policy language
+func (r *nOutOfRule) String() string {
+	rules := make([]string, len(r.rules))
+	for i, sub := range r.rules {
+		rules[i] = sub.String()
+	}
+
+	switch {
+	case len(r.rules) > 1 && r.n == len(r.rules):
+		return fmt.Sprintf("AND(%s)", strings.Join(rules, ", "))
+	case len(r.rules) > 1 && r.n == 1:
+		return fmt.Sprintf("OR(%s)", strings.Join(rules, ", "))
+	default:
+		return fmt.Sprintf("OutOf(%d, %s)", r.n, strings.Join(rules, ", "))
+	}
+}
+
+type signedByRule struct {
+	principal *principal
+}
+
+func (r *signedByRule) evaluate(identities []*Identity, used []bool) *Explanation {
+	explanation := &Explanation{Rule: r.String()}
+	for i, id := range identities {
+		if used[i] {
+			continue
+		}
+		if r.principal.satisfiedBy(id) {
+			used[i] = true
+			explanation.Satisfied = true
+			explanation.SignedBy = id
+			break
+		}
+	}
+	return explanation
+}
+
+// String returns the principal in the notation of the policy language
+func (r *signedByRule) String() string {
+	return r.principal.String()
+}
+
+func deduplicate(identities []*Identity) []*Identity {
+	seen := make(map[string]bool)
+	var result []*Identity
+	for _, id := range identities {
+		if len(id.Serialized) > 0 {
+			if seen[string(id.Serialized)] {
+				continue
+			}
+			seen[string(id.Serialized)] = true
+		}
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsementpolicy
+
+import (
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	mb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	pb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/policydsl"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIdentity(t *testing.T, mspID, name, role string, ous ...string) *Identity {
+	serialized, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(name)})
+	require.NoError(t, err)
+	return &Identity{MSPID: mspID, Role: role, OrganizationalUnits: ous, Serialized: serialized}
+}
+
+func fromString(t *testing.T, policy string) *common.SignaturePolicyEnvelope {
+	envelope, err := policydsl.FromString(policy)
+	require.NoError(t, err)
+	return envelope
+}
+
+func satisfied(t *testing.T, policy *common.SignaturePolicyEnvelope, identities ...*Identity) bool {
+	ok, err := Satisfied(policy, identities)
+	require.NoError(t, err)
+	return ok
+}
+
+func TestSatisfied(t *testing.T) {
+	org1Peer := newTestIdentity(t, "Org1MSP", "peer0.org1", RolePeer)
+	org1Peer2 := newTestIdentity(t, "Org1MSP", "peer1.org1", RolePeer)
+	org1Client := newTestIdentity(t, "Org1MSP", "user1.org1", RoleClient)
+	org2Peer := newTestIdentity(t, "Org2MSP", "peer0.org2", RolePeer)
+	org3Unknown := newTestIdentity(t, "Org3MSP", "peer0.org3", "")
+
+	t.Run("And", func(t *testing.T) {
+		policy := fromString(t, "AND('Org1MSP.peer', 'Org2MSP.peer')")
+		assert.True(t, satisfied(t, policy, org1Peer, org2Peer))
+		assert.False(t, satisfied(t, policy, org1Peer, org1Peer2))
+		assert.False(t, satisfied(t, policy, org1Client, org2Peer), "a client must not satisfy a peer principal")
+	})
+
+	t.Run("Or", func(t *testing.T) {
+		policy := fromString(t, "OR('Org1MSP.member', 'Org2MSP.member')")
+		assert.True(t, satisfied(t, policy, org1Client))
+		assert.True(t, satisfied(t, policy, org2Peer))
+		assert.False(t, satisfied(t, policy, org3Unknown))
+		assert.False(t, satisfied(t, policy))
+	})
+
+	t.Run("OutOf", func(t *testing.T) {
+		policy := fromString(t, "OutOf(2, 'Org1MSP.peer', 'Org2MSP.peer', 'Org3MSP.peer')")
+		assert.True(t, satisfied(t, policy, org1Peer, org3Unknown), "an identity with an unknown role must satisfy role principals")
+		assert.False(t, satisfied(t, policy, org2Peer))
+	})
+
+	t.Run("Each identity is used once", func(t *testing.T) {
+		policy := fromString(t, "AND('Org1MSP.member', 'Org1MSP.member')")
+		assert.True(t, satisfied(t, policy, org1Peer, org1Peer2))
+		assert.False(t, satisfied(t, policy, org1Peer))
+		assert.False(t, satisfied(t, policy, org1Peer, org1Peer), "duplicate identities must only be counted once")
+	})
+
+	t.Run("Unsatisfied sub-policy does not consume identities", func(t *testing.T) {
+		policy := fromString(t, "OR(AND('Org1MSP.peer', 'Org2MSP.peer'), 'Org1MSP.peer')")
+		assert.True(t, satisfied(t, policy, org1Peer))
+	})
+
+	t.Run("Organizational unit", func(t *testing.T) {
+		ou, err := proto.Marshal(&mb.OrganizationUnit{MspIdentifier: "Org1MSP", OrganizationalUnitIdentifier: "dept1"})
+		require.NoError(t, err)
+		policy := &common.SignaturePolicyEnvelope{
+			Rule:       policydsl.NOutOf(1, []*common.SignaturePolicy{policydsl.SignedBy(0)}),
+			Identities: []*mb.MSPPrincipal{{PrincipalClassification: mb.MSPPrincipal_ORGANIZATION_UNIT, Principal: ou}},
+		}
+		assert.True(t, satisfied(t, policy, newTestIdentity(t, "Org1MSP", "peer", RolePeer, "dept1", "peer")))
+		assert.False(t, satisfied(t, policy, org1Peer))
+	})
+
+	t.Run("Identity", func(t *testing.T) {
+		policy := policydsl.Envelope(policydsl.SignedBy(0), [][]byte{org1Peer.Serialized})
+		assert.True(t, satisfied(t, policy, org1Peer))
+		assert.False(t, satisfied(t, policy, org1Peer2))
+	})
+
+	t.Run("Invalid policy", func(t *testing.T) {
+		_, err := Satisfied(nil, []*Identity{org1Peer})
+		assert.Error(t, err)
+
+		_, err = Satisfied(&common.SignaturePolicyEnvelope{Rule: policydsl.SignedBy(1)}, []*Identity{org1Peer})
+		assert.Error(t, err, "expecting an error for a principal index that is out of range")
+	})
+}
+
+func TestEndorserIdentities(t *testing.T) {
+	serialized, err := proto.Marshal(&mb.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("not a certificate")})
+	require.NoError(t, err)
+
+	identities, err := EndorserIdentities([]*fab.TransactionProposalResponse{
+		{Endorser: "peer1", ProposalResponse: &pb.ProposalResponse{Endorsement: &pb.Endorsement{Endorser: serialized}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+	assert.Equal(t, "Org1MSP", identities[0].MSPID)
+	assert.Empty(t, identities[0].Role, "expecting an unknown role if the identity has no certificate")
+
+	_, err = EndorserIdentities([]*fab.TransactionProposalResponse{
+		{Endorser: "peer1", ProposalResponse: &pb.ProposalResponse{}},
+	})
+	assert.Error(t, err)
+}
+
+func TestFromLayouts(t *testing.T) {
+	layouts := []map[string]int{
+		{"G0": 1, "G1": 1},
+		{"G2": 2},
+	}
+	mspIDsByGroup := map[string][]string{
+		"G0": {"Org1MSP"},
+		"G1": {"Org2MSP"},
+		"G2": {"Org3MSP"},
+	}
+
+	policy, err := FromLayouts(layouts, mspIDsByGroup)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	assert.Len(t, policy.Identities, 3, "expecting one principal per MSP")
+
+	org1 := newTestIdentity(t, "Org1MSP", "peer0.org1", RolePeer)
+	org2 := newTestIdentity(t, "Org2MSP", "peer0.org2", RolePeer)
+	org3 := newTestIdentity(t, "Org3MSP", "peer0.org3", RolePeer)
+	org3b := newTestIdentity(t, "Org3MSP", "peer1.org3", RolePeer)
+
+	assert.True(t, satisfied(t, policy, org1, org2))
+	assert.False(t, satisfied(t, policy, org1))
+	assert.True(t, satisfied(t, policy, org3, org3b))
+	assert.False(t, satisfied(t, policy, org3))
+
+	policy, err = FromLayouts(append(layouts, map[string]int{"G3": 1}), mspIDsByGroup)
+	require.NoError(t, err)
+	assert.Nil(t, policy, "expecting no policy if a layout has a group without known peers")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsementpolicy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation explains the outcome of the evaluation of a policy or of one of its sub-policies
+type Explanation struct {
+	// Rule is the (sub-)policy in the notation of the policy language,
+	// e.g. OutOf(2, 'Org1MSP.peer', 'Org2MSP.peer', 'Org3MSP.peer')
+	Rule string
+	// Satisfied is true if the (sub-)policy is satisfied
+	Satisfied bool
+	// Required is the number of sub-policies that must be satisfied (OutOf rules only)
+	Required int
+	// SubPolicies explain the outcome of the sub-policies (OutOf rules only)
+	SubPolicies []*Explanation
+	// SignedBy is the identity that satisfies the principal (principals only)
+	SignedBy *Identity
+}
+
+// Unsatisfied returns the principals that are not satisfied and that cause the policy not to be
+// satisfied, i.e. the principals of the unsatisfied sub-policies of unsatisfied policies.
+// Nil is returned if the policy is satisfied.
+func (e *Explanation) Unsatisfied() []*Explanation {
+	if e.Satisfied {
+		return nil
+	}
+	if len(e.SubPolicies) == 0 {
+		return []*Explanation{e}
+	}
+
+	var unsatisfied []*Explanation
+	for _, sub := range e.SubPolicies {
+		unsatisfied = append(unsatisfied, sub.Unsatisfied()...)
+	}
+	return unsatisfied
+}
+
+// String returns the explanation as an indented tree, one (sub-)policy per line, e.g.
+//
+//	[NOT SATISFIED] AND('Org1MSP.peer', 'Org2MSP.peer') - 1 of 2 required sub-policies satisfied
+//	  [SATISFIED] 'Org1MSP.peer' - signed by peer0.org1.example.com:7051
+//	  [NOT SATISFIED] 'Org2MSP.peer' - no matching identity
+func (e *Explanation) String() string {
+	b := &strings.Builder{}
+	e.write(b, 0)
+	return b.String()
+}
+
+func (e *Explanation) write(b *strings.Builder, depth int) {
+	outcome := "[SATISFIED]"
+	if !e.Satisfied {
+		outcome = "[NOT SATISFIED]"
+	}
+	fmt.Fprintf(b, "%s%s %s - %s\n", strings.Repeat("  ", depth), outcome, e.Rule, e.detail())
+
+	for _, sub := range e.SubPolicies {
+		sub.write(b, depth+1)
+	}
+}
+
+func (e *Explanation) detail() string {
+	if e.SubPolicies != nil {
+		satisfied := 0
+		for _, sub := range e.SubPolicies {
+			if sub.Satisfied {
+				satisfied++
+			}
+		}
+		return fmt.Sprintf("%d of %d required sub-policies satisfied", satisfied, e.Required)
+	}
+	if e.SignedBy != nil {
+		return fmt.Sprintf("signed by %s", e.SignedBy)
+	}
+	return "no matching identity"
+}
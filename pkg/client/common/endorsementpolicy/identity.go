@@ -0,0 +1,128 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package endorsementpolicy evaluates chaincode endorsement policies (signature policies)
+// on the client side, e.g. to check that the endorsements collected for a transaction
+// satisfy the chaincode's policy before the transaction is sent to the orderer.
+// It also explains why a policy is not satisfied and simulates which combinations
+// of identities or peers would satisfy it.
+package endorsementpolicy
+
+import (
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	mb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// NodeOU roles that may be required by the principals of a policy
+const (
+	RoleClient  = "client"
+	RolePeer    = "peer"
+	RoleAdmin   = "admin"
+	RoleOrderer = "orderer"
+)
+
+// Identity is an identity whose signature is evaluated against a policy
+type Identity struct {
+	// MSPID is the ID of the MSP of the identity
+	MSPID string
+	// Role is the NodeOU role of the identity (client, peer, admin or orderer).
+	// An empty role is unknown and satisfies all role principals of the identity's MSP.
+	Role string
+	// OrganizationalUnits are the OUs of the identity's certificate
+	OrganizationalUnits []string
+	// Serialized is the serialized identity (msp.SerializedIdentity); it is only needed to
+	// evaluate principals that designate a specific identity
+	Serialized []byte
+	// Peer is the peer of the identity (nil if the identity is not a peer's identity)
+	Peer fab.Peer
+}
+
+// String returns the URL of the identity's peer or the MSP and role of the identity
+func (id *Identity) String() string {
+	if id.Peer != nil {
+		return id.Peer.URL()
+	}
+	if id.Role == "" {
+		return id.MSPID
+	}
+	return fmt.Sprintf("%s.%s", id.MSPID, id.Role)
+}
+
+// NewIdentity returns the Identity of the given serialized identity (msp.SerializedIdentity).
+// The role and OUs are read from the identity's certificate.
+func NewIdentity(serialized []byte) (*Identity, error) {
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serialized, sID); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal serialized identity")
+	}
+
+	identity := &Identity{
+		MSPID:      sID.Mspid,
+		Serialized: serialized,
+	}
+
+	block, _ := pem.Decode(sID.IdBytes)
+	if block == nil {
+		// The role cannot be determined - it is considered unknown
+		return identity, nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse certificate of identity of MSP [%s]", sID.Mspid)
+	}
+
+	identity.OrganizationalUnits = cert.Subject.OrganizationalUnit
+	for _, ou := range cert.Subject.OrganizationalUnit {
+		if isNodeOURole(ou) {
+			identity.Role = strings.ToLower(ou)
+			break
+		}
+	}
+	return identity, nil
+}
+
+// EndorserIdentities returns the identities of the endorsers of the given proposal responses
+func EndorserIdentities(responses []*fab.TransactionProposalResponse) ([]*Identity, error) {
+	var identities []*Identity
+	for _, r := range responses {
+		endorsement := r.ProposalResponse.GetEndorsement()
+		if endorsement == nil {
+			return nil, errors.Errorf("proposal response from endorser [%s] has no endorsement", r.Endorser)
+		}
+		identity, err := NewIdentity(endorsement.Endorser)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid identity of endorser [%s]", r.Endorser)
+		}
+		identities = append(identities, identity)
+	}
+	return identities, nil
+}
+
+// PeerIdentities returns the identities of the given peers. Since the certificates of the peers
+// are not known, the identities only satisfy role principals (peer or member) of the peers' MSPs.
+func PeerIdentities(peers []fab.Peer) []*Identity {
+	identities := make([]*Identity, len(peers))
+	for i, p := range peers {
+		identities[i] = &Identity{MSPID: p.MSPID(), Role: RolePeer, Peer: p}
+	}
+	return identities
+}
+
+func isNodeOURole(ou string) bool {
+	switch strings.ToLower(ou) {
+	case RoleClient, RolePeer, RoleAdmin, RoleOrderer:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsementpolicy
+
+import (
+	"sort"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	mb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/policydsl"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// FromLayouts returns a signature policy that is equivalent to the given endorsement layouts
+// (as returned by the discovery service). A layout maps a group to the number of endorsements
+// that are required from the group and mspIDsByGroup maps a group to the MSPs of its peers.
+// The policy is satisfied if any of the layouts is satisfied.
+//
+// Since the discovery service does not return the principals of the groups, a group is satisfied
+// by any member of the MSPs of the group's peers. Nil is returned if a layout has a group without
+// peers, since the principals of such a group are not known and the policy could otherwise reject
+// endorsements that satisfy that layout.
+func FromLayouts(layouts []map[string]int, mspIDsByGroup map[string][]string) (*common.SignaturePolicyEnvelope, error) {
+	b := &layoutPolicyBuilder{principalIndexes: make(map[string]int32)}
+
+	var rules []*common.SignaturePolicy
+	for _, layout := range layouts {
+		rule, ok, err := b.layoutRule(layout, mspIDsByGroup)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return &common.SignaturePolicyEnvelope{
+		Rule:       policydsl.NOutOf(1, rules),
+		Identities: b.principals,
+	}, nil
+}
+
+type layoutPolicyBuilder struct {
+	principals       []*mb.MSPPrincipal
+	principalIndexes map[string]int32
+}
+
+func (b *layoutPolicyBuilder) layoutRule(layout map[string]int, mspIDsByGroup map[string][]string) (*common.SignaturePolicy, bool, error) {
+	// Sort the groups so that the same layouts always result in the same policy
+	groups := make([]string, 0, len(layout))
+	for group := range layout {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	var rules []*common.SignaturePolicy
+	for _, group := range groups {
+		mspIDs := mspIDsByGroup[group]
+		if len(mspIDs) == 0 {
+			return nil, false, nil
+		}
+
+		member, err := b.anyMemberRule(mspIDs)
+		if err != nil {
+			return nil, false, err
+		}
+
+		count := layout[group]
+		members := make([]*common.SignaturePolicy, count)
+		for i := range members {
+			members[i] = member
+		}
+		rules = append(rules, policydsl.NOutOf(int32(count), members))
+	}
+	return policydsl.NOutOf(int32(len(rules)), rules), true, nil
+}
+
+func (b *layoutPolicyBuilder) anyMemberRule(mspIDs []string) (*common.SignaturePolicy, error) {
+	var rules []*common.SignaturePolicy
+	for _, mspID := range mspIDs {
+		index, err := b.memberPrincipal(mspID)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, policydsl.SignedBy(index))
+	}
+	return policydsl.NOutOf(1, rules), nil
+}
+
+func (b *layoutPolicyBuilder) memberPrincipal(mspID string) (int32, error) {
+	if index, ok := b.principalIndexes[mspID]; ok {
+		return index, nil
+	}
+
+	role, err := proto.Marshal(&mb.MSPRole{Role: mb.MSPRole_MEMBER, MspIdentifier: mspID})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to marshal MSP role")
+	}
+
+	index := int32(len(b.principals))
+	b.principals = append(b.principals, &mb.MSPPrincipal{
+		PrincipalClassification: mb.MSPPrincipal_ROLE,
+		Principal:               role,
+	})
+	b.principalIndexes[mspID] = index
+	return index, nil
+}
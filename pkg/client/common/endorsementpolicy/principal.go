@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsementpolicy
+
+import (
+	"fmt"
+	"strings"
+
+	mb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// principal is a parsed MSP principal
+type principal struct {
+	classification mb.MSPPrincipal_Classification
+	role           *mb.MSPRole
+	ou             *mb.OrganizationUnit
+	identity       *mb.SerializedIdentity
+}
+
+func parsePrincipal(p *mb.MSPPrincipal) (*principal, error) {
+	parsed := &principal{classification: p.PrincipalClassification}
+
+	switch p.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		parsed.role = &mb.MSPRole{}
+		if err := proto.Unmarshal(p.Principal, parsed.role); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal MSP role principal")
+		}
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		parsed.ou = &mb.OrganizationUnit{}
+		if err := proto.Unmarshal(p.Principal, parsed.ou); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal organization unit principal")
+		}
+	case mb.MSPPrincipal_IDENTITY:
+		parsed.identity = &mb.SerializedIdentity{}
+		if err := proto.Unmarshal(p.Principal, parsed.identity); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal identity principal")
+		}
+	}
+	return parsed, nil
+}
+
+// satisfiedBy returns true if the identity satisfies the principal. Principals that cannot be
+// evaluated without the channel MSPs (e.g. anonymity) are considered to be satisfied, so that
+// a policy is only reported as not satisfied when it is certain that it is not.
+func (p *principal) satisfiedBy(id *Identity) bool {
+	switch p.classification {
+	case mb.MSPPrincipal_ROLE:
+		if p.role.MspIdentifier != id.MSPID {
+			return false
+		}
+		if p.role.Role == mb.MSPRole_MEMBER || id.Role == "" {
+			return true
+		}
+		return id.Role == strings.ToLower(p.role.Role.String())
+
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		if p.ou.MspIdentifier != id.MSPID {
+			return false
+		}
+		for _, ou := range id.OrganizationalUnits {
+			if ou == p.ou.OrganizationalUnitIdentifier {
+				return true
+			}
+		}
+		return false
+
+	case mb.MSPPrincipal_IDENTITY:
+		actual := &mb.SerializedIdentity{}
+		if err := proto.Unmarshal(id.Serialized, actual); err != nil {
+			return false
+		}
+		return p.identity.Mspid == actual.Mspid && string(p.identity.IdBytes) == string(actual.IdBytes)
+
+	default:
+		return true
+	}
+}
+
+// mspID returns the ID of the MSP of the principal (empty if the principal has no MSP)
+func (p *principal) mspID() string {
+	switch p.classification {
+	case mb.MSPPrincipal_ROLE:
+		return p.role.MspIdentifier
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		return p.ou.MspIdentifier
+	case mb.MSPPrincipal_IDENTITY:
+		return p.identity.Mspid
+	default:
+		return ""
+	}
+}
+
+// String returns the principal in the notation of the policy language, e.g. 'Org1MSP.peer'
+func (p *principal) String() string {
+	switch p.classification {
+	case mb.MSPPrincipal_ROLE:
+		return fmt.Sprintf("'%s.%s'", p.role.MspIdentifier, strings.ToLower(p.role.Role.String()))
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		return fmt.Sprintf("'%s.OU(%s)'", p.ou.MspIdentifier, p.ou.OrganizationalUnitIdentifier)
+	case mb.MSPPrincipal_IDENTITY:
+		return fmt.Sprintf("'%s.identity'", p.identity.Mspid)
+	default:
+		return fmt.Sprintf("'%s'", p.classification)
+	}
+}
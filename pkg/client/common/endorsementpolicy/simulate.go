@@ -0,0 +1,307 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsementpolicy
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/dynamicselection/pgresolver"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultMaxCandidates = 100000
+
+	// subsetLookupMaxSize is the maximum size of the sets whose subsets are looked up one by one
+	// when computing the minimal sets (a set of size n has 2^n subsets)
+	subsetLookupMaxSize = 16
+)
+
+// SimulateOption describes a functional parameter for Simulate
+type SimulateOption func(*simulateOptions)
+
+type simulateOptions struct {
+	maxCandidates int
+}
+
+// WithMaxCandidates sets the maximum number of candidate sets of identities that are checked when
+// computing the minimal sets (default 100000). The number of candidate sets grows combinatorially
+// with the number of identities of each MSP (e.g. OutOf(3, ...) over 10 MSPs with 20 peers each has
+// close to a million of them), so Simulate returns an error rather than running for a long time when
+// the limit is exceeded. A value that is not positive means no limit.
+func WithMaxCandidates(value int) SimulateOption {
+	return func(o *simulateOptions) {
+		o.maxCandidates = value
+	}
+}
+
+// Simulation is the outcome of the simulation of a policy with a set of identities
+type Simulation struct {
+	// Satisfied is true if the signatures of all of the identities satisfy the policy
+	Satisfied bool
+	// Explanation explains which sub-policies are satisfied by the identities and which are not
+	Explanation *Explanation
+	// MinimalSets are the minimal sets of the identities that satisfy the policy, i.e. the sets
+	// that no longer satisfy the policy if any of their identities is removed. The sets are sorted
+	// by size. MinimalSets is empty if the policy is not satisfied.
+	MinimalSets [][]*Identity
+}
+
+// Simulate evaluates the policy with the signatures of the given identities (e.g. the identities of
+// the endorsers of a transaction, or the PeerIdentities of the peers of a channel) and computes the
+// minimal sets of the identities that satisfy the policy.
+//
+// The candidate sets are the combinations of the identities that satisfy the principals of the
+// policy, so their number grows quickly with the number of identities of each MSP. An error is
+// returned if there are more candidate sets than allowed (see WithMaxCandidates).
+func Simulate(policy *common.SignaturePolicyEnvelope, identities []*Identity, opts ...SimulateOption) (*Simulation, error) {
+	options := &simulateOptions{maxCandidates: defaultMaxCandidates}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	root, err := compile(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	identities = deduplicate(identities)
+	explanation := evaluate(root, identities)
+	simulation := &Simulation{
+		Satisfied:   explanation.Satisfied,
+		Explanation: explanation,
+	}
+
+	// If all of the identities do not satisfy the policy then none of their subsets does
+	if explanation.Satisfied {
+		simulation.MinimalSets, err = minimalSets(root, identities, options.maxCandidates)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return simulation, nil
+}
+
+// minimalSets returns the minimal sets of the given identities that satisfy the policy
+func minimalSets(root rule, identities []*Identity, maxCandidates int) ([][]*Identity, error) {
+	candidates, err := root.candidates(identities, maxCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var sets [][]int
+	for _, set := range candidates {
+		// A candidate may count the same identity for several principals
+		if evaluate(root, identitiesAt(identities, set)).Satisfied {
+			sets = append(sets, set)
+		}
+	}
+
+	sort.SliceStable(sets, func(i, j int) bool {
+		return len(sets[i]) < len(sets[j])
+	})
+
+	// The sets are sorted by size and distinct, so a set is minimal if none of its proper
+	// subsets has been found to be minimal
+	var minimal [][]int
+	minimalKeys := make(map[string]bool)
+	for _, set := range sets {
+		if !containsProperSubset(minimal, minimalKeys, set) {
+			minimal = append(minimal, set)
+			minimalKeys[setKey(set)] = true
+		}
+	}
+
+	result := make([][]*Identity, len(minimal))
+	for i, set := range minimal {
+		result[i] = identitiesAt(identities, set)
+	}
+	return result, nil
+}
+
+// candidates returns the sets of identities (as sorted indexes) that may satisfy the rule
+func (r *nOutOfRule) candidates(identities []*Identity, maxCandidates int) ([][]int, error) {
+	if r.n <= 0 {
+		return [][]int{{}}, nil
+	}
+
+	var children []pgresolver.Group
+	for _, sub := range r.rules {
+		sets, err := sub.candidates(identities, maxCandidates)
+		if err != nil {
+			return nil, err
+		}
+		if len(sets) > 0 {
+			children = append(children, pgresolver.NewGroupOfGroups(asGroups(sets)))
+		}
+	}
+	if len(children) < r.n {
+		return nil, nil
+	}
+
+	// Each combination of sub-policies has at least one candidate, so fail before the combinations are generated
+	if maxCandidates > 0 && new(big.Int).Binomial(int64(len(children)), int64(r.n)).Cmp(big.NewInt(int64(maxCandidates))) > 0 {
+		return nil, tooManyCandidates(r, maxCandidates)
+	}
+
+	// The same group logic as the one used by dynamic selection to resolve peer groups: all of the
+	// combinations of N sub-policies and, for each combination, one candidate of each sub-policy
+	combinations, err := pgresolver.NewGroupOfGroups(children).Nof(int32(r.n))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get combinations of sub-policies of rule %s", r)
+	}
+
+	var sets [][]int
+	seen := make(map[string]bool)
+	for _, combination := range combinations.Groups() {
+		subGroups := make([]pgresolver.Group, len(combination.Items()))
+		for i, item := range combination.Items() {
+			subGroups[i] = item.(pgresolver.Group)
+		}
+
+		// Fail before the candidates of the combination are generated
+		if maxCandidates > 0 && len(sets)+numCandidates(subGroups, maxCandidates) > maxCandidates {
+			return nil, tooManyCandidates(r, maxCandidates)
+		}
+
+		for _, group := range pgresolver.And(subGroups) {
+			set := union(group)
+			key := setKey(set)
+			if !seen[key] {
+				seen[key] = true
+				sets = append(sets, set)
+			}
+		}
+	}
+	return sets, nil
+}
+
+// candidates returns the identities that satisfy the principal
+func (r *signedByRule) candidates(identities []*Identity, maxCandidates int) ([][]int, error) {
+	var sets [][]int
+	for i, id := range identities {
+		if r.principal.satisfiedBy(id) {
+			sets = append(sets, []int{i})
+		}
+	}
+	if maxCandidates > 0 && len(sets) > maxCandidates {
+		return nil, tooManyCandidates(r, maxCandidates)
+	}
+	return sets, nil
+}
+
+// numCandidates returns the number of candidates of the combination of the given groups, or
+// more than maxCandidates if it exceeds it
+func numCandidates(groups []pgresolver.Group, maxCandidates int) int {
+	n := 1
+	for _, group := range groups {
+		n *= len(group.Items())
+		if n > maxCandidates {
+			return maxCandidates + 1
+		}
+	}
+	return n
+}
+
+func tooManyCandidates(r rule, maxCandidates int) error {
+	return errors.Errorf("rule %s has more than %d candidate sets of identities - use WithMaxCandidates to raise the limit", r, maxCandidates)
+}
+
+func asGroups(sets [][]int) []pgresolver.Group {
+	groups := make([]pgresolver.Group, len(sets))
+	for i, set := range sets {
+		items := make([]pgresolver.Item, len(set))
+		for j, index := range set {
+			items[j] = index
+		}
+		groups[i] = pgresolver.NewGroup(items)
+	}
+	return groups
+}
+
+// union returns the sorted, distinct indexes of the candidates of the given group
+func union(group pgresolver.Group) []int {
+	seen := make(map[int]bool)
+	set := []int{}
+	for _, item := range group.Items() {
+		for _, index := range item.(pgresolver.Group).Items() {
+			if !seen[index.(int)] {
+				seen[index.(int)] = true
+				set = append(set, index.(int))
+			}
+		}
+	}
+	sort.Ints(set)
+	return set
+}
+
+func identitiesAt(identities []*Identity, indexes []int) []*Identity {
+	result := make([]*Identity, len(indexes))
+	for i, index := range indexes {
+		result[i] = identities[index]
+	}
+	return result
+}
+
+// setKey returns the key of the sorted set in the maps of sets
+func setKey(set []int) string {
+	var b strings.Builder
+	for i, index := range set {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(index))
+	}
+	return b.String()
+}
+
+// containsProperSubset returns true if any of the given sets (whose keys are given) is a proper
+// subset of the given set. The subsets of small sets are looked up, which is faster than
+// scanning the sets when there are many of them.
+func containsProperSubset(sets [][]int, keys map[string]bool, set []int) bool {
+	if len(set) > subsetLookupMaxSize {
+		for _, s := range sets {
+			if len(s) < len(set) && isSubset(s, set) {
+				return true
+			}
+		}
+		return false
+	}
+
+	subset := make([]int, 0, len(set))
+	for mask := 0; mask < 1<<uint(len(set))-1; mask++ {
+		subset = subset[:0]
+		for i, index := range set {
+			if mask&(1<<uint(i)) != 0 {
+				subset = append(subset, index)
+			}
+		}
+		if keys[setKey(subset)] {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubset returns true if the sorted set s1 is a subset of the sorted set s2
+func isSubset(s1, s2 []int) bool {
+	j := 0
+	for _, v := range s1 {
+		for j < len(s2) && s2[j] < v {
+			j++
+		}
+		if j == len(s2) || s2[j] != v {
+			return false
+		}
+		j++
+	}
+	return true
+}
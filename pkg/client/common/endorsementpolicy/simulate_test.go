@@ -0,0 +1,147 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsementpolicy
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/policydsl"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPeer(url, mspID string) fab.Peer {
+	peer := mocks.NewMockPeer(url, url)
+	peer.MockMSP = mspID
+	return peer
+}
+
+func TestSimulate(t *testing.T) {
+	org1Peer1 := newTestPeer("peer1.org1.com:7051", "Org1MSP")
+	org1Peer2 := newTestPeer("peer2.org1.com:7051", "Org1MSP")
+	org2Peer1 := newTestPeer("peer1.org2.com:7051", "Org2MSP")
+	org3Peer1 := newTestPeer("peer1.org3.com:7051", "Org3MSP")
+
+	identities := PeerIdentities([]fab.Peer{org1Peer1, org1Peer2, org2Peer1, org3Peer1})
+	org1ID1, org1ID2, org2ID1, org3ID1 := identities[0], identities[1], identities[2], identities[3]
+
+	t.Run("OutOf", func(t *testing.T) {
+		s, err := Simulate(fromString(t, "OutOf(2, 'Org1MSP.peer', 'Org2MSP.peer', 'Org3MSP.peer')"), identities)
+		require.NoError(t, err)
+		assert.True(t, s.Satisfied)
+		assert.ElementsMatch(t, [][]*Identity{
+			{org1ID1, org2ID1},
+			{org1ID2, org2ID1},
+			{org1ID1, org3ID1},
+			{org1ID2, org3ID1},
+			{org2ID1, org3ID1},
+		}, s.MinimalSets)
+	})
+
+	t.Run("Same MSP", func(t *testing.T) {
+		s, err := Simulate(fromString(t, "AND('Org1MSP.member', 'Org1MSP.member')"), identities)
+		require.NoError(t, err)
+		assert.True(t, s.Satisfied)
+		assert.Equal(t, [][]*Identity{{org1ID1, org1ID2}}, s.MinimalSets)
+	})
+
+	t.Run("Supersets are not minimal", func(t *testing.T) {
+		s, err := Simulate(fromString(t, "OR('Org1MSP.peer', AND('Org1MSP.peer', 'Org2MSP.peer'))"), identities)
+		require.NoError(t, err)
+		assert.Equal(t, [][]*Identity{{org1ID1}, {org1ID2}}, s.MinimalSets)
+	})
+
+	t.Run("Role", func(t *testing.T) {
+		s, err := Simulate(fromString(t, "OR('Org1MSP.admin', 'Org2MSP.peer')"), identities)
+		require.NoError(t, err)
+		assert.Equal(t, [][]*Identity{{org2ID1}}, s.MinimalSets, "peers must not satisfy admin principals")
+	})
+
+	t.Run("No signature required", func(t *testing.T) {
+		s, err := Simulate(policydsl.AcceptAllPolicy, identities)
+		require.NoError(t, err)
+		assert.True(t, s.Satisfied)
+		assert.Equal(t, [][]*Identity{{}}, s.MinimalSets)
+	})
+
+	t.Run("Not satisfied", func(t *testing.T) {
+		s, err := Simulate(fromString(t, "AND('Org1MSP.peer', OR('Org2MSP.admin', 'Org4MSP.peer'))"), identities)
+		require.NoError(t, err)
+		assert.False(t, s.Satisfied)
+		assert.Empty(t, s.MinimalSets)
+
+		unsatisfied := s.Explanation.Unsatisfied()
+		require.Len(t, unsatisfied, 2)
+		assert.Equal(t, "'Org2MSP.admin'", unsatisfied[0].Rule)
+		assert.Equal(t, "'Org4MSP.peer'", unsatisfied[1].Rule)
+	})
+}
+
+// outOfNetwork returns an OutOf(n, ...) policy over the given number of MSPs and the identities of
+// the given number of peers of each MSP
+func outOfNetwork(t *testing.T, n, numMSPs, numPeers int) (*common.SignaturePolicyEnvelope, []*Identity) {
+	var principals []string
+	var peers []fab.Peer
+	for i := 1; i <= numMSPs; i++ {
+		mspID := fmt.Sprintf("Org%dMSP", i)
+		principals = append(principals, fmt.Sprintf("'%s.peer'", mspID))
+		for j := 1; j <= numPeers; j++ {
+			peers = append(peers, newTestPeer(fmt.Sprintf("peer%d.org%d.com:7051", j, i), mspID))
+		}
+	}
+	return fromString(t, fmt.Sprintf("OutOf(%d, %s)", n, strings.Join(principals, ", "))), PeerIdentities(peers)
+}
+
+func TestSimulateMaxCandidates(t *testing.T) {
+	// C(5,3) combinations of MSPs with 5^3 candidates each
+	policy, identities := outOfNetwork(t, 3, 5, 5)
+	s, err := Simulate(policy, identities)
+	require.NoError(t, err)
+	assert.Len(t, s.MinimalSets, 1250)
+
+	_, err = Simulate(policy, identities, WithMaxCandidates(1000))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "more than 1000 candidate sets")
+
+	// Close to a million candidates: the default limit is reached before they are generated
+	policy, identities = outOfNetwork(t, 3, 10, 20)
+	_, err = Simulate(policy, identities)
+	require.Error(t, err)
+
+	// C(30,10) combinations of MSPs: the limit is reached before the combinations are generated
+	policy, identities = outOfNetwork(t, 10, 30, 1)
+	_, err = Simulate(policy, identities)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "more than 100000 candidate sets")
+}
+
+func TestExplain(t *testing.T) {
+	org1 := PeerIdentities([]fab.Peer{newTestPeer("peer1.org1.com:7051", "Org1MSP")})
+
+	explanation, err := Explain(fromString(t, "AND('Org1MSP.peer', 'Org2MSP.peer')"), org1)
+	require.NoError(t, err)
+	assert.False(t, explanation.Satisfied)
+	assert.Equal(t, 2, explanation.Required)
+	require.Len(t, explanation.SubPolicies, 2)
+	assert.Equal(t, org1[0], explanation.SubPolicies[0].SignedBy)
+	assert.Nil(t, explanation.SubPolicies[1].SignedBy)
+
+	expected := "[NOT SATISFIED] AND('Org1MSP.peer', 'Org2MSP.peer') - 1 of 2 required sub-policies satisfied\n" +
+		"  [SATISFIED] 'Org1MSP.peer' - signed by peer1.org1.com:7051\n" +
+		"  [NOT SATISFIED] 'Org2MSP.peer' - no matching identity\n"
+	assert.Equal(t, expected, explanation.String())
+
+	explanation, err = Explain(fromString(t, "OR('Org1MSP.member', 'Org2MSP.member')"), org1)
+	require.NoError(t, err)
+	assert.True(t, explanation.Satisfied)
+	assert.Empty(t, explanation.Unsatisfied())
+}
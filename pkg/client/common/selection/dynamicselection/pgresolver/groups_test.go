@@ -187,6 +187,14 @@ func TestAndOperation(t *testing.T) {
 	verifyGroups(t, expected, r)
 }
 
+func TestAnd(t *testing.T) {
+	r := And([]Group{g(a, b), g(a)})
+	verifyGroups(t, []Group{g(a, a), g(b, a)}, r)
+	if len(r[0].Items()) != 2 {
+		t.Fatalf("duplicate items must not be collapsed: %s", r[0])
+	}
+}
+
 func g(items ...Item) Group {
 	return NewGroup(items)
 }
@@ -363,6 +363,14 @@ func getCombinations(items []Item, length int32, r int) (GroupOfGroups, error) {
 	return NewGroupOfGroups(groups), nil
 }
 
+// And returns all of the groups that contain one item of each of the given groups.
+// Unlike Reduce, the resulting groups are neither collapsed nor deduplicated.
+// For example, given the set of groups, G=[(A,B),(C,D)],
+// then And(G) = [(A,C),(A,D),(B,C),(B,D)]
+func And(groups []Group) []Group {
+	return and(groups)
+}
+
 // and performs an 'and' operation of the given set of groups
 // For example, given the set of groups, G=[(A,B),(C,D)],
 // then and(G) = [(A,C),(A,D),(B,C),(B,D)]
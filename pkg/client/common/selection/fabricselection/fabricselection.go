@@ -13,8 +13,10 @@ import (
 	"strings"
 	"time"
 
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
 	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
 	discclient "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/discovery/client"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/endorsementpolicy"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/random"
 	soptions "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/options"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/multi"
@@ -168,6 +170,39 @@ func (s *Service) GetEndorsersForChaincode(chaincodes []*fab.ChaincodeCall, opts
 	return asPeers(s.ctx, endpoints.(discclient.Endorsers)), nil
 }
 
+// GetChaincodePolicy returns a signature policy that is equivalent to the endorsement layouts
+// that the discovery service returns for the given chaincode. Nil is returned if any of the
+// layouts has a group without known peers.
+func (s *Service) GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
+	if s.chResponseCache.IsClosed() {
+		return nil, errors.Errorf("Selection service has been closed")
+	}
+
+	chaincodes := []*fab.ChaincodeCall{{ID: chaincodeID}}
+	chResponse, err := s.getChannelResponse(chaincodes, s.retryOpts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting channel response for channel [%s]", s.channelID)
+	}
+
+	layouts, endorsersByGroups, err := chResponse.EndorsementLayouts(asInvocationChain(chaincodes))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting endorsement layouts for chaincode [%s]", chaincodeID)
+	}
+
+	mspIDsByGroup := make(map[string][]string)
+	for group, endorsers := range endorsersByGroups {
+		seen := make(map[string]bool)
+		for _, endorser := range endorsers {
+			if !seen[endorser.MSPID] {
+				seen[endorser.MSPID] = true
+				mspIDsByGroup[group] = append(mspIDsByGroup[group], endorser.MSPID)
+			}
+		}
+	}
+
+	return endorsementpolicy.FromLayouts(layouts, mspIDsByGroup)
+}
+
 // Close closes all resources associated with the service
 func (s *Service) Close() {
 	logger.Debug("Closing channel response cache")
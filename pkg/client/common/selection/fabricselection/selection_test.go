@@ -226,6 +226,13 @@ func TestSelection(t *testing.T) {
 		testSelectionCCtoCC(t, service)
 	})
 
+	t.Run("Chaincode policy", func(t *testing.T) {
+		policy, err := service.GetChaincodePolicy(cc1)
+		require.NoError(t, err)
+		require.NotNil(t, policy)
+		assert.Len(t, policy.Identities, 3, "expecting one principal per MSP")
+	})
+
 	t.Run("Peer Filter", func(t *testing.T) {
 		endorsers, err := service.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: cc1}},
 			options.WithPeerFilter(func(peer fab.Peer) bool {
@@ -0,0 +1,223 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ordererselection provides strategies that determine the order in which orderers
+// are tried when a transaction is broadcast. The first orderer that accepts the transaction
+// ends the broadcast; the other orderers are only tried if the previous ones failed.
+package ordererselection
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/logging"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/config/endpoint"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/util/concurrent/rollingcounter"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const (
+	defaultSmoothingFactor  = 0.3
+	defaultRecoveryInterval = 30 * time.Second
+)
+
+// SelectorFunc is an adapter that allows an ordinary function to be used as a fab.OrdererSelector
+type SelectorFunc func(orderers []fab.Orderer) []fab.Orderer
+
+// Select returns the orderers in the order in which they should be tried
+func (f SelectorFunc) Select(orderers []fab.Orderer) []fab.Orderer {
+	return f(orderers)
+}
+
+// Random selects orderers in a random order. This is the default strategy.
+func Random() fab.OrdererSelector {
+	logger.Debugf("Creating Random orderer selector")
+	return SelectorFunc(func(orderers []fab.Orderer) []fab.Orderer {
+		selected := make([]fab.Orderer, len(orderers))
+		for i, index := range rand.Perm(len(orderers)) {
+			selected[i] = orderers[index]
+		}
+		return selected
+	})
+}
+
+// RoundRobin spreads the broadcasts evenly over the orderers by starting each
+// broadcast with the orderer that follows the one the previous broadcast started with
+func RoundRobin() fab.OrdererSelector {
+	logger.Debugf("Creating Round-robin orderer selector")
+	counter := rollingcounter.New()
+	return SelectorFunc(func(orderers []fab.Orderer) []fab.Orderer {
+		if len(orderers) == 0 {
+			return orderers
+		}
+		index := counter.Next(len(orderers))
+		selected := make([]fab.Orderer, 0, len(orderers))
+		selected = append(selected, orderers[index:]...)
+		return append(selected, orderers[:index]...)
+	})
+}
+
+// PreferOrg selects the orderers with the given URLs (typically the orderers run by the
+// client's own organization) before the other orderers. Both groups are ordered with the
+// given selector (random if nil), so that the other orderers are only tried if all of the
+// preferred orderers fail.
+func PreferOrg(selector fab.OrdererSelector, ordererURLs ...string) fab.OrdererSelector {
+	logger.Debugf("Creating Prefer-org orderer selector for orderers %v", ordererURLs)
+	if selector == nil {
+		selector = Random()
+	}
+	preferred := make(map[string]bool)
+	for _, url := range ordererURLs {
+		preferred[endpoint.ToAddress(url)] = true
+	}
+
+	return SelectorFunc(func(orderers []fab.Orderer) []fab.Orderer {
+		var own, others []fab.Orderer
+		for _, o := range orderers {
+			if preferred[endpoint.ToAddress(o.URL())] {
+				own = append(own, o)
+			} else {
+				others = append(others, o)
+			}
+		}
+		if len(own) == 0 {
+			logger.Debugf("None of the preferred orderers is available")
+		}
+		return append(selector.Select(own), selector.Select(others)...)
+	})
+}
+
+type latencyStats struct {
+	latency     time.Duration
+	samples     int
+	failures    int
+	lastFailure time.Time
+}
+
+// LatencySelector selects the orderers that respond the fastest first. Orderers whose last broadcast
+// failed are selected after the other orderers until the recovery interval has elapsed.
+// Orderers that have not been used yet are selected first so that their latency becomes known.
+//
+// LatencySelector implements fab.OrdererSelector and fab.OrdererObserver.
+type LatencySelector struct {
+	smoothingFactor  float64
+	recoveryInterval time.Duration
+	mutex            sync.RWMutex
+	stats            map[string]*latencyStats
+}
+
+// Latency returns a latency-based orderer selector. The smoothing factor (between 0 and 1) is the
+// weight given to the latest sample when updating the moving average of an orderer's latency
+// (0.3 if not in range) and the recovery interval is how long an orderer whose broadcast failed
+// is selected last (30s if not positive).
+func Latency(smoothingFactor float64, recoveryInterval time.Duration) *LatencySelector {
+	if smoothingFactor <= 0 || smoothingFactor > 1 {
+		smoothingFactor = defaultSmoothingFactor
+	}
+	if recoveryInterval <= 0 {
+		recoveryInterval = defaultRecoveryInterval
+	}
+	logger.Debugf("Creating Latency orderer selector - smoothing factor: %f, recovery interval: %s", smoothingFactor, recoveryInterval)
+
+	return &LatencySelector{
+		smoothingFactor:  smoothingFactor,
+		recoveryInterval: recoveryInterval,
+		stats:            make(map[string]*latencyStats),
+	}
+}
+
+// Select returns the orderers sorted by latency
+func (s *LatencySelector) Select(orderers []fab.Orderer) []fab.Orderer {
+	// Shuffle first so that orderers with the same latency are not always tried in the same order
+	selected := Random().Select(orderers)
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	now := time.Now()
+	sort.SliceStable(selected, func(i, j int) bool {
+		return s.less(selected[i], selected[j], now)
+	})
+	return selected
+}
+
+// Begin is invoked before a transaction is broadcast to the orderer with the given URL.
+// The returned function records the outcome and the latency of the broadcast.
+func (s *LatencySelector) Begin(url string) func(err error) {
+	start := time.Now()
+	return func(err error) {
+		s.record(url, time.Since(start), err)
+	}
+}
+
+// Latency returns the average latency of the orderer with the given URL and whether it is known
+func (s *LatencySelector) Latency(url string) (time.Duration, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stats, ok := s.stats[endpoint.ToAddress(url)]
+	if !ok || stats.samples == 0 {
+		return 0, false
+	}
+	return stats.latency, true
+}
+
+func (s *LatencySelector) less(o1, o2 fab.Orderer, now time.Time) bool {
+	stats1 := s.stats[endpoint.ToAddress(o1.URL())]
+	stats2 := s.stats[endpoint.ToAddress(o2.URL())]
+
+	failed1 := s.isFailing(stats1, now)
+	failed2 := s.isFailing(stats2, now)
+	if failed1 != failed2 {
+		return failed2
+	}
+	return latencyOf(stats1) < latencyOf(stats2)
+}
+
+func (s *LatencySelector) isFailing(stats *latencyStats, now time.Time) bool {
+	return stats != nil && stats.failures > 0 && now.Sub(stats.lastFailure) < s.recoveryInterval
+}
+
+func latencyOf(stats *latencyStats) time.Duration {
+	if stats == nil {
+		return 0
+	}
+	return stats.latency
+}
+
+func (s *LatencySelector) record(url string, latency time.Duration, err error) {
+	address := endpoint.ToAddress(url)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats, ok := s.stats[address]
+	if !ok {
+		stats = &latencyStats{}
+		s.stats[address] = stats
+	}
+
+	// The time taken by a failed broadcast (e.g. a timeout) says nothing about the orderer's latency
+	if err != nil {
+		stats.failures++
+		stats.lastFailure = time.Now()
+		logger.Debugf("Orderer [%s] - %d consecutive failure(s)", address, stats.failures)
+		return
+	}
+
+	stats.failures = 0
+	if stats.samples == 0 {
+		stats.latency = latency
+	} else {
+		stats.latency = time.Duration(s.smoothingFactor*float64(latency) + (1-s.smoothingFactor)*float64(stats.latency))
+	}
+	stats.samples++
+	logger.Debugf("Orderer [%s] - latency: %s", address, stats.latency)
+}
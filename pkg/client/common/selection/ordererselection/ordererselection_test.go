@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ordererselection
+
+import (
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	orderer1URL = "orderer1.org1.com:7050"
+	orderer2URL = "orderer2.org1.com:7050"
+	orderer3URL = "orderer1.org2.com:7050"
+)
+
+var (
+	orderer1 = mocks.NewMockOrderer("grpcs://"+orderer1URL, nil)
+	orderer2 = mocks.NewMockOrderer("grpcs://"+orderer2URL, nil)
+	orderer3 = mocks.NewMockOrderer("grpcs://"+orderer3URL, nil)
+	orderers = []fab.Orderer{orderer1, orderer2, orderer3}
+)
+
+func TestRandom(t *testing.T) {
+	selected := Random().Select(orderers)
+	assert.Len(t, selected, len(orderers))
+	assert.ElementsMatch(t, orderers, selected)
+}
+
+func TestRoundRobin(t *testing.T) {
+	selector := RoundRobin()
+
+	first := make(map[fab.Orderer]int)
+	for i := 0; i < 3*len(orderers); i++ {
+		selected := selector.Select(orderers)
+		assert.ElementsMatch(t, orderers, selected)
+		first[selected[0]]++
+	}
+	for _, o := range orderers {
+		assert.Equalf(t, 3, first[o], "expecting each orderer to be selected first the same number of times: %s", o.URL())
+	}
+
+	assert.Empty(t, selector.Select(nil))
+}
+
+func TestPreferOrg(t *testing.T) {
+	selector := PreferOrg(RoundRobin(), orderer1URL, "grpcs://"+orderer2URL)
+
+	for i := 0; i < 5; i++ {
+		selected := selector.Select(orderers)
+		assert.Len(t, selected, len(orderers))
+		assert.ElementsMatch(t, []fab.Orderer{orderer1, orderer2}, selected[:2], "own orderers must be selected first")
+		assert.Equal(t, orderer3, selected[2])
+	}
+
+	selected := PreferOrg(nil, orderer1URL).Select([]fab.Orderer{orderer3})
+	assert.Equal(t, []fab.Orderer{orderer3}, selected, "other orderers must be selected when no own orderer is available")
+}
+
+func TestLatency(t *testing.T) {
+	selector := Latency(1, time.Minute)
+
+	selector.record(orderer1.URL(), 30*time.Millisecond, nil)
+	selector.record(orderer2.URL(), 10*time.Millisecond, nil)
+
+	selected := selector.Select(orderers)
+	assert.Equal(t, []fab.Orderer{orderer3, orderer2, orderer1}, selected, "unknown orderers first, then by latency")
+
+	latency, ok := selector.Latency(orderer2URL)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, latency)
+	_, ok = selector.Latency(orderer3URL)
+	assert.False(t, ok)
+
+	selector.record(orderer3.URL(), 50*time.Millisecond, nil)
+	selector.record(orderer2.URL(), time.Second, errors.New("SERVICE_UNAVAILABLE"))
+
+	selected = selector.Select(orderers)
+	assert.Equal(t, []fab.Orderer{orderer1, orderer3, orderer2}, selected, "failing orderers must be selected last")
+
+	latency, _ = selector.Latency(orderer2URL)
+	assert.Equal(t, 10*time.Millisecond, latency, "the latency of failed broadcasts must not be recorded")
+
+	selector.Begin(orderer2.URL())(nil)
+	selected = selector.Select(orderers)
+	assert.Equal(t, orderer2, selected[0], "orderer must recover after a successful broadcast")
+}
+
+func TestLatencyRecovery(t *testing.T) {
+	selector := Latency(0, time.Millisecond)
+	assert.Equal(t, defaultSmoothingFactor, selector.smoothingFactor)
+
+	selector.record(orderer1.URL(), 10*time.Millisecond, nil)
+	selector.record(orderer2.URL(), 20*time.Millisecond, nil)
+	selector.record(orderer1.URL(), 0, errors.New("timeout"))
+
+	time.Sleep(10 * time.Millisecond)
+
+	selected := selector.Select([]fab.Orderer{orderer1, orderer2})
+	assert.Equal(t, orderer1, selected[0], "failing orderer must be selected again after the recovery interval")
+}
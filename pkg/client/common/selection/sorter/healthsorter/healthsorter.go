@@ -0,0 +1,271 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package healthsorter
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/options"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/logging"
+	coptions "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/options"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Stats contains the health statistics that are tracked for a peer
+type Stats struct {
+	// Latency is the moving average of the time taken by the peer to respond
+	Latency time.Duration
+	// ErrorRate is the moving average (between 0 and 1) of the peer's failure rate
+	ErrorRate float64
+	// InFlight is the number of requests that have been sent to the peer and not yet answered
+	InFlight int
+	// Samples is the number of responses that have been recorded
+	Samples int
+	// LastFailure is the time of the most recent failure
+	LastFailure time.Time
+}
+
+// Sorter sorts peers by health. Peers whose error rate is below the error threshold are sorted
+// by their average latency, weighted by the number of requests in flight, so that the fastest
+// peer that is not already busy comes first. Unhealthy peers are sorted after the healthy peers.
+//
+// Peers that lag behind the most up-to-date peer by more than the block height lag threshold are
+// sorted after the up-to-date peers, by block height, so that the health score never prefers a peer
+// that would be skipped by the default block height priority sorter.
+//
+// The sorter only orders the candidate peers; the selection service still picks from them the
+// peers that satisfy the endorsement policy, so endorsement policies are always honoured.
+//
+// Sorter implements fab.TargetSorter and fab.PrioritySelector. The statistics are fed by calling
+// Begin before a request is sent to a peer and invoking the returned function with the outcome.
+type Sorter struct {
+	*params
+	mutex sync.RWMutex
+	stats map[string]*Stats
+}
+
+// New returns a new health-scored Sorter
+func New(opts ...coptions.Opt) *Sorter {
+	params := defaultParams()
+	coptions.Apply(params, opts)
+
+	return &Sorter{
+		params: params,
+		stats:  make(map[string]*Stats),
+	}
+}
+
+// PeerSorter returns the sorter as a selection peer sorter function
+func (s *Sorter) PeerSorter() options.PeerSorter {
+	return s.Sort
+}
+
+// Begin records that a request is about to be sent to the peer with the given URL.
+// The returned function must be invoked with the outcome of the request.
+func (s *Sorter) Begin(peerURL string) func(err error) {
+	s.mutex.Lock()
+	stats := s.getOrCreate(peerURL)
+	stats.InFlight++
+	s.mutex.Unlock()
+
+	start := time.Now()
+	return func(err error) {
+		s.record(peerURL, time.Since(start), err)
+	}
+}
+
+// Stats returns the statistics of the peer with the given URL
+func (s *Sorter) Stats(peerURL string) (Stats, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stats, ok := s.stats[peerURL]
+	if !ok {
+		return Stats{}, false
+	}
+	return *stats, true
+}
+
+// Sort sorts the given peers by health and latency. Peers that lag behind in block height come last.
+func (s *Sorter) Sort(peers []fab.Peer) []fab.Peer {
+	if len(peers) <= 1 {
+		return peers
+	}
+
+	currentPeers, laggingPeers := s.partitionByBlockHeight(peers)
+
+	// Balance first so that peers with the same score are not always returned in the same order
+	sortedPeers := s.balancer(currentPeers)
+
+	s.mutex.RLock()
+	now := time.Now()
+	sort.SliceStable(sortedPeers, func(i, j int) bool {
+		return s.compare(sortedPeers[i], sortedPeers[j], now) > 0
+	})
+	s.mutex.RUnlock()
+
+	sort.SliceStable(laggingPeers, func(i, j int) bool {
+		return laggingPeers[i].(fab.PeerState).BlockHeight() > laggingPeers[j].(fab.PeerState).BlockHeight()
+	})
+
+	return append(append(make([]fab.Peer, 0, len(peers)), sortedPeers...), laggingPeers...)
+}
+
+// partitionByBlockHeight splits the peers into the peers that are within the block height lag threshold
+// (including peers without block height state, e.g. local peers) and the peers that lag behind
+func (s *Sorter) partitionByBlockHeight(peers []fab.Peer) ([]fab.Peer, []fab.Peer) {
+	if s.blockHeightLagThreshold < 0 {
+		return peers, nil
+	}
+
+	var maxHeight uint64
+	for _, p := range peers {
+		if peerState, ok := p.(fab.PeerState); ok && peerState.BlockHeight() > maxHeight {
+			maxHeight = peerState.BlockHeight()
+		}
+	}
+	if maxHeight <= uint64(s.blockHeightLagThreshold) {
+		return peers, nil
+	}
+	cutoffHeight := maxHeight - uint64(s.blockHeightLagThreshold)
+
+	var currentPeers, laggingPeers []fab.Peer
+	for _, p := range peers {
+		if peerState, ok := p.(fab.PeerState); ok && peerState.BlockHeight() < cutoffHeight {
+			logger.Debugf("Demoting peer [%s] at block height %d which is less than the cutoff %d", p.URL(), peerState.BlockHeight(), cutoffHeight)
+			laggingPeers = append(laggingPeers, p)
+		} else {
+			currentPeers = append(currentPeers, p)
+		}
+	}
+	return currentPeers, laggingPeers
+}
+
+// Compare returns a positive value if peer1 should be selected over peer2, a negative
+// value if peer2 should be selected over peer1 and zero if they have the same priority
+func (s *Sorter) Compare(peer1, peer2 fab.Peer) int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.compare(peer1, peer2, time.Now())
+}
+
+func (s *Sorter) compare(peer1, peer2 fab.Peer, now time.Time) int {
+	stats1 := s.stats[peer1.URL()]
+	stats2 := s.stats[peer2.URL()]
+
+	healthy1 := s.isHealthy(stats1, now)
+	healthy2 := s.isHealthy(stats2, now)
+	if healthy1 != healthy2 {
+		if healthy1 {
+			return 1
+		}
+		return -1
+	}
+	if !healthy1 && stats1.ErrorRate != stats2.ErrorRate {
+		if stats1.ErrorRate < stats2.ErrorRate {
+			return 1
+		}
+		return -1
+	}
+
+	score1 := score(stats1)
+	score2 := score(stats2)
+	switch {
+	case score1 < score2:
+		return 1
+	case score1 > score2:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func (s *Sorter) isHealthy(stats *Stats, now time.Time) bool {
+	if stats == nil || stats.ErrorRate < s.errorThreshold {
+		return true
+	}
+	// Give unhealthy peers another chance once they have not failed for a while
+	return now.Sub(stats.LastFailure) >= s.recoveryInterval
+}
+
+// score returns the expected time to respond, taking into account the requests that
+// the peer is still processing. Peers without samples get a score of zero so that
+// they are tried and their latency becomes known.
+func score(stats *Stats) float64 {
+	if stats == nil || stats.Samples == 0 {
+		return 0
+	}
+	return float64(stats.Latency) * float64(1+stats.InFlight)
+}
+
+func (s *Sorter) record(peerURL string, latency time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := s.getOrCreate(peerURL)
+	if stats.InFlight > 0 {
+		stats.InFlight--
+	}
+
+	failed := isPeerFailure(err)
+
+	var errorSample float64
+	if failed {
+		errorSample = 1
+		stats.LastFailure = time.Now()
+	}
+
+	if stats.Samples == 0 {
+		stats.ErrorRate = errorSample
+		if !failed {
+			stats.Latency = latency
+		}
+	} else {
+		stats.ErrorRate = s.smoothingFactor*errorSample + (1-s.smoothingFactor)*stats.ErrorRate
+		// The time taken by a failed request (e.g. a timeout) says nothing about the peer's latency
+		if !failed {
+			if stats.Latency == 0 {
+				stats.Latency = latency
+			} else {
+				stats.Latency = time.Duration(s.smoothingFactor*float64(latency) + (1-s.smoothingFactor)*float64(stats.Latency))
+			}
+		}
+	}
+	stats.Samples++
+
+	logger.Debugf("Peer [%s] - latency: %s, error rate: %.2f, in flight: %d", peerURL, stats.Latency, stats.ErrorRate, stats.InFlight)
+}
+
+func (s *Sorter) getOrCreate(peerURL string) *Stats {
+	stats, ok := s.stats[peerURL]
+	if !ok {
+		stats = &Stats{}
+		s.stats[peerURL] = stats
+	}
+	return stats
+}
+
+// isPeerFailure returns true if the error indicates that the peer is not healthy. Errors that
+// are returned by the chaincode mean that the peer processed the request and are not counted.
+func isPeerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if s, ok := status.FromError(err); ok {
+		switch s.Group {
+		case status.ChaincodeStatus, status.EndorserServerStatus:
+			return false
+		}
+	}
+	return true
+}
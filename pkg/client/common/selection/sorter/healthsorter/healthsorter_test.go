@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package healthsorter
+
+import (
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/balancer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	fab "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	emocks "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/client/mocks"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	peer1URL = "peer1.org1.com:9999"
+	peer2URL = "peer2.org1.com:9999"
+	peer3URL = "peer3.org2.com:9999"
+)
+
+var (
+	peer1 = mocks.NewMockPeer("p1", peer1URL)
+	peer2 = mocks.NewMockPeer("p2", peer2URL)
+	peer3 = mocks.NewMockPeer("p3", peer3URL)
+
+	allPeers = []fab.Peer{peer1, peer2, peer3}
+
+	connectionFailed = status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+)
+
+// recordLatency records a response from the peer as if it took the given time
+func recordLatency(s *Sorter, peerURL string, latency time.Duration, err error) {
+	s.record(peerURL, latency, err)
+}
+
+func TestSortByLatency(t *testing.T) {
+	s := New(WithBalancer(balancer.RoundRobin()))
+
+	recordLatency(s, peer1URL, 300*time.Millisecond, nil)
+	recordLatency(s, peer2URL, 20*time.Millisecond, nil)
+	recordLatency(s, peer3URL, 90*time.Millisecond, nil)
+
+	for i := 0; i < 5; i++ {
+		sorted := s.Sort(allPeers)
+		require.Len(t, sorted, 3)
+		assert.Equal(t, peer2URL, sorted[0].URL())
+		assert.Equal(t, peer3URL, sorted[1].URL())
+		assert.Equal(t, peer1URL, sorted[2].URL())
+	}
+
+	assert.True(t, s.Compare(peer2, peer1) > 0)
+	assert.True(t, s.Compare(peer1, peer2) < 0)
+	assert.Equal(t, 0, s.Compare(peer1, peer1))
+}
+
+func TestSortUnknownPeersFirst(t *testing.T) {
+	s := New()
+	recordLatency(s, peer1URL, 20*time.Millisecond, nil)
+
+	sorted := s.PeerSorter()([]fab.Peer{peer1, peer2})
+	assert.Equal(t, peer2URL, sorted[0].URL(), "peers without samples are tried first so that their latency becomes known")
+}
+
+func TestSortInFlight(t *testing.T) {
+	s := New()
+	recordLatency(s, peer1URL, 20*time.Millisecond, nil)
+	recordLatency(s, peer2URL, 30*time.Millisecond, nil)
+
+	assert.True(t, s.Compare(peer1, peer2) > 0)
+
+	done1 := s.Begin(peer1URL)
+	done2 := s.Begin(peer1URL)
+	assert.True(t, s.Compare(peer1, peer2) < 0, "a busy peer is demoted")
+
+	stats, ok := s.Stats(peer1URL)
+	require.True(t, ok)
+	assert.Equal(t, 2, stats.InFlight)
+
+	done1(nil)
+	done2(nil)
+	stats, _ = s.Stats(peer1URL)
+	assert.Equal(t, 0, stats.InFlight)
+	assert.Equal(t, 3, stats.Samples)
+}
+
+func TestSortUnhealthy(t *testing.T) {
+	s := New(WithErrorThreshold(0.5), WithRecoveryInterval(100*time.Millisecond))
+
+	recordLatency(s, peer1URL, 10*time.Millisecond, nil)
+	recordLatency(s, peer2URL, 500*time.Millisecond, nil)
+	recordLatency(s, peer3URL, 800*time.Millisecond, nil)
+
+	for i := 0; i < 3; i++ {
+		recordLatency(s, peer1URL, time.Second, connectionFailed)
+	}
+
+	stats, ok := s.Stats(peer1URL)
+	require.True(t, ok)
+	assert.True(t, stats.ErrorRate >= 0.5)
+	assert.Equal(t, 10*time.Millisecond, stats.Latency, "latency of failed requests is not recorded")
+
+	sorted := s.Sort(allPeers)
+	assert.Equal(t, peer2URL, sorted[0].URL())
+	assert.Equal(t, peer1URL, sorted[2].URL(), "unhealthy peer is sorted last")
+
+	time.Sleep(150 * time.Millisecond)
+	sorted = s.Sort(allPeers)
+	assert.Equal(t, peer1URL, sorted[0].URL(), "peer is tried again after the recovery interval")
+}
+
+func TestChaincodeErrorsAreNotFailures(t *testing.T) {
+	s := New()
+
+	recordLatency(s, peer1URL, 10*time.Millisecond, status.New(status.ChaincodeStatus, 500, "chaincode error", nil))
+	stats, _ := s.Stats(peer1URL)
+	assert.Equal(t, float64(0), stats.ErrorRate)
+
+	recordLatency(s, peer1URL, 10*time.Millisecond, errors.New("some error"))
+	stats, _ = s.Stats(peer1URL)
+	assert.True(t, stats.ErrorRate > 0)
+	assert.False(t, stats.LastFailure.IsZero())
+
+	_, ok := s.Stats(peer2URL)
+	assert.False(t, ok)
+}
+
+func TestSortLaggingPeersLast(t *testing.T) {
+	p1 := emocks.NewMockPeer("p1", peer1URL, 100)
+	p2 := emocks.NewMockPeer("p2", peer2URL, 98)
+	p3 := emocks.NewMockPeer("p3", peer3URL, 80)
+	peers := []fab.Peer{p1, p2, p3}
+
+	s := New(WithBalancer(balancer.RoundRobin()))
+
+	// The lagging peer is the fastest but must still come after the up-to-date peers
+	recordLatency(s, peer1URL, 300*time.Millisecond, nil)
+	recordLatency(s, peer2URL, 90*time.Millisecond, nil)
+	recordLatency(s, peer3URL, 20*time.Millisecond, nil)
+
+	sorted := s.Sort(peers)
+	require.Len(t, sorted, 3)
+	assert.Equal(t, peer2URL, sorted[0].URL())
+	assert.Equal(t, peer1URL, sorted[1].URL())
+	assert.Equal(t, peer3URL, sorted[2].URL())
+	assert.Equal(t, []fab.Peer{p1, p2, p3}, peers, "input must not be modified")
+
+	// With a threshold of 0 only the peer at the highest block height is up-to-date
+	s = New(WithBalancer(balancer.RoundRobin()), WithBlockHeightLagThreshold(0))
+	recordLatency(s, peer1URL, 300*time.Millisecond, nil)
+	recordLatency(s, peer2URL, 90*time.Millisecond, nil)
+	recordLatency(s, peer3URL, 20*time.Millisecond, nil)
+
+	sorted = s.Sort(peers)
+	require.Len(t, sorted, 3)
+	assert.Equal(t, peer1URL, sorted[0].URL())
+	assert.Equal(t, peer2URL, sorted[1].URL())
+	assert.Equal(t, peer3URL, sorted[2].URL())
+
+	// Disabled: all peers are sorted by health only
+	s = New(WithBalancer(balancer.RoundRobin()), WithBlockHeightLagThreshold(Disable))
+	recordLatency(s, peer1URL, 300*time.Millisecond, nil)
+	recordLatency(s, peer2URL, 90*time.Millisecond, nil)
+	recordLatency(s, peer3URL, 20*time.Millisecond, nil)
+
+	sorted = s.Sort(peers)
+	require.Len(t, sorted, 3)
+	assert.Equal(t, peer3URL, sorted[0].URL())
+	assert.Equal(t, peer2URL, sorted[1].URL())
+	assert.Equal(t, peer1URL, sorted[2].URL())
+}
@@ -0,0 +1,147 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package healthsorter
+
+import (
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/balancer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/options"
+)
+
+const (
+	defaultSmoothingFactor         = 0.3
+	defaultErrorThreshold          = 0.5
+	defaultRecoveryInterval        = 30 * time.Second
+	defaultBlockHeightLagThreshold = 5
+
+	// Disable disables choosing by block height threshold meaning that all peers are sorted by health only
+	Disable = -1
+)
+
+type params struct {
+	smoothingFactor         float64
+	errorThreshold          float64
+	recoveryInterval        time.Duration
+	blockHeightLagThreshold int
+	balancer                balancer.Balancer
+}
+
+func defaultParams() *params {
+	return &params{
+		smoothingFactor:         defaultSmoothingFactor,
+		errorThreshold:          defaultErrorThreshold,
+		recoveryInterval:        defaultRecoveryInterval,
+		blockHeightLagThreshold: defaultBlockHeightLagThreshold,
+		balancer:                balancer.Random(),
+	}
+}
+
+// WithSmoothingFactor sets the weight (between 0 and 1) given to the most recent sample when
+// updating the moving averages of latency and error rate. Higher values react faster to changes
+// while lower values smooth out spikes.
+func WithSmoothingFactor(value float64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(smoothingFactorSetter); ok {
+			setter.SetSmoothingFactor(value)
+		}
+	}
+}
+
+// WithErrorThreshold sets the moving-average error rate (between 0 and 1) at or above which
+// a peer is considered unhealthy. Unhealthy peers are sorted after all healthy peers.
+func WithErrorThreshold(value float64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(errorThresholdSetter); ok {
+			setter.SetErrorThreshold(value)
+		}
+	}
+}
+
+// WithRecoveryInterval sets the time after the last failure of an unhealthy peer after which
+// the peer is considered healthy again, so that it is tried again and its statistics are refreshed.
+func WithRecoveryInterval(value time.Duration) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(recoveryIntervalSetter); ok {
+			setter.SetRecoveryInterval(value)
+		}
+	}
+}
+
+// WithBlockHeightLagThreshold is the number of blocks from the highest block of a group of peers
+// that a peer can lag behind and still be considered to be up-to-date. Up-to-date peers are sorted
+// by health; peers that fall behind this threshold are demoted after them and sorted by block height,
+// in the same way as the block height priority sorter.
+//
+// If set to 0 then only the most up-to-date peers are sorted by health.
+// If set to -1 then all peers (regardless of block height) are sorted by health.
+func WithBlockHeightLagThreshold(value int) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(blockHeightLagThresholdSetter); ok {
+			setter.SetBlockHeightLagThreshold(value)
+		}
+	}
+}
+
+// WithBalancer sets the balancing strategy used to order peers that have the same score,
+// e.g. peers that have not been used yet.
+func WithBalancer(value balancer.Balancer) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(balancerSetter); ok {
+			setter.SetBalancer(value)
+		}
+	}
+}
+
+type smoothingFactorSetter interface {
+	SetSmoothingFactor(value float64)
+}
+
+func (p *params) SetSmoothingFactor(value float64) {
+	logger.Debugf("SmoothingFactor: %f", value)
+	if value <= 0 || value > 1 {
+		logger.Warnf("Invalid smoothing factor %f - using default %f", value, defaultSmoothingFactor)
+		value = defaultSmoothingFactor
+	}
+	p.smoothingFactor = value
+}
+
+type errorThresholdSetter interface {
+	SetErrorThreshold(value float64)
+}
+
+func (p *params) SetErrorThreshold(value float64) {
+	logger.Debugf("ErrorThreshold: %f", value)
+	p.errorThreshold = value
+}
+
+type recoveryIntervalSetter interface {
+	SetRecoveryInterval(value time.Duration)
+}
+
+func (p *params) SetRecoveryInterval(value time.Duration) {
+	logger.Debugf("RecoveryInterval: %s", value)
+	p.recoveryInterval = value
+}
+
+type blockHeightLagThresholdSetter interface {
+	SetBlockHeightLagThreshold(value int)
+}
+
+func (p *params) SetBlockHeightLagThreshold(value int) {
+	logger.Debugf("BlockHeightLagThreshold: %d", value)
+	p.blockHeightLagThreshold = value
+}
+
+type balancerSetter interface {
+	SetBalancer(value balancer.Balancer)
+}
+
+func (p *params) SetBalancer(value balancer.Balancer) {
+	logger.Debugf("Balancer: %#v", value)
+	p.balancer = value
+}
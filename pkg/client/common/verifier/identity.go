@@ -0,0 +1,413 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	mb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/utils"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/chconfig"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	"gitee.com/zhaochuninhefei/gmgo/sm3"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Identity validates endorser and orderer identities against the MSPs of a channel.
+// In addition to the chain of trust, it checks certificate expiry, the revocation
+// lists and the OU/NodeOU roles configured in each MSP. Failures are reported as
+// status errors with the codes CertificateExpired, UntrustedCertificate,
+// CertificateRevoked and IdentityRoleMismatch.
+type Identity struct {
+	msps map[string]*channelMSP
+	now  func() time.Time
+}
+
+type channelMSP struct {
+	name          string
+	roots         *x509.CertPool
+	intermediates *x509.CertPool
+	// revoked serial numbers keyed by the raw certificate of the issuing CA
+	revoked   map[string]map[string]struct{}
+	ous       map[string]struct{}
+	peerOU    string
+	ordererOU string
+}
+
+// NewIdentity returns an identity verifier for the given channel MSP configs
+func NewIdentity(mspConfigs []*mb.MSPConfig) (*Identity, error) {
+	msps := make(map[string]*channelMSP)
+	for _, config := range mspConfigs {
+		fabricConfig := &mb.FabricMSPConfig{}
+		if err := proto.Unmarshal(config.Config, fabricConfig); err != nil {
+			return nil, errors.Wrap(err, "unmarshal FabricMSPConfig from config failed")
+		}
+		if fabricConfig.Name == "" {
+			return nil, errors.New("MSP Configuration missing name")
+		}
+
+		m, err := newChannelMSP(fabricConfig)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to load MSP [%s]", fabricConfig.Name)
+		}
+		msps[m.name] = m
+	}
+
+	return &Identity{msps: msps, now: time.Now}, nil
+}
+
+// ValidateEndorser validates the serialized identity of an endorsing peer
+func (v *Identity) ValidateEndorser(serializedID []byte) error {
+	_, err := v.validate(serializedID, mb.MSPRole_PEER, status.EndorserClientStatus)
+	return err
+}
+
+// ValidateOrderer validates the serialized identity of an orderer
+func (v *Identity) ValidateOrderer(serializedID []byte) error {
+	_, err := v.validate(serializedID, mb.MSPRole_ORDERER, status.OrdererClientStatus)
+	return err
+}
+
+// VerifyEndorsement validates the endorser identity of a transaction proposal response
+func (v *Identity) VerifyEndorsement(response *fab.TransactionProposalResponse) error {
+	if response.ProposalResponse.GetEndorsement() == nil {
+		return errors.WithStack(status.New(status.EndorserClientStatus, status.MissingEndorsement.ToInt32(), "missing endorsement in proposal response", nil))
+	}
+	return v.ValidateEndorser(response.ProposalResponse.GetEndorsement().Endorser)
+}
+
+// VerifyBlock validates the identities of the orderers that signed the given block and
+// verifies each of their signatures over the block header and signature metadata.
+// The genesis block is not signed and is therefore not validated.
+func (v *Identity) VerifyBlock(block *common.Block) error {
+	if block.GetHeader().GetNumber() == 0 {
+		return nil
+	}
+
+	md, err := protoutil.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get signatures of block %d", block.Header.Number)
+	}
+	if len(md.Signatures) == 0 {
+		return errors.WithStack(status.New(status.OrdererClientStatus, status.SignatureVerificationFailed.ToInt32(), "no orderer signature in block metadata", []interface{}{block.Header.Number}))
+	}
+
+	headerBytes := protoutil.BlockHeaderBytes(block.Header)
+	for _, metadataSignature := range md.Signatures {
+		signatureHeader, err := protoutil.UnmarshalSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to unmarshal signature header of block %d", block.Header.Number)
+		}
+		cert, err := v.validate(signatureHeader.Creator, mb.MSPRole_ORDERER, status.OrdererClientStatus)
+		if err != nil {
+			return errors.WithMessagef(err, "invalid orderer signature on block %d", block.Header.Number)
+		}
+		signedData := bytes.Join([][]byte{md.Value, metadataSignature.SignatureHeader, headerBytes}, nil)
+		if err := verifySignature(cert, signedData, metadataSignature.Signature); err != nil {
+			return errors.WithStack(status.New(status.OrdererClientStatus, status.SignatureVerificationFailed.ToInt32(), "the orderer's signature over the block is not valid", []interface{}{block.Header.Number, err.Error()}))
+		}
+	}
+
+	return nil
+}
+
+// OrdererBlockVerifier validates the orderer signatures of the blocks delivered on a channel.
+// The orderer signatures of each config block are verified against the current MSPs before
+// the MSPs of the config block replace them, so that subsequent blocks are validated against
+// the updated channel configuration (for example a new orderer organization, a rotated CA or
+// an updated revocation list).
+// It is not safe for concurrent use; blocks must be verified in the order they are delivered.
+type OrdererBlockVerifier struct {
+	channelID string
+	identity  *Identity
+}
+
+// NewOrdererBlockVerifier returns an orderer block verifier for the given channel, starting from the given channel MSP configs
+func NewOrdererBlockVerifier(channelID string, mspConfigs []*mb.MSPConfig) (*OrdererBlockVerifier, error) {
+	identity, err := NewIdentity(mspConfigs)
+	if err != nil {
+		return nil, err
+	}
+	return &OrdererBlockVerifier{channelID: channelID, identity: identity}, nil
+}
+
+// VerifyBlock verifies the orderer signatures of the given block and, only if they are
+// valid and if it is a config block, reloads the channel MSPs from it
+func (v *OrdererBlockVerifier) VerifyBlock(block *common.Block) error {
+	if err := v.identity.VerifyBlock(block); err != nil {
+		return err
+	}
+	if !protoutil.IsConfigBlock(block) {
+		return nil
+	}
+
+	chConfig, err := chconfig.ExtractConfigFromBlock(v.channelID, block)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to extract channel config from config block %d", block.Header.Number)
+	}
+	identity, err := NewIdentity(chConfig.MSPs())
+	if err != nil {
+		return errors.WithMessagef(err, "failed to load channel MSPs from config block %d", block.Header.Number)
+	}
+	identity.now = v.identity.now
+	v.identity = identity
+
+	logger.Debugf("Reloaded orderer identity verifier for channel [%s] from config block %d", v.channelID, block.Header.Number)
+	return nil
+}
+
+// validate validates the serialized identity and returns its certificate
+func (v *Identity) validate(serializedID []byte, role mb.MSPRole_MSPRoleType, group status.Group) (*x509.Certificate, error) {
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		return nil, errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+
+	cert, err := parseCert(sID.IdBytes)
+	if err != nil {
+		return nil, errors.WithStack(status.New(group, status.EmptyCert.ToInt32(), "could not parse identity certificate", []interface{}{sID.Mspid, err.Error()}))
+	}
+
+	m, ok := v.msps[sID.Mspid]
+	if !ok {
+		return nil, errors.WithStack(status.New(group, status.UntrustedCertificate.ToInt32(), "identity belongs to an MSP that is not part of the channel", []interface{}{sID.Mspid}))
+	}
+
+	now := v.now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, errors.WithStack(status.New(group, status.CertificateExpired.ToInt32(), "identity certificate is expired or not yet valid", []interface{}{sID.Mspid, cert.SerialNumber.String(), cert.NotAfter}))
+	}
+
+	issuer, err := m.verifyChain(cert)
+	if err != nil {
+		return nil, errors.WithStack(status.New(group, status.UntrustedCertificate.ToInt32(), "identity certificate is not trusted by its MSP", []interface{}{sID.Mspid, err.Error()}))
+	}
+
+	if m.isRevoked(issuer, cert) {
+		return nil, errors.WithStack(status.New(group, status.CertificateRevoked.ToInt32(), "identity certificate has been revoked", []interface{}{sID.Mspid, cert.SerialNumber.String()}))
+	}
+
+	if err := m.checkRole(cert, role); err != nil {
+		return nil, errors.WithStack(status.New(group, status.IdentityRoleMismatch.ToInt32(), "identity does not have the required role", []interface{}{sID.Mspid, err.Error()}))
+	}
+
+	return cert, nil
+}
+
+func newChannelMSP(config *mb.FabricMSPConfig) (*channelMSP, error) {
+	if len(config.RootCerts) == 0 {
+		return nil, errors.New("MSP Configuration missing root certificates required for validating signing certificates")
+	}
+
+	m := &channelMSP{
+		name:          config.Name,
+		roots:         x509.NewCertPool(),
+		intermediates: x509.NewCertPool(),
+		revoked:       make(map[string]map[string]struct{}),
+		ous:           make(map[string]struct{}),
+	}
+
+	var cas []*x509.Certificate
+	for _, pemCerts := range config.RootCerts {
+		certs, err := parseCerts(pemCerts)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid root certificate")
+		}
+		for _, c := range certs {
+			m.roots.AddCert(c)
+		}
+		cas = append(cas, certs...)
+	}
+	for _, pemCerts := range config.IntermediateCerts {
+		certs, err := parseCerts(pemCerts)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid intermediate certificate")
+		}
+		for _, c := range certs {
+			m.intermediates.AddCert(c)
+		}
+		cas = append(cas, certs...)
+	}
+
+	for _, crlBytes := range config.RevocationList {
+		crl, err := x509.ParseCRL(crlBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse RevocationList")
+		}
+		m.addCRL(crl, cas)
+	}
+
+	for _, ou := range config.OrganizationalUnitIdentifiers {
+		m.ous[ou.OrganizationalUnitIdentifier] = struct{}{}
+	}
+
+	if nodeOUs := config.FabricNodeOus; nodeOUs != nil && nodeOUs.Enable {
+		if nodeOUs.PeerOuIdentifier != nil {
+			m.peerOU = nodeOUs.PeerOuIdentifier.OrganizationalUnitIdentifier
+		}
+		if nodeOUs.OrdererOuIdentifier != nil {
+			m.ordererOU = nodeOUs.OrdererOuIdentifier.OrganizationalUnitIdentifier
+		}
+	}
+
+	return m, nil
+}
+
+// addCRL records the revoked serial numbers of the CRL under the CA that signed it.
+// CRLs that are not signed by one of the MSP's CAs are ignored.
+func (m *channelMSP) addCRL(crl *pkix.CertificateList, cas []*x509.Certificate) {
+	for _, ca := range cas {
+		if err := ca.CheckCRLSignature(crl); err != nil {
+			continue
+		}
+		serials, ok := m.revoked[string(ca.Raw)]
+		if !ok {
+			serials = make(map[string]struct{})
+			m.revoked[string(ca.Raw)] = serials
+		}
+		for _, rc := range crl.TBSCertList.RevokedCertificates {
+			serials[rc.SerialNumber.String()] = struct{}{}
+		}
+		return
+	}
+	logger.Warnf("CRL in MSP [%s] is not signed by any of its CAs - ignoring", m.name)
+}
+
+// verifyChain verifies the certificate against the MSP's CAs and returns the issuing CA
+func (m *channelMSP) verifyChain(cert *x509.Certificate) (*x509.Certificate, error) {
+	// Expiry is checked separately, so validate the chain as of the certificate's start date
+	opts := x509.VerifyOptions{
+		Roots:         m.roots,
+		Intermediates: m.intermediates,
+		CurrentTime:   cert.NotBefore.Add(time.Second),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	chains, err := cert.Verify(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(chains) == 0 || len(chains[0]) < 2 {
+		return nil, errors.New("identity certificate must not be a CA certificate")
+	}
+	return chains[0][1], nil
+}
+
+func (m *channelMSP) isRevoked(issuer, cert *x509.Certificate) bool {
+	serials, ok := m.revoked[string(issuer.Raw)]
+	if !ok {
+		return false
+	}
+	_, revoked := serials[cert.SerialNumber.String()]
+	return revoked
+}
+
+func (m *channelMSP) checkRole(cert *x509.Certificate, role mb.MSPRole_MSPRoleType) error {
+	certOUs := cert.Subject.OrganizationalUnit
+
+	if len(m.ous) > 0 && !containsAny(certOUs, m.ous) {
+		return errors.Errorf("none of the identity's organizational units %v are in MSP %s", certOUs, m.name)
+	}
+
+	var requiredOU string
+	switch role {
+	case mb.MSPRole_PEER:
+		requiredOU = m.peerOU
+	case mb.MSPRole_ORDERER:
+		requiredOU = m.ordererOU
+	}
+	if requiredOU == "" {
+		return nil
+	}
+	if !containsAny(certOUs, map[string]struct{}{requiredOU: {}}) {
+		return errors.Errorf("identity with organizational units %v is not a %s of MSP %s", certOUs, role, m.name)
+	}
+
+	return nil
+}
+
+// verifySignature verifies the signature over the message with the public key of the certificate.
+// As in the MSP, the message is hashed with SM3 for SM2 keys and with SHA-256 for ECDSA keys,
+// and ECDSA signatures must be low-S.
+func verifySignature(cert *x509.Certificate, msg, sig []byte) error {
+	switch pub := cert.PublicKey.(type) {
+	case *sm2.PublicKey:
+		digest := sm3.Sm3Sum(msg)
+		if !pub.Verify(digest[:], sig) {
+			return errors.New("the signature is invalid")
+		}
+	case *ecdsa.PublicKey:
+		r, s, err := utils.UnmarshalECDSASignature(sig)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal signature")
+		}
+		lowS, err := utils.IsLowS(pub, s)
+		if err != nil {
+			return err
+		}
+		if !lowS {
+			return errors.New("invalid S, must be smaller than half the order")
+		}
+		digest := sha256.Sum256(msg)
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return errors.New("the signature is invalid")
+		}
+	default:
+		return errors.Errorf("unsupported public key type %T", pub)
+	}
+	return nil
+}
+
+func containsAny(values []string, set map[string]struct{}) bool {
+	for _, v := range values {
+		if _, ok := set[v]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCert(pemCert []byte) (*x509.Certificate, error) {
+	bl, _ := pem.Decode(pemCert)
+	if bl == nil {
+		return nil, errors.New("could not decode the PEM structure")
+	}
+	return x509.ParseCertificate(bl.Bytes)
+}
+
+func parseCerts(pemCerts []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for len(pemCerts) > 0 {
+		var block *pem.Block
+		block, pemCerts = pem.Decode(pemCerts)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+	return certs, nil
+}
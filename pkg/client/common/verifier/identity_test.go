@@ -0,0 +1,284 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	mb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	pb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/mocks"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	"gitee.com/zhaochuninhefei/gmgo/sm3"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMSPID = "Org1MSP"
+
+type testCA struct {
+	cert   *x509.Certificate
+	key    *sm2.PrivateKey
+	serial int64
+}
+
+func newTestCA(t *testing.T, now time.Time) *testCA {
+	key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca.org1.example.com", Organization: []string{"org1.example.com"}},
+		NotBefore:             now.Add(-24 * time.Hour),
+		NotAfter:              now.Add(24 * time.Hour),
+		SignatureAlgorithm:    x509.SM2WithSM3,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, serial: 1}
+}
+
+func (ca *testCA) issue(t *testing.T, notBefore, notAfter time.Time, ous ...string) *x509.Certificate {
+	cert, _ := ca.issueWithKey(t, notBefore, notAfter, ous...)
+	return cert
+}
+
+func (ca *testCA) issueWithKey(t *testing.T, notBefore, notAfter time.Time, ous ...string) (*x509.Certificate, *sm2.PrivateKey) {
+	key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ca.serial++
+	template := &x509.Certificate{
+		SerialNumber:       big.NewInt(ca.serial),
+		Subject:            pkix.Name{CommonName: "node.org1.example.com", OrganizationalUnit: ous},
+		NotBefore:          notBefore,
+		NotAfter:           notAfter,
+		SignatureAlgorithm: x509.SM2WithSM3,
+		KeyUsage:           x509.KeyUsageDigitalSignature,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+	return cert, key
+}
+
+// testOrderer signs blocks with the given key on behalf of the orderer certificate
+type testOrderer struct {
+	cert *x509.Certificate
+	key  *sm2.PrivateKey
+}
+
+func (ca *testCA) issueOrderer(t *testing.T, notBefore, notAfter time.Time) *testOrderer {
+	cert, key := ca.issueWithKey(t, notBefore, notAfter, "orderer")
+	return &testOrderer{cert: cert, key: key}
+}
+
+func (ca *testCA) crl(t *testing.T, now time.Time, revoked ...*x509.Certificate) []byte {
+	var revokedCerts []pkix.RevokedCertificate
+	for _, c := range revoked {
+		revokedCerts = append(revokedCerts, pkix.RevokedCertificate{SerialNumber: c.SerialNumber, RevocationTime: now})
+	}
+	raw, err := ca.cert.CreateCRL(rand.Reader, ca.key, revokedCerts, now, now.AddDate(1, 0, 0))
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: raw})
+}
+
+func certPEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func serialize(t *testing.T, mspID string, cert *x509.Certificate) []byte {
+	raw, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: certPEM(cert)})
+	require.NoError(t, err)
+	return raw
+}
+
+func mspConfig(t *testing.T, ca *testCA, crls ...[]byte) *mb.MSPConfig {
+	raw, err := proto.Marshal(&mb.FabricMSPConfig{
+		Name:           testMSPID,
+		RootCerts:      [][]byte{certPEM(ca.cert)},
+		RevocationList: crls,
+		FabricNodeOus: &mb.FabricNodeOUs{
+			Enable:              true,
+			ClientOuIdentifier:  &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "client"},
+			PeerOuIdentifier:    &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "peer"},
+			OrdererOuIdentifier: &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "orderer"},
+		},
+	})
+	require.NoError(t, err)
+	return &mb.MSPConfig{Config: raw}
+}
+
+func requireStatus(t *testing.T, err error, group status.Group, code status.Code) {
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok, "expected status error: %s", err)
+	assert.Equal(t, group, s.Group)
+	assert.Equal(t, code.ToInt32(), s.Code, "unexpected code %s", status.ToSDKStatusCode(s.Code))
+}
+
+func TestIdentityValidateEndorser(t *testing.T) {
+	now := time.Now()
+	ca := newTestCA(t, now)
+	otherCA := newTestCA(t, now)
+
+	validPeer := ca.issue(t, now.Add(-time.Hour), now.Add(time.Hour), "peer")
+	revokedPeer := ca.issue(t, now.Add(-time.Hour), now.Add(time.Hour), "peer")
+	expiredPeer := ca.issue(t, now.Add(-2*time.Hour), now.Add(-time.Hour), "peer")
+	clientID := ca.issue(t, now.Add(-time.Hour), now.Add(time.Hour), "client")
+	untrustedPeer := otherCA.issue(t, now.Add(-time.Hour), now.Add(time.Hour), "peer")
+
+	v, err := NewIdentity([]*mb.MSPConfig{mspConfig(t, ca, ca.crl(t, now, revokedPeer))})
+	require.NoError(t, err)
+
+	assert.NoError(t, v.ValidateEndorser(serialize(t, testMSPID, validPeer)))
+	requireStatus(t, v.ValidateEndorser(serialize(t, testMSPID, revokedPeer)), status.EndorserClientStatus, status.CertificateRevoked)
+	requireStatus(t, v.ValidateEndorser(serialize(t, testMSPID, expiredPeer)), status.EndorserClientStatus, status.CertificateExpired)
+	requireStatus(t, v.ValidateEndorser(serialize(t, testMSPID, untrustedPeer)), status.EndorserClientStatus, status.UntrustedCertificate)
+	requireStatus(t, v.ValidateEndorser(serialize(t, "Org2MSP", validPeer)), status.EndorserClientStatus, status.UntrustedCertificate)
+	requireStatus(t, v.ValidateEndorser(serialize(t, testMSPID, clientID)), status.EndorserClientStatus, status.IdentityRoleMismatch)
+
+	// A peer is not an orderer
+	requireStatus(t, v.ValidateOrderer(serialize(t, testMSPID, validPeer)), status.OrdererClientStatus, status.IdentityRoleMismatch)
+
+	// The certificate expires later on
+	v.now = func() time.Time { return now.Add(2 * time.Hour) }
+	requireStatus(t, v.ValidateEndorser(serialize(t, testMSPID, validPeer)), status.EndorserClientStatus, status.CertificateExpired)
+}
+
+func TestIdentityVerifyEndorsement(t *testing.T) {
+	now := time.Now()
+	ca := newTestCA(t, now)
+	peer := ca.issue(t, now.Add(-time.Hour), now.Add(time.Hour), "peer")
+
+	v, err := NewIdentity([]*mb.MSPConfig{mspConfig(t, ca)})
+	require.NoError(t, err)
+
+	response := &fab.TransactionProposalResponse{
+		ProposalResponse: &pb.ProposalResponse{
+			Endorsement: &pb.Endorsement{Endorser: serialize(t, testMSPID, peer)},
+		},
+	}
+	assert.NoError(t, v.VerifyEndorsement(response))
+
+	response.ProposalResponse.Endorsement = nil
+	requireStatus(t, v.VerifyEndorsement(response), status.EndorserClientStatus, status.MissingEndorsement)
+}
+
+func TestIdentityVerifyBlock(t *testing.T) {
+	now := time.Now()
+	ca := newTestCA(t, now)
+	orderer := ca.issueOrderer(t, now.Add(-time.Hour), now.Add(time.Hour))
+	revokedOrderer := ca.issueOrderer(t, now.Add(-time.Hour), now.Add(time.Hour))
+	otherOrderer := ca.issueOrderer(t, now.Add(-time.Hour), now.Add(time.Hour))
+
+	v, err := NewIdentity([]*mb.MSPConfig{mspConfig(t, ca, ca.crl(t, now, revokedOrderer.cert))})
+	require.NoError(t, err)
+
+	newSignedBlock := func(num uint64, orderers ...*testOrderer) *common.Block {
+		return signBlock(t, protoutil.NewBlock(num, nil), orderers...)
+	}
+
+	assert.NoError(t, v.VerifyBlock(protoutil.NewBlock(0, nil)), "genesis block is not signed")
+	assert.NoError(t, v.VerifyBlock(newSignedBlock(1, orderer)))
+	requireStatus(t, v.VerifyBlock(newSignedBlock(2)), status.OrdererClientStatus, status.SignatureVerificationFailed)
+	requireStatus(t, v.VerifyBlock(newSignedBlock(3, orderer, revokedOrderer)), status.OrdererClientStatus, status.CertificateRevoked)
+
+	// A valid orderer identity whose signature was not made with its key
+	forged := &testOrderer{cert: orderer.cert, key: otherOrderer.key}
+	requireStatus(t, v.VerifyBlock(newSignedBlock(4, orderer, forged)), status.OrdererClientStatus, status.SignatureVerificationFailed)
+
+	// The block was modified after it was signed
+	block := newSignedBlock(5, orderer)
+	block.Header.DataHash = []byte("tampered")
+	requireStatus(t, v.VerifyBlock(block), status.OrdererClientStatus, status.SignatureVerificationFailed)
+}
+
+func signBlock(t *testing.T, block *common.Block, orderers ...*testOrderer) *common.Block {
+	md := &common.Metadata{}
+	headerBytes := protoutil.BlockHeaderBytes(block.Header)
+	for _, orderer := range orderers {
+		signatureHeader := protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: serialize(t, testMSPID, orderer.cert)})
+		digest := sm3.Sm3Sum(bytes.Join([][]byte{md.Value, signatureHeader, headerBytes}, nil))
+		signature, err := orderer.key.Sign(rand.Reader, digest[:], nil)
+		require.NoError(t, err)
+		md.Signatures = append(md.Signatures, &common.MetadataSignature{
+			SignatureHeader: signatureHeader,
+			Signature:       signature,
+		})
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(md)
+	return block
+}
+
+func TestOrdererBlockVerifierConfigUpdate(t *testing.T) {
+	now := time.Now()
+	ca := newTestCA(t, now)
+	orderer := ca.issueOrderer(t, now.Add(-time.Hour), now.Add(time.Hour))
+	otherOrderer := ca.issueOrderer(t, now.Add(-time.Hour), now.Add(time.Hour))
+	rotatedCA := newTestCA(t, now)
+	rotatedOrderer := rotatedCA.issueOrderer(t, now.Add(-time.Hour), now.Add(time.Hour))
+
+	v, err := NewOrdererBlockVerifier("mychannel", []*mb.MSPConfig{mspConfig(t, ca)})
+	require.NoError(t, err)
+
+	requireStatus(t, v.VerifyBlock(signBlock(t, protoutil.NewBlock(1, nil), rotatedOrderer)), status.OrdererClientStatus, status.UntrustedCertificate)
+
+	// The config block is validated against the current MSPs and then replaces them
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{testMSPID},
+			OrdererAddress: "localhost:7050",
+			RootCA:         string(certPEM(rotatedCA.cert)),
+		},
+		Index:           2,
+		LastConfigIndex: 2,
+	}
+	configBlock := builder.Build()
+	requireStatus(t, v.VerifyBlock(signBlock(t, configBlock, rotatedOrderer)), status.OrdererClientStatus, status.UntrustedCertificate)
+
+	// A config block with a forged signature of a current orderer must not replace the MSPs
+	forged := &testOrderer{cert: orderer.cert, key: otherOrderer.key}
+	requireStatus(t, v.VerifyBlock(signBlock(t, configBlock, forged)), status.OrdererClientStatus, status.SignatureVerificationFailed)
+	assert.NoError(t, v.VerifyBlock(signBlock(t, protoutil.NewBlock(2, nil), orderer)), "MSPs must not have been reloaded")
+
+	require.NoError(t, v.VerifyBlock(signBlock(t, configBlock, orderer)))
+
+	assert.NoError(t, v.VerifyBlock(signBlock(t, protoutil.NewBlock(3, nil), rotatedOrderer)))
+	requireStatus(t, v.VerifyBlock(signBlock(t, protoutil.NewBlock(4, nil), orderer)), status.OrdererClientStatus, status.UntrustedCertificate)
+}
+
+func TestNewIdentityInvalidConfig(t *testing.T) {
+	_, err := NewIdentity([]*mb.MSPConfig{{Config: []byte("invalid")}})
+	assert.Error(t, err)
+
+	raw, err := proto.Marshal(&mb.FabricMSPConfig{Name: testMSPID})
+	require.NoError(t, err)
+	_, err = NewIdentity([]*mb.MSPConfig{{Config: raw}})
+	assert.Error(t, err, "root certificates are required")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/logging"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// processed returns true if the checkpoint shows that the given transaction has already been processed.
+// If the checkpoint cannot be read then the event is delivered, i.e. events may be
+// delivered more than once but are never lost.
+func (c *Client) processed(blockNum uint64, txIndex int) bool {
+	checkpoint, err := c.checkpointer.Checkpoint()
+	if err != nil {
+		logger.Warnf("Unable to get checkpoint: %s", err)
+		return false
+	}
+	return checkpoint.Processed(blockNum, txIndex)
+}
+
+// skipProcessedBlockEvents filters out blocks that were fully processed according to the checkpoint.
+// A partially processed block is delivered; the application skips the processed transactions.
+func (c *Client) skipProcessedBlockEvents(in <-chan *fab.BlockEvent) <-chan *fab.BlockEvent {
+	out := make(chan *fab.BlockEvent, cap(in))
+	go func() {
+		defer close(out)
+		for event := range in {
+			if event.Block != nil && event.Block.Header != nil && c.processed(event.Block.Header.Number+1, -1) {
+				logger.Debugf("Skipping block event for processed block #%d", event.Block.Header.Number)
+				continue
+			}
+			out <- event
+		}
+	}()
+	return out
+}
+
+// skipProcessedFilteredBlockEvents filters out filtered blocks that were fully processed according to the checkpoint.
+func (c *Client) skipProcessedFilteredBlockEvents(in <-chan *fab.FilteredBlockEvent) <-chan *fab.FilteredBlockEvent {
+	out := make(chan *fab.FilteredBlockEvent, cap(in))
+	go func() {
+		defer close(out)
+		for event := range in {
+			if event.FilteredBlock != nil && c.processed(event.FilteredBlock.Number+1, -1) {
+				logger.Debugf("Skipping filtered block event for processed block #%d", event.FilteredBlock.Number)
+				continue
+			}
+			out <- event
+		}
+	}()
+	return out
+}
+
+// skipProcessedCCEvents filters out chaincode events of transactions that were processed according to the checkpoint,
+// so that each chaincode event is processed exactly once, even within a partially processed block.
+func (c *Client) skipProcessedCCEvents(in <-chan *fab.CCEvent) <-chan *fab.CCEvent {
+	out := make(chan *fab.CCEvent, cap(in))
+	go func() {
+		defer close(out)
+		for event := range in {
+			if c.processed(event.BlockNumber, event.TxIndex) {
+				logger.Debugf("Skipping chaincode event [%s] of processed tx #%d in block #%d", event.EventName, event.TxIndex, event.BlockNumber)
+				continue
+			}
+			out <- event
+		}
+	}()
+	return out
+}
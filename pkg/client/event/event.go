@@ -33,7 +33,12 @@ type Client struct {
 	permitBlockEvents    bool
 	fromBlock            uint64
 	seekType             seek.Type
+	toBlock              uint64
+	toBlockSet           bool
 	eventConsumerTimeout *time.Duration
+	checkpointer         fab.EventCheckpointer
+
+	ordererIdentityValidation bool
 }
 
 // New returns a Client instance. Client receives events such as block, filtered block,
@@ -50,7 +55,7 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 	for _, param := range opts {
 		err1 := param(&eventClient)
 		if err1 != nil {
-			return nil, errors.WithMessage(err, "option failed")
+			return nil, errors.WithMessage(err1, "option failed")
 		}
 	}
 
@@ -58,24 +63,35 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		return nil, errors.New("channel service not initialized")
 	}
 
-	var es fab.EventService
-	if eventClient.permitBlockEvents {
-		var opts []options.Opt
-		opts = append(opts, client.WithBlockEvents())
+	var esOpts []options.Opt
+	if eventClient.permitBlockEvents || eventClient.toBlockSet {
+		if eventClient.permitBlockEvents {
+			esOpts = append(esOpts, client.WithBlockEvents())
+		}
 		if eventClient.seekType != "" {
-			opts = append(opts, deliverclient.WithSeekType(eventClient.seekType))
+			esOpts = append(esOpts, deliverclient.WithSeekType(eventClient.seekType))
 			if eventClient.seekType == seek.FromBlock {
-				opts = append(opts, deliverclient.WithBlockNum(eventClient.fromBlock))
+				esOpts = append(esOpts, deliverclient.WithBlockNum(eventClient.fromBlock))
 			}
 		}
+		if eventClient.toBlockSet {
+			esOpts = append(esOpts, deliverclient.WithToBlock(eventClient.toBlock))
+		}
 		if eventClient.eventConsumerTimeout != nil {
-			opts = append(opts, dispatcher.WithEventConsumerTimeout(*eventClient.eventConsumerTimeout))
+			esOpts = append(esOpts, dispatcher.WithEventConsumerTimeout(*eventClient.eventConsumerTimeout))
 		}
-		es, err = channelContext.ChannelService().EventService(opts...)
-	} else {
-		es, err = channelContext.ChannelService().EventService()
+	}
+	if eventClient.checkpointer != nil {
+		esOpts = append(esOpts, deliverclient.WithCheckpointer(eventClient.checkpointer))
+	}
+	if eventClient.ordererIdentityValidation {
+		if !eventClient.permitBlockEvents {
+			return nil, errors.New("orderer identity validation requires block events")
+		}
+		esOpts = append(esOpts, deliverclient.WithOrdererIdentityValidation())
 	}
 
+	es, err := channelContext.ChannelService().EventService(esOpts...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "event service creation failed")
 	}
@@ -93,14 +109,22 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
-	return c.eventService.RegisterBlockEvent(filter...)
+	reg, eventch, err := c.eventService.RegisterBlockEvent(filter...)
+	if err != nil || c.checkpointer == nil {
+		return reg, eventch, err
+	}
+	return reg, c.skipProcessedBlockEvents(eventch), nil
 }
 
 // RegisterFilteredBlockEvent registers for filtered block events. Unregister must be called when the registration is no longer needed.
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
-	return c.eventService.RegisterFilteredBlockEvent()
+	reg, eventch, err := c.eventService.RegisterFilteredBlockEvent()
+	if err != nil || c.checkpointer == nil {
+		return reg, eventch, err
+	}
+	return reg, c.skipProcessedFilteredBlockEvents(eventch), nil
 }
 
 // RegisterChaincodeEvent registers for chaincode events. Unregister must be called when the registration is no longer needed.
@@ -111,7 +135,11 @@ func (c *Client) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.Fil
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
-	return c.eventService.RegisterChaincodeEvent(ccID, eventFilter)
+	reg, eventch, err := c.eventService.RegisterChaincodeEvent(ccID, eventFilter)
+	if err != nil || c.checkpointer == nil {
+		return reg, eventch, err
+	}
+	return reg, c.skipProcessedCCEvents(eventch), nil
 }
 
 // RegisterTxStatusEvent registers for transaction status events. Unregister must be called when the registration is no longer needed.
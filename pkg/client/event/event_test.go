@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	pb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/checkpoint"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/deliverclient/seek"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/service"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/service/dispatcher"
@@ -48,6 +49,26 @@ func TestNewEventClient(t *testing.T) {
 		t.Fatalf("Failed to create new event client: %s", err)
 	}
 
+	_, err = New(ctx, WithBlockRange(1000, 2000))
+	if err != nil {
+		t.Fatalf("Failed to create new event client with block range: %s", err)
+	}
+
+	_, err = New(ctx, WithBlockRange(2000, 1000))
+	if err == nil {
+		t.Fatal("Should have failed with invalid block range")
+	}
+
+	_, err = New(ctx, WithBlockEvents(), WithOrdererIdentityValidation())
+	if err != nil {
+		t.Fatalf("Failed to create new event client with orderer identity validation: %s", err)
+	}
+
+	_, err = New(ctx, WithOrdererIdentityValidation())
+	if err == nil {
+		t.Fatal("Should have failed since orderer identity validation requires block events")
+	}
+
 	ctxErr := createChannelContextWithError(fabCtx, channelID)
 	_, err = New(ctxErr)
 	if err == nil {
@@ -266,6 +287,73 @@ func TestCCEvents(t *testing.T) {
 
 }
 
+func TestCCEventsWithCheckpointer(t *testing.T) {
+	chanID := "mychannel"
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withFilteredBlockLedger(sourceURL))
+	if err != nil {
+		t.Fatalf("error creating channel event client: %s", err)
+	}
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, chanID)
+
+	checkpointer := checkpoint.NewInMemoryCheckpointer()
+	client, err := New(ctx, WithCheckpointer(checkpointer))
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+
+	client.eventService = eventService
+
+	// The first two transactions of block 0 were processed before a restart
+	if err := checkpointer.CheckpointTransaction(0, 1); err != nil {
+		t.Fatalf("error updating checkpoint: %s", err)
+	}
+
+	ccID := "mycc"
+	reg, eventch, err := client.RegisterChaincodeEvent(ccID, "event.*")
+	if err != nil {
+		t.Fatalf("error registering for chaincode events: %s", err)
+	}
+	defer client.Unregister(reg)
+
+	eventProducer.Ledger().NewFilteredBlock(
+		chanID,
+		servicemocks.NewFilteredTxWithCCEvent("txid1", ccID, "event1"),
+		servicemocks.NewFilteredTxWithCCEvent("txid2", ccID, "event2"),
+		servicemocks.NewFilteredTxWithCCEvent("txid3", ccID, "event3"),
+	)
+	eventProducer.Ledger().NewFilteredBlock(
+		chanID,
+		servicemocks.NewFilteredTxWithCCEvent("txid4", ccID, "event4"),
+	)
+
+	for _, expected := range []string{"txid3", "txid4"} {
+		select {
+		case event, ok := <-eventch:
+			if !ok {
+				t.Fatal("unexpected closed channel")
+			}
+			if event.TxID != expected {
+				t.Fatalf("expecting event for TxID [%s] but received event for TxID [%s]", expected, event.TxID)
+			}
+			if err := checkpointer.CheckpointChaincodeEvent(event); err != nil {
+				t.Fatalf("error updating checkpoint: %s", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for CC event for TxID [%s]", expected)
+		}
+	}
+
+	cp, err := checkpointer.Checkpoint()
+	if err != nil {
+		t.Fatalf("error getting checkpoint: %s", err)
+	}
+	assert.Equal(t, &fab.EventCheckpoint{BlockNum: 1, TxIndex: 0}, cp)
+}
+
 func validateCCEvents(t *testing.T, eventProducer *servicemocks.MockProducer, eventch1 <-chan *fab.CCEvent, eventch2 <-chan *fab.CCEvent, chanID string, ccID1 string, ccID2 string) {
 	event1 := "event1"
 	event2 := "event2"
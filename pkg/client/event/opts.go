@@ -9,7 +9,9 @@ package event
 import (
 	"time"
 
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/deliverclient/seek"
+	"github.com/pkg/errors"
 )
 
 // ClientOption describes a functional parameter for the New constructor
@@ -42,6 +44,35 @@ func WithSeekType(seek seek.Type) ClientOption {
 	}
 }
 
+// WithBlockRange indicates that only the blocks from 'from' to 'to' (inclusive) are to be received.
+// The registration channels are closed once block 'to' has been delivered. Blocks that have not been
+// committed yet are waited for. A client with a block range is not shared with other event clients.
+// Only deliverclient supports this
+func WithBlockRange(from, to uint64) ClientOption {
+	return func(c *Client) error {
+		if from > to {
+			return errors.Errorf("invalid block range [%d, %d]", from, to)
+		}
+		c.seekType = seek.FromBlock
+		c.fromBlock = from
+		c.toBlock = to
+		c.toBlockSet = true
+		return nil
+	}
+}
+
+// WithToBlock indicates the last block (inclusive) to be received. The registration channels are
+// closed once the block has been delivered. May be combined with WithSeekType(seek.Oldest) or
+// WithSeekType(seek.FromBlock) and WithBlockNum; if no seek type is given, blocks are received from the oldest block.
+// Only deliverclient supports this
+func WithToBlock(to uint64) ClientOption {
+	return func(c *Client) error {
+		c.toBlock = to
+		c.toBlockSet = true
+		return nil
+	}
+}
+
 // WithEventConsumerTimeout is the timeout when sending events to a registered consumer.
 // If < 0, if buffer full, unblocks immediately and does not send.
 // If 0, if buffer full, will block and guarantee the event will be sent out.
@@ -52,3 +83,27 @@ func WithEventConsumerTimeout(value time.Duration) ClientOption {
 		return nil
 	}
 }
+
+// WithCheckpointer sets the checkpointer which records the progress of event processing.
+// Events are received from the last checkpoint, when the client is created and when it reconnects,
+// and events that were already processed according to the checkpoint are not delivered again.
+// The application is responsible for updating the checkpoint once it has processed an event.
+// Only deliverclient supports this
+func WithCheckpointer(checkpointer fab.EventCheckpointer) ClientOption {
+	return func(c *Client) error {
+		c.checkpointer = checkpointer
+		return nil
+	}
+}
+
+// WithOrdererIdentityValidation indicates that the identities of the orderers that signed each block
+// are to be validated against the channel MSPs (chain of trust, expiry, revocation lists and the
+// orderer NodeOU role). Blocks that fail validation are not delivered and the client reconnects
+// to another peer. Requires WithBlockEvents since filtered blocks are not signed.
+// Only deliverclient supports this
+func WithOrdererIdentityValidation() ClientOption {
+	return func(c *Client) error {
+		c.ordererIdentityValidation = true
+		return nil
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/golang/protobuf/proto"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/circuitbreaker"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/discovery"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/filter"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/verifier"
@@ -47,6 +48,7 @@ type Client struct {
 	ledger    *channel.Ledger
 	verifier  channel.ResponseVerifier
 	discovery fab.DiscoveryService
+	breaker   *circuitbreaker.Breaker
 }
 
 // mspFilter is default filter
@@ -249,6 +251,11 @@ func (c *Client) prepareRequestParams(options ...RequestOption) ([]fab.Peer, *re
 		return nil, nil, errors.WithMessage(err, "failed to determine target peers")
 	}
 
+	if c.breaker != nil {
+		// Record the outcome of the queries so that failing peers are avoided
+		targets = c.breaker.Peers(targets)
+	}
+
 	return targets, &opts, nil
 }
 
@@ -408,6 +415,10 @@ func (c *Client) calculateTargets(opts requestOptions) ([]fab.Peer, error) {
 		if targetFilter == nil {
 			targetFilter = c.filter
 		}
+
+		if c.breaker != nil {
+			targets = filterTargets(targets, c.breaker)
+		}
 	}
 
 	if targetFilter != nil {
@@ -9,7 +9,9 @@ import (
 	"strings"
 	"testing"
 
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/circuitbreaker"
 	txnmocks "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/mocks"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
 	contextImpl "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/context"
@@ -291,6 +293,30 @@ func TestQueryConfigBlock(t *testing.T) {
 	assert.NotNil(t, block)
 }
 
+func TestCircuitBreaker(t *testing.T) {
+
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test"}
+	peer.Error = status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", []interface{}{peer.MockURL})
+	lc := setupLedgerClient([]fab.Peer{&peer}, t)
+
+	breaker := circuitbreaker.New()
+	assert.Nil(t, WithCircuitBreaker(breaker)(lc))
+
+	_, err := lc.QueryInfo()
+	assert.Error(t, err)
+	assert.Equal(t, circuitbreaker.Open, breaker.State(peer.MockURL))
+
+	_, err = lc.QueryInfo()
+	s, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.NoPeersFound.ToInt32(), s.Code, "peer with open circuit is not chosen")
+	assert.Equal(t, 1, peer.ProcessProposalCalls)
+
+	_, err = lc.QueryInfo(WithTargets(&peer))
+	assert.Error(t, err)
+	assert.Equal(t, 2, peer.ProcessProposalCalls, "explicit targets are always used")
+}
+
 func setupTestChannelService(ctx context.Client, orderers []fab.Orderer) (fab.ChannelService, error) {
 	chProvider, err := fcmocks.NewMockChannelProvider(ctx)
 	if err != nil {
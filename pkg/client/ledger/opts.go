@@ -10,6 +10,7 @@ import (
 	reqContext "context"
 	"time"
 
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/circuitbreaker"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/comm"
@@ -32,6 +33,19 @@ func WithDefaultTargetFilter(filter fab.TargetFilter) ClientOption {
 	}
 }
 
+// WithCircuitBreaker sets the circuit breaker that records the outcome of the queries sent to
+// peers. Peers whose circuit is open are not chosen as targets unless targets are given explicitly.
+// The breaker may be shared with other clients.
+func WithCircuitBreaker(breaker *circuitbreaker.Breaker) ClientOption {
+	return func(rmc *Client) error {
+		if breaker == nil {
+			return errors.New("circuit breaker is required")
+		}
+		rmc.breaker = breaker
+		return nil
+	}
+}
+
 //RequestOption func for each requestOptions argument
 type RequestOption func(ctx context.Client, opts *requestOptions) error
 
@@ -6,6 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package msp
 
+import (
+	"time"
+)
+
 // AttributeRequest is a request for an attribute.
 type AttributeRequest struct {
 	Name     string
@@ -75,6 +79,53 @@ type RevokedCert struct {
 	AKI string
 }
 
+// GetCertificatesRequest defines the filters used to list certificates issued by the CA.
+// Zero values are ignored, so an empty request lists all certificates the caller is authorized to see.
+type GetCertificatesRequest struct {
+	// ID is the enrollment ID of the identity whose certificates are listed
+	ID string
+	// AKI (Authority Key Identifier) of the certificates to be listed
+	AKI string
+	// Serial number of the certificate to be listed
+	Serial string
+	// RevokedStart and RevokedEnd select certificates revoked within this time window
+	RevokedStart time.Time
+	RevokedEnd   time.Time
+	// ExpiredStart and ExpiredEnd select certificates expiring within this time window
+	ExpiredStart time.Time
+	ExpiredEnd   time.Time
+	// NotExpired excludes expired certificates
+	NotExpired bool
+	// NotRevoked excludes revoked certificates
+	NotRevoked bool
+	// CAName is the name of the CA to connect to
+	CAName string
+}
+
+// GetCertificatesResponse represents response from the server for a get certificates request
+type GetCertificatesResponse struct {
+	// Certs is an array of PEM-encoded certificates matching the request
+	Certs [][]byte
+}
+
+// GenCRLRequest defines the time windows used to select revoked certificates for a CRL
+type GenCRLRequest struct {
+	// CAName is the name of the CA to connect to
+	CAName string
+	// RevokedAfter and RevokedBefore select certificates revoked within this time window
+	RevokedAfter  time.Time
+	RevokedBefore time.Time
+	// ExpireAfter and ExpireBefore select certificates expiring within this time window
+	ExpireAfter  time.Time
+	ExpireBefore time.Time
+}
+
+// GenCRLResponse represents response from the server for a CRL generation request
+type GenCRLResponse struct {
+	// CRL is PEM-encoded certificate revocation list (CRL) that contains requested unexpired revoked certificates
+	CRL []byte
+}
+
 // IdentityRequest represents the request to add/update identity to the fabric-ca-server
 type IdentityRequest struct {
 
@@ -6,7 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 
 // Package msp enables creation and update of users on a Fabric network.
 // Msp client supports the following actions:
-// Enroll, Reenroll, Register,  Revoke and GetSigningIdentity.
+// Enroll, Reenroll, Register,  Revoke, GetCertificates, GenCRL and GetSigningIdentity.
 //
 //  Basic Flow:
 //  1) Prepare client context
@@ -367,12 +367,9 @@ func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 //  Returns:
 //  an error if re-enrollment fails
 func (c *Client) Reenroll(enrollmentID string, opts ...EnrollmentOption) error {
-	eo := enrollmentOptions{}
-	for _, param := range opts {
-		err := param(&eo)
-		if err != nil {
-			return errors.WithMessage(err, "failed to enroll")
-		}
+	req, err := c.newReenrollmentRequest(enrollmentID, opts...)
+	if err != nil {
+		return err
 	}
 
 	ca, err := newCAClient(c.ctx, c.orgName, c.caID)
@@ -380,12 +377,25 @@ func (c *Client) Reenroll(enrollmentID string, opts ...EnrollmentOption) error {
 		return err
 	}
 
+	return ca.Reenroll(req)
+}
+
+func (c *Client) newReenrollmentRequest(enrollmentID string, opts ...EnrollmentOption) (*mspapi.ReenrollmentRequest, error) {
+	eo := enrollmentOptions{}
+	for _, param := range opts {
+		err := param(&eo)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to enroll")
+		}
+	}
+
 	req := &mspapi.ReenrollmentRequest{
-		Name:    enrollmentID,
-		Profile: eo.profile,
-		Label:   eo.label,
-		CAName:  c.caName,
-		CSR:     createCSRInfo(eo.csr),
+		Name:     enrollmentID,
+		Profile:  eo.profile,
+		Label:    eo.label,
+		CAName:   c.caName,
+		CSR:      createCSRInfo(eo.csr),
+		ReuseKey: eo.reuseKey,
 	}
 
 	if req.CAName == "" {
@@ -398,7 +408,7 @@ func (c *Client) Reenroll(enrollmentID string, opts ...EnrollmentOption) error {
 		}
 		req.AttrReqs = attrs
 	}
-	return ca.Reenroll(req)
+	return req, nil
 }
 
 // Register registers a User with the Fabric CA
@@ -462,6 +472,53 @@ func (c *Client) Revoke(request *RevocationRequest) (*RevocationResponse, error)
 	}, nil
 }
 
+// GetCertificates returns certificates that the caller is authorized to see
+// and that match the filters specified in the request
+//  Parameters:
+//  request holds the certificate filters (enrollment ID, serial, AKI, expiry window, revocation status)
+//
+//  Returns:
+//  Response containing PEM-encoded certificates
+func (c *Client) GetCertificates(request *GetCertificatesRequest) (*GetCertificatesResponse, error) {
+	if request == nil {
+		return nil, errors.New("GetCertificates request is required")
+	}
+	ca, err := newCAClient(c.ctx, c.orgName, c.caID)
+	if err != nil {
+		return nil, err
+	}
+	req := mspapi.GetCertificatesRequest(*request)
+	resp, err := ca.GetCertificates(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetCertificatesResponse{Certs: resp.Certs}, nil
+}
+
+// GenCRL generates a CRL containing the revoked certificates selected by the request
+//  Parameters:
+//  request holds the revocation and expiration time ranges
+//
+//  Returns:
+//  Response containing PEM-encoded CRL
+func (c *Client) GenCRL(request *GenCRLRequest) (*GenCRLResponse, error) {
+	if request == nil {
+		return nil, errors.New("GenCRL request is required")
+	}
+	ca, err := newCAClient(c.ctx, c.orgName, c.caID)
+	if err != nil {
+		return nil, err
+	}
+	req := mspapi.GenCRLRequest(*request)
+	resp, err := ca.GenCRL(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenCRLResponse{CRL: resp.CRL}, nil
+}
+
 // GetCAInfo returns generic CA information
 func (c *Client) GetCAInfo() (*GetCAInfoResponse, error) {
 	ca, err := newCAClient(c.ctx, c.orgName, c.caID)
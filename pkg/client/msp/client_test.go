@@ -343,6 +343,56 @@ func TestRevoke(t *testing.T) {
 
 }
 
+func TestGetCertificates(t *testing.T) {
+	f := testFixture{}
+	sdk := f.setup()
+	defer f.close()
+
+	ctxProvider := sdk.Context()
+
+	msp, err := New(ctxProvider)
+	if err != nil {
+		t.Fatalf("failed to create CA client: %s", err)
+	}
+
+	resp, err := msp.GetCertificates(&GetCertificatesRequest{ID: "testuser", NotExpired: true})
+	if err != nil {
+		t.Fatalf("GetCertificates return error %s", err)
+	}
+	if len(resp.Certs) != 1 {
+		t.Fatalf("expecting %d, got %d certificates", 1, len(resp.Certs))
+	}
+
+	if _, err := msp.GetCertificates(nil); err == nil {
+		t.Fatal("GetCertificates should have failed with nil request")
+	}
+}
+
+func TestGenCRL(t *testing.T) {
+	f := testFixture{}
+	sdk := f.setup()
+	defer f.close()
+
+	ctxProvider := sdk.Context()
+
+	msp, err := New(ctxProvider)
+	if err != nil {
+		t.Fatalf("failed to create CA client: %s", err)
+	}
+
+	resp, err := msp.GenCRL(&GenCRLRequest{})
+	if err != nil {
+		t.Fatalf("GenCRL return error %s", err)
+	}
+	if len(resp.CRL) == 0 {
+		t.Fatal("GenCRL returned empty CRL")
+	}
+
+	if _, err := msp.GenCRL(nil); err == nil {
+		t.Fatal("GenCRL should have failed with nil request")
+	}
+}
+
 // TestCreateIdentityFailure tests failures in CreateIdentity
 func TestCreateIdentityFailure(t *testing.T) {
 
@@ -7,6 +7,7 @@ package msp
 
 import (
 	"fmt"
+	"time"
 
 	"gitee.com/zhaochuninhefei/cfssl-gm/log"
 	fabricCaUtil "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/sdkinternal/pkg/util"
@@ -402,6 +403,49 @@ func ExampleClient_Revoke() {
 	// Output: revoke user is completed
 }
 
+func ExampleClient_GetCertificates() {
+
+	ctx := mockClientProvider()
+
+	// Create msp client
+	c, err := New(ctx)
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	// Certificates of testuser that expire within 30 days
+	_, err = c.GetCertificates(&GetCertificatesRequest{ID: "testuser", ExpiredStart: time.Now(), ExpiredEnd: time.Now().Add(30 * 24 * time.Hour)})
+	if err != nil {
+		fmt.Printf("get certificates return error %s\n", err)
+		return
+	}
+	fmt.Println("get certificates is completed")
+
+	// Output: get certificates is completed
+}
+
+func ExampleClient_GenCRL() {
+
+	ctx := mockClientProvider()
+
+	// Create msp client
+	c, err := New(ctx)
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	_, err = c.GenCRL(&GenCRLRequest{RevokedAfter: time.Now().Add(-24 * time.Hour)})
+	if err != nil {
+		fmt.Printf("gencrl return error %s\n", err)
+		return
+	}
+	fmt.Println("gencrl is completed")
+
+	// Output: gencrl is completed
+}
+
 func ExampleWithCA() {
 
 	// Create msp client
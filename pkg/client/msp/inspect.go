@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"encoding/pem"
+	"sort"
+	"strings"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/lib/attrmgr"
+	mspctx "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/gateway"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/pkg/errors"
+)
+
+// NodeOU roles of an identity, as set in the OU of certificates issued by Fabric CA
+// and recognized by MSPs with NodeOUs enabled
+const (
+	RoleClient  = "client"
+	RolePeer    = "peer"
+	RoleOrderer = "orderer"
+	RoleAdmin   = "admin"
+)
+
+// attributes that Fabric CA adds to enrollment certificates by default
+const (
+	attrEnrollmentID = "hf.EnrollmentID"
+	attrType         = "hf.Type"
+	attrAffiliation  = "hf.Affiliation"
+)
+
+// CertificateDetails contains the information carried by an identity's enrollment certificate
+// that applications use for authorization, e.g. the attributes chaincode evaluates for ABAC
+type CertificateDetails struct {
+	// ID is the enrollment ID (hf.EnrollmentID attribute, or the common name)
+	ID string
+	// Role is the NodeOU role (client, peer, orderer or admin); empty if the certificate carries none
+	Role string
+	// Affiliation is the Fabric CA affiliation, e.g. org1.department1
+	Affiliation string
+	// Attributes are the Fabric CA attributes in the 1.2.3.4.5.6.7.8.1 extension
+	Attributes map[string]string
+	// OrganizationalUnits are the OUs of the certificate subject
+	OrganizationalUnits []string
+	// Issuer is the common name of the issuing CA
+	Issuer string
+	// SerialNumber is the certificate serial number in hex
+	SerialNumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+}
+
+// Attribute returns the value of the named attribute and whether it is present
+func (d *CertificateDetails) Attribute(name string) (string, bool) {
+	value, ok := d.Attributes[name]
+	return value, ok
+}
+
+// AssertAttributeTrue returns nil if the named attribute has the value "true",
+// which is how chaincode evaluates boolean attributes (cid.AssertAttributeValue)
+func (d *CertificateDetails) AssertAttributeTrue(name string) error {
+	value, ok := d.Attributes[name]
+	if !ok {
+		return errors.Errorf("attribute '%s' was not found", name)
+	}
+	if value != "true" {
+		return errors.Errorf("attribute '%s' is not true", name)
+	}
+	return nil
+}
+
+// AttributeNames returns the sorted names of the attributes
+func (d *CertificateDetails) AttributeNames() []string {
+	names := make([]string, 0, len(d.Attributes))
+	for name := range d.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExpiresWithin returns true if the certificate expires within d (or has already expired)
+func (d *CertificateDetails) ExpiresWithin(duration time.Duration) bool {
+	return time.Now().Add(duration).After(d.NotAfter)
+}
+
+// InspectIdentity returns the details of the identity's enrollment certificate.
+// Any msp.SigningIdentity, e.g. one returned by Client.GetSigningIdentity, can be inspected.
+func InspectIdentity(identity mspctx.Identity) (*CertificateDetails, error) {
+	if identity == nil {
+		return nil, errors.New("identity is required")
+	}
+	return InspectCertificate(identity.EnrollmentCertificate())
+}
+
+// InspectWalletIdentity returns the details of the certificate of a gateway wallet identity
+func InspectWalletIdentity(identity *gateway.X509Identity) (*CertificateDetails, error) {
+	if identity == nil {
+		return nil, errors.New("identity is required")
+	}
+	return InspectCertificate([]byte(identity.Certificate()))
+}
+
+// InspectCertificate returns the details of a PEM encoded certificate
+func InspectCertificate(certPEM []byte) (*CertificateDetails, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse certificate")
+	}
+	return inspectX509Certificate(cert)
+}
+
+func inspectX509Certificate(cert *x509.Certificate) (*CertificateDetails, error) {
+	attrs, err := attrmgr.New().GetAttributesFromCert(cert)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get attributes from certificate")
+	}
+	attributes := attrs.Attrs
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+
+	details := &CertificateDetails{
+		ID:                  cert.Subject.CommonName,
+		Attributes:          attributes,
+		OrganizationalUnits: cert.Subject.OrganizationalUnit,
+		Issuer:              cert.Issuer.CommonName,
+		SerialNumber:        cert.SerialNumber.Text(16),
+		NotBefore:           cert.NotBefore,
+		NotAfter:            cert.NotAfter,
+	}
+	if id, ok := attributes[attrEnrollmentID]; ok && id != "" {
+		details.ID = id
+	}
+
+	// Fabric CA将身份类型作为第一个OU，后续的OU依次为从属关系的各级名称
+	var affiliationOUs []string
+	for _, ou := range cert.Subject.OrganizationalUnit {
+		if details.Role == "" && isNodeOURole(ou) {
+			details.Role = strings.ToLower(ou)
+			continue
+		}
+		affiliationOUs = append(affiliationOUs, ou)
+	}
+	if details.Role == "" && isNodeOURole(attributes[attrType]) {
+		details.Role = strings.ToLower(attributes[attrType])
+	}
+
+	if affiliation, ok := attributes[attrAffiliation]; ok {
+		details.Affiliation = affiliation
+	} else {
+		details.Affiliation = strings.Join(affiliationOUs, ".")
+	}
+
+	return details, nil
+}
+
+func isNodeOURole(ou string) bool {
+	switch strings.ToLower(ou) {
+	case RoleClient, RolePeer, RoleOrderer, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/lib/attrmgr"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/gateway"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/msp/test/mockmsp"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAttributeCert returns a certificate issued the way Fabric CA issues enrollment certificates
+func newAttributeCert(t *testing.T, notAfter time.Time, attrs map[string]string, ous ...string) []byte {
+	key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:       big.NewInt(0x1f),
+		Subject:            pkix.Name{CommonName: "user1", OrganizationalUnit: ous},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           notAfter,
+		SignatureAlgorithm: x509.SM2WithSM3,
+		KeyUsage:           x509.KeyUsageDigitalSignature,
+	}
+	if attrs != nil {
+		withExt := &x509.Certificate{}
+		require.NoError(t, attrmgr.New().AddAttributesToCert(&attrmgr.Attributes{Attrs: attrs}, withExt))
+		template.ExtraExtensions = withExt.Extensions
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})
+}
+
+func TestInspectCertificate(t *testing.T) {
+	notAfter := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	cert := newAttributeCert(t, notAfter, map[string]string{
+		"hf.EnrollmentID": "user1@org1",
+		"hf.Type":         "client",
+		"hf.Affiliation":  "org1.department1",
+		"app.admin":       "true",
+		"app.region":      "east",
+	}, "client", "org1", "department1")
+
+	details, err := InspectCertificate(cert)
+	require.NoError(t, err)
+
+	assert.Equal(t, "user1@org1", details.ID)
+	assert.Equal(t, RoleClient, details.Role)
+	assert.Equal(t, "org1.department1", details.Affiliation)
+	assert.Equal(t, []string{"client", "org1", "department1"}, details.OrganizationalUnits)
+	assert.Equal(t, "1f", details.SerialNumber)
+	assert.True(t, notAfter.Equal(details.NotAfter))
+	assert.False(t, details.ExpiresWithin(24*time.Hour))
+	assert.True(t, details.ExpiresWithin(72*time.Hour))
+
+	assert.Equal(t, []string{"app.admin", "app.region", "hf.Affiliation", "hf.EnrollmentID", "hf.Type"}, details.AttributeNames())
+	region, ok := details.Attribute("app.region")
+	assert.True(t, ok)
+	assert.Equal(t, "east", region)
+	_, ok = details.Attribute("app.missing")
+	assert.False(t, ok)
+
+	assert.NoError(t, details.AssertAttributeTrue("app.admin"))
+	assert.Error(t, details.AssertAttributeTrue("app.region"))
+	assert.Error(t, details.AssertAttributeTrue("app.missing"))
+}
+
+func TestInspectCertificateWithoutAttributes(t *testing.T) {
+	cert := newAttributeCert(t, time.Now().Add(time.Hour), nil, "peer", "org1")
+
+	details, err := InspectCertificate(cert)
+	require.NoError(t, err)
+
+	assert.Equal(t, "user1", details.ID)
+	assert.Equal(t, RolePeer, details.Role)
+	assert.Equal(t, "org1", details.Affiliation)
+	assert.Empty(t, details.Attributes)
+
+	_, err = InspectCertificate([]byte("invalid"))
+	assert.Error(t, err)
+}
+
+func TestInspectIdentity(t *testing.T) {
+	cert := newAttributeCert(t, time.Now().Add(time.Hour), map[string]string{"hf.Type": "admin"})
+
+	identity := mockmsp.NewMockSigningIdentity("user1", "Org1MSP")
+	identity.SetEnrollmentCertificate(cert)
+
+	details, err := InspectIdentity(identity)
+	require.NoError(t, err)
+	assert.Equal(t, RoleAdmin, details.Role, "role is taken from hf.Type when the certificate has no OU")
+
+	details, err = InspectWalletIdentity(gateway.NewX509Identity("Org1MSP", string(cert), "key"))
+	require.NoError(t, err)
+	assert.Equal(t, RoleAdmin, details.Role)
+
+	_, err = InspectIdentity(nil)
+	assert.Error(t, err)
+	_, err = InspectWalletIdentity(nil)
+	assert.Error(t, err)
+}
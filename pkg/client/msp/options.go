@@ -25,6 +25,7 @@ type enrollmentOptions struct {
 	typ      string
 	attrReqs []*AttributeRequest
 	csr      *CSRInfo
+	reuseKey bool
 }
 
 // ClientOption describes a functional parameter for the New constructor
@@ -107,3 +108,12 @@ func WithCSR(csr *CSRInfo) EnrollmentOption {
 		return nil
 	}
 }
+
+// WithReuseKey enrollment option. Only honored by Reenroll: the new certificate
+// is issued for the existing private key instead of a newly generated one.
+func WithReuseKey() EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.reuseKey = true
+		return nil
+	}
+}
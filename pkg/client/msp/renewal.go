@@ -0,0 +1,574 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"encoding/pem"
+	"strings"
+	"sync"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/metrics"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/metrics/disabled"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/multi"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/logging"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	mspctx "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
+	sdkmetrics "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fabsdk/metrics"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/gateway"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const (
+	defaultRenewBefore   = 7 * 24 * time.Hour
+	defaultCheckInterval = time.Hour
+)
+
+// RenewalEvent is emitted by the RenewalManager each time it attempts to renew an identity
+type RenewalEvent struct {
+	// ID is the enrollment ID of a user store identity or the label of a wallet identity
+	ID string
+	// MSPID of the identity
+	MSPID string
+	// Wallet is true when the identity is held in a gateway wallet
+	Wallet bool
+	// NotAfter is the expiry of the certificate that was due for renewal
+	NotAfter time.Time
+	// RenewedNotAfter is the expiry of the renewed certificate (zero if renewal failed)
+	RenewedNotAfter time.Time
+	// Err is set if renewal failed
+	Err error
+}
+
+// renewal options collector
+type renewalOptions struct {
+	renewBefore     time.Duration
+	checkInterval   time.Duration
+	reuseKey        bool
+	enrollmentOpts  []EnrollmentOption
+	listener        func(*RenewalEvent)
+	metricsProvider metrics.Provider
+}
+
+// RenewalOption describes a functional parameter for NewRenewalManager
+type RenewalOption func(*renewalOptions) error
+
+// WithRenewBefore sets how long before the certificate's NotAfter the identity is re-enrolled (default 7 days)
+func WithRenewBefore(d time.Duration) RenewalOption {
+	return func(o *renewalOptions) error {
+		if d <= 0 {
+			return errors.New("renew before duration must be positive")
+		}
+		o.renewBefore = d
+		return nil
+	}
+}
+
+// WithCheckInterval sets how often the expiry of watched identities is checked (default 1 hour)
+func WithCheckInterval(d time.Duration) RenewalOption {
+	return func(o *renewalOptions) error {
+		if d <= 0 {
+			return errors.New("check interval must be positive")
+		}
+		o.checkInterval = d
+		return nil
+	}
+}
+
+// WithRenewalKeyReuse renews user store identities for their existing private key
+// instead of generating a new key. Wallet identities always keep their key since
+// the wallet stores the key itself.
+func WithRenewalKeyReuse() RenewalOption {
+	return func(o *renewalOptions) error {
+		o.reuseKey = true
+		return nil
+	}
+}
+
+// WithRenewalEnrollmentOptions sets the options (profile, label, attribute requests, CSR)
+// passed to each re-enrollment
+func WithRenewalEnrollmentOptions(opts ...EnrollmentOption) RenewalOption {
+	return func(o *renewalOptions) error {
+		o.enrollmentOpts = append(o.enrollmentOpts, opts...)
+		return nil
+	}
+}
+
+// WithRenewalListener registers a callback invoked after each renewal attempt
+func WithRenewalListener(listener func(*RenewalEvent)) RenewalOption {
+	return func(o *renewalOptions) error {
+		o.listener = listener
+		return nil
+	}
+}
+
+// WithRenewalMetricsProvider sets the provider used to create the renewal metrics
+func WithRenewalMetricsProvider(p metrics.Provider) RenewalOption {
+	return func(o *renewalOptions) error {
+		o.metricsProvider = p
+		return nil
+	}
+}
+
+// RenewalManager watches the expiry of enrolled identities and re-enrolls them
+// a configurable time before their enrollment certificate expires.
+//
+// Identities in the user store are renewed through Reenroll; identities obtained
+// from Identity or WalletIdentity are swapped in place so that SDK contexts created
+// with them (e.g. fabsdk.WithIdentity or gateway.WithSigningIdentity) sign with the
+// renewed certificate from then on.
+type RenewalManager struct {
+	client  *Client
+	mspID   string
+	opts    renewalOptions
+	metrics *sdkmetrics.RenewalMetrics
+	now     func() time.Time
+
+	mutex      sync.Mutex
+	watched    map[string]bool
+	identities map[string]*RenewableIdentity
+	wallets    map[walletKey]*walletEntry
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// wallet identities are keyed by wallet and label since different wallets may hold identities with the same label
+type walletKey struct {
+	wallet *gateway.Wallet
+	label  string
+}
+
+type walletEntry struct {
+	wallet   *gateway.Wallet
+	label    string
+	identity *RenewableIdentity
+}
+
+// NewRenewalManager returns a renewal manager for the identities of the client's organization.
+// Call Start to begin watching; identities are only checked on demand (CheckNow) before that.
+func (c *Client) NewRenewalManager(opts ...RenewalOption) (*RenewalManager, error) {
+	o := renewalOptions{
+		renewBefore:     defaultRenewBefore,
+		checkInterval:   defaultCheckInterval,
+		metricsProvider: &disabled.Provider{},
+	}
+	for _, param := range opts {
+		err := param(&o)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create renewal manager")
+		}
+	}
+
+	org, ok := c.ctx.EndpointConfig().NetworkConfig().Organizations[strings.ToLower(c.orgName)]
+	if !ok {
+		return nil, errors.Errorf("non-existent organization: '%s'", c.orgName)
+	}
+
+	return &RenewalManager{
+		client:     c,
+		mspID:      org.MSPID,
+		opts:       o,
+		metrics:    sdkmetrics.NewRenewalMetrics(o.metricsProvider),
+		now:        time.Now,
+		watched:    make(map[string]bool),
+		identities: make(map[string]*RenewableIdentity),
+		wallets:    make(map[walletKey]*walletEntry),
+	}, nil
+}
+
+// Watch adds user store identities to the set of identities checked for expiry.
+// Identities of user stores that can be listed (e.g. the default file user store) are watched automatically.
+func (m *RenewalManager) Watch(enrollmentIDs ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, id := range enrollmentIDs {
+		m.watched[id] = true
+	}
+}
+
+// Identity returns a signing identity for the given enrolled user that follows renewals.
+// The identity is watched for expiry.
+func (m *RenewalManager) Identity(enrollmentID string) (*RenewableIdentity, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if id, ok := m.identities[enrollmentID]; ok {
+		return id, nil
+	}
+
+	si, err := m.client.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	id := &RenewableIdentity{current: si}
+	m.identities[enrollmentID] = id
+	m.watched[enrollmentID] = true
+	return id, nil
+}
+
+// WalletIdentity returns a signing identity for the given wallet identity that follows renewals.
+// The wallet identity is watched for expiry and the renewed certificate is written back to the wallet.
+func (m *RenewalManager) WalletIdentity(wallet *gateway.Wallet, label string) (*RenewableIdentity, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := walletKey{wallet: wallet, label: label}
+	if entry, ok := m.wallets[key]; ok {
+		return entry.identity, nil
+	}
+
+	x509ID, err := getWalletX509Identity(wallet, label)
+	if err != nil {
+		return nil, err
+	}
+	si, err := gateway.NewWalletSigningIdentity(label, x509ID, m.client.ctx.CryptoSuite())
+	if err != nil {
+		return nil, err
+	}
+
+	id := &RenewableIdentity{current: si}
+	m.wallets[key] = &walletEntry{wallet: wallet, label: label, identity: id}
+	return id, nil
+}
+
+// Start starts checking watched identities periodically. Identities are checked immediately.
+func (m *RenewalManager) Start() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go m.run(m.stop, m.done)
+}
+
+// Stop stops the periodic checks and waits for an ongoing check to complete
+func (m *RenewalManager) Stop() {
+	m.mutex.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (m *RenewalManager) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.opts.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.CheckNow(); err != nil {
+			logger.Warnf("certificate renewal failed: %s", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// CheckNow checks all watched identities and renews those that expire within the renew-before window
+func (m *RenewalManager) CheckNow() error {
+	var errs error
+
+	ids, err := m.userStoreIdentities()
+	if err != nil {
+		errs = multi.Append(errs, err)
+	}
+	for _, id := range ids {
+		if err := m.checkUser(id); err != nil {
+			errs = multi.Append(errs, err)
+		}
+	}
+
+	for _, entry := range m.walletEntries() {
+		if err := m.checkWallet(entry); err != nil {
+			errs = multi.Append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// Renew renews the given user store identity regardless of its expiry
+func (m *RenewalManager) Renew(enrollmentID string) error {
+	user, err := m.client.ctx.UserStore().Load(mspctx.IdentityIdentifier{ID: enrollmentID, MSPID: m.mspID})
+	if err != nil {
+		return errors.WithMessagef(err, "loading user %s failed", enrollmentID)
+	}
+	cert, err := parseCertificate(user.EnrollmentCertificate)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid enrollment certificate for user %s", enrollmentID)
+	}
+	return m.renewUser(enrollmentID, cert.NotAfter)
+}
+
+func (m *RenewalManager) userStoreIdentities() ([]string, error) {
+	m.mutex.Lock()
+	set := make(map[string]bool, len(m.watched))
+	for id := range m.watched {
+		set[id] = true
+	}
+	m.mutex.Unlock()
+
+	var err error
+	if lister, ok := m.client.ctx.UserStore().(mspctx.UserLister); ok {
+		var users []*mspctx.UserData
+		users, err = lister.List()
+		for _, u := range users {
+			if u.MSPID == m.mspID {
+				set[u.ID] = true
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	return ids, errors.WithMessage(err, "listing user store identities failed")
+}
+
+func (m *RenewalManager) walletEntries() []*walletEntry {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entries := make([]*walletEntry, 0, len(m.wallets))
+	for _, entry := range m.wallets {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (m *RenewalManager) checkUser(enrollmentID string) error {
+	user, err := m.client.ctx.UserStore().Load(mspctx.IdentityIdentifier{ID: enrollmentID, MSPID: m.mspID})
+	if err != nil {
+		return errors.WithMessagef(err, "loading user %s failed", enrollmentID)
+	}
+	cert, err := parseCertificate(user.EnrollmentCertificate)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid enrollment certificate for user %s", enrollmentID)
+	}
+
+	m.metrics.CertExpiry.With("mspid", m.mspID, "id", enrollmentID).Set(cert.NotAfter.Sub(m.now()).Seconds())
+	if !m.due(cert) {
+		return nil
+	}
+	return m.renewUser(enrollmentID, cert.NotAfter)
+}
+
+func (m *RenewalManager) renewUser(enrollmentID string, notAfter time.Time) error {
+	logger.Infof("renewing enrollment certificate of %s (expires %s)", enrollmentID, notAfter)
+
+	opts := m.opts.enrollmentOpts
+	if m.opts.reuseKey {
+		opts = append(opts[:len(opts):len(opts)], WithReuseKey())
+	}
+
+	evt := &RenewalEvent{ID: enrollmentID, MSPID: m.mspID, NotAfter: notAfter}
+	err := m.client.Reenroll(enrollmentID, opts...)
+	if err == nil {
+		var si mspctx.SigningIdentity
+		si, err = m.client.GetSigningIdentity(enrollmentID)
+		if err == nil {
+			evt.RenewedNotAfter, err = notAfterOf(si.EnrollmentCertificate())
+			m.swap(m.identity(enrollmentID), si)
+		}
+	}
+	return m.publish(evt, err)
+}
+
+func (m *RenewalManager) checkWallet(entry *walletEntry) error {
+	x509ID, err := getWalletX509Identity(entry.wallet, entry.label)
+	if err != nil {
+		return err
+	}
+	cert, err := parseCertificate([]byte(x509ID.Certificate()))
+	if err != nil {
+		return errors.WithMessagef(err, "invalid certificate for wallet identity %s", entry.label)
+	}
+
+	m.metrics.CertExpiry.With("mspid", x509ID.MspID, "id", entry.label).Set(cert.NotAfter.Sub(m.now()).Seconds())
+	if !m.due(cert) {
+		return nil
+	}
+
+	logger.Infof("renewing certificate of wallet identity %s (expires %s)", entry.label, cert.NotAfter)
+
+	evt := &RenewalEvent{ID: entry.label, MSPID: x509ID.MspID, Wallet: true, NotAfter: cert.NotAfter}
+	renewed, err := m.renewWalletIdentity(entry, x509ID)
+	if err == nil {
+		evt.RenewedNotAfter, err = notAfterOf(renewed)
+	}
+	return m.publish(evt, err)
+}
+
+func (m *RenewalManager) renewWalletIdentity(entry *walletEntry, x509ID *gateway.X509Identity) ([]byte, error) {
+	si, err := gateway.NewWalletSigningIdentity(entry.label, x509ID, m.client.ctx.CryptoSuite())
+	if err != nil {
+		return nil, err
+	}
+
+	// The wallet holds the private key, so the renewed certificate is issued for the same key
+	opts := append(m.opts.enrollmentOpts[:len(m.opts.enrollmentOpts):len(m.opts.enrollmentOpts)], WithReuseKey())
+	req, err := m.client.newReenrollmentRequest(entry.label, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := newCAClient(m.client.ctx, m.client.orgName, m.client.caID)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := ca.ReenrollIdentity(si, req)
+	if err != nil {
+		return nil, err
+	}
+
+	renewedID := gateway.NewX509Identity(x509ID.MspID, string(cert), x509ID.Key())
+	if err := entry.wallet.Put(entry.label, renewedID); err != nil {
+		return nil, errors.WithMessagef(err, "storing renewed wallet identity %s failed", entry.label)
+	}
+	renewedSI, err := gateway.NewWalletSigningIdentity(entry.label, renewedID, m.client.ctx.CryptoSuite())
+	if err != nil {
+		return nil, err
+	}
+	m.swap(entry.identity, renewedSI)
+	return cert, nil
+}
+
+func (m *RenewalManager) identity(enrollmentID string) *RenewableIdentity {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.identities[enrollmentID]
+}
+
+func (m *RenewalManager) swap(id *RenewableIdentity, si mspctx.SigningIdentity) {
+	if id != nil {
+		id.set(si)
+	}
+}
+
+func (m *RenewalManager) due(cert *x509.Certificate) bool {
+	return !m.now().Add(m.opts.renewBefore).Before(cert.NotAfter)
+}
+
+func (m *RenewalManager) publish(evt *RenewalEvent, err error) error {
+	labels := []string{"mspid", evt.MSPID, "id", evt.ID}
+	if err != nil {
+		err = errors.WithMessagef(err, "renewing certificate of %s failed", evt.ID)
+		evt.Err = err
+		m.metrics.RenewalsFailed.With(labels...).Add(1)
+	} else {
+		m.metrics.RenewalsSucceeded.With(labels...).Add(1)
+		m.metrics.CertExpiry.With(labels...).Set(evt.RenewedNotAfter.Sub(m.now()).Seconds())
+	}
+	if m.opts.listener != nil {
+		m.opts.listener(evt)
+	}
+	return err
+}
+
+func getWalletX509Identity(wallet *gateway.Wallet, label string) (*gateway.X509Identity, error) {
+	id, err := wallet.Get(label)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "loading wallet identity %s failed", label)
+	}
+	x509ID, ok := id.(*gateway.X509Identity)
+	if !ok {
+		return nil, errors.Errorf("wallet identity %s is not an X.509 identity", label)
+	}
+	return x509ID, nil
+}
+
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("no PEM data found in certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func notAfterOf(certPEM []byte) (time.Time, error) {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return time.Time{}, errors.WithMessage(err, "invalid renewed certificate")
+	}
+	return cert.NotAfter, nil
+}
+
+// RenewableIdentity is a signing identity whose certificate and key are swapped
+// atomically when the RenewalManager renews it
+type RenewableIdentity struct {
+	mutex   sync.RWMutex
+	current mspctx.SigningIdentity
+}
+
+// Current returns the signing identity currently in use
+func (r *RenewableIdentity) Current() mspctx.SigningIdentity {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.current
+}
+
+func (r *RenewableIdentity) set(si mspctx.SigningIdentity) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.current = si
+}
+
+// Identifier returns the identifier of the current identity
+func (r *RenewableIdentity) Identifier() *mspctx.IdentityIdentifier {
+	return r.Current().Identifier()
+}
+
+// Verify a signature over some message using the current identity as reference
+func (r *RenewableIdentity) Verify(msg []byte, sig []byte) error {
+	return r.Current().Verify(msg, sig)
+}
+
+// Serialize converts the current identity to bytes
+func (r *RenewableIdentity) Serialize() ([]byte, error) {
+	return r.Current().Serialize()
+}
+
+// EnrollmentCertificate returns the enrollment certificate of the current identity
+func (r *RenewableIdentity) EnrollmentCertificate() []byte {
+	return r.Current().EnrollmentCertificate()
+}
+
+// Sign the message with the current identity
+func (r *RenewableIdentity) Sign(msg []byte) ([]byte, error) {
+	return r.Current().Sign(msg)
+}
+
+// PublicVersion returns the public parts of the current identity
+func (r *RenewableIdentity) PublicVersion() mspctx.Identity {
+	return r.Current().PublicVersion()
+}
+
+// PrivateKey returns the private key of the current identity
+func (r *RenewableIdentity) PrivateKey() core.Key {
+	return r.Current().PrivateKey()
+}
@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"sync"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"github.com/pkg/errors"
+)
+
+const defaultPolicyRefreshInterval = 5 * time.Minute
+
+type cachedPolicy struct {
+	policy  *common.SignaturePolicyEnvelope
+	expires time.Time
+}
+
+// LifecyclePolicyProvider provides the endorsement policies of the chaincodes that are committed
+// to a channel with the _lifecycle chaincode. It may be passed to channel.WithEndorsementPolicyCheck
+// in order to check the endorsements of a transaction before it is sent to the orderer.
+type LifecyclePolicyProvider struct {
+	channelID       string
+	refreshInterval time.Duration
+	queryCommitted  func(name string) ([]LifecycleChaincodeDefinition, error)
+	mutex           sync.Mutex
+	policies        map[string]*cachedPolicy
+}
+
+// NewLifecyclePolicyProvider returns a provider of the endorsement policies of the chaincodes committed
+// to the given channel. The policies are queried with the given client and options and are cached for
+// the given refresh interval (5 minutes if not positive), so that upgrades of the chaincode definitions
+// are picked up.
+func NewLifecyclePolicyProvider(rc *Client, channelID string, refreshInterval time.Duration, options ...RequestOption) *LifecyclePolicyProvider {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultPolicyRefreshInterval
+	}
+
+	return &LifecyclePolicyProvider{
+		channelID:       channelID,
+		refreshInterval: refreshInterval,
+		queryCommitted: func(name string) ([]LifecycleChaincodeDefinition, error) {
+			return rc.LifecycleQueryCommittedCC(channelID, LifecycleQueryCommittedCCRequest{Name: name}, options...)
+		},
+		policies: make(map[string]*cachedPolicy),
+	}
+}
+
+// GetChaincodePolicy returns the signature policy of the committed definition of the given chaincode.
+// Nil is returned if the endorsement policy of the chaincode is a channel config policy (e.g. the default
+// /Channel/Application/Endorsement), since such policies can only be evaluated with the channel
+// configuration, or if the chaincode is not committed with the lifecycle chaincode (e.g. it was
+// instantiated with lscc); the endorsement policy check then falls back to the policy derived by the
+// selection service from the discovery endorsement layouts.
+func (p *LifecyclePolicyProvider) GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
+	p.mutex.Lock()
+	cached, ok := p.policies[chaincodeID]
+	p.mutex.Unlock()
+
+	if ok && time.Now().Before(cached.expires) {
+		return cached.policy, nil
+	}
+
+	// The lock is not held while querying so that the policies of other chaincodes are not blocked
+	definitions, err := p.queryCommitted(chaincodeID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to query committed definition of chaincode [%s] on channel [%s]", chaincodeID, p.channelID)
+	}
+
+	var policy *common.SignaturePolicyEnvelope
+	if len(definitions) == 0 {
+		logger.Debugf("Chaincode [%s] is not committed on channel [%s] with the lifecycle chaincode", chaincodeID, p.channelID)
+	} else if policy = definitions[0].SignaturePolicy; policy == nil {
+		logger.Debugf("Chaincode [%s] is endorsed according to channel config policy [%s]", chaincodeID, definitions[0].ChannelConfigPolicy)
+	}
+
+	p.mutex.Lock()
+	p.policies[chaincodeID] = &cachedPolicy{
+		policy:  policy,
+		expires: time.Now().Add(p.refreshInterval),
+	}
+	p.mutex.Unlock()
+
+	return policy, nil
+}
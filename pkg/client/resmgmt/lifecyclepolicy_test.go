@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/policydsl"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecyclePolicyProvider(t *testing.T) {
+	policy := policydsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"})
+
+	queries := 0
+	var queryErr error
+	p := NewLifecyclePolicyProvider(nil, "mychannel", time.Minute)
+	p.queryCommitted = func(name string) ([]LifecycleChaincodeDefinition, error) {
+		queries++
+		if queryErr != nil {
+			return nil, queryErr
+		}
+		switch name {
+		case "cc1":
+			return []LifecycleChaincodeDefinition{{Name: name, SignaturePolicy: policy}}, nil
+		case "cc2":
+			return []LifecycleChaincodeDefinition{{Name: name, ChannelConfigPolicy: "/Channel/Application/Endorsement"}}, nil
+		default:
+			return nil, nil
+		}
+	}
+
+	t.Run("Signature policy", func(t *testing.T) {
+		actual, err := p.GetChaincodePolicy("cc1")
+		require.NoError(t, err)
+		require.Equal(t, policy, actual)
+
+		_, err = p.GetChaincodePolicy("cc1")
+		require.NoError(t, err)
+		require.Equal(t, 1, queries, "expecting the policy to be cached")
+	})
+
+	t.Run("Channel config policy", func(t *testing.T) {
+		actual, err := p.GetChaincodePolicy("cc2")
+		require.NoError(t, err)
+		require.Nil(t, actual)
+	})
+
+	t.Run("Not committed", func(t *testing.T) {
+		actual, err := p.GetChaincodePolicy("cc3")
+		require.NoError(t, err)
+		require.Nil(t, actual)
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		p.refreshInterval = time.Millisecond
+		p.policies = make(map[string]*cachedPolicy)
+		_, err := p.GetChaincodePolicy("cc1")
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+
+		queryErr = errors.New("injected query error")
+		_, err = p.GetChaincodePolicy("cc1")
+		require.Error(t, err, "expecting the policy to be queried again after the refresh interval")
+	})
+}
+
+func TestLifecyclePolicyProviderConcurrentQueries(t *testing.T) {
+	policy := policydsl.SignedByAnyMember([]string{"Org1MSP"})
+
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	p := NewLifecyclePolicyProvider(nil, "mychannel", time.Minute)
+	p.queryCommitted = func(name string) ([]LifecycleChaincodeDefinition, error) {
+		if name == "slowcc" {
+			close(blocked)
+			<-release
+		}
+		return []LifecycleChaincodeDefinition{{Name: name, SignaturePolicy: policy}}, nil
+	}
+
+	errch := make(chan error, 1)
+	go func() {
+		_, err := p.GetChaincodePolicy("slowcc")
+		errch <- err
+	}()
+	<-blocked
+
+	// The policy of another chaincode is available while the slow query is in progress
+	actual, err := p.GetChaincodePolicy("cc1")
+	require.NoError(t, err)
+	require.Equal(t, policy, actual)
+
+	close(release)
+	require.NoError(t, <-errch)
+}
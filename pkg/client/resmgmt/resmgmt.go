@@ -33,6 +33,7 @@ import (
 	"github.com/pkg/errors"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/sdkinternal/configtxlator/update"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/circuitbreaker"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/verifier"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/multi"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/retry"
@@ -136,6 +137,7 @@ type Client struct {
 	filter             fab.TargetFilter
 	localCtxProvider   context.LocalProvider
 	lifecycleProcessor *lifecycleProcessor
+	breaker            *circuitbreaker.Breaker
 }
 
 // mspFilter filters peers by MSP ID
@@ -159,6 +161,20 @@ func WithDefaultTargetFilter(filter fab.TargetFilter) ClientOption {
 	}
 }
 
+// WithCircuitBreaker sets the circuit breaker that records the outcome of the requests sent to
+// peers and orderers. Orderers whose circuit is open are not chosen and requests to them fail
+// fast; peers are never dropped from the targets of administrative operations such as
+// JoinChannel and InstallCC. The breaker may be shared with other clients.
+func WithCircuitBreaker(breaker *circuitbreaker.Breaker) ClientOption {
+	return func(rmc *Client) error {
+		if breaker == nil {
+			return errors.New("circuit breaker is required")
+		}
+		rmc.breaker = breaker
+		return nil
+	}
+}
+
 // New returns a resource management client instance.
 func New(ctxProvider context.ClientProvider, opts ...ClientOption) (*Client, error) {
 	ctx, err := ctxProvider()
@@ -218,7 +234,7 @@ func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error
 	rc.resolveTimeouts(&opts)
 
 	//set parent request context for overall timeout
-	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
+	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext), contextImpl.WithCircuitBreaker(rc.circuitBreaker()))
 	parentReqCtx = reqContext.WithValue(parentReqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts)
 	defer parentReqCancel()
 
@@ -337,9 +353,22 @@ func (rc *Client) calculateTargets(targets []fab.Peer, filter fab.TargetFilter)
 		targets = filterTargets(targets, targetFilter)
 	}
 
+	if rc.breaker != nil {
+		// Record the outcome of the proposals so that failing peers are reported
+		targets = rc.breaker.Peers(targets)
+	}
+
 	return targets, nil
 }
 
+// circuitBreaker returns the circuit breaker to set in request contexts (nil if none)
+func (rc *Client) circuitBreaker() fab.CircuitBreaker {
+	if rc.breaker == nil {
+		return nil
+	}
+	return rc.breaker
+}
+
 // isChaincodeInstalled verify if chaincode is installed on peer
 func (rc *Client) isChaincodeInstalled(reqCtx reqContext.Context, req InstallCCRequest, peer fab.ProposalProcessor, retryOpts retry.Opts) (bool, error) {
 
@@ -385,7 +414,7 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 	rc.resolveTimeouts(&opts)
 
 	//set parent request context for overall timeout
-	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
+	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext), contextImpl.WithCircuitBreaker(rc.circuitBreaker()))
 	parentReqCtx = reqContext.WithValue(parentReqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts)
 	defer parentReqCancel()
 
@@ -692,6 +721,14 @@ func (rc *Client) selectRandomChannelPeer(ctx context.Channel) (fab.ProposalProc
 		return nil, errors.Errorf("no targets in MSP [%s]", ctx.Identifier().MSPID)
 	}
 
+	if rc.breaker != nil {
+		// Any of the peers will do, so avoid the ones that are failing
+		if available := filterTargets(targets, rc.breaker); len(available) > 0 {
+			targets = available
+		}
+		targets = rc.breaker.Peers(targets)
+	}
+
 	// select random channel peer
 	randomNumber := rand.Intn(len(targets))
 	return targets[randomNumber], nil
@@ -1310,13 +1347,13 @@ func (rc *Client) requestOrderer(opts *requestOptions, channelID string) (fab.Or
 }
 
 func (rc *Client) ordererConfig(channelID string) (*fab.OrdererConfig, error) {
-	orderers := rc.ctx.EndpointConfig().ChannelOrderers(channelID)
+	orderers := rc.availableOrderers(rc.ctx.EndpointConfig().ChannelOrderers(channelID))
 	if len(orderers) > 0 {
 		randomNumber := rand.Intn(len(orderers))
 		return &orderers[randomNumber], nil
 	}
 
-	orderers = rc.ctx.EndpointConfig().OrderersConfig()
+	orderers = rc.availableOrderers(rc.ctx.EndpointConfig().OrderersConfig())
 	if len(orderers) == 0 {
 		return nil, errors.New("no orderers found")
 	}
@@ -1326,6 +1363,24 @@ func (rc *Client) ordererConfig(channelID string) (*fab.OrdererConfig, error) {
 	return &orderers[randomNumber], nil
 }
 
+// availableOrderers returns the orderers whose circuit is not open. If the circuits of all
+// orderers are open then all orderers are returned and the request fails fast when it is sent.
+func (rc *Client) availableOrderers(orderers []fab.OrdererConfig) []fab.OrdererConfig {
+	if rc.breaker == nil {
+		return orderers
+	}
+	var available []fab.OrdererConfig
+	for _, orderer := range orderers {
+		if rc.breaker.State(orderer.URL) != circuitbreaker.Open {
+			available = append(available, orderer)
+		}
+	}
+	if len(available) == 0 {
+		return orderers
+	}
+	return available
+}
+
 // prepareRequestOpts prepares request options
 func (rc *Client) prepareRequestOpts(options ...RequestOption) (requestOptions, error) {
 	opts := requestOptions{}
@@ -1352,7 +1407,7 @@ func (rc *Client) createRequestContext(opts requestOptions, defaultTimeoutType f
 		opts.Timeouts[defaultTimeoutType] = rc.ctx.EndpointConfig().Timeout(defaultTimeoutType)
 	}
 
-	return contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[defaultTimeoutType]), contextImpl.WithParent(opts.ParentContext))
+	return contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[defaultTimeoutType]), contextImpl.WithParent(opts.ParentContext), contextImpl.WithCircuitBreaker(rc.circuitBreaker()))
 }
 
 //resolveTimeouts sets default for timeouts from config if not provided through opts
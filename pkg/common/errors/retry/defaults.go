@@ -38,6 +38,18 @@ const (
 	ResMgmtDefaultBackoffFactor = 2.5
 )
 
+// Orderer Broadcast Suggested Defaults
+const (
+	// BroadcastDefaultAttempts number of times a transaction is broadcast again by default
+	BroadcastDefaultAttempts = 3
+	// BroadcastDefaultInitialBackoff default initial backoff
+	BroadcastDefaultInitialBackoff = 500 * time.Millisecond
+	// BroadcastDefaultMaxBackoff default maximum backoff
+	BroadcastDefaultMaxBackoff = 5 * time.Second
+	// BroadcastDefaultBackoffFactor default backoff factor
+	BroadcastDefaultBackoffFactor = 2.0
+)
+
 // DefaultOpts default retry options
 var DefaultOpts = Opts{
 	Attempts:       DefaultAttempts,
@@ -65,6 +77,16 @@ var DefaultResMgmtOpts = Opts{
 	RetryableCodes: ResMgmtDefaultRetryableCodes,
 }
 
+// DefaultBroadcastOpts default retry options used when the same transaction is broadcast
+// again after all orderers have failed (e.g. while a Raft cluster elects a new leader)
+var DefaultBroadcastOpts = Opts{
+	Attempts:       BroadcastDefaultAttempts,
+	InitialBackoff: BroadcastDefaultInitialBackoff,
+	MaxBackoff:     BroadcastDefaultMaxBackoff,
+	BackoffFactor:  BroadcastDefaultBackoffFactor,
+	RetryableCodes: BroadcastRetryableCodes,
+}
+
 // DefaultRetryableCodes these are the error codes, grouped by source of error,
 // that are considered to be transient error conditions by default
 var DefaultRetryableCodes = map[status.Group][]status.Code{
@@ -157,6 +179,20 @@ var ChannelClientRetryableCodes = map[status.Group][]status.Code{
 	},
 }
 
+// BroadcastRetryableCodes are the codes for which the same transaction (same transaction ID)
+// is broadcast again. Broadcasting the same envelope again never endorses a new transaction.
+var BroadcastRetryableCodes = map[status.Group][]status.Code{
+	status.OrdererClientStatus: {
+		status.ConnectionFailed,
+	},
+	status.OrdererServerStatus: {
+		status.Code(common.Status_SERVICE_UNAVAILABLE),
+	},
+	status.GRPCTransportStatus: {
+		status.Code(grpcCodes.Unavailable),
+	},
+}
+
 // ChannelConfigRetryableCodes error codes to be taken into account for query channel config retry
 var ChannelConfigRetryableCodes = map[status.Group][]status.Code{
 	status.EndorserClientStatus: {status.EndorsementMismatch},
@@ -60,6 +60,29 @@ const (
 
 	// PvtDataDisseminationFailed indicates that Gossip failed to disseminate private data to the required number of peers
 	PvtDataDisseminationFailed Code = 24
+
+	// CertificateRevoked is returned when an endorser or orderer certificate appears in a
+	// revocation list of its channel MSP
+	CertificateRevoked Code = 25
+
+	// CertificateExpired is returned when an endorser or orderer certificate has expired or is not yet valid
+	CertificateExpired Code = 26
+
+	// UntrustedCertificate is returned when an endorser or orderer certificate does not chain
+	// to the root certificates of a channel MSP
+	UntrustedCertificate Code = 27
+
+	// IdentityRoleMismatch is returned when an endorser or orderer identity does not carry the
+	// OU or NodeOU role required by its channel MSP
+	IdentityRoleMismatch Code = 28
+
+	// BroadcastInProgress is returned when a transaction is sent to the ordering service while
+	// the same transaction (same transaction ID) is still being broadcast
+	BroadcastInProgress Code = 29
+
+	// EndorsementPolicyNotSatisfied is returned when the endorsements collected for a transaction
+	// do not satisfy the endorsement policy of the chaincode
+	EndorsementPolicyNotSatisfied Code = 30
 )
 
 // CodeName maps the codes in this packages to human-readable strings
@@ -78,6 +101,12 @@ var CodeName = map[int32]string{
 	12: "GENERIC_TRANSIENT",
 	23: "CHAINCODE_NAME_NOT_FOUND",
 	24: "PRIVATE_DATA_DISSEMINATION_FAILED",
+	25: "CERTIFICATE_REVOKED",
+	26: "CERTIFICATE_EXPIRED",
+	27: "UNTRUSTED_CERTIFICATE",
+	28: "IDENTITY_ROLE_MISMATCH",
+	29: "BROADCAST_IN_PROGRESS",
+	30: "ENDORSEMENT_POLICY_NOT_SATISFIED",
 }
 
 // ToInt32 cast to int32
@@ -86,6 +86,15 @@ type Key interface {
 	PublicKey() (Key, error)
 }
 
+// SigningKey is a private key that produces signatures itself instead of through
+// the CryptoSuite, for example a key that is held by an external signing service.
+type SigningKey interface {
+	Key
+
+	// Sign signs digest. The caller is responsible for hashing the message.
+	Sign(digest []byte, opts SignerOpts) (signature []byte, err error)
+}
+
 // HashOpts contains options for hashing with a CSP.
 type HashOpts interface {
 
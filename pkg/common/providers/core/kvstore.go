@@ -32,3 +32,12 @@ type KVStore interface {
 	 */
 	Delete(key interface{}) error
 }
+
+// KVStoreKeyLister is implemented by key-value stores that can list the keys they hold.
+type KVStoreKeyLister interface {
+
+	/**
+	 * Keys returns the string keys that start with the given prefix.
+	 */
+	Keys(prefix string) ([]string, error)
+}
@@ -16,7 +16,11 @@ type CryptoSuiteConfig interface {
 	SecurityProviderLibPath() string
 	SecurityProviderPin() string
 	SecurityProviderLabel() string
+	SecurityProviderMechanisms() map[string]uint
+	SecurityProviderImmutable() bool
+	SecurityProviderEphemeral() bool
 	KeyStorePath() string
+	KeyStorePassphrase() ([]byte, error)
 }
 
 // Providers represents the SDK configured core providers context.
@@ -54,6 +54,8 @@ type CCEvent struct {
 	// BlockNumber contains the block number in which the
 	// chaincode event was committed
 	BlockNumber uint64
+	// TxIndex is the index of the transaction within the block
+	TxIndex int
 	// SourceURL specifies the URL of the peer that produced the event
 	SourceURL string
 }
@@ -160,3 +162,37 @@ type EventClient interface {
 	// - close: If true then the client will also be closed
 	TransferRegistrations(close bool) (EventSnapshot, error)
 }
+
+// EventCheckpoint records the point up to which channel events have been processed.
+type EventCheckpoint struct {
+	// BlockNum is the number of the block from which processing resumes.
+	// All blocks before BlockNum have been fully processed.
+	BlockNum uint64
+	// TxIndex is the index (within BlockNum) of the last processed transaction,
+	// or -1 if no transaction in BlockNum has been processed yet.
+	TxIndex int
+}
+
+// Processed returns true if the transaction at txIndex in the given block has already been processed.
+func (cp *EventCheckpoint) Processed(blockNum uint64, txIndex int) bool {
+	if cp == nil {
+		return false
+	}
+	if blockNum != cp.BlockNum {
+		return blockNum < cp.BlockNum
+	}
+	return txIndex <= cp.TxIndex
+}
+
+// EventCheckpointer persists the progress of event processing so that, after a
+// reconnect or a restart, the event client resumes from the last checkpoint.
+type EventCheckpointer interface {
+	// Checkpoint returns the current checkpoint or nil if nothing has been checkpointed yet.
+	Checkpoint() (*EventCheckpoint, error)
+
+	// CheckpointBlock records that the given block has been fully processed.
+	CheckpointBlock(blockNum uint64) error
+
+	// CheckpointTransaction records that the transaction at txIndex in the given block has been processed.
+	CheckpointTransaction(blockNum uint64, txIndex int) error
+}
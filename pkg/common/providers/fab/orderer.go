@@ -25,3 +25,16 @@ type SignedEnvelope struct {
 	Payload   []byte
 	Signature []byte
 }
+
+// OrdererSelector determines the order in which orderers are tried when an envelope is
+// sent to the ordering service. The next orderer is only tried if the previous one failed.
+type OrdererSelector interface {
+	Select(orderers []Orderer) []Orderer
+}
+
+// OrdererObserver may be implemented by an OrdererSelector that needs to know the outcome
+// of the broadcasts (e.g. to select orderers by latency). Begin is invoked before an envelope
+// is broadcast to the orderer with the given URL and the returned function with the outcome.
+type OrdererObserver interface {
+	Begin(url string) func(err error)
+}
@@ -83,6 +83,16 @@ type PrioritySelector interface {
 	Compare(peer1, peer2 Peer) int
 }
 
+// CircuitBreaker tracks the outcome of requests sent to endpoints (peers and orderers)
+// and rejects requests to endpoints that keep failing
+type CircuitBreaker interface {
+	// Allow returns true if a request may be sent to the endpoint with the given URL. It is
+	// called right before the request is sent and may take up a probe of a recovering endpoint.
+	Allow(url string) bool
+	// Record records the outcome of a request sent to the endpoint with the given URL
+	Record(url string, err error)
+}
+
 // CommManager enables network communication.
 type CommManager interface {
 	DialContext(ctx reqContext.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error)
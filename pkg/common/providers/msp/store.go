@@ -20,6 +20,11 @@ type UserStore interface {
 	Load(IdentityIdentifier) (*UserData, error)
 }
 
+// UserLister is implemented by UserStores that are able to enumerate the users they hold
+type UserLister interface {
+	List() ([]*UserData, error)
+}
+
 // PrivKeyKey is a composite key for accessing a private key in the key store
 type PrivKeyKey struct {
 	ID    string
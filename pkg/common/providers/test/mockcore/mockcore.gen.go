@@ -48,6 +48,21 @@ func (mr *MockCryptoSuiteConfigMockRecorder) IsSecurityEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSecurityEnabled", reflect.TypeOf((*MockCryptoSuiteConfig)(nil).IsSecurityEnabled))
 }
 
+// KeyStorePassphrase mocks base method
+func (m *MockCryptoSuiteConfig) KeyStorePassphrase() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyStorePassphrase")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeyStorePassphrase indicates an expected call of KeyStorePassphrase
+func (mr *MockCryptoSuiteConfigMockRecorder) KeyStorePassphrase() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyStorePassphrase", reflect.TypeOf((*MockCryptoSuiteConfig)(nil).KeyStorePassphrase))
+}
+
 // KeyStorePath mocks base method
 func (m *MockCryptoSuiteConfig) KeyStorePath() string {
 	m.ctrl.T.Helper()
@@ -104,6 +119,34 @@ func (mr *MockCryptoSuiteConfigMockRecorder) SecurityProvider() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProvider", reflect.TypeOf((*MockCryptoSuiteConfig)(nil).SecurityProvider))
 }
 
+// SecurityProviderEphemeral mocks base method
+func (m *MockCryptoSuiteConfig) SecurityProviderEphemeral() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProviderEphemeral")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SecurityProviderEphemeral indicates an expected call of SecurityProviderEphemeral
+func (mr *MockCryptoSuiteConfigMockRecorder) SecurityProviderEphemeral() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProviderEphemeral", reflect.TypeOf((*MockCryptoSuiteConfig)(nil).SecurityProviderEphemeral))
+}
+
+// SecurityProviderImmutable mocks base method
+func (m *MockCryptoSuiteConfig) SecurityProviderImmutable() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProviderImmutable")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SecurityProviderImmutable indicates an expected call of SecurityProviderImmutable
+func (mr *MockCryptoSuiteConfigMockRecorder) SecurityProviderImmutable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProviderImmutable", reflect.TypeOf((*MockCryptoSuiteConfig)(nil).SecurityProviderImmutable))
+}
+
 // SecurityProviderLabel mocks base method
 func (m *MockCryptoSuiteConfig) SecurityProviderLabel() string {
 	m.ctrl.T.Helper()
@@ -132,6 +175,20 @@ func (mr *MockCryptoSuiteConfigMockRecorder) SecurityProviderLibPath() *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProviderLibPath", reflect.TypeOf((*MockCryptoSuiteConfig)(nil).SecurityProviderLibPath))
 }
 
+// SecurityProviderMechanisms mocks base method
+func (m *MockCryptoSuiteConfig) SecurityProviderMechanisms() map[string]uint {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProviderMechanisms")
+	ret0, _ := ret[0].(map[string]uint)
+	return ret0
+}
+
+// SecurityProviderMechanisms indicates an expected call of SecurityProviderMechanisms
+func (mr *MockCryptoSuiteConfigMockRecorder) SecurityProviderMechanisms() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProviderMechanisms", reflect.TypeOf((*MockCryptoSuiteConfig)(nil).SecurityProviderMechanisms))
+}
+
 // SecurityProviderPin mocks base method
 func (m *MockCryptoSuiteConfig) SecurityProviderPin() string {
 	m.ctrl.T.Helper()
@@ -289,6 +289,8 @@ type reqContextKey string
 var ReqContextTimeoutOverrides = reqContextKey("timeout-overrides")
 var reqContextCommManager = reqContextKey("commManager")
 var reqContextClient = reqContextKey("clientContext")
+var reqContextCircuitBreaker = reqContextKey("circuitBreaker")
+var reqContextOrdererSelector = reqContextKey("ordererSelector")
 
 //WithTimeoutType sets timeout by type defined in config to request context
 func WithTimeoutType(timeoutType fab.TimeoutType) ReqContextOptions {
@@ -311,13 +313,29 @@ func WithParent(context reqContext.Context) ReqContextOptions {
 	}
 }
 
+//WithCircuitBreaker sets the circuit breaker that guards the requests sent to peers and orderers
+func WithCircuitBreaker(breaker fab.CircuitBreaker) ReqContextOptions {
+	return func(ctx *requestContextOpts) {
+		ctx.circuitBreaker = breaker
+	}
+}
+
+//WithOrdererSelector sets the strategy used to select the orderers to which transactions are sent
+func WithOrdererSelector(selector fab.OrdererSelector) ReqContextOptions {
+	return func(ctx *requestContextOpts) {
+		ctx.ordererSelector = selector
+	}
+}
+
 //ReqContextOptions parameter for creating requestContext
 type ReqContextOptions func(opts *requestContextOpts)
 
 type requestContextOpts struct {
-	timeoutType   fab.TimeoutType
-	timeout       time.Duration
-	parentContext reqContext.Context
+	timeoutType     fab.TimeoutType
+	timeout         time.Duration
+	parentContext   reqContext.Context
+	circuitBreaker  fab.CircuitBreaker
+	ordererSelector fab.OrdererSelector
 }
 
 // NewRequest creates a request-scoped context.
@@ -346,6 +364,12 @@ func NewRequest(client context.Client, options ...ReqContextOptions) (reqContext
 
 	ctx := reqContext.WithValue(parentContext, reqContextCommManager, client.InfraProvider().CommManager())
 	ctx = reqContext.WithValue(ctx, reqContextClient, client)
+	if reqCtxOpts.circuitBreaker != nil {
+		ctx = reqContext.WithValue(ctx, reqContextCircuitBreaker, reqCtxOpts.circuitBreaker)
+	}
+	if reqCtxOpts.ordererSelector != nil {
+		ctx = reqContext.WithValue(ctx, reqContextOrdererSelector, reqCtxOpts.ordererSelector)
+	}
 	ctx, cancel := reqContext.WithTimeout(ctx, timeout)
 
 	return ctx, cancel
@@ -363,6 +387,18 @@ func RequestClientContext(ctx reqContext.Context) (context.Client, bool) {
 	return clientContext, ok
 }
 
+// RequestCircuitBreaker extracts the CircuitBreaker from the request-scoped context.
+func RequestCircuitBreaker(ctx reqContext.Context) (fab.CircuitBreaker, bool) {
+	breaker, ok := ctx.Value(reqContextCircuitBreaker).(fab.CircuitBreaker)
+	return breaker, ok
+}
+
+// RequestOrdererSelector extracts the OrdererSelector from the request-scoped context.
+func RequestOrdererSelector(ctx reqContext.Context) (fab.OrdererSelector, bool) {
+	selector, ok := ctx.Value(reqContextOrdererSelector).(fab.OrdererSelector)
+	return selector, ok
+}
+
 // requestTimeoutOverrides extracts the timeout from timeout override map from the request-scoped context.
 func requestTimeoutOverride(ctx reqContext.Context, timeoutType fab.TimeoutType) time.Duration {
 	timeoutOverrides, ok := ctx.Value(ReqContextTimeoutOverrides).(map[fab.TimeoutType]time.Duration)
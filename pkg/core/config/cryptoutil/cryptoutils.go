@@ -8,6 +8,7 @@ package cryptoutil
 
 import (
 	"crypto"
+	"crypto/ecdsa"
 	"encoding/pem"
 	"io"
 
@@ -100,7 +101,7 @@ func X509KeyPair(certPEMBlock []byte, pk core.Key, cs core.CryptoSuite) (tls.Cer
 	}
 
 	switch x509Cert.PublicKey.(type) {
-	case *sm2.PublicKey:
+	case *sm2.PublicKey, *ecdsa.PublicKey:
 		cert.PrivateKey = &PrivateKey{cs, pk, x509Cert.PublicKey}
 	default:
 		return fail(errors.New("tls: unknown public key algorithm"))
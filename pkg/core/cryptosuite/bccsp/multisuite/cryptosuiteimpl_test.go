@@ -12,6 +12,7 @@ import (
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/test/mockcore"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite/bccsp/pkcs11"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite/bccsp/wrapper"
 	"github.com/golang/mock/gomock"
 )
@@ -41,6 +42,7 @@ func TestCryptoSuiteByConfigSW(t *testing.T) {
 	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
 	mockConfig.EXPECT().SecurityLevel().Return(256)
 	mockConfig.EXPECT().KeyStorePath().Return("/tmp/msp")
+	mockConfig.EXPECT().KeyStorePassphrase().Return(nil, nil)
 
 	//Get cryptosuite using config
 	c, err := GetSuiteByConfig(mockConfig)
@@ -51,32 +53,58 @@ func TestCryptoSuiteByConfigSW(t *testing.T) {
 	verifySuiteType(t, c, "*sw.CSP")
 }
 
-// func TestCryptoSuiteByConfigPKCS11(t *testing.T) {
+func TestCryptoSuiteByConfigHybridSW(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
 
-// 	mockCtrl := gomock.NewController(t)
-// 	defer mockCtrl.Finish()
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sw")
+	mockConfig.EXPECT().SecurityProvider().Return("sw")
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3+SHA2")
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().KeyStorePath().Return("/tmp/msp")
+	mockConfig.EXPECT().KeyStorePassphrase().Return(nil, nil)
 
-// 	//Prepare Config
-// 	providerLib, softHSMPin, softHSMTokenLabel := pkcs11.FindPKCS11Lib()
+	//Get cryptosuite using config
+	c, err := GetSuiteByConfig(mockConfig)
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+	verifySuiteType(t, c, "*sw.CSP")
+}
 
-// 	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
-// 	mockConfig.EXPECT().SecurityProvider().Return("pkcs11")
-// 	mockConfig.EXPECT().SecurityProvider().Return("pkcs11")
-// 	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
-// 	mockConfig.EXPECT().SecurityLevel().Return(256)
-// 	mockConfig.EXPECT().SecurityProviderLibPath().Return(providerLib)
-// 	mockConfig.EXPECT().SecurityProviderLabel().Return(softHSMTokenLabel)
-// 	mockConfig.EXPECT().SecurityProviderPin().Return(softHSMPin)
-// 	mockConfig.EXPECT().SoftVerify().Return(true)
+func TestCryptoSuiteByConfigPKCS11(t *testing.T) {
 
-// 	//Get cryptosuite using config
-// 	c, err := GetSuiteByConfig(mockConfig)
-// 	if err != nil {
-// 		t.Fatalf("Not supposed to get error, but got: %s", err)
-// 	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	//Prepare Config
+	providerLib, softHSMPin, softHSMTokenLabel := pkcs11.FindPKCS11Lib()
+	if providerLib == "" {
+		t.Skip("PKCS11_LIB not set, skipping test against a PKCS11 token")
+	}
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("pkcs11")
+	mockConfig.EXPECT().SecurityProvider().Return("pkcs11")
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().SecurityProviderLibPath().Return(providerLib)
+	mockConfig.EXPECT().SecurityProviderLabel().Return(softHSMTokenLabel)
+	mockConfig.EXPECT().SecurityProviderPin().Return(softHSMPin)
+	mockConfig.EXPECT().SoftVerify().Return(true)
+	mockConfig.EXPECT().SecurityProviderMechanisms().Return(nil)
+	mockConfig.EXPECT().SecurityProviderImmutable().Return(false)
+	mockConfig.EXPECT().SecurityProviderEphemeral().Return(true)
 
-// 	verifySuiteType(t, c, "*pkcs11.impl")
-// }
+	//Get cryptosuite using config
+	c, err := GetSuiteByConfig(mockConfig)
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+
+	verifySuiteType(t, c, "*pkcs11.impl")
+}
 
 func verifySuiteType(t *testing.T, c core.CryptoSuite, expectedType string) {
 	w, ok := c.(*wrapper.CryptoSuite)
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"os"
+	"strings"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/pkcs11"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/logging"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite/bccsp/wrapper"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+const (
+	defaultPKCS11Pin   = "98765432"
+	defaultPKCS11Label = "ForFabric"
+)
+
+//GetSuiteByConfig returns cryptosuite adaptor for bccsp loaded according to given config
+func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
+	if config.SecurityProvider() != "pkcs11" {
+		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
+	}
+
+	opts, err := getOptsByConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	bccsp, err := getBCCSPFromOpts(opts, config)
+	if err != nil {
+		return nil, err
+	}
+	return wrapper.NewCryptoSuite(bccsp), nil
+}
+
+func getBCCSPFromOpts(opts *pkcs11.PKCS11Opts, config core.CryptoSuiteConfig) (bccsp.BCCSP, error) {
+	keyStore := sw.NewDummyKeyStore()
+	if !opts.Ephemeral {
+		passphrase, err := config.KeyStorePassphrase()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get keystore passphrase")
+		}
+		ks, err := sw.NewFileBasedKeyStore(passphrase, config.KeyStorePath(), false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to initialize software key store")
+		}
+		keyStore = ks
+	}
+
+	csp, err := pkcs11.New(*opts, keyStore)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not initialize BCCSP PKCS11")
+	}
+	return csp, nil
+}
+
+//getOptsByConfig Returns Factory opts for given SDK config
+func getOptsByConfig(c core.CryptoSuiteConfig) (*pkcs11.PKCS11Opts, error) {
+	mechs, err := getMechanismsByConfig(c.SecurityProviderMechanisms())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &pkcs11.PKCS11Opts{
+		HashFamily: c.SecurityAlgorithm(),
+		SecLevel:   c.SecurityLevel(),
+		Library:    c.SecurityProviderLibPath(),
+		Label:      c.SecurityProviderLabel(),
+		Pin:        c.SecurityProviderPin(),
+		SoftVerify: c.SoftVerify(),
+		Immutable:  c.SecurityProviderImmutable(),
+		Ephemeral:  c.SecurityProviderEphemeral(),
+		Mechanisms: mechs,
+	}
+	logger.Debug("Initialized PKCS11 cryptosuite")
+
+	return opts, nil
+}
+
+//getMechanismsByConfig overrides the default GM mechanisms with the configured ones,
+//an unknown name or a zero value is a configuration error
+func getMechanismsByConfig(configured map[string]uint) (*pkcs11.GMMechanisms, error) {
+	mechs := pkcs11.DefaultGMMechanisms()
+	fields := map[string]*uint{
+		"keytypesm2":    &mechs.KeyTypeSM2,
+		"keytypesm4":    &mechs.KeyTypeSM4,
+		"sm2keypairgen": &mechs.SM2KeyPairGen,
+		"sm2sm3sign":    &mechs.SM2SM3Sign,
+		"sm3":           &mechs.SM3,
+		"sm4keygen":     &mechs.SM4KeyGen,
+		"sm4cbcpad":     &mechs.SM4CBCPad,
+	}
+	for name, value := range configured {
+		field, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return nil, errors.Errorf("Unsupported PKCS11 mechanism in config: %s", name)
+		}
+		if value == 0 {
+			return nil, errors.Errorf("Invalid value for PKCS11 mechanism %s in config", name)
+		}
+		*field = value
+	}
+	return mechs, nil
+}
+
+//FindPKCS11Lib returns the PKCS11 library, pin and token label to be used in tests.
+//SoftHSM does not implement the GM mechanisms, so the library of a token supporting SM2/SM3/SM4 must be set
+//in the environment variable PKCS11_LIB, the environment variables PKCS11_PIN and PKCS11_LABEL override the default pin and label.
+func FindPKCS11Lib() (lib, pin, label string) {
+	lib = os.Getenv("PKCS11_LIB")
+
+	pin = os.Getenv("PKCS11_PIN")
+	if pin == "" {
+		pin = defaultPKCS11Pin
+	}
+
+	label = os.Getenv("PKCS11_LABEL")
+	if label == "" {
+		label = defaultPKCS11Label
+	}
+
+	return lib, pin, label
+}
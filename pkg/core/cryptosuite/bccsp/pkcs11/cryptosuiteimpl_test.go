@@ -0,0 +1,210 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"bytes"
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/pkcs11"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/test/mockcore"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite/bccsp/wrapper"
+	"gitee.com/zhaochuninhefei/gmgo/sm3"
+	"github.com/golang/mock/gomock"
+)
+
+func TestBadConfig(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("UNKNOWN")
+	mockConfig.EXPECT().SecurityProvider().Return("UNKNOWN")
+
+	//Get cryptosuite using config
+	_, err := GetSuiteByConfig(mockConfig)
+	if err == nil {
+		t.Fatal("Unknown security provider should return error")
+	}
+}
+
+func TestCryptoSuiteByBadLibPath(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("pkcs11")
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().SecurityProviderLibPath().Return("")
+	mockConfig.EXPECT().SecurityProviderLabel().Return("ForFabric")
+	mockConfig.EXPECT().SecurityProviderPin().Return("98765432")
+	mockConfig.EXPECT().SoftVerify().Return(true)
+	mockConfig.EXPECT().SecurityProviderMechanisms().Return(nil)
+	mockConfig.EXPECT().SecurityProviderImmutable().Return(false)
+	mockConfig.EXPECT().SecurityProviderEphemeral().Return(true)
+
+	//Get cryptosuite using config
+	_, err := GetSuiteByConfig(mockConfig)
+	if err == nil {
+		t.Fatal("Empty PKCS11 library path should return error")
+	}
+}
+
+func TestCryptoSuiteByBadMechanisms(t *testing.T) {
+	for _, mechanisms := range []map[string]uint{{"sm9": 1}, {"sm3": 0}} {
+		mockCtrl := gomock.NewController(t)
+
+		mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+		mockConfig.EXPECT().SecurityProvider().Return("pkcs11")
+		mockConfig.EXPECT().SecurityProviderMechanisms().Return(mechanisms)
+
+		//Get cryptosuite using config
+		_, err := GetSuiteByConfig(mockConfig)
+		if err == nil {
+			t.Fatalf("Invalid PKCS11 mechanisms %v should return error", mechanisms)
+		}
+		mockCtrl.Finish()
+	}
+}
+
+func TestOptsByConfig(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().SecurityProviderLibPath().Return("/usr/lib/libgmp11.so")
+	mockConfig.EXPECT().SecurityProviderLabel().Return("ForFabric")
+	mockConfig.EXPECT().SecurityProviderPin().Return("98765432")
+	mockConfig.EXPECT().SoftVerify().Return(false)
+	mockConfig.EXPECT().SecurityProviderMechanisms().Return(map[string]uint{"SM3": 0x80000101, "sm2sm3sign": 0x80000102})
+	mockConfig.EXPECT().SecurityProviderImmutable().Return(true)
+	mockConfig.EXPECT().SecurityProviderEphemeral().Return(false)
+
+	opts, err := getOptsByConfig(mockConfig)
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+	if !opts.Immutable || opts.Ephemeral || opts.SoftVerify {
+		t.Fatalf("Unexpected PKCS11 flags: %+v", opts)
+	}
+
+	expected := pkcs11.DefaultGMMechanisms()
+	expected.SM3 = 0x80000101
+	expected.SM2SM3Sign = 0x80000102
+	if *opts.Mechanisms != *expected {
+		t.Fatalf("Unexpected PKCS11 mechanisms: %+v", opts.Mechanisms)
+	}
+}
+
+func TestCryptoSuiteByConfigPKCS11(t *testing.T) {
+	c := getSuite(t, true)
+
+	msg := []byte("Hello World")
+
+	//SM3 digest computed by the token
+	digest, err := c.Hash(msg, &bccsp.SM3Opts{})
+	if err != nil {
+		t.Fatalf("Failed computing SM3 digest with the PKCS11 token: %s", err)
+	}
+	expected := sm3.Sm3Sum(msg)
+	if !bytes.Equal(digest, expected[:]) {
+		t.Fatal("Unexpected SM3 digest from the PKCS11 token")
+	}
+}
+
+func TestSM2SignVerifyPKCS11(t *testing.T) {
+	for _, softVerify := range []bool{true, false} {
+		c := getSuite(t, softVerify)
+
+		key, err := c.KeyGen(&bccsp.SM2KeyGenOpts{Temporary: true})
+		if err != nil {
+			t.Fatalf("Failed generating PKCS11 SM2 key: %s", err)
+		}
+
+		msg := []byte("Hello World")
+		signature, err := c.Sign(key, msg, nil)
+		if err != nil {
+			t.Fatalf("Failed signing with PKCS11 SM2 key: %s", err)
+		}
+
+		pub, err := key.PublicKey()
+		if err != nil {
+			t.Fatalf("Failed getting public key: %s", err)
+		}
+		valid, err := c.Verify(pub, signature, msg, nil)
+		if err != nil || !valid {
+			t.Fatalf("Failed verifying SM2 signature [softVerify=%t]: %v", softVerify, err)
+		}
+
+		valid, err = c.Verify(pub, signature, []byte("tampered"), nil)
+		if err != nil || valid {
+			t.Fatalf("Tampered message should not verify [softVerify=%t]: %v", softVerify, err)
+		}
+
+		k, err := c.GetKey(key.SKI())
+		if err != nil || !k.Private() {
+			t.Fatalf("Failed getting PKCS11 SM2 key by SKI: %v", err)
+		}
+	}
+}
+
+func TestSM4EncryptDecryptPKCS11(t *testing.T) {
+	//SM4 is not exposed by core.CryptoSuite, use the underlying BCCSP
+	c := getSuite(t, true).(*wrapper.CryptoSuite).BCCSP
+
+	key, err := c.KeyGen(&bccsp.SM4KeyGenOpts{Temporary: true})
+	if err != nil {
+		t.Fatalf("Failed generating PKCS11 SM4 key: %s", err)
+	}
+
+	msg := []byte("Hello World")
+	ciphertext, err := c.Encrypt(key, msg, &bccsp.SM4EncrypterDecrypterOpts{})
+	if err != nil {
+		t.Fatalf("Failed encrypting with PKCS11 SM4 key: %s", err)
+	}
+	plaintext, err := c.Decrypt(key, ciphertext, &bccsp.SM4EncrypterDecrypterOpts{})
+	if err != nil {
+		t.Fatalf("Failed decrypting with PKCS11 SM4 key: %s", err)
+	}
+	if !bytes.Equal(msg, plaintext) {
+		t.Fatal("Decrypted text does not match the original message")
+	}
+}
+
+func getSuite(t *testing.T, softVerify bool) core.CryptoSuite {
+	lib, pin, label := FindPKCS11Lib()
+	if lib == "" {
+		t.Skip("PKCS11_LIB not set, skipping tests against a PKCS11 token")
+	}
+
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("pkcs11")
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().SecurityProviderLibPath().Return(lib)
+	mockConfig.EXPECT().SecurityProviderLabel().Return(label)
+	mockConfig.EXPECT().SecurityProviderPin().Return(pin)
+	mockConfig.EXPECT().SoftVerify().Return(softVerify)
+	mockConfig.EXPECT().SecurityProviderMechanisms().Return(nil)
+	mockConfig.EXPECT().SecurityProviderImmutable().Return(false)
+	mockConfig.EXPECT().SecurityProviderEphemeral().Return(true)
+
+	//Get cryptosuite using config
+	c, err := GetSuiteByConfig(mockConfig)
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+	return c
+}
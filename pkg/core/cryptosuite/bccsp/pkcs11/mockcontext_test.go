@@ -0,0 +1,399 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/pkcs11"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	"gitee.com/zhaochuninhefei/gmgo/sm3"
+	mPkcs11 "github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+func TestMockTokenSM3(t *testing.T) {
+	csp, token := newMockTokenBCCSP(t, true)
+
+	msg := []byte("Hello World")
+	digest, err := csp.Hash(msg, &bccsp.SM3Opts{})
+	if err != nil {
+		t.Fatalf("Failed computing SM3 digest: %s", err)
+	}
+	expected := sm3.Sm3Sum(msg)
+	if !bytes.Equal(digest, expected[:]) {
+		t.Fatal("Unexpected SM3 digest from the PKCS11 token")
+	}
+	if token.digests != 1 {
+		t.Fatalf("SM3 digest was not computed by the token, digests: %d", token.digests)
+	}
+}
+
+func TestMockTokenSM2SignVerify(t *testing.T) {
+	for _, softVerify := range []bool{true, false} {
+		csp, token := newMockTokenBCCSP(t, softVerify)
+
+		key, err := csp.KeyGen(&bccsp.SM2KeyGenOpts{Temporary: true})
+		if err != nil {
+			t.Fatalf("Failed generating SM2 key: %s", err)
+		}
+		pub, err := key.PublicKey()
+		if err != nil {
+			t.Fatalf("Failed getting public key: %s", err)
+		}
+		raw, err := pub.Bytes()
+		if err != nil {
+			t.Fatalf("Failed marshalling public key: %s", err)
+		}
+		swPub, err := csp.KeyImport(raw, &bccsp.SM2PublicKeyImportOpts{Temporary: true})
+		if err != nil {
+			t.Fatalf("Failed importing public key: %s", err)
+		}
+		if !bytes.Equal(key.SKI(), swPub.SKI()) {
+			t.Fatal("SKI of the token key does not match the software SKI")
+		}
+
+		msg := []byte("Hello World")
+		signature, err := csp.Sign(key, msg, nil)
+		if err != nil {
+			t.Fatalf("Failed signing with SM2 key: %s", err)
+		}
+		if token.signs != 1 {
+			t.Fatalf("Signature was not computed by the token, signs: %d", token.signs)
+		}
+
+		valid, err := csp.Verify(pub, signature, msg, nil)
+		if err != nil || !valid {
+			t.Fatalf("Failed verifying SM2 signature [softVerify=%t]: %v", softVerify, err)
+		}
+		valid, err = csp.Verify(pub, signature, []byte("tampered"), nil)
+		if err != nil || valid {
+			t.Fatalf("Tampered message should not verify [softVerify=%t]: %v", softVerify, err)
+		}
+		if softVerify && token.verifies != 0 {
+			t.Fatalf("Software verification should not use the token, verifies: %d", token.verifies)
+		}
+		if !softVerify && token.verifies != 2 {
+			t.Fatalf("Verification was not done by the token, verifies: %d", token.verifies)
+		}
+
+		//signatures of the token verify with the software implementation
+		valid, err = csp.Verify(swPub, signature, msg, nil)
+		if err != nil || !valid {
+			t.Fatalf("Failed verifying SM2 signature in software: %v", err)
+		}
+
+		k, err := csp.GetKey(key.SKI())
+		if err != nil || !k.Private() {
+			t.Fatalf("Failed getting SM2 key by SKI: %v", err)
+		}
+	}
+}
+
+func TestMockTokenSM4EncryptDecrypt(t *testing.T) {
+	csp, token := newMockTokenBCCSP(t, true)
+
+	key, err := csp.KeyGen(&bccsp.SM4KeyGenOpts{Temporary: true})
+	if err != nil {
+		t.Fatalf("Failed generating SM4 key: %s", err)
+	}
+
+	msg := []byte("Hello World")
+	ciphertext, err := csp.Encrypt(key, msg, &bccsp.SM4EncrypterDecrypterOpts{})
+	if err != nil {
+		t.Fatalf("Failed encrypting with SM4 key: %s", err)
+	}
+	plaintext, err := csp.Decrypt(key, ciphertext, &bccsp.SM4EncrypterDecrypterOpts{})
+	if err != nil {
+		t.Fatalf("Failed decrypting with SM4 key: %s", err)
+	}
+	if !bytes.Equal(msg, plaintext) {
+		t.Fatal("Decrypted text does not match the original message")
+	}
+
+	//the token output is compatible with the software implementation
+	expected, err := sw.SM4CBCPKCS7Decrypt(token.secretKey(key.SKI()), ciphertext)
+	if err != nil || !bytes.Equal(msg, expected) {
+		t.Fatalf("Ciphertext of the token does not decrypt in software: %v", err)
+	}
+
+	k, err := csp.GetKey(key.SKI())
+	if err != nil || !k.Symmetric() {
+		t.Fatalf("Failed getting SM4 key by SKI: %v", err)
+	}
+}
+
+func TestMockTokenMechanisms(t *testing.T) {
+	mechs := pkcs11.DefaultGMMechanisms()
+	mechs.SM3 = 0x80000101
+
+	token := newMockToken(mechs)
+	csp, err := pkcs11.NewWithContext(pkcs11.PKCS11Opts{SecLevel: 256, HashFamily: "SM3", Mechanisms: mechs}, sw.NewDummyKeyStore(), token)
+	if err != nil {
+		t.Fatalf("Failed initializing BCCSP: %s", err)
+	}
+	if _, err = csp.Hash([]byte("Hello World"), &bccsp.SM3Opts{}); err != nil {
+		t.Fatalf("Configured mechanism was not used: %s", err)
+	}
+
+	//the token rejects the default mechanism
+	csp, err = pkcs11.NewWithContext(pkcs11.PKCS11Opts{SecLevel: 256, HashFamily: "SM3"}, sw.NewDummyKeyStore(), token)
+	if err != nil {
+		t.Fatalf("Failed initializing BCCSP: %s", err)
+	}
+	if _, err = csp.Hash([]byte("Hello World"), &bccsp.SM3Opts{}); err == nil {
+		t.Fatal("Unsupported mechanism should return error")
+	}
+}
+
+func newMockTokenBCCSP(t *testing.T, softVerify bool) (bccsp.BCCSP, *mockToken) {
+	token := newMockToken(pkcs11.DefaultGMMechanisms())
+	opts := pkcs11.PKCS11Opts{
+		SecLevel:   256,
+		HashFamily: "SM3",
+		SoftVerify: softVerify,
+		Ephemeral:  true,
+	}
+	csp, err := pkcs11.NewWithContext(opts, sw.NewDummyKeyStore(), token)
+	if err != nil {
+		t.Fatalf("Failed initializing BCCSP: %s", err)
+	}
+	return csp, token
+}
+
+//mockObject is a key object held by mockToken
+type mockObject struct {
+	id     []byte
+	class  uint
+	sm2Key *sm2.PrivateKey
+	sm4Key []byte
+}
+
+//mockToken implements pkcs11.Context, the GM algorithms are done in software
+type mockToken struct {
+	mechs   *pkcs11.GMMechanisms
+	objects map[mPkcs11.ObjectHandle]*mockObject
+	next    mPkcs11.ObjectHandle
+
+	param   []byte
+	key     mPkcs11.ObjectHandle
+	findID  []byte
+	isFound bool
+
+	signs    int
+	verifies int
+	digests  int
+}
+
+func newMockToken(mechs *pkcs11.GMMechanisms) *mockToken {
+	return &mockToken{mechs: mechs, objects: make(map[mPkcs11.ObjectHandle]*mockObject)}
+}
+
+func (m *mockToken) secretKey(ski []byte) []byte {
+	for _, o := range m.objects {
+		if o.class == mPkcs11.CKO_SECRET_KEY && bytes.Equal(o.id, ski) {
+			return o.sm4Key
+		}
+	}
+	return nil
+}
+
+func (m *mockToken) add(o *mockObject) mPkcs11.ObjectHandle {
+	m.next++
+	m.objects[m.next] = o
+	return m.next
+}
+
+func (m *mockToken) init(mechs []*mPkcs11.Mechanism, expected uint, key mPkcs11.ObjectHandle) error {
+	if len(mechs) != 1 || mechs[0].Mechanism != expected {
+		return errors.New("pkcs11: 0x70: CKR_MECHANISM_INVALID")
+	}
+	m.param = mechs[0].Parameter
+	m.key = key
+	return nil
+}
+
+func attrValue(attrs []*mPkcs11.Attribute, typ uint) []byte {
+	for _, a := range attrs {
+		if a.Type == typ {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+func (m *mockToken) GetSession() mPkcs11.SessionHandle {
+	return 1
+}
+
+func (m *mockToken) ReturnSession(session mPkcs11.SessionHandle) {
+}
+
+func (m *mockToken) GetAttributeValue(session mPkcs11.SessionHandle, objectHandle mPkcs11.ObjectHandle, attrs []*mPkcs11.Attribute) ([]*mPkcs11.Attribute, error) {
+	o, ok := m.objects[objectHandle]
+	if !ok || o.sm2Key == nil {
+		return nil, errors.New("pkcs11: 0x82: CKR_OBJECT_HANDLE_INVALID")
+	}
+	point, err := asn1.Marshal(elliptic.Marshal(o.sm2Key.Curve, o.sm2Key.X, o.sm2Key.Y))
+	if err != nil {
+		return nil, err
+	}
+	return []*mPkcs11.Attribute{mPkcs11.NewAttribute(mPkcs11.CKA_EC_POINT, point)}, nil
+}
+
+func (m *mockToken) SetAttributeValue(session mPkcs11.SessionHandle, objectHandle mPkcs11.ObjectHandle, attrs []*mPkcs11.Attribute) error {
+	o, ok := m.objects[objectHandle]
+	if !ok {
+		return errors.New("pkcs11: 0x82: CKR_OBJECT_HANDLE_INVALID")
+	}
+	if id := attrValue(attrs, mPkcs11.CKA_ID); id != nil {
+		o.id = id
+	}
+	return nil
+}
+
+func (m *mockToken) GenerateKeyPair(session mPkcs11.SessionHandle, mechs []*mPkcs11.Mechanism, public, private []*mPkcs11.Attribute) (mPkcs11.ObjectHandle, mPkcs11.ObjectHandle, error) {
+	if err := m.init(mechs, m.mechs.SM2KeyPairGen, 0); err != nil {
+		return 0, 0, err
+	}
+	key, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		return 0, 0, err
+	}
+	pub := m.add(&mockObject{id: attrValue(public, mPkcs11.CKA_ID), class: mPkcs11.CKO_PUBLIC_KEY, sm2Key: key})
+	priv := m.add(&mockObject{id: attrValue(private, mPkcs11.CKA_ID), class: mPkcs11.CKO_PRIVATE_KEY, sm2Key: key})
+	return pub, priv, nil
+}
+
+func (m *mockToken) GenerateKey(session mPkcs11.SessionHandle, mechs []*mPkcs11.Mechanism, temp []*mPkcs11.Attribute) (mPkcs11.ObjectHandle, error) {
+	if err := m.init(mechs, m.mechs.SM4KeyGen, 0); err != nil {
+		return 0, err
+	}
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return 0, err
+	}
+	return m.add(&mockObject{id: attrValue(temp, mPkcs11.CKA_ID), class: mPkcs11.CKO_SECRET_KEY, sm4Key: key}), nil
+}
+
+func (m *mockToken) FindObjectsInit(session mPkcs11.SessionHandle, temp []*mPkcs11.Attribute) error {
+	m.findID = attrValue(temp, mPkcs11.CKA_ID)
+	m.isFound = false
+	return nil
+}
+
+func (m *mockToken) FindObjects(session mPkcs11.SessionHandle, max int) ([]mPkcs11.ObjectHandle, bool, error) {
+	if m.isFound {
+		return nil, false, nil
+	}
+	m.isFound = true
+	for h, o := range m.objects {
+		if o.class == mPkcs11.CKO_SECRET_KEY && bytes.Equal(o.id, m.findID) {
+			return []mPkcs11.ObjectHandle{h}, false, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (m *mockToken) FindObjectsFinal(session mPkcs11.SessionHandle) error {
+	m.findID = nil
+	return nil
+}
+
+func (m *mockToken) FindKeyPairFromSKI(session mPkcs11.SessionHandle, ski []byte, keyType bool) (*mPkcs11.ObjectHandle, error) {
+	class := uint(mPkcs11.CKO_PUBLIC_KEY)
+	if keyType {
+		class = mPkcs11.CKO_PRIVATE_KEY
+	}
+	for h, o := range m.objects {
+		if o.class == class && bytes.Equal(o.id, ski) {
+			handle := h
+			return &handle, nil
+		}
+	}
+	return nil, errors.Errorf("key not found [%x]", ski)
+}
+
+func (m *mockToken) EncryptInit(session mPkcs11.SessionHandle, mechs []*mPkcs11.Mechanism, o mPkcs11.ObjectHandle) error {
+	return m.init(mechs, m.mechs.SM4CBCPad, o)
+}
+
+func (m *mockToken) Encrypt(session mPkcs11.SessionHandle, message []byte) ([]byte, error) {
+	ciphertext, err := sw.SM4CBCPKCS7EncryptWithIV(m.param, m.objects[m.key].sm4Key, message)
+	if err != nil {
+		return nil, err
+	}
+	//the token does not prepend the IV
+	return ciphertext[len(m.param):], nil
+}
+
+func (m *mockToken) DecryptInit(session mPkcs11.SessionHandle, mechs []*mPkcs11.Mechanism, o mPkcs11.ObjectHandle) error {
+	return m.init(mechs, m.mechs.SM4CBCPad, o)
+}
+
+func (m *mockToken) Decrypt(session mPkcs11.SessionHandle, cypher []byte) ([]byte, error) {
+	return sw.SM4CBCPKCS7Decrypt(m.objects[m.key].sm4Key, append(append([]byte{}, m.param...), cypher...))
+}
+
+func (m *mockToken) SignInit(session mPkcs11.SessionHandle, mechs []*mPkcs11.Mechanism, o mPkcs11.ObjectHandle) error {
+	return m.init(mechs, m.mechs.SM2SM3Sign, o)
+}
+
+func (m *mockToken) Sign(session mPkcs11.SessionHandle, message []byte) ([]byte, error) {
+	m.signs++
+	der, err := m.objects[m.key].sm2Key.Sign(rand.Reader, message, nil)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := sw.UnmarshalSM2Signature(der)
+	if err != nil {
+		return nil, err
+	}
+	//the token returns r||s
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return sig, nil
+}
+
+func (m *mockToken) VerifyInit(session mPkcs11.SessionHandle, mechs []*mPkcs11.Mechanism, key mPkcs11.ObjectHandle) error {
+	return m.init(mechs, m.mechs.SM2SM3Sign, key)
+}
+
+func (m *mockToken) Verify(session mPkcs11.SessionHandle, data []byte, signature []byte) error {
+	m.verifies++
+	if len(signature) != 64 {
+		return errors.New("pkcs11: 0xC1: CKR_SIGNATURE_LEN_RANGE")
+	}
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	der, err := sw.MarshalSM2Signature(r, s)
+	if err != nil {
+		return err
+	}
+	if !m.objects[m.key].sm2Key.PublicKey.Verify(data, der) {
+		return errors.New("pkcs11: 0xC0: CKR_SIGNATURE_INVALID")
+	}
+	return nil
+}
+
+func (m *mockToken) DigestInit(session mPkcs11.SessionHandle, mechs []*mPkcs11.Mechanism) error {
+	return m.init(mechs, m.mechs.SM3, 0)
+}
+
+func (m *mockToken) Digest(session mPkcs11.SessionHandle, message []byte) ([]byte, error) {
+	m.digests++
+	digest := sm3.Sm3Sum(message)
+	return digest[:], nil
+}
@@ -24,7 +24,10 @@ func GetSuiteByConfig(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
 		return nil, errors.Errorf("Unsupported BCCSP Provider: %s", config.SecurityProvider())
 	}
 
-	opts := getOptsByConfig(config)
+	opts, err := getOptsByConfig(config)
+	if err != nil {
+		return nil, err
+	}
 	bccsp, err := getBCCSPFromOpts(opts)
 	if err != nil {
 		return nil, err
@@ -65,18 +68,36 @@ func GetSuite(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (co
 	return wrapper.NewCryptoSuite(bccsp), nil
 }
 
+// ReEncryptKeyStore re-encrypts every key file in the keystore directory with newPassphrase.
+// oldPassphrase must be empty when migrating a plaintext keystore, in which case files that are
+// already encrypted are left untouched so that the migration can be safely re-run.
+// An empty newPassphrase decrypts the keystore. Returns the number of key files rewritten.
+func ReEncryptKeyStore(keyStorePath string, oldPassphrase, newPassphrase []byte) (int, error) {
+	n, err := sw.ReEncryptFileBasedKeyStore(keyStorePath, oldPassphrase, newPassphrase)
+	if err != nil {
+		return n, errors.Wrapf(err, "failed re-encrypting keystore [%s]", keyStorePath)
+	}
+	logger.Debugf("Re-encrypted %d key files in keystore [%s]", n, keyStorePath)
+	return n, nil
+}
+
 //GetOptsByConfig Returns Factory opts for given SDK config
-func getOptsByConfig(c core.CryptoSuiteConfig) *bccspSw.SwOpts {
+func getOptsByConfig(c core.CryptoSuiteConfig) (*bccspSw.SwOpts, error) {
+	passphrase, err := c.KeyStorePassphrase()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get keystore passphrase")
+	}
 	opts := &bccspSw.SwOpts{
 		HashFamily: c.SecurityAlgorithm(),
 		SecLevel:   c.SecurityLevel(),
 		FileKeystore: &bccspSw.FileKeystoreOpts{
 			KeyStorePath: c.KeyStorePath(),
+			Pwd:          passphrase,
 		},
 	}
 	logger.Debug("Initialized SW cryptosuite")
 
-	return opts
+	return opts, nil
 }
 
 func getEphemeralOpts() *bccspSw.SwOpts {
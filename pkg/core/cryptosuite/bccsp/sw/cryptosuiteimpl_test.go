@@ -8,9 +8,15 @@ package sw
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/test/mockcore"
 	"gitee.com/zhaochuninhefei/gmgo/sm3"
@@ -41,6 +47,7 @@ func TestCryptoSuiteByConfigSW(t *testing.T) {
 	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
 	mockConfig.EXPECT().SecurityLevel().Return(256)
 	mockConfig.EXPECT().KeyStorePath().Return("/tmp/msp")
+	mockConfig.EXPECT().KeyStorePassphrase().Return(nil, nil)
 
 	//Get cryptosuite using config
 	c, err := GetSuiteByConfig(mockConfig)
@@ -60,6 +67,7 @@ func TestCryptoSuiteByBadConfigSW(t *testing.T) {
 	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
 	mockConfig.EXPECT().SecurityLevel().Return(256)
 	mockConfig.EXPECT().KeyStorePath().Return("")
+	mockConfig.EXPECT().KeyStorePassphrase().Return(nil, nil)
 
 	//Get cryptosuite using config
 	_, err := GetSuiteByConfig(mockConfig)
@@ -76,6 +84,179 @@ func TestCryptoSuiteDefaultEphemeral(t *testing.T) {
 	verifyHashFn(t, c)
 }
 
+func TestCryptoSuiteByConfigHybridSW(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sw").AnyTimes()
+	mockConfig.EXPECT().SecurityAlgorithm().Return(bccsp.SM3SHA2)
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().KeyStorePath().Return("/tmp/msp")
+	mockConfig.EXPECT().KeyStorePassphrase().Return(nil, nil)
+
+	//Get cryptosuite using config
+	c, err := GetSuiteByConfig(mockConfig)
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+
+	verifyHashFn(t, c)
+
+	msg := []byte("Hello")
+	e := sha256.Sum256(msg)
+	a, err := c.Hash(msg, &bccsp.SHA256Opts{})
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+	if !bytes.Equal(a, e[:]) {
+		t.Fatal("Expected SHA 256 hash function")
+	}
+
+	// both key families sign and verify within the same suite
+	verifySignature(t, c, &bccsp.SM2KeyGenOpts{Temporary: true}, &bccsp.SM3Opts{})
+	verifySignature(t, c, &bccsp.ECDSAP256KeyGenOpts{Temporary: true}, &bccsp.SHA256Opts{})
+}
+
+func TestCryptoSuiteGMRejectsECDSA(t *testing.T) {
+	c, err := GetSuite(256, bccsp.SM3, sw.NewDummyKeyStore())
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+
+	_, err = c.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
+	if err == nil {
+		t.Fatal("ECDSA key generation should fail when hybrid mode is not enabled")
+	}
+}
+
+func TestCryptoSuiteByConfigEncryptedKeyStore(t *testing.T) {
+	keyStorePath, err := ioutil.TempDir("", "encryptedks")
+	if err != nil {
+		t.Fatalf("Failed creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(keyStorePath)
+
+	passphrase := []byte("passphrase")
+	c := getSuiteWithPassphrase(t, keyStorePath, passphrase)
+
+	k, err := c.KeyGen(&bccsp.SM2KeyGenOpts{Temporary: false})
+	if err != nil {
+		t.Fatalf("Failed generating key: %s", err)
+	}
+
+	verifyKeyFilesEncrypted(t, keyStorePath, true)
+
+	// the key is readable with the passphrase only
+	if _, err = getSuiteWithPassphrase(t, keyStorePath, passphrase).GetKey(k.SKI()); err != nil {
+		t.Fatalf("Failed loading encrypted key with passphrase: %s", err)
+	}
+	if _, err = getSuiteWithPassphrase(t, keyStorePath, nil).GetKey(k.SKI()); err == nil {
+		t.Fatal("Loading encrypted key without passphrase should fail")
+	}
+}
+
+func TestReEncryptKeyStore(t *testing.T) {
+	keyStorePath, err := ioutil.TempDir("", "plaintextks")
+	if err != nil {
+		t.Fatalf("Failed creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(keyStorePath)
+
+	k, err := getSuiteWithPassphrase(t, keyStorePath, nil).KeyGen(&bccsp.SM2KeyGenOpts{Temporary: false})
+	if err != nil {
+		t.Fatalf("Failed generating key: %s", err)
+	}
+	verifyKeyFilesEncrypted(t, keyStorePath, false)
+
+	passphrase := []byte("passphrase")
+	n, err := ReEncryptKeyStore(keyStorePath, nil, passphrase)
+	if err != nil || n == 0 {
+		t.Fatalf("Failed migrating plaintext keystore [%d]: %v", n, err)
+	}
+	verifyKeyFilesEncrypted(t, keyStorePath, true)
+
+	// re-running the migration leaves encrypted files untouched
+	n, err = ReEncryptKeyStore(keyStorePath, nil, passphrase)
+	if err != nil || n != 0 {
+		t.Fatalf("Re-running migration should be a no-op [%d]: %v", n, err)
+	}
+
+	if _, err = getSuiteWithPassphrase(t, keyStorePath, passphrase).GetKey(k.SKI()); err != nil {
+		t.Fatalf("Failed loading migrated key with passphrase: %s", err)
+	}
+
+	// change passphrase
+	newPassphrase := []byte("new passphrase")
+	if _, err = ReEncryptKeyStore(keyStorePath, passphrase, newPassphrase); err != nil {
+		t.Fatalf("Failed changing keystore passphrase: %s", err)
+	}
+	if _, err = getSuiteWithPassphrase(t, keyStorePath, newPassphrase).GetKey(k.SKI()); err != nil {
+		t.Fatalf("Failed loading key with new passphrase: %s", err)
+	}
+}
+
+func getSuiteWithPassphrase(t *testing.T, keyStorePath string, passphrase []byte) core.CryptoSuite {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockConfig := mockcore.NewMockCryptoSuiteConfig(mockCtrl)
+	mockConfig.EXPECT().SecurityProvider().Return("sw").AnyTimes()
+	mockConfig.EXPECT().SecurityAlgorithm().Return("SM3")
+	mockConfig.EXPECT().SecurityLevel().Return(256)
+	mockConfig.EXPECT().KeyStorePath().Return(keyStorePath)
+	mockConfig.EXPECT().KeyStorePassphrase().Return(passphrase, nil)
+
+	c, err := GetSuiteByConfig(mockConfig)
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+	return c
+}
+
+func verifyKeyFilesEncrypted(t *testing.T, keyStorePath string, encrypted bool) {
+	files, err := ioutil.ReadDir(keyStorePath)
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Expected key files in keystore: %v", err)
+	}
+	for _, f := range files {
+		raw, err := ioutil.ReadFile(filepath.Join(keyStorePath, f.Name()))
+		if err != nil {
+			t.Fatalf("Failed reading key file: %s", err)
+		}
+		if strings.Contains(string(raw), "ENCRYPTED") != encrypted {
+			t.Fatalf("Key file [%s] encrypted state should be %t", f.Name(), encrypted)
+		}
+	}
+}
+
+func verifySignature(t *testing.T, c core.CryptoSuite, keyGenOpts core.KeyGenOpts, hashOpts core.HashOpts) {
+	k, err := c.KeyGen(keyGenOpts)
+	if err != nil {
+		t.Fatalf("Failed generating key with opts [%s]: %s", keyGenOpts.Algorithm(), err)
+	}
+
+	digest, err := c.Hash([]byte("Hello"), hashOpts)
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+
+	signature, err := c.Sign(k, digest, nil)
+	if err != nil {
+		t.Fatalf("Failed signing with [%s] key: %s", keyGenOpts.Algorithm(), err)
+	}
+
+	pk, err := k.PublicKey()
+	if err != nil {
+		t.Fatalf("Not supposed to get error, but got: %s", err)
+	}
+
+	valid, err := c.Verify(pk, signature, digest, nil)
+	if err != nil || !valid {
+		t.Fatalf("Signature of [%s] key should be valid: %v", keyGenOpts.Algorithm(), err)
+	}
+}
+
 func verifyHashFn(t *testing.T, c core.CryptoSuite) {
 	msg := []byte("Hello")
 	e := sm3.Sm3Sum(msg)
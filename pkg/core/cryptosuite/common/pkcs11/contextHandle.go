@@ -436,6 +436,32 @@ func (handle *ContextHandle) Verify(session mPkcs11.SessionHandle, data []byte,
 	return handle.ctx.Verify(session, data, signature)
 }
 
+// DigestInit initializes a message-digesting operation.
+func (handle *ContextHandle) DigestInit(session mPkcs11.SessionHandle, m []*mPkcs11.Mechanism) error {
+	handle.lock.RLock()
+	defer handle.lock.RUnlock()
+
+	e := handle.isInRecovery()
+	if e != nil {
+		return e
+	}
+
+	return handle.ctx.DigestInit(session, m)
+}
+
+// Digest digests message in a single part.
+func (handle *ContextHandle) Digest(session mPkcs11.SessionHandle, message []byte) ([]byte, error) {
+	handle.lock.RLock()
+	defer handle.lock.RUnlock()
+
+	e := handle.isInRecovery()
+	if e != nil {
+		return nil, e
+	}
+
+	return handle.ctx.Digest(session, message)
+}
+
 // CreateObject creates a new object.
 func (handle *ContextHandle) CreateObject(session mPkcs11.SessionHandle, temp []*mPkcs11.Attribute) (mPkcs11.ObjectHandle, error) {
 	handle.lock.RLock()
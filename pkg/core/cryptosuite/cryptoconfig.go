@@ -14,6 +14,7 @@ import (
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/config/lookup"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/util/pathvar"
+	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 )
 
@@ -23,6 +24,7 @@ const (
 	defLevel         = 256
 	defProvider      = "SW"
 	defSoftVerify    = true
+	defEphemeral     = true
 )
 
 //ConfigFromBackend returns CryptoSuite config implementation for given backend
@@ -110,8 +112,54 @@ func (c *Config) SecurityProviderLabel() string {
 	return c.backend.GetString("client.BCCSP.security.label")
 }
 
+//SecurityProviderMechanisms returns the PKCS11 key types and mechanisms used for the GM algorithms,
+//keyed by keytypesm2, keytypesm4, sm2keypairgen, sm2sm3sign, sm3, sm4keygen and sm4cbcpad.
+//Values not configured fall back to the defaults of the PKCS11 provider.
+func (c *Config) SecurityProviderMechanisms() map[string]uint {
+	val, ok := c.backend.Lookup("client.BCCSP.security.mechanisms")
+	if !ok || val == nil {
+		return nil
+	}
+	mechanisms := make(map[string]uint)
+	for k, v := range cast.ToStringMap(val) {
+		mechanisms[strings.ToLower(k)] = cast.ToUint(v)
+	}
+	return mechanisms
+}
+
+//SecurityProviderImmutable flag, if true the key objects created in the PKCS11 token are made immutable
+func (c *Config) SecurityProviderImmutable() bool {
+	return c.backend.GetBool("client.BCCSP.security.immutable")
+}
+
+//SecurityProviderEphemeral flag, if true the PKCS11 provider uses a dummy keystore for the keys held outside the token,
+//otherwise they are kept in the keystore at KeyStorePath
+func (c *Config) SecurityProviderEphemeral() bool {
+	val, ok := c.backend.Lookup("client.BCCSP.security.ephemeral")
+	if !ok || val == nil {
+		return defEphemeral
+	}
+	return cast.ToBool(val)
+}
+
 // KeyStorePath returns the keystore path used by BCCSP
 func (c *Config) KeyStorePath() string {
 	keystorePath := pathvar.Subst(c.backend.GetString("client.credentialStore.cryptoStore.path"))
 	return filepath.Join(keystorePath, "keystore")
 }
+
+// KeyStorePassphrase returns the passphrase used to encrypt the keys written to the keystore.
+// ${VARNAME} is replaced with the environment variable, so the passphrase need not be kept in the config file.
+// An empty passphrase means keys are stored as plaintext PEM.
+// An error is returned if the passphrase references an environment variable that is not set, so that
+// the keys are neither written as plaintext nor encrypted with the literal ${VARNAME}.
+func (c *Config) KeyStorePassphrase() ([]byte, error) {
+	passphrase := pathvar.Subst(c.backend.GetString("client.credentialStore.cryptoStore.passphrase"))
+	if passphrase == "" {
+		return nil, nil
+	}
+	if strings.Contains(passphrase, "${") {
+		return nil, errors.New("keystore passphrase references an environment variable that is not set")
+	}
+	return []byte(passphrase), nil
+}
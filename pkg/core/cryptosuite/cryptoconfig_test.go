@@ -38,6 +38,9 @@ func TestEmptyTestFile(t *testing.T) {
 	// Note that we transform to lower case in SecurityProvider()
 	assert.Equal(t, "sw", cryptoConfig.SecurityProvider())
 	assert.Equal(t, true, cryptoConfig.SoftVerify())
+	assert.Equal(t, true, cryptoConfig.SecurityProviderEphemeral())
+	assert.Equal(t, false, cryptoConfig.SecurityProviderImmutable())
+	assert.Nil(t, cryptoConfig.SecurityProviderMechanisms())
 }
 
 func TestCAConfigKeyStorePath(t *testing.T) {
@@ -62,6 +65,30 @@ func TestCAConfigKeyStorePath(t *testing.T) {
 	}
 }
 
+func TestCAConfigKeyStorePassphrase(t *testing.T) {
+	const envVar = "FABRIC_SDK_TEST_KEYSTORE_PASSPHRASE"
+	backendMap := make(map[string]interface{})
+	backendMap["client.credentialStore.cryptoStore.passphrase"] = "${" + envVar + "}"
+	cryptoConfig := ConfigFromBackend(&mocks.MockConfigBackend{KeyValueMap: backendMap})
+
+	// unset environment variable must neither disable encryption nor be used as a literal passphrase
+	os.Unsetenv(envVar)
+	passphrase, err := cryptoConfig.KeyStorePassphrase()
+	assert.Error(t, err)
+	assert.Nil(t, passphrase)
+
+	os.Setenv(envVar, "secret")
+	defer os.Unsetenv(envVar)
+	passphrase, err = cryptoConfig.KeyStorePassphrase()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), passphrase)
+
+	backendMap["client.credentialStore.cryptoStore.passphrase"] = ""
+	passphrase, err = cryptoConfig.KeyStorePassphrase()
+	assert.NoError(t, err)
+	assert.Nil(t, passphrase)
+}
+
 func TestCAConfigBCCSPSecurityEnabled(t *testing.T) {
 	configPath := filepath.Join(metadata.GetProjectPath(), "pkg", "core", "config", "testdata", configTestFile)
 	backend, err := config.FromFile(configPath)()
@@ -286,6 +313,12 @@ func TestCryptoConfigWithMultipleBackends(t *testing.T) {
 	backendMap["client.BCCSP.security.label"] = "TESTLABEL"
 	backends = append(backends, &mocks.MockConfigBackend{KeyValueMap: backendMap})
 
+	backendMap = make(map[string]interface{})
+	backendMap["client.BCCSP.security.mechanisms"] = map[string]interface{}{"SM3": "0x80008101", "sm4cbcpad": 2147516930}
+	backendMap["client.BCCSP.security.immutable"] = true
+	backendMap["client.BCCSP.security.ephemeral"] = false
+	backends = append(backends, &mocks.MockConfigBackend{KeyValueMap: backendMap})
+
 	cryptoConfig := ConfigFromBackend(backends...)
 
 	assert.Equal(t, cryptoConfig.IsSecurityEnabled(), true)
@@ -295,6 +328,9 @@ func TestCryptoConfigWithMultipleBackends(t *testing.T) {
 	assert.Equal(t, cryptoConfig.SecurityProviderPin(), "1234")
 	assert.Equal(t, cryptoConfig.KeyStorePath(), "/tmp/keystore")
 	assert.Equal(t, cryptoConfig.SecurityProviderLabel(), "TESTLABEL")
+	assert.Equal(t, cryptoConfig.SecurityProviderMechanisms(), map[string]uint{"sm3": 0x80008101, "sm4cbcpad": 0x80008202})
+	assert.Equal(t, cryptoConfig.SecurityProviderImmutable(), true)
+	assert.Equal(t, cryptoConfig.SecurityProviderEphemeral(), false)
 }
 
 //getCustomBackend returns custom backend to override config values and to avoid using new config file for test scenarios
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package cryptosuite
 
 import (
+	"crypto/ecdsa"
+	"crypto/x509"
 	"sync/atomic"
 
 	"errors"
@@ -88,3 +90,51 @@ func GetSM2KeyGenOpts(ephemeral bool) core.KeyGenOpts {
 func GetSM2PrivateKeyImportOpts(ephemeral bool) core.KeyImportOpts {
 	return &bccsp.SM2PrivateKeyImportOpts{Temporary: ephemeral}
 }
+
+//GetSHA256Opts returns options relating to SHA-256, only supported in hybrid (SM3+SHA2) mode.
+func GetSHA256Opts() core.HashOpts {
+	return &bccsp.SHA256Opts{}
+}
+
+//GetECDSAP256KeyGenOpts returns options for ECDSA key generation with curve P-256,
+// only supported in hybrid (SM3+SHA2) mode.
+func GetECDSAP256KeyGenOpts(ephemeral bool) core.KeyGenOpts {
+	return &bccsp.ECDSAP256KeyGenOpts{Temporary: ephemeral}
+}
+
+//GetECDSAPrivateKeyImportOpts options for ECDSA secret key importation in DER format
+// or PKCS#8 format, only supported in hybrid (SM3+SHA2) mode.
+func GetECDSAPrivateKeyImportOpts(ephemeral bool) core.KeyImportOpts {
+	return &bccsp.ECDSAPrivateKeyImportOpts{Temporary: ephemeral}
+}
+
+//GetSignatureHashOpts returns options for computing the digest signed by the given key:
+// SHA-256 for ECDSA keys (hybrid mode), SM3 otherwise.
+func GetSignatureHashOpts(key core.Key) core.HashOpts {
+	if IsECDSAKey(key) {
+		return GetSHA256Opts()
+	}
+	return GetSM3Opts()
+}
+
+//IsECDSAKey returns true if the given asymmetric key is an ECDSA key
+func IsECDSAKey(key core.Key) bool {
+	if key == nil || key.Symmetric() {
+		return false
+	}
+	pub, err := key.PublicKey()
+	if err != nil {
+		return false
+	}
+	raw, err := pub.Bytes()
+	if err != nil {
+		return false
+	}
+	// the standard library rejects SM2 public keys (unsupported curve)
+	pk, err := x509.ParsePKIXPublicKey(raw)
+	if err != nil {
+		return false
+	}
+	_, ok := pk.(*ecdsa.PublicKey)
+	return ok
+}
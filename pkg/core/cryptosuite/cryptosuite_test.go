@@ -9,6 +9,8 @@ import (
 	"sync/atomic"
 	"testing"
 
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp"
+	bccspSw "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite/bccsp/sw"
 	"github.com/stretchr/testify/assert"
 )
@@ -17,6 +19,9 @@ const (
 	sm3HashOptsAlgorithm       = "SM3"
 	sm2KeyGenOpts              = "SM2"
 	sm2ImportOpts              = "SM2"
+	sha256HashOptsAlgorithm    = "SHA256"
+	ecdsap256KeyGenOpts        = "ECDSAP256"
+	ecdsaImportOpts            = "ECDSA"
 	setDefAlreadySetErrorMsg   = "default crypto suite is already set"
 	InvalidDefSuiteSetErrorMsg = "attempting to set invalid default suite"
 )
@@ -77,6 +82,11 @@ func TestHashOpts(t *testing.T) {
 	assert.NotZero(t, hashOpts, "Not supposed to be empty sm3HashOpts")
 	assert.True(t, hashOpts.Algorithm() == sm3HashOptsAlgorithm, "Unexpected SHA hash opts, expected [%s], got [%s]", sm3HashOptsAlgorithm, hashOpts.Algorithm())
 
+	//Get CryptoSuite SHA256 Opts
+	hashOpts = GetSHA256Opts()
+	assert.NotZero(t, hashOpts, "Not supposed to be empty sha256HashOpts")
+	assert.True(t, hashOpts.Algorithm() == sha256HashOptsAlgorithm, "Unexpected SHA hash opts, expected [%s], got [%s]", sha256HashOptsAlgorithm, hashOpts.Algorithm())
+
 }
 
 func TestKeyGenOpts(t *testing.T) {
@@ -86,10 +96,10 @@ func TestKeyGenOpts(t *testing.T) {
 	assert.True(t, keygenOpts.Ephemeral(), "Expected keygenOpts.Ephemeral() ==> true")
 	assert.True(t, keygenOpts.Algorithm() == sm2KeyGenOpts, "Unexpected ECDSA KeyGen opts, expected [%v], got [%v]", sm2KeyGenOpts, keygenOpts.Algorithm())
 
-	// keygenOpts = GetECDSAP256KeyGenOpts(false)
-	// assert.NotZero(t, keygenOpts, "Not supposed to be empty ECDSAP256KeyGenOpts")
-	// assert.False(t, keygenOpts.Ephemeral(), "Expected keygenOpts.Ephemeral() ==> false")
-	// assert.True(t, keygenOpts.Algorithm() == ecdsap256KeyGenOpts, "Unexpected ECDSA KeyGen opts, expected [%v], got [%v]", ecdsap256KeyGenOpts, keygenOpts.Algorithm())
+	keygenOpts = GetECDSAP256KeyGenOpts(false)
+	assert.NotZero(t, keygenOpts, "Not supposed to be empty ECDSAP256KeyGenOpts")
+	assert.False(t, keygenOpts.Ephemeral(), "Expected keygenOpts.Ephemeral() ==> false")
+	assert.True(t, keygenOpts.Algorithm() == ecdsap256KeyGenOpts, "Unexpected ECDSA KeyGen opts, expected [%v], got [%v]", ecdsap256KeyGenOpts, keygenOpts.Algorithm())
 
 }
 
@@ -99,8 +109,23 @@ func TestKeyImportOpts(t *testing.T) {
 	assert.True(t, importOpts.Ephemeral(), "Expected keygenOpts.Ephemeral() ==> true")
 	assert.True(t, importOpts.Algorithm() == sm2ImportOpts, "UUnexpected ECDSA import opts, expected [%v], got [%v]", sm2ImportOpts, importOpts.Algorithm())
 
-	// importOpts = GetECDSAPrivateKeyImportOpts(false)
-	// assert.NotZero(t, importOpts, "Not supposed to be empty ECDSAP256KeyGenOpts")
-	// assert.False(t, importOpts.Ephemeral(), "Expected keygenOpts.Ephemeral() ==> false")
-	// assert.True(t, importOpts.Algorithm() == ecdsaImportOpts, "Unexpected ECDSA import opts, expected [%v], got [%v]", ecdsaImportOpts, importOpts.Algorithm())
+	importOpts = GetECDSAPrivateKeyImportOpts(false)
+	assert.NotZero(t, importOpts, "Not supposed to be empty ECDSAP256KeyGenOpts")
+	assert.False(t, importOpts.Ephemeral(), "Expected keygenOpts.Ephemeral() ==> false")
+	assert.True(t, importOpts.Algorithm() == ecdsaImportOpts, "Unexpected ECDSA import opts, expected [%v], got [%v]", ecdsaImportOpts, importOpts.Algorithm())
+}
+
+func TestSignatureHashOpts(t *testing.T) {
+	hybridSuite, err := sw.GetSuite(256, bccsp.SM3SHA2, bccspSw.NewDummyKeyStore())
+	assert.Nil(t, err, "Not supposed to get error on creating hybrid suite")
+
+	sm2Key, err := hybridSuite.KeyGen(GetSM2KeyGenOpts(true))
+	assert.Nil(t, err, "Not supposed to get error on generating SM2 key")
+	assert.False(t, IsECDSAKey(sm2Key), "SM2 key is not supposed to be detected as ECDSA")
+	assert.Equal(t, sm3HashOptsAlgorithm, GetSignatureHashOpts(sm2Key).Algorithm())
+
+	ecdsaKey, err := hybridSuite.KeyGen(GetECDSAP256KeyGenOpts(true))
+	assert.Nil(t, err, "Not supposed to get error on generating ECDSA key")
+	assert.True(t, IsECDSAKey(ecdsaKey), "ECDSA key is supposed to be detected as ECDSA")
+	assert.Equal(t, sha256HashOptsAlgorithm, GetSignatureHashOpts(ecdsaKey).Algorithm())
 }
@@ -22,7 +22,11 @@ type CryptoConfigOptions struct {
 	securityProviderLibPath
 	securityProviderPin
 	securityProviderLabel
+	securityProviderMechanisms
+	securityProviderImmutable
+	securityProviderEphemeral
 	keyStorePath
+	keyStorePassphrase
 }
 
 type applier func()
@@ -69,11 +73,32 @@ type securityProviderLabel interface {
 	SecurityProviderLabel() string
 }
 
+// securityProviderMechanisms interface allows to uniquely override CryptoConfig interface's SecurityProviderMechanisms() function
+type securityProviderMechanisms interface {
+	SecurityProviderMechanisms() map[string]uint
+}
+
+// securityProviderImmutable interface allows to uniquely override CryptoConfig interface's SecurityProviderImmutable() function
+type securityProviderImmutable interface {
+	SecurityProviderImmutable() bool
+}
+
+// securityProviderEphemeral interface allows to uniquely override CryptoConfig interface's SecurityProviderEphemeral() function
+type securityProviderEphemeral interface {
+	SecurityProviderEphemeral() bool
+}
+
 // keyStorePath interface allows to uniquely override CryptoConfig interface's KeyStorePath() function
 type keyStorePath interface {
 	KeyStorePath() string
 }
 
+// keyStorePassphrase interface allows to uniquely override CryptoConfig interface's KeyStorePassphrase() function,
+// it can be used to plug in a passphrase provider (eg. reading the passphrase from a secret manager)
+type keyStorePassphrase interface {
+	KeyStorePassphrase() ([]byte, error)
+}
+
 // BuildCryptoSuiteConfigFromOptions will return an CryptoConfig instance pre-built with Optional interfaces
 // provided in fabsdk's WithConfigCrypto(opts...) call
 func BuildCryptoSuiteConfigFromOptions(opts ...interface{}) (core.CryptoSuiteConfig, error) {
@@ -103,7 +128,11 @@ func UpdateMissingOptsWithDefaultConfig(c *CryptoConfigOptions, d core.CryptoSui
 	s.set(c.securityProviderLibPath, nil, func() { c.securityProviderLibPath = d })
 	s.set(c.securityProviderPin, nil, func() { c.securityProviderPin = d })
 	s.set(c.securityProviderLabel, nil, func() { c.securityProviderLabel = d })
+	s.set(c.securityProviderMechanisms, nil, func() { c.securityProviderMechanisms = d })
+	s.set(c.securityProviderImmutable, nil, func() { c.securityProviderImmutable = d })
+	s.set(c.securityProviderEphemeral, nil, func() { c.securityProviderEphemeral = d })
 	s.set(c.keyStorePath, nil, func() { c.keyStorePath = d })
+	s.set(c.keyStorePassphrase, nil, func() { c.keyStorePassphrase = d })
 
 	return c
 }
@@ -111,7 +140,7 @@ func UpdateMissingOptsWithDefaultConfig(c *CryptoConfigOptions, d core.CryptoSui
 // IsCryptoConfigFullyOverridden will return true if all of the argument's sub interfaces is not nil
 // (ie CryptoSuiteConfig interface not fully overridden)
 func IsCryptoConfigFullyOverridden(c *CryptoConfigOptions) bool {
-	return !anyNil(c.isSecurityEnabled, c.securityAlgorithm, c.securityLevel, c.securityProvider, c.softVerify, c.securityProviderLibPath, c.securityProviderPin, c.securityProviderLabel, c.keyStorePath)
+	return !anyNil(c.isSecurityEnabled, c.securityAlgorithm, c.securityLevel, c.securityProvider, c.softVerify, c.securityProviderLibPath, c.securityProviderPin, c.securityProviderLabel, c.securityProviderMechanisms, c.securityProviderImmutable, c.securityProviderEphemeral, c.keyStorePath, c.keyStorePassphrase)
 }
 
 // will override CryptoSuiteConfig interface with functions provided by o (option)
@@ -126,7 +155,11 @@ func setCryptoConfigWithOptionInterface(c *CryptoConfigOptions, o interface{}) e
 	s.set(c.securityProviderLibPath, func() bool { _, ok := o.(securityProviderLibPath); return ok }, func() { c.securityProviderLibPath = o.(securityProviderLibPath) })
 	s.set(c.securityProviderPin, func() bool { _, ok := o.(securityProviderPin); return ok }, func() { c.securityProviderPin = o.(securityProviderPin) })
 	s.set(c.securityProviderLabel, func() bool { _, ok := o.(securityProviderLabel); return ok }, func() { c.securityProviderLabel = o.(securityProviderLabel) })
+	s.set(c.securityProviderMechanisms, func() bool { _, ok := o.(securityProviderMechanisms); return ok }, func() { c.securityProviderMechanisms = o.(securityProviderMechanisms) })
+	s.set(c.securityProviderImmutable, func() bool { _, ok := o.(securityProviderImmutable); return ok }, func() { c.securityProviderImmutable = o.(securityProviderImmutable) })
+	s.set(c.securityProviderEphemeral, func() bool { _, ok := o.(securityProviderEphemeral); return ok }, func() { c.securityProviderEphemeral = o.(securityProviderEphemeral) })
 	s.set(c.keyStorePath, func() bool { _, ok := o.(keyStorePath); return ok }, func() { c.keyStorePath = o.(keyStorePath) })
+	s.set(c.keyStorePassphrase, func() bool { _, ok := o.(keyStorePassphrase); return ok }, func() { c.keyStorePassphrase = o.(keyStorePassphrase) })
 
 	if !s.isSet {
 		return errors.Errorf("option %#v is not a sub interface of CryptoSuiteConfig, at least one of its functions must be implemented.", o)
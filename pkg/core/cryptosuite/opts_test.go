@@ -13,16 +13,20 @@ import (
 )
 
 var (
-	m0 = &Config{}
-	m1 = &mockIsSecurityEnabled{}
-	m2 = &mockSecurityAlgorithm{}
-	m3 = &mockSecurityLevel{}
-	m4 = &mockSecurityProvider{}
-	m5 = &mockSoftVerify{}
-	m6 = &mockSecurityProviderLibPath{}
-	m7 = &mockSecurityProviderPin{}
-	m8 = &mockSecurityProviderLabel{}
-	m9 = &mockKeyStorePath{}
+	m0  = &Config{}
+	m1  = &mockIsSecurityEnabled{}
+	m2  = &mockSecurityAlgorithm{}
+	m3  = &mockSecurityLevel{}
+	m4  = &mockSecurityProvider{}
+	m5  = &mockSoftVerify{}
+	m6  = &mockSecurityProviderLibPath{}
+	m7  = &mockSecurityProviderPin{}
+	m8  = &mockSecurityProviderLabel{}
+	m9  = &mockKeyStorePath{}
+	m10 = &mockKeyStorePassphrase{}
+	m11 = &mockSecurityProviderMechanisms{}
+	m12 = &mockSecurityProviderImmutable{}
+	m13 = &mockSecurityProviderEphemeral{}
 )
 
 func TestCreateCustomFullCryptotConfig(t *testing.T) {
@@ -73,11 +77,15 @@ func TestCreateCustomCryptoConfig(t *testing.T) {
 	require.Nil(t, cco.securityProviderPin, "securityProviderPin created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderPin)
 	require.Nil(t, cco.securityProviderLabel, "securityProviderLabel created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderLabel)
 	require.Nil(t, cco.keyStorePath, "keyStorePath created with nil interface but got non nil one: %s. Expected nil interface", cco.keyStorePath)
+	require.Nil(t, cco.keyStorePassphrase, "keyStorePassphrase created with nil interface but got non nil one: %s. Expected nil interface", cco.keyStorePassphrase)
+	require.Nil(t, cco.securityProviderMechanisms, "securityProviderMechanisms created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderMechanisms)
+	require.Nil(t, cco.securityProviderImmutable, "securityProviderImmutable created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderImmutable)
+	require.Nil(t, cco.securityProviderEphemeral, "securityProviderEphemeral created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderEphemeral)
 }
 
 func TestCreateCustomCryptoConfigRemainingFunctions(t *testing.T) {
 	// try to build with the remaining implementations not tested above
-	cryptoConfigOption, err := BuildCryptoSuiteConfigFromOptions(m5, m6, m7, m8, m9)
+	cryptoConfigOption, err := BuildCryptoSuiteConfigFromOptions(m5, m6, m7, m8, m9, m10, m11, m12, m13)
 	if err != nil {
 		t.Fatalf("BuildCryptoSuiteConfigFromOptions returned unexpected error %s", err)
 	}
@@ -108,6 +116,23 @@ func TestCreateCustomCryptoConfigRemainingFunctions(t *testing.T) {
 	s = cco.KeyStorePath()
 	require.Equal(t, "test/keystore/path", s, "KeyStorePath did not return expected interface value")
 
+	// test m10 implementation
+	p, err := cco.KeyStorePassphrase()
+	require.NoError(t, err)
+	require.Equal(t, []byte("passphrase"), p, "KeyStorePassphrase did not return expected interface value")
+
+	// test m11 implementation
+	m := cco.SecurityProviderMechanisms()
+	require.Equal(t, map[string]uint{"sm3": 0x80000001}, m, "SecurityProviderMechanisms did not return expected interface value")
+
+	// test m12 implementation
+	b = cco.SecurityProviderImmutable()
+	require.True(t, b, "SecurityProviderImmutable did not return expected interface value")
+
+	// test m13 implementation
+	b = cco.SecurityProviderEphemeral()
+	require.False(t, b, "SecurityProviderEphemeral did not return expected interface value")
+
 	// verify if an interface was not passed as an option but was not nil, it should be nil (ie these implementations should not be populated in cco: m1, m2, m3 and m4)
 	require.Nil(t, cco.isSecurityEnabled, "isSecurityEnabled created with nil interface but got non nil one: %s. Expected nil interface", cco.isSecurityEnabled)
 	require.Nil(t, cco.securityAlgorithm, "securityAlgorithm created with nil interface but got non nil one: %s. Expected nil interface", cco.securityAlgorithm)
@@ -141,6 +166,10 @@ func TestCreateCustomCryptoConfigWithSomeDefaultFunctions(t *testing.T) {
 	require.Nil(t, cco.securityProviderPin, "securityProviderPin created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderPin)
 	require.Nil(t, cco.securityProviderLabel, "securityProviderLabel created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderLabel)
 	require.Nil(t, cco.keyStorePath, "keyStorePath created with nil interface but got non nil one: %s. Expected nil interface", cco.keyStorePath)
+	require.Nil(t, cco.keyStorePassphrase, "keyStorePassphrase created with nil interface but got non nil one: %s. Expected nil interface", cco.keyStorePassphrase)
+	require.Nil(t, cco.securityProviderMechanisms, "securityProviderMechanisms created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderMechanisms)
+	require.Nil(t, cco.securityProviderImmutable, "securityProviderImmutable created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderImmutable)
+	require.Nil(t, cco.securityProviderEphemeral, "securityProviderEphemeral created with nil interface but got non nil one: %s. Expected nil interface", cco.securityProviderEphemeral)
 
 	// do the same test using IsCryptoConfigFullyOverridden() call
 	require.False(t, IsCryptoConfigFullyOverridden(cco), "IsCryptoConfigFullyOverridden is supposed to return false with an Options instance not implementing all the interface functions")
@@ -177,6 +206,10 @@ func TestCreateCustomCryptoConfigWithSomeDefaultFunctions(t *testing.T) {
 	require.NotNil(t, cco.securityProviderPin, "securityProviderPin should be populated with default interface but got nil one: %s. Expected default interface", cco.securityProviderPin)
 	require.NotNil(t, cco.securityProviderLabel, "securityProviderLabel should be populated with default interface but got nil one: %s. Expected default interface", cco.securityProviderLabel)
 	require.NotNil(t, cco.keyStorePath, "keyStorePath should be populated with default interface but got nil one: %s. Expected default interface", cco.keyStorePath)
+	require.NotNil(t, cco.keyStorePassphrase, "keyStorePassphrase should be populated with default interface but got nil one: %s. Expected default interface", cco.keyStorePassphrase)
+	require.NotNil(t, cco.securityProviderMechanisms, "securityProviderMechanisms should be populated with default interface but got nil one: %s. Expected default interface", cco.securityProviderMechanisms)
+	require.NotNil(t, cco.securityProviderImmutable, "securityProviderImmutable should be populated with default interface but got nil one: %s. Expected default interface", cco.securityProviderImmutable)
+	require.NotNil(t, cco.securityProviderEphemeral, "securityProviderEphemeral should be populated with default interface but got nil one: %s. Expected default interface", cco.securityProviderEphemeral)
 
 	// do the same test using IsCryptoConfigFullyOverridden() call
 	require.True(t, IsCryptoConfigFullyOverridden(cco), "IsCryptoConfigFullyOverridden is supposed to return true since all the interface functions should be implemented")
@@ -235,3 +268,27 @@ type mockKeyStorePath struct{}
 func (m *mockKeyStorePath) KeyStorePath() string {
 	return "test/keystore/path"
 }
+
+type mockKeyStorePassphrase struct{}
+
+func (m *mockKeyStorePassphrase) KeyStorePassphrase() ([]byte, error) {
+	return []byte("passphrase"), nil
+}
+
+type mockSecurityProviderMechanisms struct{}
+
+func (m *mockSecurityProviderMechanisms) SecurityProviderMechanisms() map[string]uint {
+	return map[string]uint{"sm3": 0x80000001}
+}
+
+type mockSecurityProviderImmutable struct{}
+
+func (m *mockSecurityProviderImmutable) SecurityProviderImmutable() bool {
+	return true
+}
+
+type mockSecurityProviderEphemeral struct{}
+
+func (m *mockSecurityProviderEphemeral) SecurityProviderEphemeral() bool {
+	return false
+}
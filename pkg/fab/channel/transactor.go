@@ -12,6 +12,8 @@ import (
 
 	"github.com/pkg/errors"
 
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/retry"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
 	contextImpl "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/context"
@@ -24,6 +26,7 @@ type Transactor struct {
 	reqCtx    reqContext.Context
 	ChannelID string
 	orderers  []fab.Orderer
+	cfg       fab.ChannelCfg
 }
 
 // NewTransactor returns a Transactor for the current context and channel config.
@@ -47,6 +50,7 @@ func NewTransactor(reqCtx reqContext.Context, cfg fab.ChannelCfg) (*Transactor,
 		reqCtx:    reqCtx,
 		ChannelID: cfg.ID(),
 		orderers:  orderers,
+		cfg:       cfg,
 	}
 	return &t, nil
 }
@@ -70,44 +74,55 @@ func orderersFromChannelCfg(ctx context.Client, cfg fab.ChannelCfg) ([]fab.Order
 
 	// Add orderer if specified in channel config
 	for _, target := range cfg.Orderers() {
+		o, err := ordererFromChannelCfg(ctx, ordererDict, target)
+		if err != nil {
+			return nil, err
+		}
+		if o != nil {
+			orderers = append(orderers, o)
+		}
+	}
+	return orderers, nil
+}
 
-		// Figure out orderer configuration
-		oCfg, ok := ordererDict[target]
-
-		//try entity matcher
-		if !ok {
-			logger.Debugf("Failed to get channel Cfg orderer [%s] from ordererDict, now trying orderer Matchers in Entity Matchers", target)
-			// Try to find a match from entityMatchers config
-			matchingOrdererConfig, found, ignore := ctx.EndpointConfig().OrdererConfig(strings.ToLower(target))
-			if ignore {
-				logger.Debugf("orderer [%s] is ignored and will not be added", target)
-				continue
-			}
-
-			if found {
-				logger.Debugf("Found matching ordererConfig from entity Matchers for channel Cfg Orderer [%s]", target)
-				oCfg = *matchingOrdererConfig
-				ok = true
-			}
+// ordererFromChannelCfg creates the orderer with the given channel config address.
+// nil is returned if the orderer is ignored by the entity matchers.
+func ordererFromChannelCfg(ctx context.Client, ordererDict map[string]fab.OrdererConfig, target string) (fab.Orderer, error) {
+
+	// Figure out orderer configuration
+	oCfg, ok := ordererDict[target]
+
+	//try entity matcher
+	if !ok {
+		logger.Debugf("Failed to get channel Cfg orderer [%s] from ordererDict, now trying orderer Matchers in Entity Matchers", target)
+		// Try to find a match from entityMatchers config
+		matchingOrdererConfig, found, ignore := ctx.EndpointConfig().OrdererConfig(strings.ToLower(target))
+		if ignore {
+			logger.Debugf("orderer [%s] is ignored and will not be added", target)
+			return nil, nil
 		}
 
-		//create orderer using channel config block orderer address
-		if !ok {
-			logger.Debugf("Unable to find matching ordererConfig from entity Matchers for channel Cfg Orderer [%s]", target)
-			oCfg = fab.OrdererConfig{
-				URL: target,
-			}
-			logger.Debugf("Created a new OrdererConfig with URL as [%s]", target)
+		if found {
+			logger.Debugf("Found matching ordererConfig from entity Matchers for channel Cfg Orderer [%s]", target)
+			oCfg = *matchingOrdererConfig
+			ok = true
 		}
+	}
 
-		o, err := ctx.InfraProvider().CreateOrdererFromConfig(&oCfg)
-		if err != nil {
-			return nil, errors.WithMessage(err, "failed to create orderer from config")
+	//create orderer using channel config block orderer address
+	if !ok {
+		logger.Debugf("Unable to find matching ordererConfig from entity Matchers for channel Cfg Orderer [%s]", target)
+		oCfg = fab.OrdererConfig{
+			URL: target,
 		}
-		orderers = append(orderers, o)
+		logger.Debugf("Created a new OrdererConfig with URL as [%s]", target)
+	}
 
+	o, err := ctx.InfraProvider().CreateOrdererFromConfig(&oCfg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create orderer from config")
 	}
-	return orderers, nil
+	return o, nil
 }
 
 //deprecated
@@ -183,6 +198,91 @@ func (t *Transactor) CreateTransaction(request fab.TransactionRequest) (*fab.Tra
 }
 
 // SendTransaction send a transaction to the chain’s orderer service (one or more orderer endpoints) for consensus and committing to the ledger.
+//
+// If none of the orderers accepts the transaction, the transaction is sent to the other orderers of
+// the channel config (if any). If the ordering service is still unavailable (e.g. while a Raft cluster
+// elects a new leader), the same transaction is broadcast again according to retry.DefaultBroadcastOpts
+// until the request context is done.
+// The transaction is never endorsed again, so it can be accepted by the ordering service at most once.
 func (t *Transactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
-	return txn.Send(t.reqCtx, tx, t.orderers)
+	resp, err := txn.Send(t.reqCtx, tx, t.orderers)
+	if err == nil || !isBroadcastFailure(err) {
+		return resp, err
+	}
+
+	orderers := t.orderers
+	others, e := t.otherChannelOrderers()
+	if e != nil {
+		logger.Warnf("Unable to get the other orderers of channel [%s]: %s", t.ChannelID, e)
+	} else if len(others) > 0 {
+		logger.Infof("Broadcast to the orderers of channel [%s] failed - failing over to %d other orderer(s): %s", t.ChannelID, len(others), err)
+		resp, err = txn.Send(t.reqCtx, tx, others)
+		if err == nil {
+			return resp, nil
+		}
+		orderers = append(append([]fab.Orderer{}, orderers...), others...)
+	}
+
+	retryHandler := retry.New(retry.DefaultBroadcastOpts)
+	for retryHandler.Required(err) {
+		select {
+		case <-t.reqCtx.Done():
+			return nil, errors.WithStack(status.New(status.ClientStatus, status.Timeout.ToInt32(), "request timed out or been cancelled while broadcasting transaction", []interface{}{err.Error()}))
+		default:
+		}
+		logger.Infof("Broadcasting transaction again to the orderers of channel [%s]: %s", t.ChannelID, err)
+		resp, err = txn.Send(t.reqCtx, tx, orderers)
+		if err == nil {
+			return resp, nil
+		}
+	}
+	return nil, err
+}
+
+// otherChannelOrderers returns the orderers of the channel config to which the transactor does not send transactions
+func (t *Transactor) otherChannelOrderers() ([]fab.Orderer, error) {
+	if t.cfg == nil {
+		return nil, nil
+	}
+
+	ctx, ok := contextImpl.RequestClientContext(t.reqCtx)
+	if !ok {
+		return nil, errors.New("failed get client context from reqContext for SendTransaction")
+	}
+
+	known := make(map[string]bool)
+	for _, o := range t.orderers {
+		known[endpoint.ToAddress(o.URL())] = true
+	}
+
+	ordererDict := orderersByTarget(ctx)
+	var others []fab.Orderer
+	for _, target := range t.cfg.Orderers() {
+		o, err := ordererFromChannelCfg(ctx, ordererDict, target)
+		if err != nil {
+			return nil, err
+		}
+		if o == nil || known[endpoint.ToAddress(o.URL())] {
+			continue
+		}
+		known[endpoint.ToAddress(o.URL())] = true
+		others = append(others, o)
+	}
+	return others, nil
+}
+
+// isBroadcastFailure returns true if the orderers could not be reached or were temporarily unable to
+// process the transaction (e.g. while a Raft cluster elects a new leader), as opposed to an invalid
+// transaction (e.g. BAD_REQUEST, FORBIDDEN) that any other orderer would reject as well
+func isBroadcastFailure(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	for _, code := range retry.BroadcastRetryableCodes[s.Group] {
+		if status.Code(s.Code) == code {
+			return true
+		}
+	}
+	return false
 }
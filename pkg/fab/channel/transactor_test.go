@@ -15,6 +15,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/test/mockfab"
@@ -67,6 +69,85 @@ func TestTransaction(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestTransactionFailover(t *testing.T) {
+	transactor := createTransactor(t)
+	tx := createTransaction(t, transactor)
+
+	failing := mocks.NewMockOrderer("failing.example.com", nil)
+	failing.EnqueueSendBroadcastError(status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil))
+	transactor.orderers = []fab.Orderer{failing}
+	transactor.cfg.(*mocks.MockChannelCfg).MockOrderers = []string{"orderer.example.com"}
+
+	resp, err := transactor.SendTransaction(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com", resp.Orderer, "expecting failover to the other orderer of the channel config")
+}
+
+func TestTransactionNoFailoverOnInvalidTransaction(t *testing.T) {
+	transactor := createTransactor(t)
+	tx := createTransaction(t, transactor)
+
+	rejecting := mocks.NewMockOrderer("rejecting.example.com", nil)
+	rejecting.EnqueueSendBroadcastError(status.New(status.OrdererServerStatus, int32(common.Status_BAD_REQUEST), "bad request", nil))
+	transactor.orderers = []fab.Orderer{rejecting}
+	transactor.cfg.(*mocks.MockChannelCfg).MockOrderers = []string{"orderer.example.com"}
+
+	_, err := transactor.SendTransaction(tx)
+	assert.NotNil(t, err, "expecting an invalid transaction not to be broadcast to the other orderers")
+	s, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, int32(common.Status_BAD_REQUEST), s.Code)
+
+	assert.True(t, isBroadcastFailure(status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)))
+	assert.True(t, isBroadcastFailure(status.New(status.OrdererServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "no Raft leader", nil)))
+	assert.False(t, isBroadcastFailure(status.New(status.OrdererServerStatus, int32(common.Status_FORBIDDEN), "forbidden", nil)))
+}
+
+func TestTransactionBroadcastRetry(t *testing.T) {
+	transactor := createTransactor(t)
+	reqCtx, cancel := context.NewRequest(mocks.NewMockContext(mspmocks.NewMockSigningIdentity("test", "test")), context.WithTimeout(10*time.Second))
+	defer cancel()
+	transactor.reqCtx = reqCtx
+	tx := createTransaction(t, transactor)
+
+	leaderless := mocks.NewMockOrderer("orderer.example.com", nil)
+	leaderless.EnqueueSendBroadcastError(status.New(status.OrdererServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "no Raft leader", nil))
+	transactor.orderers = []fab.Orderer{leaderless}
+
+	resp, err := transactor.SendTransaction(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, "orderer.example.com", resp.Orderer, "expecting the transaction to be broadcast again")
+
+	_, err = transactor.SendTransaction(nil)
+	assert.NotNil(t, err)
+}
+
+func TestTransactionBroadcastRetryCancelled(t *testing.T) {
+	// the request context of the transactor has already been cancelled
+	transactor := createTransactor(t)
+	tx := createTransaction(t, transactor)
+
+	leaderless := mocks.NewMockOrderer("orderer.example.com", nil)
+	leaderless.EnqueueSendBroadcastError(status.New(status.OrdererServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "no Raft leader", nil))
+	transactor.orderers = []fab.Orderer{leaderless}
+
+	_, err := transactor.SendTransaction(tx)
+	assert.NotNil(t, err, "expecting the transaction not to be broadcast again")
+	s, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.Equal(t, status.ClientStatus, s.Group)
+	assert.EqualValues(t, status.Timeout.ToInt32(), s.Code)
+}
+
+func createTransaction(t *testing.T, transactor *Transactor) *fab.Transaction {
+	tp := createTransactionProposal(t, transactor)
+	tpr := createTransactionProposalResponse(t, transactor, tp)
+
+	tx, err := txn.New(fab.TransactionRequest{Proposal: tp, ProposalResponses: tpr})
+	assert.Nil(t, err)
+	return tx
+}
+
 func TestTransactionBadStatus(t *testing.T) {
 	transactor := createTransactor(t)
 	tp := createTransactionProposal(t, transactor)
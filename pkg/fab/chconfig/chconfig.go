@@ -364,6 +364,14 @@ func prepareOpts(options ...Option) (Opts, error) {
 	return opts, nil
 }
 
+// ExtractConfigFromBlock extracts the channel configuration from the given config block
+func ExtractConfigFromBlock(channelID string, block *common.Block) (*ChannelCfg, error) {
+	if block == nil || block.Data == nil || len(block.Data.Data) == 0 {
+		return nil, errors.New("expected data in config block")
+	}
+	return extractConfig(channelID, block)
+}
+
 func extractConfig(channelID string, block *common.Block) (*ChannelCfg, error) {
 	if block.Header == nil {
 		return nil, errors.New("expected header in block")
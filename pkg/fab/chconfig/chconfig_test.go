@@ -331,6 +331,28 @@ func TestCapabilities(t *testing.T) {
 	assert.Truef(t, chConfig.HasCapability(fab.ApplicationGroupKey, V1_4Capability), "expecting application capability [%s]", V1_4Capability)
 }
 
+func TestExtractConfigFromBlock(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP", "Org2MSP"},
+			OrdererAddress: "localhost:7050",
+			RootCA:         validRootCA,
+		},
+		Index:           3,
+		LastConfigIndex: 3,
+	}
+
+	chConfig, err := ExtractConfigFromBlock("mychannel", builder.Build())
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", chConfig.ID())
+	assert.Equal(t, uint64(3), chConfig.BlockNumber())
+	assert.Equal(t, []string{"localhost:7050"}, chConfig.Orderers())
+
+	_, err = ExtractConfigFromBlock("mychannel", &common.Block{Header: &common.BlockHeader{}})
+	assert.Error(t, err)
+}
+
 func testResolveOptsDefaultValues(t *testing.T, channelID string) {
 	user := mspmocks.NewMockSigningIdentity("test", "test")
 	ctx := mocks.NewMockContext(user)
@@ -133,6 +133,21 @@ func (cr *channelResponse) Endorsers(invocationChain discclient.InvocationChain,
 	return f.Filter(cr.peers), nil
 }
 
+// EndorsementLayouts returns a single layout that requires the endorsement of all peers
+func (cr *channelResponse) EndorsementLayouts(invocationChain discclient.InvocationChain) ([]map[string]int, map[string][]*discclient.Peer, error) {
+	if cr.endorsersErr != nil {
+		return nil, nil, cr.endorsersErr
+	}
+
+	layout := make(map[string]int)
+	endorsersByGroups := make(map[string][]*discclient.Peer)
+	for _, p := range cr.peers {
+		layout[p.MSPID]++
+		endorsersByGroups[p.MSPID] = append(endorsersByGroups[p.MSPID], p)
+	}
+	return []map[string]int{layout}, endorsersByGroups, nil
+}
+
 type localResponse struct {
 	peers []*discclient.Peer
 	err   error
@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package checkpoint provides implementations of fab.EventCheckpointer
+// that persist event processing progress in a core.KVStore.
+package checkpoint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/logging"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/keyvaluestore"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+const defaultKey = "checkpoint"
+
+type checkpointRecord struct {
+	BlockNum uint64 `json:"blockNum"`
+	TxIndex  int    `json:"txIndex"`
+}
+
+// Checkpointer stores the event checkpoint under a single key of a core.KVStore.
+// The current checkpoint is cached in memory and written through on every update.
+type Checkpointer struct {
+	store   core.KVStore
+	key     string
+	mutex   sync.RWMutex
+	current *fab.EventCheckpoint
+}
+
+// New returns a Checkpointer that stores the checkpoint in the given store under the given key.
+// An existing checkpoint for the key is loaded from the store.
+func New(store core.KVStore, key string) (*Checkpointer, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	if key == "" {
+		return nil, errors.New("key is empty")
+	}
+
+	cp := &Checkpointer{
+		store: store,
+		key:   key,
+	}
+
+	value, err := store.Load(key)
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return cp, nil
+		}
+		return nil, errors.WithMessage(err, "failed to load checkpoint")
+	}
+
+	valueBytes, ok := value.([]byte)
+	if !ok {
+		return nil, errors.Errorf("unexpected checkpoint value type: %T", value)
+	}
+	record := &checkpointRecord{}
+	if err := json.Unmarshal(valueBytes, record); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal checkpoint")
+	}
+	cp.current = &fab.EventCheckpoint{BlockNum: record.BlockNum, TxIndex: record.TxIndex}
+	logger.Debugf("Loaded checkpoint [%s]: block %d, tx index %d", key, record.BlockNum, record.TxIndex)
+
+	return cp, nil
+}
+
+// NewFileCheckpointer returns a Checkpointer that stores the checkpoint in the given file.
+// The file is replaced atomically on every update so that a crash never leaves a partially written checkpoint.
+func NewFileCheckpointer(path string) (*Checkpointer, error) {
+	store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{
+		Path: filepath.Dir(path),
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create file store")
+	}
+	return New(&atomicFileStore{FileKeyValueStore: store}, filepath.Base(path))
+}
+
+// atomicFileStore is a file store that writes each value to a temporary file
+// in the same directory and renames it over the target file.
+type atomicFileStore struct {
+	*keyvaluestore.FileKeyValueStore
+}
+
+// Store sets the value for the key.
+func (s *atomicFileStore) Store(key interface{}, value interface{}) error {
+	keyString, ok := key.(string)
+	if !ok {
+		return errors.New("converting key to string failed")
+	}
+	valueBytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("converting value to byte array failed")
+	}
+	return writeFileAtomic(filepath.Join(s.GetPath(), keyString), valueBytes)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// NewInMemoryCheckpointer returns a Checkpointer that keeps the checkpoint in memory only.
+// It allows resuming after a reconnect but not after a restart of the process.
+func NewInMemoryCheckpointer() *Checkpointer {
+	cp, err := New(keyvaluestore.NewMemKeyValueStore(), defaultKey)
+	if err != nil {
+		// Cannot happen since the store is empty
+		panic(err)
+	}
+	return cp
+}
+
+// Checkpoint returns the current checkpoint or nil if nothing has been checkpointed yet.
+func (c *Checkpointer) Checkpoint() (*fab.EventCheckpoint, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.current == nil {
+		return nil, nil
+	}
+	cp := *c.current
+	return &cp, nil
+}
+
+// CheckpointBlock records that the given block has been fully processed.
+func (c *Checkpointer) CheckpointBlock(blockNum uint64) error {
+	return c.update(blockNum+1, -1)
+}
+
+// CheckpointTransaction records that the transaction at txIndex in the given block has been processed.
+// Events are delivered in order, so all blocks before the given block are considered to be fully processed.
+func (c *Checkpointer) CheckpointTransaction(blockNum uint64, txIndex int) error {
+	if txIndex < 0 {
+		return errors.Errorf("invalid transaction index: %d", txIndex)
+	}
+	return c.update(blockNum, txIndex)
+}
+
+// CheckpointChaincodeEvent records that the transaction which emitted the given chaincode event has been processed.
+func (c *Checkpointer) CheckpointChaincodeEvent(event *fab.CCEvent) error {
+	if event == nil {
+		return errors.New("event is nil")
+	}
+	return c.CheckpointTransaction(event.BlockNumber, event.TxIndex)
+}
+
+// Delete removes the checkpoint from the store.
+func (c *Checkpointer) Delete() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.store.Delete(c.key); err != nil {
+		return errors.WithMessage(err, "failed to delete checkpoint")
+	}
+	c.current = nil
+	return nil
+}
+
+func (c *Checkpointer) update(blockNum uint64, txIndex int) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// The checkpoint never moves backwards
+	if c.current.Processed(blockNum, txIndex) {
+		return nil
+	}
+
+	valueBytes, err := json.Marshal(&checkpointRecord{BlockNum: blockNum, TxIndex: txIndex})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal checkpoint")
+	}
+	if err := c.store.Store(c.key, valueBytes); err != nil {
+		return errors.WithMessage(err, "failed to store checkpoint")
+	}
+	c.current = &fab.EventCheckpoint{BlockNum: blockNum, TxIndex: txIndex}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package checkpoint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryCheckpointer(t *testing.T) {
+	cp := NewInMemoryCheckpointer()
+
+	checkpoint, err := cp.Checkpoint()
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	require.NoError(t, cp.CheckpointBlock(5))
+	checkpoint, err = cp.Checkpoint()
+	require.NoError(t, err)
+	assert.Equal(t, &fab.EventCheckpoint{BlockNum: 6, TxIndex: -1}, checkpoint)
+
+	require.NoError(t, cp.CheckpointChaincodeEvent(&fab.CCEvent{BlockNumber: 6, TxIndex: 2}))
+	checkpoint, err = cp.Checkpoint()
+	require.NoError(t, err)
+	assert.Equal(t, &fab.EventCheckpoint{BlockNum: 6, TxIndex: 2}, checkpoint)
+	assert.True(t, checkpoint.Processed(5, 10))
+	assert.True(t, checkpoint.Processed(6, 2))
+	assert.False(t, checkpoint.Processed(6, 3))
+	assert.False(t, checkpoint.Processed(7, 0))
+
+	// The checkpoint never moves backwards
+	require.NoError(t, cp.CheckpointTransaction(6, 1))
+	require.NoError(t, cp.CheckpointBlock(4))
+	checkpoint, err = cp.Checkpoint()
+	require.NoError(t, err)
+	assert.Equal(t, &fab.EventCheckpoint{BlockNum: 6, TxIndex: 2}, checkpoint)
+
+	assert.Error(t, cp.CheckpointTransaction(6, -1))
+
+	require.NoError(t, cp.Delete())
+	checkpoint, err = cp.Checkpoint()
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+}
+
+func TestFileCheckpointer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "mychannel", "checkpoint.json")
+
+	cp, err := NewFileCheckpointer(path)
+	require.NoError(t, err)
+	require.NoError(t, cp.CheckpointTransaction(10, 3))
+	require.NoError(t, cp.CheckpointTransaction(10, 4))
+	require.NoError(t, cp.CheckpointTransaction(10, 3))
+
+	// No temporary files are left behind
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "checkpoint.json", files[0].Name())
+
+	// Simulate a restart
+	cp, err = NewFileCheckpointer(path)
+	require.NoError(t, err)
+	checkpoint, err := cp.Checkpoint()
+	require.NoError(t, err)
+	assert.Equal(t, &fab.EventCheckpoint{BlockNum: 10, TxIndex: 4}, checkpoint)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("invalid"), 0600))
+	_, err = NewFileCheckpointer(path)
+	assert.Error(t, err)
+}
@@ -414,6 +414,11 @@ func (c *Client) reconnect() {
 		}
 	}
 
+	if c.Stopped() {
+		logger.Debug("Event client was closed by the beforeReconnect handler. Not reconnecting.")
+		return
+	}
+
 	if err := c.connectWithRetry(c.maxReconnAttempts, c.timeBetweenConnAttempts); err != nil {
 		logger.Warnf("Could not reconnect event client: %s", err)
 		if !c.Stopped() {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	ab "gitee.com/zhaochuninhefei/fabric-protos-go-gm/orderer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/verifier"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/logging"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/options"
 	fabcontext "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
@@ -72,6 +73,33 @@ func New(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fa
 
 	dispatcher := dispatcher.New(context, chConfig, discoveryWrapper, params.connProvider, opts...)
 
+	if params.ordererIdentityValidation {
+		// The verifier follows the config blocks received on the channel
+		blockVerifier, err := verifier.NewOrdererBlockVerifier(chConfig.ID(), chConfig.MSPs())
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create orderer identity verifier")
+		}
+		dispatcher.SetBlockVerifier(blockVerifier.VerifyBlock)
+	}
+
+	// resume from the checkpoint, if any
+	if params.checkpointer != nil {
+		checkpoint, err := params.checkpointer.Checkpoint()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get checkpoint")
+		}
+		if checkpoint != nil {
+			logger.Debugf("Resuming from checkpoint: block %d, tx index %d", checkpoint.BlockNum, checkpoint.TxIndex)
+			params.seekType = seek.FromBlock
+			params.fromBlock = checkpoint.BlockNum
+		}
+	}
+
+	// a bounded range starts from the oldest block unless specified otherwise
+	if params.toBlockSet && params.seekType == "" {
+		params.seekType = seek.Oldest
+	}
+
 	//default seek type is `Newest`
 	if params.seekType == "" {
 		params.seekType = seek.Newest
@@ -88,6 +116,15 @@ func New(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fa
 	client.SetAfterConnectHandler(client.seek)
 	client.SetBeforeReconnectHandler(client.setSeekFromLastBlockReceived)
 
+	if params.toBlockSet {
+		// The deliver server ends the stream after the last requested block. Close the client so that
+		// the registration channels are closed, instead of reconnecting.
+		dispatcher.SetDeliveryCompleteHandler(func() {
+			logger.Debugf("Block range delivered up to block %d. Closing event client.", params.toBlock)
+			client.Close()
+		})
+	}
+
 	if err := client.Start(); err != nil {
 		return nil, err
 	}
@@ -124,21 +161,59 @@ func (c *Client) seek() error {
 }
 
 func (c *Client) setSeekFromLastBlockReceived() error {
+	complete, err := c.updateSeekFromLastBlockReceived()
+	if err != nil {
+		return err
+	}
+	if complete {
+		// All blocks of the requested range have already been received, so there is nothing to seek.
+		// Close the client so that the registration channels are closed, instead of reconnecting.
+		logger.Debugf("Block range already delivered up to block %d. Closing event client.", c.toBlock)
+		c.Close()
+	}
+	return nil
+}
+
+// updateSeekFromLastBlockReceived updates the seek info for a reconnect and returns true
+// if a bounded block range has already been delivered completely.
+func (c *Client) updateSeekFromLastBlockReceived() (bool, error) {
 	c.Lock()
 	defer c.Unlock()
 
 	// Make sure that, when we reconnect, we receive all of the events that we've missed
 	lastBlockNum := c.Dispatcher().LastBlockNum()
 	if lastBlockNum < math.MaxUint64 {
+		if c.toBlockSet && lastBlockNum >= c.toBlock {
+			return true, nil
+		}
 		c.seekType = seek.FromBlock
 		c.fromBlock = c.Dispatcher().LastBlockNum() + 1
 		logger.Debugf("Setting seek info from last block received + 1: %d", c.fromBlock)
+	} else if c.toBlockSet {
+		// We haven't received any blocks of the range yet. Seek the range again.
+		logger.Debugf("Keeping seek info for the block range")
 	} else {
 		// We haven't received any blocks yet. Just ask for the newest
 		logger.Debugf("Setting seek info from newest")
 		c.seekType = seek.Newest
 	}
-	return nil
+
+	// Blocks before the checkpoint have already been processed, so don't ask for them again
+	if c.checkpointer != nil {
+		checkpoint, err := c.checkpointer.Checkpoint()
+		if err != nil {
+			return false, errors.WithMessage(err, "failed to get checkpoint")
+		}
+		if checkpoint != nil && (c.seekType != seek.FromBlock || checkpoint.BlockNum > c.fromBlock) {
+			if c.toBlockSet && checkpoint.BlockNum > c.toBlock {
+				return true, nil
+			}
+			c.seekType = seek.FromBlock
+			c.fromBlock = checkpoint.BlockNum
+			logger.Debugf("Setting seek info from checkpoint: %d", c.fromBlock)
+		}
+	}
+	return false, nil
 }
 
 func (c *Client) seekInfo() (*ab.SeekInfo, error) {
@@ -147,12 +222,26 @@ func (c *Client) seekInfo() (*ab.SeekInfo, error) {
 
 	switch c.seekType {
 	case seek.Newest:
+		if c.toBlockSet {
+			return nil, errors.New("a block range is not supported with seek type newest")
+		}
 		logger.Debugf("Returning seek info: Newest")
 		return seek.InfoNewest(), nil
 	case seek.Oldest:
+		if c.toBlockSet {
+			logger.Debugf("Returning seek info: Range(0, %d)", c.toBlock)
+			return seek.InfoRange(0, c.toBlock), nil
+		}
 		logger.Debugf("Returning seek info: Oldest")
 		return seek.InfoOldest(), nil
 	case seek.FromBlock:
+		if c.toBlockSet {
+			if c.fromBlock > c.toBlock {
+				return nil, errors.Errorf("from block %d is greater than to block %d", c.fromBlock, c.toBlock)
+			}
+			logger.Debugf("Returning seek info: Range(%d, %d)", c.fromBlock, c.toBlock)
+			return seek.InfoRange(c.fromBlock, c.toBlock), nil
+		}
 		logger.Debugf("Returning seek info: FromBlock(%d)", c.fromBlock)
 		return seek.InfoFrom(c.fromBlock), nil
 	default:
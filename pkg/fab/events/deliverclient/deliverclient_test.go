@@ -134,6 +134,130 @@ func TestClientConnect_ImmediateTimeout(t *testing.T) {
 	require.Contains(t, err.Error(), "connection is closed")
 }
 
+func TestBlockRange(t *testing.T) {
+	channelID := "mychannel"
+
+	ledger := servicemocks.NewMockLedger(delivermocks.BlockEventFactory, sourceURL)
+	conn := delivermocks.NewConnection(clientmocks.WithLedger(ledger))
+
+	eventClient, err := New(
+		newMockContext(),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(peer1, peer2),
+		client.WithBlockEvents(),
+		withConnectionProvider(clientmocks.NewProviderFactory().Provider(conn)),
+		WithSeekType(seek.FromBlock),
+		WithBlockNum(0),
+		WithToBlock(1),
+	)
+	require.NoErrorf(t, err, "error creating deliver event client")
+
+	seekInfo, err := eventClient.seekInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), seekInfo.Stop.GetSpecified().Number)
+
+	err = eventClient.Connect()
+	require.NoErrorf(t, err, "error connecting deliver event client")
+
+	_, beventch, err := eventClient.RegisterBlockEvent()
+	require.NoErrorf(t, err, "error registering block events")
+
+	for i := 0; i < 2; i++ {
+		ledger.NewBlock(channelID,
+			servicemocks.NewTransaction("txID", pb.TxValidationCode_VALID, cb.HeaderType_ENDORSER_TRANSACTION),
+		)
+	}
+
+	for expectBlockNum := uint64(0); expectBlockNum < 2; expectBlockNum++ {
+		select {
+		case block := <-beventch:
+			require.Equal(t, expectBlockNum, block.Block.Header.Number)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for block #%d", expectBlockNum)
+		}
+	}
+
+	// The deliver server reports success after the last block of the range
+	conn.ProduceEvent(newDeliverStatusResponse(cb.Status_SUCCESS))
+
+	select {
+	case _, ok := <-beventch:
+		require.False(t, ok, "expecting block event channel to be closed")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for block event channel to be closed")
+	}
+}
+
+func TestBlockRangeCompleteBeforeReconnect(t *testing.T) {
+	channelID := "mychannel"
+
+	ledger := servicemocks.NewMockLedger(delivermocks.BlockEventFactory, sourceURL)
+	conn := delivermocks.NewConnection(clientmocks.WithLedger(ledger))
+
+	eventClient, err := New(
+		newMockContext(),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(peer1, peer2),
+		client.WithBlockEvents(),
+		withConnectionProvider(clientmocks.NewProviderFactory().Provider(conn)),
+		client.WithReconnect(true),
+		client.WithReconnectInitialDelay(0),
+		WithSeekType(seek.FromBlock),
+		WithBlockNum(0),
+		WithToBlock(1),
+	)
+	require.NoErrorf(t, err, "error creating deliver event client")
+
+	err = eventClient.Connect()
+	require.NoErrorf(t, err, "error connecting deliver event client")
+
+	_, beventch, err := eventClient.RegisterBlockEvent()
+	require.NoErrorf(t, err, "error registering block events")
+
+	for i := 0; i < 2; i++ {
+		ledger.NewBlock(channelID,
+			servicemocks.NewTransaction("txID", pb.TxValidationCode_VALID, cb.HeaderType_ENDORSER_TRANSACTION),
+		)
+	}
+	for expectBlockNum := uint64(0); expectBlockNum < 2; expectBlockNum++ {
+		select {
+		case block := <-beventch:
+			require.Equal(t, expectBlockNum, block.Block.Header.Number)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for block #%d", expectBlockNum)
+		}
+	}
+
+	// The connection is lost before the deliver server reports success. Since the last block
+	// of the range has already been received, the client completes instead of seeking again.
+	conn.ProduceEvent(clientdisp.NewDisconnectedEvent(errors.New("testing range completion")))
+
+	select {
+	case _, ok := <-beventch:
+		require.False(t, ok, "expecting block event channel to be closed")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for block event channel to be closed")
+	}
+}
+
+func TestBlockRangeSeekInfo(t *testing.T) {
+	c := &Client{Client: &client.Client{}, params: params{seekType: seek.Oldest, toBlock: 5, toBlockSet: true}}
+	seekInfo, err := c.seekInfo()
+	require.NoError(t, err)
+	require.NotNil(t, seekInfo.Start.GetSpecified())
+	require.Equal(t, uint64(0), seekInfo.Start.GetSpecified().Number)
+	require.Equal(t, uint64(5), seekInfo.Stop.GetSpecified().Number)
+
+	c.seekType = seek.FromBlock
+	c.fromBlock = 6
+	_, err = c.seekInfo()
+	require.Error(t, err)
+
+	c.seekType = seek.Newest
+	_, err = c.seekInfo()
+	require.Error(t, err)
+}
+
 // TestReconnect tests the ability of the Channel Event Client to retry multiple
 // times to connect, and reconnect after it has disconnected.
 func TestReconnect(t *testing.T) {
@@ -34,6 +34,8 @@ type dsConnection interface {
 // This also avoids the need for synchronization.
 type Dispatcher struct {
 	*clientdisp.Dispatcher
+	deliveryCompleteHandler func()
+	blockVerifier           func(block *cb.Block) error
 }
 
 // New returns a new deliver dispatcher
@@ -43,6 +45,20 @@ func New(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fa
 	}
 }
 
+// SetDeliveryCompleteHandler sets the handler that is invoked (in a separate Go routine) when the deliver
+// server reports that all of the requested blocks have been delivered. This only happens if
+// a stop position was specified in the seek request. The handler must be set before the dispatcher is started.
+func (ed *Dispatcher) SetDeliveryCompleteHandler(h func()) {
+	ed.deliveryCompleteHandler = h
+}
+
+// SetBlockVerifier sets the function that verifies each block received from the deliver server.
+// Blocks that fail verification are not published and the dispatcher disconnects from the peer
+// that delivered them. The verifier must be set before the dispatcher is started.
+func (ed *Dispatcher) SetBlockVerifier(v func(block *cb.Block) error) {
+	ed.blockVerifier = v
+}
+
 // Start starts the dispatcher
 func (ed *Dispatcher) Start() error {
 	ed.registerHandlers()
@@ -78,6 +94,13 @@ func (ed *Dispatcher) handleEvent(e esdispatcher.Event) {
 	case *pb.DeliverResponse_Status:
 		ed.handleDeliverResponseStatus(response)
 	case *pb.DeliverResponse_Block:
+		if ed.blockVerifier != nil {
+			if err := ed.blockVerifier(response.Block); err != nil {
+				logger.Errorf("Rejecting block from [%s]: %s", delevent.SourceURL, err)
+				ed.disconnect(clientdisp.NewDisconnectedEvent(errors.WithMessagef(err, "block rejected from [%s]", delevent.SourceURL)))
+				return
+			}
+		}
 		ed.HandleBlock(response.Block, delevent.SourceURL)
 	case *pb.DeliverResponse_FilteredBlock:
 		ed.HandleFilteredBlock(response.FilteredBlock, delevent.SourceURL)
@@ -90,11 +113,20 @@ func (ed *Dispatcher) handleDeliverResponseStatus(evt *pb.DeliverResponse_Status
 	logger.Debugf("Got deliver response status event: %#v", evt)
 
 	if evt.Status == cb.Status_SUCCESS {
+		if ed.deliveryCompleteHandler != nil {
+			logger.Debug("All requested blocks have been delivered")
+			// The handler may close the client, which submits events to this dispatcher, so it must not block
+			go ed.deliveryCompleteHandler()
+		}
 		return
 	}
 
 	logger.Warnf("Got deliver response status event: %#v. Disconnecting...", evt)
 
+	ed.disconnect(disconnectedEventFromStatus(evt.Status))
+}
+
+func (ed *Dispatcher) disconnect(disconnectedEvent *clientdisp.DisconnectedEvent) {
 	errch := make(chan error, 1)
 	ed.Dispatcher.HandleDisconnectEvent(&clientdisp.DisconnectEvent{
 		Errch: errch,
@@ -104,7 +136,7 @@ func (ed *Dispatcher) handleDeliverResponseStatus(evt *pb.DeliverResponse_Status
 		logger.Warnf("Error disconnecting: %s", err)
 	}
 
-	ed.Dispatcher.HandleDisconnectedEvent(disconnectedEventFromStatus(evt.Status))
+	ed.Dispatcher.HandleDisconnectedEvent(disconnectedEvent)
 }
 
 func (ed *Dispatcher) registerHandlers() {
@@ -19,7 +19,12 @@ type params struct {
 	connProvider api.ConnectionProvider
 	seekType     seek.Type
 	fromBlock    uint64
+	toBlock      uint64
+	toBlockSet   bool
 	respTimeout  time.Duration
+	checkpointer fab.EventCheckpointer
+
+	ordererIdentityValidation bool
 }
 
 func defaultParams() *params {
@@ -48,6 +53,43 @@ func WithBlockNum(value uint64) options.Opt {
 	}
 }
 
+// WithToBlock specifies the last block (inclusive) to be received. Once the block has been
+// received the client is closed and all registration channels are closed.
+// If no seek type is specified then blocks are received from the oldest block.
+// Note that this option is not valid if SeekType is set to Newest.
+func WithToBlock(value uint64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(toBlockSetter); ok {
+			setter.SetToBlock(value)
+		}
+	}
+}
+
+// WithCheckpointer specifies the checkpointer which records the progress of event processing.
+// If the checkpointer holds a checkpoint when the client is created then events are received
+// from the checkpoint and the seek type and block number are ignored. On reconnect, events are
+// received from the later of the checkpoint and the block following the last block received.
+func WithCheckpointer(value fab.EventCheckpointer) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(checkpointerSetter); ok {
+			setter.SetCheckpointer(value)
+		}
+	}
+}
+
+// WithOrdererIdentityValidation indicates that the identities and signatures of the orderers that
+// signed each block are to be validated against the channel MSPs. Blocks with an invalid signature,
+// or signed by an expired, untrusted or revoked certificate, or by an identity without the orderer
+// role, are not published and the client disconnects from the peer that delivered them.
+// Note that this option is only valid for block events since filtered blocks are not signed.
+func WithOrdererIdentityValidation() options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(ordererIdentityValidationSetter); ok {
+			setter.SetOrdererIdentityValidation(true)
+		}
+	}
+}
+
 type seekTypeSetter interface {
 	SetSeekType(value seek.Type)
 }
@@ -56,6 +98,18 @@ type fromBlockSetter interface {
 	SetFromBlock(value uint64)
 }
 
+type toBlockSetter interface {
+	SetToBlock(value uint64)
+}
+
+type checkpointerSetter interface {
+	SetCheckpointer(value fab.EventCheckpointer)
+}
+
+type ordererIdentityValidationSetter interface {
+	SetOrdererIdentityValidation(value bool)
+}
+
 func (p *params) PermitBlockEvents() {
 	logger.Debug("PermitBlockEvents")
 	p.connProvider = deliverProvider
@@ -79,6 +133,22 @@ func (p *params) SetSeekType(value seek.Type) {
 	}
 }
 
+func (p *params) SetToBlock(value uint64) {
+	logger.Debugf("ToBlock: %d", value)
+	p.toBlock = value
+	p.toBlockSet = true
+}
+
+func (p *params) SetCheckpointer(value fab.EventCheckpointer) {
+	logger.Debugf("Checkpointer: %#v", value)
+	p.checkpointer = value
+}
+
+func (p *params) SetOrdererIdentityValidation(value bool) {
+	logger.Debugf("OrdererIdentityValidation: %t", value)
+	p.ordererIdentityValidation = value
+}
+
 func (p *params) SetResponseTimeout(value time.Duration) {
 	logger.Debugf("ResponseTimeout: %s", value)
 	p.respTimeout = value
@@ -48,6 +48,13 @@ func InfoFrom(fromBlock uint64) *ab.SeekInfo {
 	return newSeekInfo(seekFromPos(fromBlock), maxPos)
 }
 
+// InfoRange returns a SeekInfo struct that indicates to the deliver server
+// that we want the blocks from fromBlock to toBlock (inclusive). The deliver server
+// waits for blocks that have not been committed yet and ends the stream after toBlock.
+func InfoRange(fromBlock, toBlock uint64) *ab.SeekInfo {
+	return newSeekInfo(seekFromPos(fromBlock), seekFromPos(toBlock))
+}
+
 func seekFromPos(fromBlock uint64) *ab.SeekPosition {
 	return &ab.SeekPosition{
 		Type: &ab.SeekPosition_Specified{
@@ -10,6 +10,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	"github.com/pkg/errors"
@@ -38,6 +40,8 @@ type FileKeyValueStore struct {
 	keySerializer KeySerializer
 	marshaller    Marshaller
 	unmarshaller  Unmarshaller
+	// keys can only be listed if they map to file paths with the default key serializer
+	listable bool
 }
 
 // FileKeyValueStoreOptions allow overriding store defaults
@@ -82,6 +86,7 @@ func New(opts *FileKeyValueStoreOptions) (*FileKeyValueStore, error) {
 	if opts.Path == "" {
 		return nil, errors.New("FileKeyValueStore path is empty")
 	}
+	listable := opts.KeySerializer == nil
 	if opts.KeySerializer == nil {
 		// Default key serializer
 		opts.KeySerializer = func(key interface{}) (string, error) {
@@ -103,6 +108,7 @@ func New(opts *FileKeyValueStoreOptions) (*FileKeyValueStore, error) {
 		keySerializer: opts.KeySerializer,
 		marshaller:    opts.Marshaller,
 		unmarshaller:  opts.Unmarshaller,
+		listable:      listable,
 	}, nil
 }
 
@@ -168,3 +174,36 @@ func (fkvs *FileKeyValueStore) Delete(key interface{}) error {
 	}
 	return os.Remove(file)
 }
+
+// Keys returns the keys that start with the given prefix.
+// Keys can only be listed if the store uses the default key serializer.
+func (fkvs *FileKeyValueStore) Keys(prefix string) ([]string, error) {
+	if !fkvs.listable {
+		return nil, errors.New("listing keys is not supported with a custom key serializer")
+	}
+	var keys []string
+	err := filepath.Walk(fkvs.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(fkvs.path, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing keys failed")
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -71,6 +71,19 @@ func testFKVS(t *testing.T, KeySerializer KeySerializer) {
 		t.Fatalf("SetValue %s failed [%s]", key1, err1)
 	}
 
+	// Check listing keys
+	keys, err := store.(core.KVStoreKeyLister).Keys("key")
+	if KeySerializer == nil {
+		if err != nil {
+			t.Fatalf("Keys failed [%s]", err)
+		}
+		if len(keys) != 2 || keys[0] != key1 || keys[1] != key2 {
+			t.Fatalf("Keys returned unexpected keys %v", keys)
+		}
+	} else if err == nil {
+		t.Fatal("Keys should fail with a custom key serializer")
+	}
+
 	// Check key1, value1
 	checkKeyValue(store, key1, value1, t)
 
@@ -84,6 +97,35 @@ func testFKVS(t *testing.T, KeySerializer KeySerializer) {
 	checkEmptyStringValue(store, t)
 }
 
+func TestFKVSKeysWithPath(t *testing.T) {
+	store, err := New(&FileKeyValueStoreOptions{Path: storePath})
+	if err != nil {
+		t.Fatalf("New failed [%s]", err)
+	}
+	if err1 := cleanup(storePath); err1 != nil {
+		t.Fatalf("%s", err1)
+	}
+	defer cleanup(storePath)
+
+	keys, err := store.Keys("org1/")
+	if err != nil || len(keys) != 0 {
+		t.Fatalf("Keys of a missing store path should be empty [%v, %v]", keys, err)
+	}
+
+	for _, key := range []string{"org1/user1", "org1/user2", "org10/user1"} {
+		if err := store.Store(key, []byte(key)); err != nil {
+			t.Fatalf("Store %s failed [%s]", key, err)
+		}
+	}
+	keys, err = store.Keys("org1/")
+	if err != nil {
+		t.Fatalf("Keys failed [%s]", err)
+	}
+	if len(keys) != 2 || keys[0] != "org1/user1" || keys[1] != "org1/user2" {
+		t.Fatalf("Keys returned unexpected keys %v", keys)
+	}
+}
+
 func checkKeyValue(store core.KVStore, key string, value []byte, t *testing.T) {
 	if err := checkStoreValue(store, key, value); err != nil {
 		t.Fatalf("checkStoreValue %s failed [%s]", key, err)
@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// MemKeyValueStore stores values in memory. Its content is lost when the process exits.
+type MemKeyValueStore struct {
+	mutex sync.RWMutex
+	store map[interface{}]interface{}
+}
+
+// NewMemKeyValueStore creates a new instance of MemKeyValueStore
+func NewMemKeyValueStore() *MemKeyValueStore {
+	return &MemKeyValueStore{
+		store: make(map[interface{}]interface{}),
+	}
+}
+
+// Load returns the value stored in the store for a key.
+// If a value for the key was not found, returns (nil, ErrNotFound)
+func (m *MemKeyValueStore) Load(key interface{}) (interface{}, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	value, ok := m.store[key]
+	if !ok {
+		return nil, core.ErrKeyValueNotFound
+	}
+	return value, nil
+}
+
+// Store sets the value for the key.
+func (m *MemKeyValueStore) Store(key interface{}, value interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.store[key] = value
+	return nil
+}
+
+// Delete deletes the value for a key.
+func (m *MemKeyValueStore) Delete(key interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.store, key)
+	return nil
+}
+
+// Keys returns the string keys that start with the given prefix.
+func (m *MemKeyValueStore) Keys(prefix string) ([]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var keys []string
+	for key := range m.store {
+		if k, ok := key.(string); ok && strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bytes"
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+)
+
+func TestMemKVS(t *testing.T) {
+	var store core.KVStore = NewMemKeyValueStore()
+
+	if err := store.Store(nil, []byte("1234")); err == nil || err.Error() != "key is nil" {
+		t.Fatal("Store(nil, ...) should throw error")
+	}
+	if err := store.Store("key", nil); err == nil || err.Error() != "value is nil" {
+		t.Fatal("Store(..., nil) should throw error")
+	}
+
+	if _, err := store.Load("key"); err != core.ErrKeyValueNotFound {
+		t.Fatal("Load should return ErrKeyValueNotFound for a missing key")
+	}
+	if err := store.Store("key", []byte("1234")); err != nil {
+		t.Fatalf("Store failed [%s]", err)
+	}
+	value, err := store.Load("key")
+	if err != nil {
+		t.Fatalf("Load failed [%s]", err)
+	}
+	if !bytes.Equal(value.([]byte), []byte("1234")) {
+		t.Fatalf("Load returned unexpected value [%v]", value)
+	}
+
+	if err := store.Delete("key"); err != nil {
+		t.Fatalf("Delete failed [%s]", err)
+	}
+	if _, err := store.Load("key"); err != core.ErrKeyValueNotFound {
+		t.Fatal("Load should return ErrKeyValueNotFound after Delete")
+	}
+	if err := store.Delete("key"); err != nil {
+		t.Fatalf("Delete of a missing key should not fail [%s]", err)
+	}
+}
+
+func TestMemKVSKeys(t *testing.T) {
+	store := NewMemKeyValueStore()
+	for _, key := range []interface{}{"org1/user2", "org1/user1", "org10/user1", 1} {
+		if err := store.Store(key, []byte("value")); err != nil {
+			t.Fatalf("Store failed [%s]", err)
+		}
+	}
+
+	keys, err := store.Keys("org1/")
+	if err != nil {
+		t.Fatalf("Keys failed [%s]", err)
+	}
+	if len(keys) != 2 || keys[0] != "org1/user1" || keys[1] != "org1/user2" {
+		t.Fatalf("Keys returned unexpected keys %v", keys)
+	}
+}
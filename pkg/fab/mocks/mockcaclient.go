@@ -8,12 +8,17 @@ package mocks
 
 import (
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/msp/api"
 	"github.com/pkg/errors"
 )
 
 // MockCAClient is a mock CAClient
 type MockCAClient struct {
+	// Certs are the PEM-encoded certificates returned by GetCertificates
+	Certs [][]byte
+	// CRL is the PEM-encoded CRL returned by GenCRL
+	CRL []byte
 }
 
 // NewMockCAClient Constructor for a CA client.
@@ -32,6 +37,11 @@ func (mgr *MockCAClient) Reenroll(request *api.ReenrollmentRequest) error {
 	return errors.New("not implemented")
 }
 
+// ReenrollIdentity re-enrolls a signing identity
+func (mgr *MockCAClient) ReenrollIdentity(identity msp.SigningIdentity, request *api.ReenrollmentRequest) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
 // Register registers a user with a Fabric network
 func (mgr *MockCAClient) Register(request *api.RegistrationRequest) (string, error) {
 	return "", errors.New("not implemented")
@@ -96,3 +106,33 @@ func (mgr *MockCAClient) RemoveAffiliation(request *api.AffiliationRequest) (*ap
 func (mgr *MockCAClient) GetCAInfo() (*api.GetCAInfoResponse, error) {
 	return nil, errors.New("not implemented")
 }
+
+// GetCertificates returns certificates matching the request filters
+// The mock holds no revoked certificates, so a request for a revoked time range returns none.
+func (mgr *MockCAClient) GetCertificates(request *api.GetCertificatesRequest) (*api.GetCertificatesResponse, error) {
+	if request == nil {
+		return nil, errors.New("get certificates request is required")
+	}
+	revokedRange := !request.RevokedStart.IsZero() || !request.RevokedEnd.IsZero()
+	if request.NotRevoked && revokedRange {
+		return nil, errors.New("cannot specify revoked time range and notrevoked")
+	}
+	if request.NotExpired && (!request.ExpiredStart.IsZero() || !request.ExpiredEnd.IsZero()) {
+		return nil, errors.New("cannot specify expired time range and notexpired")
+	}
+	if revokedRange {
+		return &api.GetCertificatesResponse{Certs: [][]byte{}}, nil
+	}
+	return &api.GetCertificatesResponse{Certs: mgr.Certs}, nil
+}
+
+// GenCRL generates a CRL
+func (mgr *MockCAClient) GenCRL(request *api.GenCRLRequest) (*api.GenCRLResponse, error) {
+	if request == nil {
+		return nil, errors.New("gen CRL request is required")
+	}
+	if mgr.CRL == nil {
+		return nil, errors.New("no CRL configured")
+	}
+	return &api.GenCRLResponse{CRL: mgr.CRL}, nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/msp/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockCAClientGetCertificates(t *testing.T) {
+	cert := []byte("-----BEGIN CERTIFICATE-----")
+	caClient := &MockCAClient{Certs: [][]byte{cert}}
+
+	_, err := caClient.GetCertificates(nil)
+	assert.Error(t, err)
+
+	resp, err := caClient.GetCertificates(&api.GetCertificatesRequest{ID: "user1", NotRevoked: true})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{cert}, resp.Certs)
+
+	resp, err = caClient.GetCertificates(&api.GetCertificatesRequest{RevokedStart: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Certs)
+
+	_, err = caClient.GetCertificates(&api.GetCertificatesRequest{NotRevoked: true, RevokedEnd: time.Now()})
+	assert.Error(t, err)
+
+	_, err = caClient.GetCertificates(&api.GetCertificatesRequest{NotExpired: true, ExpiredEnd: time.Now()})
+	assert.Error(t, err)
+}
+
+func TestMockCAClientGenCRL(t *testing.T) {
+	caClient := &MockCAClient{}
+
+	_, err := caClient.GenCRL(&api.GenCRLRequest{})
+	assert.Error(t, err)
+
+	caClient.CRL = []byte("-----BEGIN X509 CRL-----")
+	_, err = caClient.GenCRL(nil)
+	assert.Error(t, err)
+
+	resp, err := caClient.GenCRL(&api.GenCRLRequest{RevokedAfter: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, caClient.CRL, resp.CRL)
+}
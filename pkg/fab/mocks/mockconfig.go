@@ -224,6 +224,11 @@ func (c *MockConfig) KeyStorePath() string {
 	return "/tmp/fabsdkgo_test"
 }
 
+// KeyStorePassphrase ...
+func (c *MockConfig) KeyStorePassphrase() ([]byte, error) {
+	return nil, nil
+}
+
 // CredentialStorePath ...
 func (c *MockConfig) CredentialStorePath() string {
 	return "/tmp/userstore"
@@ -313,6 +318,21 @@ func (c *MockConfig) SecurityProviderLabel() string {
 	return ""
 }
 
+// SecurityProviderMechanisms ...
+func (c *MockConfig) SecurityProviderMechanisms() map[string]uint {
+	return nil
+}
+
+// SecurityProviderImmutable ...
+func (c *MockConfig) SecurityProviderImmutable() bool {
+	return false
+}
+
+// SecurityProviderEphemeral ...
+func (c *MockConfig) SecurityProviderEphemeral() bool {
+	return true
+}
+
 //SecurityProviderPin ...
 func (c *MockConfig) SecurityProviderPin() string {
 	return ""
@@ -24,7 +24,7 @@ type SigningManager struct {
 // @param {Config} config - configuration provider
 // @returns {SigningManager} new signing manager
 func New(cryptoProvider core.CryptoSuite) (*SigningManager, error) {
-	return &SigningManager{cryptoProvider: cryptoProvider, hashOpts: cryptosuite.GetSM3Opts()}, nil
+	return &SigningManager{cryptoProvider: cryptoProvider}, nil
 }
 
 // Sign will sign the given object using provided key
@@ -37,10 +37,20 @@ func (mgr *SigningManager) Sign(object []byte, key core.Key) ([]byte, error) {
 		return nil, errors.New("key (for signing) required")
 	}
 
-	digest, err := mgr.cryptoProvider.Hash(object, mgr.hashOpts)
+	// hash algorithm follows the key type (SM3 for SM2 keys, SHA-256 for ECDSA keys in hybrid mode)
+	hashOpts := mgr.hashOpts
+	if hashOpts == nil {
+		hashOpts = cryptosuite.GetSignatureHashOpts(key)
+	}
+
+	digest, err := mgr.cryptoProvider.Hash(object, hashOpts)
 	if err != nil {
 		return nil, err
 	}
+	// keys held outside of the crypto suite (e.g. by a remote signer) sign the digest themselves
+	if signingKey, ok := key.(core.SigningKey); ok {
+		return signingKey.Sign(digest, mgr.signerOpts)
+	}
 	signature, err := mgr.cryptoProvider.Sign(key, digest, mgr.signerOpts)
 	if err != nil {
 		return nil, err
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"testing"
 
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	bccspwrapper "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite/bccsp/wrapper"
 	fcmocks "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/mocks"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/msp/test/mockmsp"
@@ -46,5 +47,30 @@ func TestSigningManager(t *testing.T) {
 	if !bytes.Equal(signedObj, expectedObj) {
 		t.Fatalf("Expecting %s, got %s", expectedObj, signedObj)
 	}
+}
+
+type mockSigningKey struct {
+	core.Key
+}
+
+func (k *mockSigningKey) Sign(digest []byte, opts core.SignerOpts) ([]byte, error) {
+	return []byte("remoteSignature"), nil
+}
+
+func TestSigningManagerWithSigningKey(t *testing.T) {
+
+	signingMgr, err := New(&fcmocks.MockCryptoSuite{})
+	if err != nil {
+		t.Fatalf("Failed to  setup discovery provider: %s", err)
+	}
 
+	signedObj, err := signingMgr.Sign([]byte("Hello"), &mockSigningKey{Key: bccspwrapper.GetKey(&mockmsp.MockKey{})})
+	if err != nil {
+		t.Fatalf("Failed to sign object: %s", err)
+	}
+
+	expectedObj := []byte("remoteSignature")
+	if !bytes.Equal(signedObj, expectedObj) {
+		t.Fatalf("Expecting %s, got %s", expectedObj, signedObj)
+	}
 }
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"github.com/golang/protobuf/proto"
+)
+
+// acceptedTxRetention is how long the ID of a transaction that was accepted by an orderer is
+// remembered. A transaction that is sent again within that time is not broadcast again.
+const acceptedTxRetention = 10 * time.Minute
+
+// broadcasts tracks the transactions that are being broadcast or that have been accepted by the
+// ordering service, so that a transaction is accepted at most once even if it is sent again
+// (e.g. by a failover to other orderers or by an application retry of the same transaction).
+//
+// Note that a transaction may still reach the ordering service twice if an orderer accepted it
+// but the response was lost (e.g. the connection dropped) and the envelope was then sent to
+// another orderer. Since the same envelope (with the same transaction ID) is sent, the committing
+// peers only validate the first one and mark the other one as a duplicate (DUPLICATE_TXID).
+var broadcasts = newBroadcastTracker(acceptedTxRetention)
+
+type acceptedTx struct {
+	response *fab.TransactionResponse
+	time     time.Time
+}
+
+type broadcastTracker struct {
+	mutex     sync.Mutex
+	retention time.Duration
+	inFlight  map[string]struct{}
+	accepted  map[string]*acceptedTx
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func newBroadcastTracker(retention time.Duration) *broadcastTracker {
+	return &broadcastTracker{
+		retention: retention,
+		inFlight:  make(map[string]struct{}),
+		accepted:  make(map[string]*acceptedTx),
+		now:       time.Now,
+	}
+}
+
+// begin is invoked before the transaction with the given ID is broadcast. It returns the
+// response of the orderer if the transaction was already accepted, or an error if the
+// transaction is currently being broadcast.
+func (t *broadcastTracker) begin(txID string) (*fab.TransactionResponse, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if tx, ok := t.accepted[txID]; ok && t.now().Sub(tx.time) < t.retention {
+		logger.Debugf("Transaction [%s] was already accepted by orderer [%s]", txID, tx.response.Orderer)
+		return tx.response, nil
+	}
+	if _, ok := t.inFlight[txID]; ok {
+		return nil, status.New(status.ClientStatus, status.BroadcastInProgress.ToInt32(), "transaction is already being broadcast", []interface{}{txID})
+	}
+	t.inFlight[txID] = struct{}{}
+	return nil, nil
+}
+
+// end is invoked once the broadcast of the transaction with the given ID has completed.
+// The response is nil if no orderer accepted the transaction.
+func (t *broadcastTracker) end(txID string, response *fab.TransactionResponse) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.inFlight, txID)
+	if response == nil {
+		return
+	}
+
+	now := t.now()
+	t.accepted[txID] = &acceptedTx{response: response, time: now}
+
+	// Prune expired entries at most twice per retention period so that the cost is amortized
+	if now.Sub(t.lastPrune) < t.retention/2 {
+		return
+	}
+	for id, tx := range t.accepted {
+		if now.Sub(tx.time) >= t.retention {
+			delete(t.accepted, id)
+		}
+	}
+	t.lastPrune = now
+}
+
+// envelopeTxID returns the transaction ID of the given envelope or an empty string
+// if the envelope does not carry one
+func envelopeTxID(envelope *fab.SignedEnvelope) string {
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil || payload.Header == nil {
+		return ""
+	}
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, channelHeader); err != nil {
+		return ""
+	}
+	return channelHeader.TxId
+}
+
+// selectOrderers returns the orderers in the order in which they should be tried.
+// Orderers are tried in a random order unless a selector is provided.
+func selectOrderers(selector fab.OrdererSelector, orderers []fab.Orderer) []fab.Orderer {
+	if selector != nil {
+		return selector.Select(orderers)
+	}
+
+	selected := make([]fab.Orderer, len(orderers))
+	for i, index := range rand.Perm(len(orderers)) {
+		selected[i] = orderers[index]
+	}
+	return selected
+}
+
+// observeOrderer notifies the selector (if it is an observer) that an envelope is about to be
+// broadcast to the orderer. The returned function must be invoked with the outcome.
+func observeOrderer(selector fab.OrdererSelector, orderer fab.Orderer) func(err error) {
+	observer, ok := selector.(fab.OrdererObserver)
+	if !ok {
+		return func(error) {}
+	}
+	return observer.Begin(orderer.URL())
+}
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/context"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/mocks"
+	mspmocks "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/msp/test/mockmsp"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderedSelector selects the orderers in the given order and records the broadcast outcomes
+type orderedSelector struct {
+	outcomes map[string][]error
+}
+
+func (s *orderedSelector) Select(orderers []fab.Orderer) []fab.Orderer {
+	return orderers
+}
+
+func (s *orderedSelector) Begin(url string) func(err error) {
+	return func(err error) {
+		s.outcomes[url] = append(s.outcomes[url], err)
+	}
+}
+
+func newTxEnvelope(t *testing.T, txID string) *fab.SignedEnvelope {
+	channelHeader, err := proto.Marshal(&common.ChannelHeader{TxId: txID, ChannelId: "mychannel"})
+	require.NoError(t, err)
+	payload, err := proto.Marshal(&common.Payload{Header: &common.Header{ChannelHeader: channelHeader}})
+	require.NoError(t, err)
+	return &fab.SignedEnvelope{Payload: payload}
+}
+
+func TestBroadcastFailoverAtMostOnce(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	lsnr1 := make(chan *fab.SignedEnvelope, 10)
+	lsnr2 := make(chan *fab.SignedEnvelope, 10)
+	orderer1 := mocks.NewMockOrderer("orderer1", lsnr1)
+	orderer2 := mocks.NewMockOrderer("orderer2", lsnr2)
+	orderer1.EnqueueSendBroadcastError(status.New(status.OrdererServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "no Raft leader", nil))
+
+	selector := &orderedSelector{outcomes: make(map[string][]error)}
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second), context.WithOrdererSelector(selector))
+	defer cancel()
+
+	envelope := newTxEnvelope(t, "txid-failover")
+	orderers := []fab.Orderer{orderer1, orderer2}
+
+	resp, err := broadcastEnvelope(reqCtx, envelope, orderers)
+	require.NoError(t, err)
+	assert.Equal(t, "orderer2", resp.Orderer, "expecting the broadcast to fail over to the next selected orderer")
+	assert.Len(t, lsnr1, 1)
+	assert.Len(t, lsnr2, 1)
+	require.Len(t, selector.outcomes["orderer1"], 1)
+	assert.Error(t, selector.outcomes["orderer1"][0])
+	require.Len(t, selector.outcomes["orderer2"], 1)
+	assert.NoError(t, selector.outcomes["orderer2"][0])
+
+	// The transaction was accepted - sending it again must not broadcast it again
+	resp, err = broadcastEnvelope(reqCtx, envelope, orderers)
+	require.NoError(t, err)
+	assert.Equal(t, "orderer2", resp.Orderer)
+	assert.Len(t, lsnr1, 1)
+	assert.Len(t, lsnr2, 1)
+}
+
+func TestBroadcastTracker(t *testing.T) {
+	tracker := newBroadcastTracker(time.Minute)
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+
+	resp, err := tracker.begin("tx1")
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+
+	_, err = tracker.begin("tx1")
+	require.Error(t, err, "expecting an error for a transaction that is being broadcast")
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.EqualValues(t, status.BroadcastInProgress, s.Code)
+
+	// The broadcast failed - the transaction may be broadcast again
+	tracker.end("tx1", nil)
+	_, err = tracker.begin("tx1")
+	require.NoError(t, err)
+
+	accepted := &fab.TransactionResponse{Orderer: "orderer1"}
+	tracker.end("tx1", accepted)
+	resp, err = tracker.begin("tx1")
+	require.NoError(t, err)
+	assert.Equal(t, accepted, resp)
+
+	// Expired transactions are forgotten
+	now = now.Add(time.Minute)
+	resp, err = tracker.begin("tx1")
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+	tracker.end("tx1", nil)
+
+	_, err = tracker.begin("tx2")
+	require.NoError(t, err)
+	tracker.end("tx2", accepted)
+	assert.Len(t, tracker.accepted, 1, "expecting expired transactions to be pruned")
+}
+
+func TestEnvelopeTxID(t *testing.T) {
+	assert.Equal(t, "txid", envelopeTxID(newTxEnvelope(t, "txid")))
+	assert.Empty(t, envelopeTxID(&fab.SignedEnvelope{Payload: []byte("")}))
+	assert.Empty(t, envelopeTxID(&fab.SignedEnvelope{Payload: []byte("invalid")}))
+}
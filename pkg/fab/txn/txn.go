@@ -9,9 +9,9 @@ package txn
 
 import (
 	reqContext "context"
-	"math/rand"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/multi"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/errors/status"
 	"github.com/pkg/errors"
 
 	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
@@ -155,37 +155,83 @@ func BroadcastPayload(reqCtx reqContext.Context, payload *common.Payload, ordere
 	return broadcastEnvelope(reqCtx, envelope, orderers)
 }
 
-// broadcastEnvelope will send the given envelope to some orderer, picking random endpoints
-// until all are exhausted
+// broadcastEnvelope will send the given envelope to some orderer, picking endpoints in the order
+// given by the orderer selector (random by default) until all are exhausted
 func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
 	// Check if orderers are defined
 	if len(orderers) == 0 {
 		return nil, errors.New("orderers not set")
 	}
 
-	// Copy aside the ordering service endpoints
-	randOrderers := []fab.Orderer{}
-	randOrderers = append(randOrderers, orderers...)
-
 	// get a context client instance to create child contexts with timeout read from the config in sendBroadcast()
 	ctxClient, ok := context.RequestClientContext(reqCtx)
 	if !ok {
 		return nil, errors.New("failed get client context from reqContext for SendTransaction")
 	}
 
-	// Iterate them in a random order and try broadcasting 1 by 1
+	// Make sure that the same transaction is not accepted twice by the ordering service
+	txID := envelopeTxID(envelope)
+	if txID != "" {
+		resp, err := broadcasts.begin(txID)
+		if err != nil || resp != nil {
+			return resp, err
+		}
+	}
+
+	resp, err := failoverBroadcast(reqCtx, envelope, orderers, ctxClient)
+
+	if txID != "" {
+		broadcasts.end(txID, resp)
+	}
+	return resp, err
+}
+
+// failoverBroadcast tries broadcasting the envelope to the orderers 1 by 1 until one of them accepts it
+func failoverBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer, ctxClient ctxprovider.Client) (*fab.TransactionResponse, error) {
+	breaker, _ := context.RequestCircuitBreaker(reqCtx)
+	selector, _ := context.RequestOrdererSelector(reqCtx)
+
 	var errResp error
-	for _, i := range rand.Perm(len(randOrderers)) {
-		resp, err := sendBroadcast(reqCtx, envelope, randOrderers[i], ctxClient)
+	for _, orderer := range selectOrderers(selector, orderers) {
+		if !allowOrderer(breaker, orderer) {
+			continue
+		}
+		done := observeOrderer(selector, orderer)
+		resp, err := sendBroadcast(reqCtx, envelope, orderer, ctxClient)
+		done(err)
+		recordOrderer(breaker, orderer, err)
 		if err != nil {
+			logger.Debugf("Broadcast to orderer [%s] failed - trying the next orderer: %s", orderer.URL(), err)
 			errResp = err
 		} else {
 			return resp, nil
 		}
 	}
+	if errResp == nil {
+		return nil, errOrderersUnavailable
+	}
 	return nil, errResp
 }
 
+// errOrderersUnavailable is returned when the circuits of all orderers are open
+var errOrderersUnavailable = status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), "all orderers are unavailable (circuit open)", nil)
+
+// allowOrderer returns false if the circuit breaker rejects requests to the orderer
+func allowOrderer(breaker fab.CircuitBreaker, orderer fab.Orderer) bool {
+	if breaker == nil || breaker.Allow(orderer.URL()) {
+		return true
+	}
+	logger.Debugf("Skipping orderer [%s] since its circuit is open", orderer.URL())
+	return false
+}
+
+// recordOrderer records the outcome of a request sent to the orderer with the circuit breaker
+func recordOrderer(breaker fab.CircuitBreaker, orderer fab.Orderer, err error) {
+	if breaker != nil {
+		breaker.Record(orderer.URL(), err)
+	}
+}
+
 func sendBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer, client ctxprovider.Client) (*fab.TransactionResponse, error) {
 	logger.Debugf("Broadcasting envelope to orderer: %s\n", orderer.URL())
 	// create a childContext for this SendBroadcast orderer using the config's timeout value
@@ -218,20 +264,26 @@ func SendPayload(reqCtx reqContext.Context, payload *common.Payload, orderers []
 		return nil, err
 	}
 
-	// Copy aside the ordering service endpoints
-	randOrderers := []fab.Orderer{}
-	randOrderers = append(randOrderers, orderers...)
+	breaker, _ := context.RequestCircuitBreaker(reqCtx)
+	selector, _ := context.RequestOrdererSelector(reqCtx)
 
-	// Iterate them in a random order and try broadcasting 1 by 1
+	// Iterate them in the selected order and try broadcasting 1 by 1
 	var errResp error
-	for _, i := range rand.Perm(len(randOrderers)) {
-		resp, err := sendEnvelope(reqCtx, envelope, randOrderers[i])
+	for _, orderer := range selectOrderers(selector, orderers) {
+		if !allowOrderer(breaker, orderer) {
+			continue
+		}
+		resp, err := sendEnvelope(reqCtx, envelope, orderer)
+		recordOrderer(breaker, orderer, err)
 		if err != nil {
 			errResp = err
 		} else {
 			return resp, nil
 		}
 	}
+	if errResp == nil {
+		return nil, errOrderersUnavailable
+	}
 	return nil, errResp
 }
 
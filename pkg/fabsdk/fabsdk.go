@@ -226,6 +226,19 @@ type providerInit interface {
 	Initialize(providers contextApi.Providers) error
 }
 
+// cryptoConfigMSPProviderFactory is implemented by MSP provider factories that need the crypto suite config
+// to create the identity manager provider (eg. to decrypt private keys with the keystore passphrase)
+type cryptoConfigMSPProviderFactory interface {
+	CreateIdentityManagerProviderWithCryptoConfig(endpointConfig fab.EndpointConfig, cryptoSuiteConfig core.CryptoSuiteConfig, cryptoProvider core.CryptoSuite, userStore msp.UserStore) (msp.IdentityManagerProvider, error)
+}
+
+func createIdentityManagerProvider(factory sdkApi.MSPProviderFactory, cfg *configs, cryptoSuite core.CryptoSuite, userStore msp.UserStore) (msp.IdentityManagerProvider, error) {
+	if f, ok := factory.(cryptoConfigMSPProviderFactory); ok {
+		return f.CreateIdentityManagerProviderWithCryptoConfig(cfg.endpointConfig, cfg.cryptoSuiteConfig, cryptoSuite, userStore)
+	}
+	return factory.CreateIdentityManagerProvider(cfg.endpointConfig, cryptoSuite, userStore)
+}
+
 func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option) error { //nolint
 	for _, option := range opts {
 		err := option(&sdk.opts)
@@ -262,7 +275,7 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 	}
 
 	// Initialize IdentityManagerProvider
-	identityManagerProvider, err := sdk.opts.MSP.CreateIdentityManagerProvider(cfg.endpointConfig, sdk.cryptoSuite, userStore)
+	identityManagerProvider, err := createIdentityManagerProvider(sdk.opts.MSP, cfg, sdk.cryptoSuite, userStore)
 	if err != nil {
 		return errors.WithMessage(err, "failed to create identity manager provider")
 	}
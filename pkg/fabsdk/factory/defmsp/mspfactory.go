@@ -51,3 +51,13 @@ func (f *ProviderFactory) CreateUserStore(config msp.IdentityConfig) (msp.UserSt
 func (f *ProviderFactory) CreateIdentityManagerProvider(endpointConfig fab.EndpointConfig, cryptoProvider core.CryptoSuite, userStore msp.UserStore) (msp.IdentityManagerProvider, error) {
 	return msppvdr.New(endpointConfig, cryptoProvider, userStore)
 }
+
+// CreateIdentityManagerProviderWithCryptoConfig returns a new default implementation of MSP provider,
+// the private keys read from the organizations' crypto paths are decrypted with the configured keystore passphrase
+func (f *ProviderFactory) CreateIdentityManagerProviderWithCryptoConfig(endpointConfig fab.EndpointConfig, cryptoSuiteConfig core.CryptoSuiteConfig, cryptoProvider core.CryptoSuite, userStore msp.UserStore) (msp.IdentityManagerProvider, error) {
+	passphrase, err := cryptoSuiteConfig.KeyStorePassphrase()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get keystore passphrase")
+	}
+	return msppvdr.New(endpointConfig, cryptoProvider, userStore, mspimpl.WithKeyStorePassphrase(passphrase))
+}
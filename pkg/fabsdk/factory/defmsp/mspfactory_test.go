@@ -139,6 +139,16 @@ func TestCreateIdentityManager(t *testing.T) {
 	if !ok {
 		t.Fatal("Unexpected signing manager created")
 	}
+
+	provider, err = factory.CreateIdentityManagerProviderWithCryptoConfig(endpointCfg, cryptoCfg, cryptosuite, userStore)
+	if err != nil {
+		t.Fatalf("Unexpected error creating provider with crypto config %s", err)
+	}
+
+	_, ok = provider.IdentityManager("Org1")
+	if !ok {
+		t.Fatal("Unexpected error creating identity manager with crypto config")
+	}
 }
 
 func TestCreateUserStoreWithoutCredentialStorePath(t *testing.T) {
@@ -66,6 +66,55 @@ var (
 		LabelNames:   []string{"chaincode", "Fcn"},
 		StatsdFormat: "%{#fqname}.%{type}.%{channel}.%{execution}",
 	}
+	renewalsSucceeded = metrics.CounterOpts{
+		Namespace:    "msp",
+		Name:         "renewals_succeeded",
+		Help:         "The number of enrollment certificates renewed before expiry.",
+		LabelNames:   []string{"mspid", "id"},
+		StatsdFormat: "%{#fqname}.%{mspid}.%{id}",
+	}
+	renewalsFailed = metrics.CounterOpts{
+		Namespace:    "msp",
+		Name:         "renewals_failed",
+		Help:         "The number of enrollment certificate renewals that failed.",
+		LabelNames:   []string{"mspid", "id"},
+		StatsdFormat: "%{#fqname}.%{mspid}.%{id}",
+	}
+	certExpiry = metrics.GaugeOpts{
+		Namespace:    "msp",
+		Name:         "cert_expiry_seconds",
+		Help:         "The time in seconds until the enrollment certificate of an identity expires.",
+		LabelNames:   []string{"mspid", "id"},
+		StatsdFormat: "%{#fqname}.%{mspid}.%{id}",
+	}
+	breakerStateChanges = metrics.CounterOpts{
+		Namespace:    "circuit_breaker",
+		Name:         "state_changes",
+		Help:         "The number of times the circuit of an endpoint changed to the given state.",
+		LabelNames:   []string{"endpoint", "state"},
+		StatsdFormat: "%{#fqname}.%{endpoint}.%{state}",
+	}
+	breakerState = metrics.GaugeOpts{
+		Namespace:    "circuit_breaker",
+		Name:         "state",
+		Help:         "The circuit state of an endpoint (0 closed, 1 open, 2 half-open).",
+		LabelNames:   []string{"endpoint"},
+		StatsdFormat: "%{#fqname}.%{endpoint}",
+	}
+	breakerFailures = metrics.CounterOpts{
+		Namespace:    "circuit_breaker",
+		Name:         "failures",
+		Help:         "The number of failed requests to an endpoint recorded by the circuit breaker.",
+		LabelNames:   []string{"endpoint"},
+		StatsdFormat: "%{#fqname}.%{endpoint}",
+	}
+	breakerRejections = metrics.CounterOpts{
+		Namespace:    "circuit_breaker",
+		Name:         "rejections",
+		Help:         "The number of requests to an endpoint rejected because its circuit is open.",
+		LabelNames:   []string{"endpoint"},
+		StatsdFormat: "%{#fqname}.%{endpoint}",
+	}
 )
 
 // ClientMetrics contains the metrics used in the (channel) client
@@ -93,3 +142,37 @@ func NewClientMetrics(p metrics.Provider) *ClientMetrics {
 		ExecutionTimeouts:  p.NewCounter(executionTimeouts),
 	}
 }
+
+// RenewalMetrics contains the metrics used by the enrollment certificate renewal manager
+type RenewalMetrics struct {
+	RenewalsSucceeded metrics.Counter
+	RenewalsFailed    metrics.Counter
+	CertExpiry        metrics.Gauge
+}
+
+// NewRenewalMetrics builds a new instance of RenewalMetrics
+func NewRenewalMetrics(p metrics.Provider) *RenewalMetrics {
+	return &RenewalMetrics{
+		RenewalsSucceeded: p.NewCounter(renewalsSucceeded),
+		RenewalsFailed:    p.NewCounter(renewalsFailed),
+		CertExpiry:        p.NewGauge(certExpiry),
+	}
+}
+
+// CircuitBreakerMetrics contains the metrics used by the peer and orderer circuit breaker
+type CircuitBreakerMetrics struct {
+	StateChanges metrics.Counter
+	State        metrics.Gauge
+	Failures     metrics.Counter
+	Rejections   metrics.Counter
+}
+
+// NewCircuitBreakerMetrics builds a new instance of CircuitBreakerMetrics
+func NewCircuitBreakerMetrics(p metrics.Provider) *CircuitBreakerMetrics {
+	return &CircuitBreakerMetrics{
+		StateChanges: p.NewCounter(breakerStateChanges),
+		State:        p.NewGauge(breakerState),
+		Failures:     p.NewCounter(breakerFailures),
+		Rejections:   p.NewCounter(breakerRejections),
+	}
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package chpvdr
 
 import (
+	"fmt"
 	"strconv"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/options"
@@ -67,6 +68,7 @@ type eventCacheKey struct {
 	key           string
 	channelConfig fab.ChannelCfg
 	opts          []options.Opt
+	bounded       bool
 }
 
 // newEventCacheKey returns a new eventCacheKey
@@ -84,6 +86,7 @@ func newEventCacheKey(chConfig fab.ChannelCfg, opts ...options.Opt) (*eventCache
 		channelConfig: chConfig,
 		key:           string(hash),
 		opts:          opts,
+		bounded:       params.toBlockSet,
 	}, nil
 }
 
@@ -98,7 +101,10 @@ func (k *eventCacheKey) String() string {
 }
 
 type params struct {
-	permitBlockEvents bool
+	permitBlockEvents         bool
+	checkpointer              fab.EventCheckpointer
+	toBlockSet                bool
+	ordererIdentityValidation bool
 }
 
 func defaultParams() *params {
@@ -109,8 +115,28 @@ func (p *params) PermitBlockEvents() {
 	p.permitBlockEvents = true
 }
 
+func (p *params) SetToBlock(value uint64) {
+	p.toBlockSet = true
+}
+
+func (p *params) SetCheckpointer(value fab.EventCheckpointer) {
+	p.checkpointer = value
+}
+
+func (p *params) SetOrdererIdentityValidation(value bool) {
+	p.ordererIdentityValidation = value
+}
+
 func (p *params) getOptKey() string {
 	//	Construct opts portion
 	optKey := "blockEvents:" + strconv.FormatBool(p.permitBlockEvents)
+	// Event clients with different checkpointers resume from different blocks so they may not be shared
+	if p.checkpointer != nil {
+		optKey += fmt.Sprintf(",checkpointer:%p", p.checkpointer)
+	}
+	// Event clients that validate orderer identities drop blocks that other clients would deliver
+	if p.ordererIdentityValidation {
+		optKey += ",ordererIdentityValidation:true"
+	}
 	return optKey
 }
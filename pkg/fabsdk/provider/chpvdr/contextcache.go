@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package chpvdr
 
 import (
+	"time"
+
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/discovery/dynamicdiscovery"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/discovery/staticdiscovery"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/common/selection/dynamicselection"
@@ -32,6 +34,7 @@ type contextCache struct {
 	selectionServiceCache cache
 	chCfgCache            cache
 	membershipCache       cache
+	eventIdleTime         time.Duration
 }
 
 var cfgCacheProvider = func(opts ...options.Opt) cache {
@@ -44,7 +47,8 @@ func newContextCache(ctx fab.ClientContext, opts []options.Opt) *contextCache {
 	membershipRefresh := ctx.EndpointConfig().Timeout(fab.ChannelMembershipRefresh)
 
 	c := &contextCache{
-		ctx: ctx,
+		ctx:           ctx,
+		eventIdleTime: eventIdleTime,
 	}
 
 	c.chCfgCache = cfgCacheProvider(append(opts, chconfig.WithRefreshInterval(chConfigRefresh))...)
@@ -170,6 +174,15 @@ func (c *contextCache) GetEventService(channelID string, opts ...options.Opt) (f
 	if err != nil {
 		return nil, err
 	}
+	if key.bounded {
+		// A bounded event service is closed once the requested blocks have been delivered, so it is never shared
+		return NewEventClientRef(
+			c.eventIdleTime,
+			func() (fab.EventClient, error) {
+				return c.createEventClient(chnlCfg, opts...)
+			},
+		), nil
+	}
 	eventService, err := c.eventServiceCache.Get(key)
 	if err != nil {
 		return nil, err
@@ -24,12 +24,12 @@ type MSPProvider struct {
 }
 
 // New creates a MSP context provider
-func New(endpointConfig fab.EndpointConfig, cryptoSuite core.CryptoSuite, userStore msp.UserStore) (*MSPProvider, error) {
+func New(endpointConfig fab.EndpointConfig, cryptoSuite core.CryptoSuite, userStore msp.UserStore, opts ...mspimpl.IdentityManagerOption) (*MSPProvider, error) {
 
 	identityManager := make(map[string]msp.IdentityManager)
 	netConfig := endpointConfig.NetworkConfig()
 	for orgName := range netConfig.Organizations {
-		mgr, err := mspimpl.NewIdentityManager(orgName, userStore, cryptoSuite, endpointConfig)
+		mgr, err := mspimpl.NewIdentityManager(orgName, userStore, cryptoSuite, endpointConfig, opts...)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to initialize identity manager for organization: %s", orgName)
 		}
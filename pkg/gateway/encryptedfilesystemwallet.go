@@ -0,0 +1,244 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+/*
+pkg/gateway/encryptedfilesystemwallet.go 加密的本地文件系统钱包
+*/
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gitee.com/zhaochuninhefei/gmgo/sm3"
+	"gitee.com/zhaochuninhefei/gmgo/sm4"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// 钱包元数据文件，保存密钥派生参数以及口令校验值
+	walletMetaFile = ".wallet"
+	// 元数据与加密条目的格式版本
+	encryptedWalletVersion = 1
+	// 口令派生SM4密钥使用的PBKDF2(SM3)迭代次数
+	walletKDFIterations = 100000
+	walletKDF           = "PBKDF2-SM3"
+	walletCipher        = "SM4-GCM"
+	walletSaltLength    = 16
+	// 用于校验口令是否正确的明文
+	walletCheckValue = "fabric-sdk-go-gm wallet"
+)
+
+// walletMeta 钱包元数据
+type walletMeta struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Cipher     string `json:"cipher"`
+	Check      []byte `json:"check"`
+}
+
+// encryptedEntry 加密后的钱包条目
+type encryptedEntry struct {
+	Version int    `json:"version"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// encryptedFileSystemWalletStore stores identity information encrypted with SM4 in the filesystem.
+// Instances are created using NewEncryptedFileSystemWallet()
+//  实现`pkg/gateway/spi.go`的`WalletStore`接口。
+//  每个身份使用SM4-GCM加密后保存在`<label>.id`文件中，label作为附加认证数据，
+//  因此条目文件无法在不同label之间互换。
+type encryptedFileSystemWalletStore struct {
+	files *fileSystemWalletStore
+	aead  cipher.AEAD
+}
+
+// NewEncryptedFileSystemWallet creates an instance of a wallet, held in the filesystem,
+// whose identities are encrypted with an SM4 key derived from the password.
+// The key derivation parameters are stored with the wallet, so the same password
+// must be used every time the wallet is opened.
+//  Parameters:
+//  path specifies where on the filesystem to store the wallet.
+//  password is used to derive the encryption key.
+//
+//  Returns:
+//  A Wallet object.
+func NewEncryptedFileSystemWallet(path string, password []byte) (*Wallet, error) {
+	if len(password) == 0 {
+		return nil, errors.New("password is required")
+	}
+
+	cleanPath := filepath.Clean(path)
+	if err := os.MkdirAll(cleanPath, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	aead, err := openWalletCipher(filepath.Join(cleanPath, walletMetaFile), password)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &encryptedFileSystemWalletStore{
+		files: &fileSystemWalletStore{cleanPath},
+		aead:  aead,
+	}
+	return &Wallet{store}, nil
+}
+
+// openWalletCipher 读取钱包元数据并使用口令派生密钥，钱包第一次打开时创建元数据
+func openWalletCipher(metaPath string, password []byte) (cipher.AEAD, error) {
+	raw, err := ioutil.ReadFile(filepath.Clean(metaPath))
+	if os.IsNotExist(err) {
+		return createWalletMeta(metaPath, password)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read wallet metadata")
+	}
+
+	meta := &walletMeta{}
+	if err := json.Unmarshal(raw, meta); err != nil {
+		return nil, errors.Wrap(err, "invalid wallet metadata")
+	}
+	if meta.Version != encryptedWalletVersion || meta.KDF != walletKDF || meta.Cipher != walletCipher {
+		return nil, errors.Errorf("unsupported wallet format: version %d, %s, %s", meta.Version, meta.KDF, meta.Cipher)
+	}
+
+	aead, err := newWalletCipher(password, meta.Salt, meta.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := openEntry(aead, meta.Check, []byte(walletCheckValue)); err != nil {
+		return nil, errors.New("incorrect wallet password")
+	}
+	return aead, nil
+}
+
+func createWalletMeta(metaPath string, password []byte) (cipher.AEAD, error) {
+	salt := make([]byte, walletSaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+
+	aead, err := newWalletCipher(password, salt, walletKDFIterations)
+	if err != nil {
+		return nil, err
+	}
+	check, err := sealEntry(aead, []byte(walletCheckValue), []byte(walletCheckValue))
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(&walletMeta{
+		Version:    encryptedWalletVersion,
+		KDF:        walletKDF,
+		Iterations: walletKDFIterations,
+		Salt:       salt,
+		Cipher:     walletCipher,
+		Check:      check,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal wallet metadata")
+	}
+	if err := ioutil.WriteFile(filepath.Clean(metaPath), raw, 0600); err != nil {
+		return nil, errors.Wrap(err, "failed to write wallet metadata")
+	}
+	return aead, nil
+}
+
+// newWalletCipher 使用PBKDF2(SM3)从口令派生SM4密钥
+func newWalletCipher(password, salt []byte, iterations int) (cipher.AEAD, error) {
+	if len(salt) == 0 || iterations <= 0 {
+		return nil, errors.New("invalid key derivation parameters")
+	}
+	key := pbkdf2.Key(password, salt, iterations, sm4.BlockSize, sm3.New)
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create SM4 cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create SM4-GCM cipher")
+	}
+	return aead, nil
+}
+
+func sealEntry(aead cipher.AEAD, content, label []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	raw, err := json.Marshal(&encryptedEntry{
+		Version: encryptedWalletVersion,
+		Nonce:   nonce,
+		Data:    aead.Seal(nil, nonce, content, label),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal wallet entry")
+	}
+	return raw, nil
+}
+
+func openEntry(aead cipher.AEAD, raw, label []byte) ([]byte, error) {
+	entry := &encryptedEntry{}
+	if err := json.Unmarshal(raw, entry); err != nil {
+		return nil, errors.Wrap(err, "invalid encrypted wallet entry")
+	}
+	if entry.Version != encryptedWalletVersion || len(entry.Nonce) != aead.NonceSize() {
+		return nil, errors.Errorf("unsupported encrypted wallet entry version %d", entry.Version)
+	}
+	content, err := aead.Open(nil, entry.Nonce, entry.Data, label)
+	if err != nil {
+		return nil, errors.New("failed to decrypt wallet entry")
+	}
+	return content, nil
+}
+
+// Put an identity into the wallet.
+func (efw *encryptedFileSystemWalletStore) Put(label string, content []byte) error {
+	raw, err := sealEntry(efw.aead, content, []byte(label))
+	if err != nil {
+		return err
+	}
+	return efw.files.Put(label, raw)
+}
+
+// Get an identity from the wallet.
+func (efw *encryptedFileSystemWalletStore) Get(label string) ([]byte, error) {
+	raw, err := efw.files.Get(label)
+	if err != nil {
+		return nil, err
+	}
+	content, err := openEntry(efw.aead, raw, []byte(label))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read identity [%s]", label)
+	}
+	return content, nil
+}
+
+// Remove an identity from the wallet. If the identity does not exist, this method does nothing.
+func (efw *encryptedFileSystemWalletStore) Remove(label string) error {
+	return efw.files.Remove(label)
+}
+
+// Exists tests the existence of an identity in the wallet.
+func (efw *encryptedFileSystemWalletStore) Exists(label string) bool {
+	return efw.files.Exists(label)
+}
+
+// List all of the labels in the wallet.
+func (efw *encryptedFileSystemWalletStore) List() ([]string, error) {
+	return efw.files.List()
+}
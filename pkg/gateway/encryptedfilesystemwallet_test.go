@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testWalletPassword = []byte("wallet-password")
+
+func createEncryptedFileSystemWallet() (*Wallet, error) {
+	dir := filepath.Join("testdata", "wallet", "encrypted")
+	os.RemoveAll(dir)
+	return NewEncryptedFileSystemWallet(dir, testWalletPassword)
+}
+
+func TestEncryptedFileSystemWalletSuite(t *testing.T) {
+	testWalletSuite(t, createEncryptedFileSystemWallet)
+	os.RemoveAll(filepath.Join("testdata", "wallet", "encrypted"))
+}
+
+func TestEncryptedFileSystemWalletPassword(t *testing.T) {
+	dir := filepath.Join("testdata", "wallet", "encrypted")
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	wallet, err := NewEncryptedFileSystemWallet(dir, testWalletPassword)
+	if err != nil {
+		t.Fatalf("Failed to create encrypted wallet: %s", err)
+	}
+	if err := wallet.Put("user1", NewX509Identity("mspId", "testCert", "testPrivKey")); err != nil {
+		t.Fatalf("Failed to put identity: %s", err)
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "user1.id"))
+	if err != nil {
+		t.Fatalf("Failed to read wallet file: %s", err)
+	}
+	if bytes.Contains(raw, []byte("testPrivKey")) {
+		t.Fatal("Private key must not be stored in plaintext")
+	}
+
+	reopened, err := NewEncryptedFileSystemWallet(dir, testWalletPassword)
+	if err != nil {
+		t.Fatalf("Failed to reopen encrypted wallet: %s", err)
+	}
+	id, err := reopened.Get("user1")
+	if err != nil {
+		t.Fatalf("Failed to get identity: %s", err)
+	}
+	if id.(*X509Identity).Key() != "testPrivKey" {
+		t.Fatalf("Unexpected private key: %s", id.(*X509Identity).Key())
+	}
+
+	if _, err := NewEncryptedFileSystemWallet(dir, []byte("wrong-password")); err == nil {
+		t.Fatal("Expected error for wrong password")
+	}
+	if _, err := NewEncryptedFileSystemWallet(dir, nil); err == nil {
+		t.Fatal("Expected error for empty password")
+	}
+
+	// An entry cannot be moved to another label
+	if err := ioutil.WriteFile(filepath.Join(dir, "user2.id"), raw, 0600); err != nil {
+		t.Fatalf("Failed to write wallet file: %s", err)
+	}
+	if _, err := reopened.Get("user2"); err == nil {
+		t.Fatal("Expected error for entry copied from another label")
+	}
+}
@@ -47,21 +47,31 @@ func NewFileSystemWallet(path string) (*Wallet, error) {
 }
 
 // Put an identity into the wallet.
+// The identity is written to a temporary file that is then renamed over the identity file,
+// so that a shorter identity never leaves the tail of the previous one behind and a crash
+// never leaves a partially written identity.
 func (fsw *fileSystemWalletStore) Put(label string, content []byte) error {
-	pathname := filepath.Join(fsw.path, label) + dataFileExtension
-
-	f, err := os.OpenFile(filepath.Clean(pathname), os.O_RDWR|os.O_CREATE, 0600)
+	pathname := filepath.Clean(filepath.Join(fsw.path, label) + dataFileExtension)
 
+	f, err := ioutil.TempFile(filepath.Dir(pathname), filepath.Base(pathname)+".tmp")
 	if err != nil {
 		return err
 	}
+	tmpPath := f.Name()
 
 	if _, err := f.Write(content); err != nil {
 		_ = f.Close() // ignore error; Write error takes precedence
+		_ = os.Remove(tmpPath)
 		return err
 	}
 
 	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, pathname); err != nil {
+		_ = os.Remove(tmpPath)
 		return err
 	}
 
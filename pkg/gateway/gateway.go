@@ -65,6 +65,8 @@ type gatewayOptions struct {
 	// FromBlock specify the initial block to be considerer by event client
 	FromBlock    uint64
 	FromBlockSet bool
+	// CheckpointStore stores the event checkpoint of each network under the channel name
+	CheckpointStore core.KVStore
 }
 
 // Option functional arguments can be supplied when connecting to the gateway.
@@ -234,6 +236,29 @@ func WithIdentity(wallet wallet, label string) IdentityOption {
 	}
 }
 
+// WithSigningIdentity is an optional argument to the Connect method which specifies
+// the signing identity that is to be used to connect to the network.
+// This allows an identity that changes over time, such as one kept up to date by
+// the msp client's RenewalManager, or one whose private key is held by a signing
+// service (see package msp/remotesigner), to be used by the gateway.
+//
+//   Parameters:
+//   identity is the signing identity used for all operations under this gateway connection
+//
+//   Returns:
+//   An IdentityOption which can be passed as the second parameter to the Connect() function
+func WithSigningIdentity(identity mspProvider.SigningIdentity) IdentityOption {
+	return func(gw *Gateway) error {
+		if identity == nil {
+			return errors.New("signing identity is nil")
+		}
+		gw.options.Identity = identity
+		gw.mspfactory = &walletmsp{}
+
+		return nil
+	}
+}
+
 // WithUser is an optional argument to the Connect method which specifies
 // the identity that is to be used to connect to the network.
 // The creadentials are extracted from the credential store specified in the connection profile.
@@ -269,6 +294,17 @@ func WithBlockNum(from uint64) Option {
 	}
 }
 
+// WithCheckpointStore optionally sets the store in which the event checkpoint of each network is persisted.
+// The checkpoint of a network is stored under its channel name. When a checkpoint exists,
+// events are received from the checkpoint and the block number set by WithBlockNum is ignored.
+// Use Network.Checkpointer to record the progress of event processing.
+func WithCheckpointStore(store core.KVStore) Option {
+	return func(gw *Gateway) error {
+		gw.options.CheckpointStore = store
+		return nil
+	}
+}
+
 // GetNetwork returns an object representing a network channel.
 //  Parameters:
 //  name is the name of the network channel
@@ -137,6 +137,32 @@ func TestConnectWithIdentity(t *testing.T) {
 	}
 }
 
+func TestConnectWithSigningIdentity(t *testing.T) {
+	id := &walletIdentity{id: "user", mspID: "testMSP", enrollmentCertificate: []byte(testCert)}
+
+	gw, err := Connect(
+		WithConfig(config.FromFile("testdata/connection-tls.json")),
+		WithSigningIdentity(id),
+	)
+
+	if err != nil {
+		t.Fatalf("Failed to create gateway: %s", err)
+	}
+
+	if gw.options.Identity != id {
+		t.Fatal("Identity not set")
+	}
+
+	_, err = Connect(
+		WithConfig(config.FromFile("testdata/connection-tls.json")),
+		WithSigningIdentity(nil),
+	)
+
+	if err == nil {
+		t.Fatal("Expected to fail to create gateway with nil identity")
+	}
+}
+
 func TestConnectWithBadConfig(t *testing.T) {
 	wallet := NewInMemoryWallet()
 	wallet.Put("user", NewX509Identity("testMSP", testCert, testPrivKey))
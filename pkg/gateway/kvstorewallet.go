@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+/*
+pkg/gateway/kvstorewallet.go 基于core.KVStore的钱包
+*/
+
+import (
+	"sort"
+	"strings"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+const defaultKVStoreWalletPrefix = "wallet"
+
+// listableKVStore 可以按前缀列举key的KVStore
+type listableKVStore interface {
+	core.KVStore
+	core.KVStoreKeyLister
+}
+
+// kvStoreWalletStore stores identity information in a core.KVStore.
+// Instances are created using NewKVStoreWallet()
+//  实现`pkg/gateway/spi.go`的`WalletStore`接口。
+//  每个身份保存在独立的key中，label列表通过按前缀列举key得到，
+//  因此共享同一个KVStore的多个钱包实例或进程不会互相覆盖。
+type kvStoreWalletStore struct {
+	store  listableKVStore
+	prefix string
+}
+
+// NewKVStoreWallet creates an instance of a wallet, held in the given key value store.
+// Identities are stored under the key "<prefix>/<label>".
+//  Parameters:
+//  store is the key value store, e.g. one created with pkg/fab/keyvaluestore.
+//  It must implement core.KVStoreKeyLister so that the labels can be listed.
+//  prefix is prepended to all keys; if empty, "wallet" is used.
+//
+//  Returns:
+//  A Wallet object.
+func NewKVStoreWallet(store core.KVStore, prefix string) (*Wallet, error) {
+	if store == nil {
+		return nil, errors.New("key value store is required")
+	}
+	listable, ok := store.(listableKVStore)
+	if !ok {
+		return nil, errors.New("key value store must implement core.KVStoreKeyLister")
+	}
+	if prefix == "" {
+		prefix = defaultKVStoreWalletPrefix
+	}
+	return &Wallet{&kvStoreWalletStore{store: listable, prefix: prefix}}, nil
+}
+
+func (s *kvStoreWalletStore) keyPrefix() string {
+	return s.prefix + "/"
+}
+
+func (s *kvStoreWalletStore) key(label string) string {
+	return s.keyPrefix() + label
+}
+
+// Put an identity into the wallet.
+func (s *kvStoreWalletStore) Put(label string, content []byte) error {
+	return errors.WithMessagef(s.store.Store(s.key(label), content), "failed to store identity [%s]", label)
+}
+
+// Get an identity from the wallet.
+func (s *kvStoreWalletStore) Get(label string) ([]byte, error) {
+	value, err := s.store.Load(s.key(label))
+	if err == core.ErrKeyValueNotFound {
+		return nil, errors.New("label doesn't exist: " + label)
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to load identity [%s]", label)
+	}
+	return toBytes(value)
+}
+
+// Remove an identity from the wallet. If the identity does not exist, this method does nothing.
+func (s *kvStoreWalletStore) Remove(label string) error {
+	if err := s.store.Delete(s.key(label)); err != nil && err != core.ErrKeyValueNotFound {
+		return errors.WithMessagef(err, "failed to delete identity [%s]", label)
+	}
+	return nil
+}
+
+// Exists tests the existence of an identity in the wallet.
+func (s *kvStoreWalletStore) Exists(label string) bool {
+	_, err := s.store.Load(s.key(label))
+	return err == nil
+}
+
+// List all of the labels in the wallet.
+func (s *kvStoreWalletStore) List() ([]string, error) {
+	keys, err := s.store.Keys(s.keyPrefix())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list wallet labels")
+	}
+	labels := make([]string, 0, len(keys))
+	for _, key := range keys {
+		labels = append(labels, strings.TrimPrefix(key, s.keyPrefix()))
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// toBytes 转换KVStore中保存的值，自定义Unmarshaller可能以字符串形式返回
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, errors.Errorf("unexpected value type in key value store: %T", value)
+	}
+}
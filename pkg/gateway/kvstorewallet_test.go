@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/keyvaluestore"
+)
+
+func TestKVStoreWalletSuite(t *testing.T) {
+	testWalletSuite(t, func() (*Wallet, error) {
+		return NewKVStoreWallet(keyvaluestore.NewMemKeyValueStore(), "")
+	})
+}
+
+func TestFileKVStoreWalletSuite(t *testing.T) {
+	dir := filepath.Join("testdata", "wallet", "kvstore")
+	defer os.RemoveAll(dir)
+
+	testWalletSuite(t, func() (*Wallet, error) {
+		os.RemoveAll(dir)
+		store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{Path: dir})
+		if err != nil {
+			return nil, err
+		}
+		return NewKVStoreWallet(store, "org1")
+	})
+}
+
+func TestKVStoreWalletSharedStore(t *testing.T) {
+	store := keyvaluestore.NewMemKeyValueStore()
+	wallet1, _ := NewKVStoreWallet(store, "wallet1")
+	wallet2, _ := NewKVStoreWallet(store, "wallet2")
+
+	if err := wallet1.Put("user1", NewX509Identity("mspId", "testCert", "testPrivKey")); err != nil {
+		t.Fatalf("Failed to put identity: %s", err)
+	}
+	if wallet2.Exists("user1") {
+		t.Fatal("Wallets with different prefixes must not share identities")
+	}
+
+	reopened, _ := NewKVStoreWallet(store, "wallet1")
+	labels, err := reopened.List()
+	if err != nil {
+		t.Fatalf("Failed to list identities: %s", err)
+	}
+	if len(labels) != 1 || labels[0] != "user1" {
+		t.Fatalf("Unexpected wallet contents: %s", labels)
+	}
+
+	if _, err := NewKVStoreWallet(nil, ""); err == nil {
+		t.Fatal("Expected error for nil store")
+	}
+}
+
+func TestKVStoreWalletConcurrentInstances(t *testing.T) {
+	dir := filepath.Join("testdata", "wallet", "kvstore-shared")
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	// Each goroutine uses its own store and wallet instance, as separate processes would
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{Path: dir})
+			if err != nil {
+				errs <- err
+				return
+			}
+			wallet, err := NewKVStoreWallet(store, "org1")
+			if err != nil {
+				errs <- err
+				return
+			}
+			errs <- wallet.Put(fmt.Sprintf("user%d", i), NewX509Identity("mspId", "testCert", "testPrivKey"))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Failed to put identity: %s", err)
+		}
+	}
+
+	store, _ := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{Path: dir})
+	wallet, _ := NewKVStoreWallet(store, "org1")
+	labels, err := wallet.List()
+	if err != nil {
+		t.Fatalf("Failed to list identities: %s", err)
+	}
+	if len(labels) != 10 {
+		t.Fatalf("Expected 10 identities, got %s", labels)
+	}
+}
+
+// kvStoreWithoutKeys hides the Keys method of the wrapped store
+type kvStoreWithoutKeys struct {
+	core.KVStore
+}
+
+func TestKVStoreWalletRequiresKeyLister(t *testing.T) {
+	if _, err := NewKVStoreWallet(&kvStoreWithoutKeys{keyvaluestore.NewMemKeyValueStore()}, ""); err == nil {
+		t.Fatal("Expected error for a store that cannot list its keys")
+	}
+}
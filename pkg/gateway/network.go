@@ -12,6 +12,7 @@ import (
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/ledger"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/checkpoint"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/deliverclient/seek"
 	"github.com/pkg/errors"
 )
@@ -21,10 +22,11 @@ import (
 // A Network object represents the set of peers in a Fabric network (channel).
 // Applications should get a Network instance from a Gateway using the GetNetwork method.
 type Network struct {
-	name    string
-	gateway *Gateway
-	client  *channel.Client
-	event   *event.Client
+	name         string
+	gateway      *Gateway
+	client       *channel.Client
+	event        *event.Client
+	checkpointer *checkpoint.Checkpointer
 }
 
 // 根据 gateway 与 channelProvider 创建网络通道实例
@@ -51,6 +53,13 @@ func newNetwork(gateway *Gateway, channelProvider context.ChannelProvider) (*Net
 	if gateway.options.FromBlockSet {
 		eventOpts = append(eventOpts, event.WithSeekType(seek.FromBlock), event.WithBlockNum(gateway.options.FromBlock))
 	}
+	if gateway.options.CheckpointStore != nil {
+		n.checkpointer, err = checkpoint.New(gateway.options.CheckpointStore, n.name)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create checkpointer")
+		}
+		eventOpts = append(eventOpts, event.WithCheckpointer(n.checkpointer))
+	}
 
 	n.event, err = event.New(channelProvider, eventOpts...)
 	if err != nil {
@@ -101,6 +110,14 @@ func (n *Network) GetContractWithName(chaincodeID string, name string) *Contract
 	return newContract(n, chaincodeID, name)
 }
 
+// Checkpointer returns the checkpointer that records the progress of event processing on this network,
+// or nil if the gateway was not connected with a checkpoint store.
+// Events that were processed according to the checkpoint are not delivered again, so the application
+// should update the checkpoint, e.g. with CheckpointChaincodeEvent, once it has processed an event.
+func (n *Network) Checkpointer() *checkpoint.Checkpointer {
+	return n.checkpointer
+}
+
 // RegisterBlockEvent registers for block events. Unregister must be called when the registration is no longer needed.
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/keyvaluestore"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/mocks"
 )
 
@@ -50,6 +51,35 @@ func TestNewNetworkWithEventOptions(t *testing.T) {
 	}
 }
 
+func TestNewNetworkWithCheckpointStore(t *testing.T) {
+	c := mockChannelProvider("mychannel")
+
+	store := keyvaluestore.NewMemKeyValueStore()
+	gw := &Gateway{
+		options: &gatewayOptions{
+			CheckpointStore: store,
+		},
+	}
+
+	nw, err := newNetwork(gw, c)
+
+	if err != nil {
+		t.Fatalf("Failed to create network: %s", err)
+	}
+
+	if nw.Checkpointer() == nil {
+		t.Fatal("Checkpointer not initialized")
+	}
+
+	if err := nw.Checkpointer().CheckpointBlock(5); err != nil {
+		t.Fatalf("Failed to update checkpoint: %s", err)
+	}
+
+	if _, err := store.Load("mychannel"); err != nil {
+		t.Fatalf("Checkpoint not stored under the channel name: %s", err)
+	}
+}
+
 func TestGetContract(t *testing.T) {
 	c := mockChannelProvider("mychannel")
 
@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+/*
+pkg/gateway/sqlwallet.go 基于database/sql的数据库钱包
+*/
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+const defaultSQLWalletTable = "wallet"
+
+var sqlIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLWalletOption describes a functional parameter for NewSQLWallet
+type SQLWalletOption func(*sqlWalletStore)
+
+// WithSQLTable sets the name of the table that holds the identities (default "wallet")
+func WithSQLTable(table string) SQLWalletOption {
+	return func(s *sqlWalletStore) {
+		s.table = table
+	}
+}
+
+// WithNumberedPlaceholders uses $1, $2... as query placeholders instead of ?, as required by PostgreSQL
+func WithNumberedPlaceholders() SQLWalletOption {
+	return func(s *sqlWalletStore) {
+		s.placeholder = func(n int) string { return fmt.Sprintf("$%d", n) }
+	}
+}
+
+// WithCreateTableStatement sets the statement that creates the table if it does not exist.
+// The default statement works with SQLite and MySQL. An empty statement skips table creation,
+// in which case the table must have a unique text column "label" and a binary column "content".
+func WithCreateTableStatement(stmt string) SQLWalletOption {
+	return func(s *sqlWalletStore) {
+		s.createTable = &stmt
+	}
+}
+
+// sqlWalletStore stores identity information in a SQL database.
+// Instances are created using NewSQLWallet()
+//  实现`pkg/gateway/spi.go`的`WalletStore`接口。
+//  多个应用实例可以通过同一个数据库共享钱包。
+type sqlWalletStore struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+	createTable *string
+}
+
+// NewSQLWallet creates an instance of a wallet, held in a SQL database.
+// The database driver is chosen by the caller when opening db.
+//  Parameters:
+//  db is the database handle.
+//  opts are options for the table name, placeholders and table creation.
+//
+//  Returns:
+//  A Wallet object.
+func NewSQLWallet(db *sql.DB, opts ...SQLWalletOption) (*Wallet, error) {
+	if db == nil {
+		return nil, errors.New("database is required")
+	}
+
+	store := &sqlWalletStore{
+		db:          db,
+		table:       defaultSQLWalletTable,
+		placeholder: func(int) string { return "?" },
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	if !sqlIdentifierPattern.MatchString(store.table) {
+		return nil, errors.Errorf("invalid table name: %s", store.table)
+	}
+
+	createTable := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (label VARCHAR(255) NOT NULL PRIMARY KEY, content BLOB NOT NULL)", store.table)
+	if store.createTable != nil {
+		createTable = *store.createTable
+	}
+	if createTable != "" {
+		if _, err := db.Exec(createTable); err != nil {
+			return nil, errors.Wrapf(err, "failed to create wallet table %s", store.table)
+		}
+	}
+
+	return &Wallet{store}, nil
+}
+
+// Put an identity into the wallet.
+//  先更新再插入，避免依赖特定数据库的upsert语法。
+//  并发的Put可能在更新与插入之间插入了相同的标签，导致插入违反唯一约束，此时改为更新该标签。
+func (s *sqlWalletStore) Put(label string, content []byte) error {
+	updated, err := s.update(label, content)
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		return nil
+	}
+
+	if _, err := s.db.Exec(fmt.Sprintf("INSERT INTO %s (label, content) VALUES (%s, %s)", s.table, s.placeholder(1), s.placeholder(2)), label, content); err != nil {
+		// MySQL reports no affected rows when the content is unchanged, so check for the label instead
+		if !s.Exists(label) {
+			return errors.Wrapf(err, "failed to insert identity [%s]", label)
+		}
+		_, err = s.update(label, content)
+		return err
+	}
+
+	return nil
+}
+
+// update updates the content of an existing identity and returns the number of affected rows
+func (s *sqlWalletStore) update(label string, content []byte) (int64, error) {
+	result, err := s.db.Exec(fmt.Sprintf("UPDATE %s SET content = %s WHERE label = %s", s.table, s.placeholder(1), s.placeholder(2)), content, label)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to update identity [%s]", label)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get affected rows")
+	}
+	return updated, nil
+}
+
+// Get an identity from the wallet.
+func (s *sqlWalletStore) Get(label string) ([]byte, error) {
+	var content []byte
+	err := s.db.QueryRow(fmt.Sprintf("SELECT content FROM %s WHERE label = %s", s.table, s.placeholder(1)), label).Scan(&content)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("label doesn't exist: " + label)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get identity [%s]", label)
+	}
+	return content, nil
+}
+
+// Remove an identity from the wallet. If the identity does not exist, this method does nothing.
+func (s *sqlWalletStore) Remove(label string) error {
+	if _, err := s.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE label = %s", s.table, s.placeholder(1)), label); err != nil {
+		return errors.Wrapf(err, "failed to remove identity [%s]", label)
+	}
+	return nil
+}
+
+// Exists tests the existence of an identity in the wallet.
+func (s *sqlWalletStore) Exists(label string) bool {
+	var count int
+	err := s.db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE label = %s", s.table, s.placeholder(1)), label).Scan(&count)
+	return err == nil && count > 0
+}
+
+// List all of the labels in the wallet.
+func (s *sqlWalletStore) List() ([]string, error) {
+	rows, err := s.db.Query(fmt.Sprintf("SELECT label FROM %s ORDER BY label", s.table))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list identities")
+	}
+	defer rows.Close()
+
+	var labels []string
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			return nil, errors.Wrap(err, "failed to read label")
+		}
+		labels = append(labels, label)
+	}
+	return labels, errors.Wrap(rows.Err(), "failed to list identities")
+}
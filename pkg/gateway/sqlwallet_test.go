@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openSQLiteDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %s", err)
+	}
+	// every connection to :memory: opens a new database
+	db.SetMaxOpenConns(1)
+	return db
+}
+
+func TestSQLWalletSuite(t *testing.T) {
+	testWalletSuite(t, func() (*Wallet, error) {
+		return NewSQLWallet(openSQLiteDB(t))
+	})
+}
+
+func TestSQLWalletShared(t *testing.T) {
+	db := openSQLiteDB(t)
+	defer db.Close()
+
+	wallet1, err := NewSQLWallet(db, WithSQLTable("identities"))
+	if err != nil {
+		t.Fatalf("Failed to create SQL wallet: %s", err)
+	}
+	wallet2, err := NewSQLWallet(db, WithSQLTable("identities"))
+	if err != nil {
+		t.Fatalf("Failed to create SQL wallet: %s", err)
+	}
+
+	if err := wallet1.Put("user1", NewX509Identity("mspId", "testCert1", "testPrivKey")); err != nil {
+		t.Fatalf("Failed to put identity: %s", err)
+	}
+	if err := wallet2.Put("user1", NewX509Identity("mspId", "testCert2", "testPrivKey")); err != nil {
+		t.Fatalf("Failed to replace identity: %s", err)
+	}
+
+	id, err := wallet1.Get("user1")
+	if err != nil {
+		t.Fatalf("Failed to get identity: %s", err)
+	}
+	if id.(*X509Identity).Certificate() != "testCert2" {
+		t.Fatalf("Unexpected certificate: %s", id.(*X509Identity).Certificate())
+	}
+
+	labels, err := wallet1.List()
+	if err != nil {
+		t.Fatalf("Failed to list identities: %s", err)
+	}
+	if len(labels) != 1 {
+		t.Fatalf("Unexpected wallet contents: %s", labels)
+	}
+}
+
+func TestNewSQLWalletErrors(t *testing.T) {
+	if _, err := NewSQLWallet(nil); err == nil {
+		t.Fatal("Expected error for nil database")
+	}
+
+	db := openSQLiteDB(t)
+	defer db.Close()
+
+	if _, err := NewSQLWallet(db, WithSQLTable("wallet; DROP TABLE x")); err == nil {
+		t.Fatal("Expected error for invalid table name")
+	}
+
+	wallet, err := NewSQLWallet(db, WithSQLTable("missing"), WithCreateTableStatement(""))
+	if err != nil {
+		t.Fatalf("Failed to create SQL wallet: %s", err)
+	}
+	if err := wallet.Put("user1", NewX509Identity("mspId", "testCert", "testPrivKey")); err == nil {
+		t.Fatal("Expected error when the table does not exist")
+	}
+}
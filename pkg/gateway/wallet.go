@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 
 	pb_msp "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	fabricCaUtil "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/sdkinternal/pkg/util"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
@@ -70,6 +71,11 @@ func (w *Wallet) Get(label string) (Identity, error) {
 		return nil, err
 	}
 
+	return identityFromJSON(content)
+}
+
+// identityFromJSON 根据身份JSON中的type属性反序列化身份对象
+func identityFromJSON(content []byte) (Identity, error) {
 	var data map[string]interface{}
 	if err := json.Unmarshal(content, &data); err != nil {
 		return nil, errors.Wrap(err, "Invalid identity format")
@@ -132,6 +138,23 @@ func (f *walletmsp) CreateIdentityManagerProvider(config fab.EndpointConfig, cry
 	return nil, nil
 }
 
+// NewWalletSigningIdentity returns a signing identity for the X.509 identity stored in a wallet under label.
+// The private key is imported into cryptoSuite.
+//
+// 根据钱包中的X.509身份创建签名身份，私钥导入到指定的密码套件
+func NewWalletSigningIdentity(label string, id *X509Identity, cryptoSuite core.CryptoSuite) (msp.SigningIdentity, error) {
+	privateKey, err := fabricCaUtil.ImportBCCSPKeyFromPEMBytes([]byte(id.Key()), cryptoSuite, true)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to import key of wallet identity %s", label)
+	}
+	return &walletIdentity{
+		id:                    label,
+		mspID:                 id.mspID(),
+		enrollmentCertificate: []byte(id.Certificate()),
+		privateKey:            privateKey,
+	}, nil
+}
+
 // walletIdentity is a representation of a Fabric User
 //
 // fabric用户的身份结构体
@@ -29,6 +29,7 @@ func testWalletSuite(t *testing.T, gen walletGenerator) {
 		{"testRemovalFromWallet", testRemovalFromWallet},
 		{"testRemoveNonExist", testRemoveNonExist},
 		{"testPutInvalidID", testPutInvalidID},
+		{"testOverwriteWithShorterID", testOverwriteWithShorterID},
 	}
 	for _, test := range tests {
 		t.Run(test.title, func(t *testing.T) {
@@ -117,6 +118,26 @@ func testPutInvalidID(t *testing.T, wallet *Wallet) {
 	}
 }
 
+func testOverwriteWithShorterID(t *testing.T, wallet *Wallet) {
+	if err := wallet.Put("label1", NewX509Identity("msp", "testCertWithAVeryLongContent", "testPrivKey")); err != nil {
+		t.Fatalf("Failed to put identity: %s", err)
+	}
+	if err := wallet.Put("label1", NewX509Identity("msp", "testCert", "testPrivKey")); err != nil {
+		t.Fatalf("Failed to overwrite identity: %s", err)
+	}
+	entry, err := wallet.Get("label1")
+	if err != nil {
+		t.Fatalf("Failed to lookup identity: %s", err)
+	}
+	if entry.(*X509Identity).Certificate() != "testCert" {
+		t.Fatalf("Unexpected certificate: %s", entry.(*X509Identity).Certificate())
+	}
+	contents, _ := wallet.List()
+	if !reflect.DeepEqual(contents, []string{"label1"}) {
+		t.Fatalf("Unexpected wallet contents: %s", contents)
+	}
+}
+
 func TestGetFromCorruptWallet(t *testing.T) {
 	wallet := &Wallet{&corruptWallet{}}
 	_, err := wallet.Get("user")
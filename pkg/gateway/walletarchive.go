@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+/*
+pkg/gateway/walletarchive.go 钱包整体导出为口令保护的归档文件，以及从归档文件导入
+*/
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// 归档内容的附加认证数据
+const walletArchiveAAD = "fabric-sdk-go-gm wallet archive"
+
+// walletArchive 归档文件格式，密钥派生与加密方式与加密文件钱包相同
+type walletArchive struct {
+	Version    int             `json:"version"`
+	KDF        string          `json:"kdf"`
+	Iterations int             `json:"iterations"`
+	Salt       []byte          `json:"salt"`
+	Cipher     string          `json:"cipher"`
+	Content    json.RawMessage `json:"content"`
+}
+
+// ExportArchive writes all identities of the wallet to w as a single archive,
+// encrypted with an SM4 key derived from the password.
+//  Parameters:
+//  wallet is the wallet to export.
+//  w receives the archive.
+//  password is used to derive the encryption key.
+//
+//  Returns:
+//  The labels of the exported identities.
+func ExportArchive(wallet *Wallet, w io.Writer, password []byte) ([]string, error) {
+	if len(password) == 0 {
+		return nil, errors.New("password is required")
+	}
+
+	labels, err := wallet.List()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list identities")
+	}
+	sort.Strings(labels)
+
+	identities := make(map[string]json.RawMessage, len(labels))
+	for _, label := range labels {
+		content, err := wallet.store.Get(label)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get identity [%s]", label)
+		}
+		identities[label] = content
+	}
+	plaintext, err := json.Marshal(identities)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal identities")
+	}
+
+	salt := make([]byte, walletSaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+	aead, err := newWalletCipher(password, salt, walletKDFIterations)
+	if err != nil {
+		return nil, err
+	}
+	content, err := sealEntry(aead, plaintext, []byte(walletArchiveAAD))
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(&walletArchive{
+		Version:    encryptedWalletVersion,
+		KDF:        walletKDF,
+		Iterations: walletKDFIterations,
+		Salt:       salt,
+		Cipher:     walletCipher,
+		Content:    content,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal wallet archive")
+	}
+	if _, err := w.Write(raw); err != nil {
+		return nil, errors.Wrap(err, "failed to write wallet archive")
+	}
+	return labels, nil
+}
+
+// ImportArchive puts all identities of an archive created by ExportArchive into the wallet.
+// Identities with the same label are replaced. Nothing is imported unless the whole archive
+// can be decrypted and every identity in it is valid.
+//  Parameters:
+//  wallet is the wallet to import the identities into.
+//  r provides the archive.
+//  password is the password the archive was exported with.
+//
+//  Returns:
+//  The labels of the imported identities.
+func ImportArchive(wallet *Wallet, r io.Reader, password []byte) ([]string, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read wallet archive")
+	}
+
+	archive := &walletArchive{}
+	if err := json.Unmarshal(raw, archive); err != nil {
+		return nil, errors.Wrap(err, "invalid wallet archive")
+	}
+	if archive.Version != encryptedWalletVersion || archive.KDF != walletKDF || archive.Cipher != walletCipher {
+		return nil, errors.Errorf("unsupported wallet archive format: version %d, %s, %s", archive.Version, archive.KDF, archive.Cipher)
+	}
+
+	aead, err := newWalletCipher(password, archive.Salt, archive.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := openEntry(aead, archive.Content, []byte(walletArchiveAAD))
+	if err != nil {
+		return nil, errors.New("incorrect archive password or corrupted archive")
+	}
+
+	identities := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(plaintext, &identities); err != nil {
+		return nil, errors.Wrap(err, "invalid wallet archive content")
+	}
+
+	labels := make([]string, 0, len(identities))
+	for label, content := range identities {
+		if _, err := identityFromJSON(content); err != nil {
+			return nil, errors.WithMessagef(err, "invalid identity [%s] in wallet archive", label)
+		}
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		if err := wallet.store.Put(label, identities[label]); err != nil {
+			return nil, errors.WithMessagef(err, "failed to import identity [%s]", label)
+		}
+	}
+	return labels, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletArchive(t *testing.T) {
+	password := []byte("archive-password")
+
+	source := NewInMemoryWallet()
+	require.NoError(t, source.Put("user1", NewX509Identity("Org1MSP", "testCert1", "testPrivKey1")))
+	require.NoError(t, source.Put("user2", NewX509Identity("Org2MSP", "testCert2", "testPrivKey2")))
+
+	archive := &bytes.Buffer{}
+	labels, err := ExportArchive(source, archive, password)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1", "user2"}, labels)
+	assert.NotContains(t, archive.String(), "testPrivKey1", "archive must be encrypted")
+
+	target := NewInMemoryWallet()
+	require.NoError(t, target.Put("user1", NewX509Identity("Org1MSP", "oldCert", "oldPrivKey")))
+
+	_, err = ImportArchive(target, bytes.NewReader(archive.Bytes()), []byte("wrong-password"))
+	assert.Error(t, err)
+
+	labels, err = ImportArchive(target, bytes.NewReader(archive.Bytes()), password)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1", "user2"}, labels)
+
+	id, err := target.Get("user1")
+	require.NoError(t, err)
+	assert.Equal(t, "testCert1", id.(*X509Identity).Certificate())
+	id, err = target.Get("user2")
+	require.NoError(t, err)
+	assert.Equal(t, "Org2MSP", id.(*X509Identity).MspID)
+}
+
+func TestWalletArchiveErrors(t *testing.T) {
+	_, err := ExportArchive(NewInMemoryWallet(), &bytes.Buffer{}, nil)
+	assert.Error(t, err, "password is required")
+
+	_, err = ImportArchive(NewInMemoryWallet(), bytes.NewReader([]byte("invalid")), []byte("password"))
+	assert.Error(t, err)
+
+	_, err = ImportArchive(NewInMemoryWallet(), bytes.NewReader([]byte(`{"version":2}`)), []byte("password"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,321 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+/*
+pkg/gateway/walletmsp.go 钱包身份与MSP目录、cryptogen输出以及SDK凭证存储之间的导入导出
+*/
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	fabricCaUtil "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/sdkinternal/pkg/util"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/config/cryptoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite"
+	mspimpl "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/msp"
+	gmx509 "gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/pkg/errors"
+)
+
+const (
+	signcertsDir = "signcerts"
+	keystoreDir  = "keystore"
+	// 导出时使用的文件名，与fabric-ca-client以及cryptogen 2.x保持一致
+	exportCertFile = "cert.pem"
+	exportKeyFile  = "priv_sk"
+)
+
+// MSPOption describes a functional parameter for importing identities into or exporting identities from a wallet
+type MSPOption func(*mspOptions)
+
+type mspOptions struct {
+	keyPassword []byte
+	cryptoSuite core.CryptoSuite
+}
+
+// WithKeyPassword sets the password of encrypted private key files. On import, keys that are
+// stored as encrypted PEM are decrypted with the password; on export, the key is written encrypted.
+func WithKeyPassword(password []byte) MSPOption {
+	return func(o *mspOptions) {
+		o.keyPassword = password
+	}
+}
+
+// WithMSPCryptoSuite sets the crypto suite used to match private keys with certificates
+// (default is the SDK's default crypto suite)
+func WithMSPCryptoSuite(cs core.CryptoSuite) MSPOption {
+	return func(o *mspOptions) {
+		o.cryptoSuite = cs
+	}
+}
+
+func newMSPOptions(opts []MSPOption) *mspOptions {
+	o := &mspOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.cryptoSuite == nil {
+		o.cryptoSuite = cryptosuite.GetDefault()
+	}
+	return o
+}
+
+// NewX509IdentityFromMSPDir reads the signing certificate and the matching private key
+// from an MSP directory (signcerts/ and keystore/), as written by fabric-ca-client or cryptogen.
+//  Parameters:
+//  mspID is the MSP ID of the identity's organization.
+//  mspDir is the path of the MSP directory.
+//
+//  Returns:
+//  An X509Identity that can be put into a wallet.
+func NewX509IdentityFromMSPDir(mspID, mspDir string, opts ...MSPOption) (*X509Identity, error) {
+	o := newMSPOptions(opts)
+
+	cert, err := readSigncert(filepath.Join(mspDir, signcertsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	keyDir := filepath.Join(mspDir, keystoreDir)
+	files, err := ioutil.ReadDir(keyDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read keystore %s", keyDir)
+	}
+	var candidates []string
+	for _, file := range files {
+		if !file.IsDir() {
+			candidates = append(candidates, filepath.Join(keyDir, file.Name()))
+		}
+	}
+
+	key, err := findPrivateKey(cert, candidates, o)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to find private key in %s", keyDir)
+	}
+	return NewX509Identity(mspID, string(cert), string(key)), nil
+}
+
+// ImportFromMSPDir puts the identity held in an MSP directory into the wallet.
+//  Parameters:
+//  wallet is the wallet to import the identity into.
+//  label specifies the name of the identity in the wallet.
+//  mspID is the MSP ID of the identity's organization.
+//  mspDir is the path of the MSP directory.
+func ImportFromMSPDir(wallet *Wallet, label, mspID, mspDir string, opts ...MSPOption) error {
+	id, err := NewX509IdentityFromMSPDir(mspID, mspDir, opts...)
+	if err != nil {
+		return err
+	}
+	return wallet.Put(label, id)
+}
+
+// ImportFromCryptogen puts the users generated by cryptogen into the wallet.
+// Users are read from <dir>/peerOrganizations/<org>/users/<user>@<org>/msp and from the
+// same layout under ordererOrganizations. Only organizations listed in mspIDs are imported.
+//  Parameters:
+//  wallet is the wallet to import the identities into.
+//  cryptoConfigDir is the cryptogen output directory (e.g. crypto-config).
+//  mspIDs maps organization domains (e.g. org1.example.com) to their MSP IDs.
+//
+//  Returns:
+//  The labels of the imported identities, which are the user directory names (e.g. User1@org1.example.com).
+func ImportFromCryptogen(wallet *Wallet, cryptoConfigDir string, mspIDs map[string]string, opts ...MSPOption) ([]string, error) {
+	var labels []string
+	for _, orgType := range []string{"peerOrganizations", "ordererOrganizations"} {
+		for domain, mspID := range mspIDs {
+			usersDir := filepath.Join(cryptoConfigDir, orgType, domain, "users")
+			users, err := ioutil.ReadDir(usersDir)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return labels, errors.Wrapf(err, "failed to read users of %s", domain)
+			}
+			for _, user := range users {
+				if !user.IsDir() {
+					continue
+				}
+				label := user.Name()
+				if err := ImportFromMSPDir(wallet, label, mspID, filepath.Join(usersDir, label, "msp"), opts...); err != nil {
+					return labels, errors.WithMessagef(err, "failed to import user %s", label)
+				}
+				labels = append(labels, label)
+			}
+		}
+	}
+	if len(labels) == 0 {
+		return nil, errors.Errorf("no users found in %s for the given organizations", cryptoConfigDir)
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// ImportFromUserStore puts the users enrolled with the SDK into the wallet. The enrollment
+// certificates are read from the CertFileUserStore at userStorePath (client.credentialStore.path)
+// and the private keys from the "keystore" directory of the crypto store at cryptoStorePath
+// (client.credentialStore.cryptoStore.path), where the SDK stores them as <cryptoStorePath>/keystore/<SKI>_sk.
+//  Parameters:
+//  wallet is the wallet to import the identities into.
+//  userStorePath is the path of the user store.
+//  cryptoStorePath is the path of the crypto store.
+//
+//  Returns:
+//  The labels of the imported identities, which are the user IDs.
+func ImportFromUserStore(wallet *Wallet, userStorePath, cryptoStorePath string, opts ...MSPOption) ([]string, error) {
+	o := newMSPOptions(opts)
+
+	userStore, err := mspimpl.NewCertFileUserStore(userStorePath)
+	if err != nil {
+		return nil, err
+	}
+	users, err := userStore.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []string
+	for _, user := range users {
+		pubKey, err := cryptoutil.GetPublicKeyFromCert(user.EnrollmentCertificate, o.cryptoSuite)
+		if err != nil {
+			return labels, errors.WithMessagef(err, "invalid enrollment certificate of user %s", user.ID)
+		}
+		// SDK的密钥存储以`<SKI>_sk`命名私钥文件
+		candidates := []string{filepath.Join(cryptoStorePath, keystoreDir, hex.EncodeToString(pubKey.SKI())+"_sk")}
+		key, err := findPrivateKey(user.EnrollmentCertificate, candidates, o)
+		if err != nil {
+			return labels, errors.WithMessagef(err, "failed to find private key of user %s", user.ID)
+		}
+		if err := wallet.Put(user.ID, NewX509Identity(user.MSPID, string(user.EnrollmentCertificate), string(key))); err != nil {
+			return labels, err
+		}
+		labels = append(labels, user.ID)
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// ExportToMSPDir writes an identity from the wallet to an MSP directory, with the certificate
+// in signcerts/cert.pem and the private key in keystore/priv_sk. The wallet does not hold CA
+// certificates, so cacerts must be added separately for the directory to be used as a local MSP.
+//  Parameters:
+//  wallet is the wallet holding the identity.
+//  label specifies the name of the identity in the wallet.
+//  mspDir is the path of the MSP directory, which is created if it does not exist.
+func ExportToMSPDir(wallet *Wallet, label, mspDir string, opts ...MSPOption) error {
+	o := newMSPOptions(opts)
+
+	id, err := wallet.Get(label)
+	if err != nil {
+		return err
+	}
+	x509ID, ok := id.(*X509Identity)
+	if !ok {
+		return errors.Errorf("identity [%s] is not an X.509 identity", label)
+	}
+
+	key := []byte(x509ID.Key())
+	if len(o.keyPassword) > 0 {
+		if key, err = sw.ReEncryptPEM(key, nil, o.keyPassword); err != nil {
+			return errors.WithMessage(err, "failed to encrypt private key")
+		}
+	}
+
+	for _, dir := range []string{signcertsDir, keystoreDir} {
+		if err := os.MkdirAll(filepath.Join(mspDir, dir), 0750); err != nil {
+			return err
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(mspDir, signcertsDir, exportCertFile), []byte(x509ID.Certificate()), 0644); err != nil {
+		return errors.Wrap(err, "failed to write certificate")
+	}
+	if err := ioutil.WriteFile(filepath.Join(mspDir, keystoreDir, exportKeyFile), key, 0600); err != nil {
+		return errors.Wrap(err, "failed to write private key")
+	}
+	return nil
+}
+
+// readSigncert 读取signcerts目录中的证书，目录中只能有一个证书
+func readSigncert(dir string) ([]byte, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", dir)
+	}
+
+	var cert []byte
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		raw, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if block, _ := pem.Decode(raw); block == nil || !strings.Contains(block.Type, "CERTIFICATE") {
+			continue
+		}
+		if cert != nil {
+			return nil, errors.Errorf("more than one certificate found in %s", dir)
+		}
+		cert = raw
+	}
+	if cert == nil {
+		return nil, errors.Errorf("no certificate found in %s", dir)
+	}
+	return cert, nil
+}
+
+// findPrivateKey 在候选文件中查找与证书公钥匹配的私钥，返回明文PEM
+func findPrivateKey(cert []byte, candidates []string, o *mspOptions) ([]byte, error) {
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(cert, o.cryptoSuite)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid certificate")
+	}
+
+	var encryptedKeys []string
+	for _, file := range candidates {
+		raw, err := ioutil.ReadFile(filepath.Clean(file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		block, _ := pem.Decode(raw)
+		if block == nil || !strings.Contains(block.Type, "PRIVATE KEY") {
+			continue
+		}
+		if gmx509.IsEncryptedPEMBlock(block) {
+			if len(o.keyPassword) == 0 {
+				encryptedKeys = append(encryptedKeys, file)
+				continue
+			}
+			if raw, err = sw.ReEncryptPEM(raw, o.keyPassword, nil); err != nil {
+				return nil, errors.WithMessagef(err, "failed to decrypt private key %s", file)
+			}
+		}
+		key, err := fabricCaUtil.ImportBCCSPKeyFromPEMBytes(raw, o.cryptoSuite, true)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(key.SKI(), pubKey.SKI()) {
+			return raw, nil
+		}
+	}
+	if len(encryptedKeys) > 0 {
+		return nil, errors.Errorf("no private key matches the certificate; encrypted keys %v require a password", encryptedKeys)
+	}
+	return nil, errors.New("no private key matches the certificate")
+}
@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/config/cryptoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite"
+	mspimpl "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/msp"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	gmx509 "gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCredentials returns a self-signed SM2 certificate and its private key as PEM
+func newTestCredentials(t *testing.T, cn string) ([]byte, []byte) {
+	key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	template := &gmx509.Certificate{
+		SerialNumber:       big.NewInt(time.Now().UnixNano()),
+		Subject:            pkix.Name{CommonName: cn},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           time.Now().Add(time.Hour),
+		SignatureAlgorithm: gmx509.SM2WithSM3,
+		KeyUsage:           gmx509.KeyUsageDigitalSignature,
+	}
+	raw, err := gmx509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyPEM, err := gmx509.WritePrivateKeyToPem(key, nil)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}), keyPEM
+}
+
+func writeMSPDir(t *testing.T, dir string, cert []byte, keys map[string][]byte) {
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "signcerts"), 0750))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "keystore"), 0750))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "signcerts", "cert.pem"), cert, 0644))
+	for name, key := range keys {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "keystore", name), key, 0600))
+	}
+}
+
+func requireX509Identity(t *testing.T, wallet *Wallet, label, mspID string, cert, key []byte) {
+	id, err := wallet.Get(label)
+	require.NoError(t, err)
+	x509ID := id.(*X509Identity)
+	assert.Equal(t, mspID, x509ID.MspID)
+	assert.Equal(t, string(cert), x509ID.Certificate())
+	assert.Equal(t, string(key), x509ID.Key())
+}
+
+func TestImportFromMSPDir(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := newTestCredentials(t, "user1")
+	_, otherKey := newTestCredentials(t, "user2")
+	writeMSPDir(t, dir, cert, map[string][]byte{"other_sk": otherKey, "priv_sk": key})
+
+	wallet := NewInMemoryWallet()
+	require.NoError(t, ImportFromMSPDir(wallet, "user1", "Org1MSP", dir))
+	requireX509Identity(t, wallet, "user1", "Org1MSP", cert, key)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "keystore", "priv_sk")))
+	assert.Error(t, ImportFromMSPDir(wallet, "user1", "Org1MSP", dir), "no matching key")
+
+	assert.Error(t, ImportFromMSPDir(wallet, "user1", "Org1MSP", filepath.Join(dir, "missing")))
+}
+
+func TestImportFromMSPDirEncryptedKey(t *testing.T) {
+	dir := t.TempDir()
+	password := []byte("key-password")
+	cert, key := newTestCredentials(t, "user1")
+	encryptedKey, err := sw.ReEncryptPEM(key, nil, password)
+	require.NoError(t, err)
+	writeMSPDir(t, dir, cert, map[string][]byte{"priv_sk": encryptedKey})
+
+	wallet := NewInMemoryWallet()
+	assert.Error(t, ImportFromMSPDir(wallet, "user1", "Org1MSP", dir), "password is required")
+	require.NoError(t, ImportFromMSPDir(wallet, "user1", "Org1MSP", dir, WithKeyPassword(password)))
+
+	id, err := wallet.Get("user1")
+	require.NoError(t, err)
+	block, _ := pem.Decode([]byte(id.(*X509Identity).Key()))
+	require.NotNil(t, block)
+	assert.False(t, gmx509.IsEncryptedPEMBlock(block), "wallet holds the decrypted key")
+}
+
+func TestImportFromCryptogen(t *testing.T) {
+	dir := t.TempDir()
+	users := map[string]string{
+		"User1@org1.example.com": filepath.Join(dir, "peerOrganizations", "org1.example.com", "users", "User1@org1.example.com", "msp"),
+		"Admin@org1.example.com": filepath.Join(dir, "peerOrganizations", "org1.example.com", "users", "Admin@org1.example.com", "msp"),
+		"Admin@example.com":      filepath.Join(dir, "ordererOrganizations", "example.com", "users", "Admin@example.com", "msp"),
+		"User1@org2.example.com": filepath.Join(dir, "peerOrganizations", "org2.example.com", "users", "User1@org2.example.com", "msp"),
+	}
+	credentials := make(map[string][2][]byte)
+	for label, mspDir := range users {
+		cert, key := newTestCredentials(t, label)
+		writeMSPDir(t, mspDir, cert, map[string][]byte{"priv_sk": key})
+		credentials[label] = [2][]byte{cert, key}
+	}
+
+	wallet := NewInMemoryWallet()
+	labels, err := ImportFromCryptogen(wallet, dir, map[string]string{"org1.example.com": "Org1MSP", "example.com": "OrdererMSP"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Admin@example.com", "Admin@org1.example.com", "User1@org1.example.com"}, labels)
+
+	requireX509Identity(t, wallet, "User1@org1.example.com", "Org1MSP", credentials["User1@org1.example.com"][0], credentials["User1@org1.example.com"][1])
+	requireX509Identity(t, wallet, "Admin@example.com", "OrdererMSP", credentials["Admin@example.com"][0], credentials["Admin@example.com"][1])
+	assert.False(t, wallet.Exists("User1@org2.example.com"))
+
+	_, err = ImportFromCryptogen(wallet, dir, map[string]string{"org3.example.com": "Org3MSP"})
+	assert.Error(t, err, "no users found")
+}
+
+func TestImportFromUserStore(t *testing.T) {
+	userStorePath := t.TempDir()
+	cryptoStorePath := t.TempDir()
+	// The SDK stores private keys in the keystore directory of the crypto store
+	keyStorePath := filepath.Join(cryptoStorePath, "keystore")
+	require.NoError(t, os.MkdirAll(keyStorePath, 0750))
+
+	userStore, err := mspimpl.NewCertFileUserStore(userStorePath)
+	require.NoError(t, err)
+
+	cert, key := newTestCredentials(t, "user1")
+	require.NoError(t, userStore.Store(&msp.UserData{MSPID: "Org1MSP", ID: "user1", EnrollmentCertificate: cert}))
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(cert, cryptosuite.GetDefault())
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(keyStorePath, hex.EncodeToString(pubKey.SKI())+"_sk"), key, 0600))
+
+	wallet := NewInMemoryWallet()
+	labels, err := ImportFromUserStore(wallet, userStorePath, cryptoStorePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1"}, labels)
+	requireX509Identity(t, wallet, "user1", "Org1MSP", cert, key)
+
+	_, err = ImportFromUserStore(NewInMemoryWallet(), userStorePath, keyStorePath)
+	assert.Error(t, err, "private key is not in the keystore directory of the crypto store")
+
+	_, err = ImportFromUserStore(NewInMemoryWallet(), userStorePath, t.TempDir())
+	assert.Error(t, err, "private key is missing")
+}
+
+func TestExportToMSPDir(t *testing.T) {
+	cert, key := newTestCredentials(t, "user1")
+	wallet := NewInMemoryWallet()
+	require.NoError(t, wallet.Put("user1", NewX509Identity("Org1MSP", string(cert), string(key))))
+
+	dir := filepath.Join(t.TempDir(), "msp")
+	require.NoError(t, ExportToMSPDir(wallet, "user1", dir))
+
+	imported := NewInMemoryWallet()
+	require.NoError(t, ImportFromMSPDir(imported, "user1", "Org1MSP", dir))
+	requireX509Identity(t, imported, "user1", "Org1MSP", cert, key)
+
+	encryptedDir := filepath.Join(t.TempDir(), "msp")
+	password := []byte("key-password")
+	require.NoError(t, ExportToMSPDir(wallet, "user1", encryptedDir, WithKeyPassword(password)))
+	raw, err := ioutil.ReadFile(filepath.Join(encryptedDir, "keystore", "priv_sk"))
+	require.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	require.NotNil(t, block)
+	assert.True(t, gmx509.IsEncryptedPEMBlock(block))
+	require.NoError(t, ImportFromMSPDir(imported, "user2", "Org1MSP", encryptedDir, WithKeyPassword(password)))
+
+	assert.Error(t, ExportToMSPDir(wallet, "missing", dir))
+}
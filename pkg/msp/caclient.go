@@ -299,9 +299,9 @@ func (c *CAClientImpl) Reenroll(request *api.ReenrollmentRequest) error {
 		return errors.Wrapf(err, "failed to retrieve user: %s", request.Name)
 	}
 
-	cert, err := c.adapter.Reenroll(user.PrivateKey(), user.EnrollmentCertificate(), request)
+	cert, err := c.ReenrollIdentity(user, request)
 	if err != nil {
-		return errors.Wrap(err, "reenroll failed")
+		return err
 	}
 	userData := &msp.UserData{
 		MSPID:                 c.orgMSPID,
@@ -316,6 +316,25 @@ func (c *CAClientImpl) Reenroll(request *api.ReenrollmentRequest) error {
 	return nil
 }
 
+// ReenrollIdentity re-enrolls the given signing identity and returns the new enrollment certificate.
+// Unlike Reenroll, the certificate is not persisted to the user store; this allows identities
+// kept outside of the SDK stores (e.g. gateway wallets) to be renewed.
+func (c *CAClientImpl) ReenrollIdentity(identity msp.SigningIdentity, request *api.ReenrollmentRequest) ([]byte, error) {
+
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if identity == nil {
+		return nil, errors.New("signing identity is required")
+	}
+
+	cert, err := c.adapter.Reenroll(identity.PrivateKey(), identity.EnrollmentCertificate(), request)
+	if err != nil {
+		return nil, errors.Wrap(err, "reenroll failed")
+	}
+	return cert, nil
+}
+
 // Register a User with the Fabric CA
 // request: Registration Request
 // Returns Enrolment Secret
@@ -374,6 +393,53 @@ func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (*api.RevocationRe
 	return resp, nil
 }
 
+// GetCertificates returns certificates that the caller is authorized to see
+// and that match the filters specified in the request
+//
+//  Returns:
+//  Response containing PEM-encoded certificates
+func (c *CAClientImpl) GetCertificates(request *api.GetCertificatesRequest) (*api.GetCertificatesResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	if request == nil {
+		return nil, errors.New("get certificates request is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.adapter.GetCertificates(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+}
+
+// GenCRL generates a CRL containing the revoked certificates selected by the request
+//
+//  Returns:
+//  Response containing PEM-encoded CRL
+func (c *CAClientImpl) GenCRL(request *api.GenCRLRequest) (*api.GenCRLResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	if request == nil {
+		return nil, errors.New("gencrl request is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.adapter.GenCRL(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+}
+
 // GetCAInfo returns generic CA information
 func (c *CAClientImpl) GetCAInfo() (*api.GetCAInfoResponse, error) {
 	if c.adapter == nil {
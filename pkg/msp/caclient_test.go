@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/test/mockfab"
 
@@ -441,6 +442,81 @@ func TestRevoke(t *testing.T) {
 	}
 }
 
+// TestGetCertificates tests listing certificates
+func TestGetCertificates(t *testing.T) {
+
+	f := textFixture{}
+	f.setup()
+	defer f.close()
+
+	// GetCertificates with nil request
+	_, err := f.caClient.GetCertificates(nil)
+	if err == nil {
+		t.Fatal("Expected error with nil request")
+	}
+
+	expiredEnd := time.Now().Add(30 * 24 * time.Hour)
+	resp, err := f.caClient.GetCertificates(&api.GetCertificatesRequest{ID: "test", NotRevoked: true, ExpiredEnd: expiredEnd})
+	if err != nil {
+		t.Fatalf("GetCertificates return error %s", err)
+	}
+	if len(resp.Certs) != 1 {
+		t.Fatalf("expecting %d, got %d certificates", 1, len(resp.Certs))
+	}
+	if !strings.Contains(string(resp.Certs[0]), "BEGIN CERTIFICATE") {
+		t.Fatalf("expecting PEM-encoded certificate, got %s", resp.Certs[0])
+	}
+
+	query := caServer.CertificatesQuery()
+	if query.Get("id") != "test" || query.Get("notrevoked") != "true" || query.Get("notexpired") != "false" {
+		t.Fatalf("unexpected certificates query %v", query)
+	}
+	if query.Get("expired_end") != formatTime(expiredEnd) || query.Get("expired_start") != "" {
+		t.Fatalf("unexpected expired time range in certificates query %v", query)
+	}
+
+	// The mock CA holds no revoked certificates
+	revokedStart := time.Now().Add(-24 * time.Hour)
+	resp, err = f.caClient.GetCertificates(&api.GetCertificatesRequest{ID: "test", RevokedStart: revokedStart})
+	if err != nil {
+		t.Fatalf("GetCertificates return error %s", err)
+	}
+	if len(resp.Certs) != 0 {
+		t.Fatalf("expecting no certificates, got %d", len(resp.Certs))
+	}
+	if caServer.CertificatesQuery().Get("revoked_start") != formatTime(revokedStart) {
+		t.Fatalf("unexpected revoked time range in certificates query %v", caServer.CertificatesQuery())
+	}
+
+	// Fabric CA rejects a revoked time range combined with notrevoked
+	_, err = f.caClient.GetCertificates(&api.GetCertificatesRequest{ID: "test", NotRevoked: true, RevokedEnd: time.Now()})
+	if err == nil {
+		t.Fatal("Expected error with revoked time range and notrevoked")
+	}
+}
+
+// TestGenCRL tests CRL generation
+func TestGenCRL(t *testing.T) {
+
+	f := textFixture{}
+	f.setup()
+	defer f.close()
+
+	// GenCRL with nil request
+	_, err := f.caClient.GenCRL(nil)
+	if err == nil {
+		t.Fatal("Expected error with nil request")
+	}
+
+	resp, err := f.caClient.GenCRL(&api.GenCRLRequest{RevokedAfter: time.Now().Add(-24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("GenCRL return error %s", err)
+	}
+	if !strings.Contains(string(resp.CRL), "BEGIN X509 CRL") {
+		t.Fatalf("expecting PEM-encoded CRL, got %s", resp.CRL)
+	}
+}
+
 // TestCAConfigError will test CAClient creation with bad CAConfig
 func TestCAConfigError(t *testing.T) {
 
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"io/ioutil"
+	"strings"
+
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/keyvaluestore"
@@ -20,8 +23,24 @@ type CertFileUserStore struct {
 	store core.KVStore
 }
 
+const certFileSuffix = "-cert.pem"
+
 func storeKeyFromUserIdentifier(key msp.IdentityIdentifier) string {
-	return key.ID + "@" + key.MSPID + "-cert.pem"
+	return key.ID + "@" + key.MSPID + certFileSuffix
+}
+
+// userIdentifierFromStoreKey is the reverse of storeKeyFromUserIdentifier.
+// User IDs may contain '@', MSP IDs may not.
+func userIdentifierFromStoreKey(key string) (msp.IdentityIdentifier, bool) {
+	if !strings.HasSuffix(key, certFileSuffix) {
+		return msp.IdentityIdentifier{}, false
+	}
+	name := strings.TrimSuffix(key, certFileSuffix)
+	i := strings.LastIndex(name, "@")
+	if i <= 0 || i == len(name)-1 {
+		return msp.IdentityIdentifier{}, false
+	}
+	return msp.IdentityIdentifier{ID: name[:i], MSPID: name[i+1:]}, true
 }
 
 // NewCertFileUserStore1 creates a new instance of CertFileUserStore
@@ -76,3 +95,32 @@ func (s *CertFileUserStore) Store(user *msp.UserData) error {
 func (s *CertFileUserStore) Delete(key msp.IdentityIdentifier) error {
 	return s.store.Delete(storeKeyFromUserIdentifier(key))
 }
+
+// List returns all users stored in the store.
+// Listing is only supported when the store is backed by a file key value store.
+func (s *CertFileUserStore) List() ([]*msp.UserData, error) {
+	fkvs, ok := s.store.(*keyvaluestore.FileKeyValueStore)
+	if !ok {
+		return nil, errors.New("listing users is only supported by file based user stores")
+	}
+	files, err := ioutil.ReadDir(fkvs.GetPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "reading user store directory failed")
+	}
+	var users []*msp.UserData
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		key, ok := userIdentifierFromStoreKey(f.Name())
+		if !ok {
+			continue
+		}
+		user, err := s.Load(key)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "loading user %s failed", f.Name())
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
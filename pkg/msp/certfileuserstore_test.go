@@ -124,6 +124,47 @@ func checkNonExistingKey(store *CertFileUserStore, t *testing.T) {
 	}
 }
 
+func TestListUsers(t *testing.T) {
+
+	cleanupTestPath(t, storePathRoot)
+	defer cleanupTestPath(t, storePathRoot)
+
+	store, err := NewCertFileUserStore(storePath)
+	if err != nil {
+		t.Fatalf("NewFileKeyValueStore failed [%s]", err)
+	}
+	cleanupTestPath(t, storePath)
+
+	user1 := &msp.UserData{
+		MSPID:                 "Org1",
+		ID:                    "User1@org1.example.com",
+		EnrollmentCertificate: []byte(testCert1),
+	}
+	user2 := &msp.UserData{
+		MSPID:                 "Org2",
+		ID:                    "user2",
+		EnrollmentCertificate: []byte(testCert2),
+	}
+	createStore(store, user1, t, user2)
+
+	users, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed [%s]", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	for _, u := range users {
+		expected := user1
+		if u.ID == user2.ID {
+			expected = user2
+		}
+		if u.ID != expected.ID || u.MSPID != expected.MSPID || !bytes.Equal(u.EnrollmentCertificate, expected.EnrollmentCertificate) {
+			t.Fatalf("unexpected user %s@%s", u.ID, u.MSPID)
+		}
+	}
+}
+
 func TestCreateNewStore(t *testing.T) {
 
 	_, err := NewCertFileUserStore("")
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 
 	"encoding/json"
+	"time"
 
 	calib "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/lib"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/lib/client/credential"
@@ -92,6 +93,15 @@ func (c *fabricCAAdapter) Reenroll(key core.Key, cert []byte, request *api.Reenr
 		}
 		careq.AttrReqs = attrs
 	}
+	if request.ReuseKey {
+		if careq.CSR == nil {
+			careq.CSR = &caapi.CSRInfo{}
+		}
+		if careq.CSR.KeyRequest == nil {
+			careq.CSR.KeyRequest = &caapi.KeyRequest{}
+		}
+		careq.CSR.KeyRequest.ReuseKey = true
+	}
 
 	caidentity, err := c.newIdentity(key, cert)
 	if err != nil {
@@ -179,6 +189,83 @@ func (c *fabricCAAdapter) Revoke(key core.Key, cert []byte, request *api.Revocat
 	}, nil
 }
 
+// GetCertificates returns certificates matching the request filters
+// key: registrar private key
+// cert: registrar enrollment certificate
+// request: Get Certificates Request
+func (c *fabricCAAdapter) GetCertificates(key core.Key, cert []byte, request *api.GetCertificatesRequest) (*api.GetCertificatesResponse, error) {
+	logger.Debugf("Retrieving certificates [%+v]", request)
+
+	var req = caapi.GetCertificatesRequest{
+		ID:     request.ID,
+		AKI:    request.AKI,
+		Serial: request.Serial,
+		Revoked: caapi.TimeRange{
+			StartTime: formatTime(request.RevokedStart),
+			EndTime:   formatTime(request.RevokedEnd),
+		},
+		Expired: caapi.TimeRange{
+			StartTime: formatTime(request.ExpiredStart),
+			EndTime:   formatTime(request.ExpiredEnd),
+		},
+		NotExpired: request.NotExpired,
+		NotRevoked: request.NotRevoked,
+		CAName:     request.CAName,
+	}
+
+	registrar, err := c.newIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	var certs [][]byte
+
+	err = registrar.GetCertificates(&req, func(decoder *json.Decoder) error {
+		var certPEM struct {
+			PEM string
+		}
+		decodeErr := decoder.Decode(&certPEM)
+		if decodeErr != nil {
+			return decodeErr
+		}
+
+		certs = append(certs, []byte(certPEM.PEM))
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get certificates")
+	}
+
+	return &api.GetCertificatesResponse{Certs: certs}, nil
+}
+
+// GenCRL generates a CRL containing the revoked certificates selected by the request
+// key: registrar private key
+// cert: registrar enrollment certificate
+// request: Generate CRL Request
+func (c *fabricCAAdapter) GenCRL(key core.Key, cert []byte, request *api.GenCRLRequest) (*api.GenCRLResponse, error) {
+	var req = caapi.GenCRLRequest{
+		CAName:        request.CAName,
+		RevokedAfter:  request.RevokedAfter,
+		RevokedBefore: request.RevokedBefore,
+		ExpireAfter:   request.ExpireAfter,
+		ExpireBefore:  request.ExpireBefore,
+	}
+
+	registrar, err := c.newIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.GenCRL(&req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate CRL")
+	}
+
+	return &api.GenCRLResponse{CRL: resp.CRL}, nil
+}
+
 // GetCAInfo returns generic CA information
 func (c *fabricCAAdapter) GetCAInfo(caname string) (*api.GetCAInfoResponse, error) {
 	logger.Debugf("Get CA info [%s]", caname)
@@ -555,6 +642,15 @@ func getAllAttributes(attrs []caapi.Attribute) []api.Attribute {
 	return attriburtes
 }
 
+// formatTime formats t as expected by the Fabric CA time range query parameters.
+// The zero time yields an empty string so that the filter is omitted.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
 func (c *fabricCAAdapter) newIdentity(key core.Key, cert []byte) (*calib.Identity, error) {
 	x509Cred := x509.NewCredential(key, cert, c.caClient)
 
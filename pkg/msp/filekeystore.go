@@ -8,13 +8,16 @@ package msp
 
 import (
 	"encoding/hex"
+	"encoding/pem"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/bccsp/sw"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/keyvaluestore"
+	gmx509 "gitee.com/zhaochuninhefei/gmgo/x509"
 	"github.com/pkg/errors"
 )
 
@@ -22,22 +25,54 @@ import (
 // This function will detect if private keys are stored in v1 or v2 format.
 func NewFileKeyStore(cryptoConfigMSPPath string) (core.KVStore, error) {
 	opts := &keyvaluestore.FileKeyValueStoreOptions{
-		Path: cryptoConfigMSPPath,
-		KeySerializer: func(key interface{}) (string, error) {
-			pkk, ok := key.(*msp.PrivKeyKey)
+		Path:          cryptoConfigMSPPath,
+		KeySerializer: privKeySerializer(cryptoConfigMSPPath),
+	}
+	return keyvaluestore.New(opts)
+}
+
+// NewEncryptedFileKeyStore loads keys stored in the cryptoconfig directory layout as PEM encrypted with passphrase.
+// Keys are encrypted when stored and returned as plaintext PEM when loaded, so callers are unaware of the encryption.
+// Keys that are still stored as plaintext PEM are loaded as is.
+func NewEncryptedFileKeyStore(cryptoConfigMSPPath string, passphrase []byte) (core.KVStore, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+	opts := &keyvaluestore.FileKeyValueStoreOptions{
+		Path:          cryptoConfigMSPPath,
+		KeySerializer: privKeySerializer(cryptoConfigMSPPath),
+		Marshaller: func(value interface{}) ([]byte, error) {
+			pemBytes, ok := value.([]byte)
 			if !ok {
-				return "", errors.New("converting key to PrivKeyKey failed")
+				return nil, errors.New("converting value to byte array failed")
 			}
-			if pkk == nil || pkk.MSPID == "" || pkk.ID == "" || pkk.SKI == nil {
-				return "", errors.New("invalid key")
+			return sw.ReEncryptPEM(pemBytes, nil, passphrase)
+		},
+		Unmarshaller: func(value []byte) (interface{}, error) {
+			block, _ := pem.Decode(value)
+			if block == nil || !gmx509.IsEncryptedPEMBlock(block) {
+				return value, nil
 			}
-
-			return cryptoConfigPrivateKeyPath(cryptoConfigMSPPath, pkk.ID, pkk.SKI), nil
+			return sw.ReEncryptPEM(value, passphrase, nil)
 		},
 	}
 	return keyvaluestore.New(opts)
 }
 
+func privKeySerializer(cryptoConfigMSPPath string) keyvaluestore.KeySerializer {
+	return func(key interface{}) (string, error) {
+		pkk, ok := key.(*msp.PrivKeyKey)
+		if !ok {
+			return "", errors.New("converting key to PrivKeyKey failed")
+		}
+		if pkk == nil || pkk.MSPID == "" || pkk.ID == "" || pkk.SKI == nil {
+			return "", errors.New("invalid key")
+		}
+
+		return cryptoConfigPrivateKeyPath(cryptoConfigMSPPath, pkk.ID, pkk.SKI), nil
+	}
+}
+
 func cryptoConfigPrivateKeyPath(cryptoConfigMSPPath, id string, ski []byte) string {
 	// TODO: refactor to case insensitive or remove eventually.
 	r := strings.NewReplacer("{userName}", id, "{username}", id)
@@ -7,11 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/rand"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	gmx509 "gitee.com/zhaochuninhefei/gmgo/x509"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCryptoConfigPrivKeyPathV1(t *testing.T) {
@@ -40,6 +48,39 @@ func TestCryptoConfigPrivKeyPathV2(t *testing.T) {
 	assert.Contains(t, p, "priv_sk")
 }
 
+func TestEncryptedFileKeyStore(t *testing.T) {
+	cryptoConfigPath, err := ioutil.TempDir("", "encryptedkeystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(cryptoConfigPath)
+
+	_, err = NewEncryptedFileKeyStore(cryptoConfigPath, nil)
+	assert.Error(t, err, "empty passphrase should be rejected")
+
+	store, err := NewEncryptedFileKeyStore(filepath.Join(cryptoConfigPath, "{username}"), []byte("passphrase"))
+	require.NoError(t, err)
+
+	privKey, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pemBytes, err := gmx509.WritePrivateKeyToPem(privKey, nil)
+	require.NoError(t, err)
+
+	key := &msp.PrivKeyKey{ID: "user", MSPID: "Org1MSP", SKI: []byte{0, 1}}
+	require.NoError(t, store.Store(key, pemBytes))
+
+	// the file on disk is encrypted
+	raw, err := ioutil.ReadFile(cryptoConfigPrivateKeyPath(filepath.Join(cryptoConfigPath, "{username}"), "user", key.SKI))
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(raw), "ENCRYPTED"), "key file should be encrypted")
+
+	// the loaded key is plaintext PEM
+	loaded, err := store.Load(key)
+	require.NoError(t, err)
+	loadedBytes, ok := loaded.([]byte)
+	require.True(t, ok)
+	assert.False(t, strings.Contains(string(loadedBytes), "ENCRYPTED"), "loaded key should be plaintext")
+	assert.Contains(t, string(loadedBytes), "PRIVATE KEY")
+}
+
 func testDir() string {
 	_, filename, _, _ := runtime.Caller(0)
 	return filepath.Dir(filename)
@@ -253,6 +253,28 @@ func TestGetSigningIdentityFromMSPDir(t *testing.T) {
 	checkSigningIdentityFromMSPDir(mgr, t)
 }
 
+func TestGetSigningIdentityFromMSPDirWithPassphrase(t *testing.T) {
+
+	configPath := filepath.Join(getConfigPath(), configMSPOnly)
+	configBackend, err := config.FromFile(configPath)()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpointConfig, err := fab.ConfigFromBackend(configBackend...)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to read config: %s", err))
+	}
+
+	// keys of the MSP dir are plaintext PEM, they are loaded as is by the encrypted key store
+	mgr, err := NewIdentityManager(orgName, nil, cryptosuite.GetDefault(), endpointConfig, WithKeyStorePassphrase([]byte("passphrase")))
+	if err != nil {
+		t.Fatalf("Failed to setup credential manager: %s", err)
+	}
+
+	checkSigningIdentityFromMSPDir(mgr, t)
+}
+
 func checkSigningIdentityFromMSPDir(mgr *IdentityManager, t *testing.T) {
 	_, err := mgr.GetSigningIdentity("")
 	if err == nil {
@@ -29,8 +29,30 @@ type IdentityManager struct {
 	userStore       msp.UserStore
 }
 
+// IdentityManagerOption describes a functional parameter for NewIdentityManager
+type IdentityManagerOption func(*identityManagerOption) error
+
+type identityManagerOption struct {
+	keyStorePassphrase []byte
+}
+
+// WithKeyStorePassphrase sets the passphrase of the private keys read from the organization's crypto path,
+// keys are decrypted when loaded and plaintext PEM keys are loaded as is
+func WithKeyStorePassphrase(passphrase []byte) IdentityManagerOption {
+	return func(o *identityManagerOption) error {
+		o.keyStorePassphrase = passphrase
+		return nil
+	}
+}
+
 // NewIdentityManager creates a new instance of IdentityManager
-func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite core.CryptoSuite, endpointConfig fab.EndpointConfig) (*IdentityManager, error) {
+func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite core.CryptoSuite, endpointConfig fab.EndpointConfig, opts ...IdentityManagerOption) (*IdentityManager, error) {
+	options := identityManagerOption{}
+	for _, param := range opts {
+		if err := param(&options); err != nil {
+			return nil, errors.WithMessage(err, "failed to create identity manager")
+		}
+	}
 
 	netConfig := endpointConfig.NetworkConfig()
 	// viper keys are case insensitive
@@ -52,7 +74,11 @@ func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite cor
 		if !filepath.IsAbs(orgCryptoPathTemplate) {
 			orgCryptoPathTemplate = filepath.Join(endpointConfig.CryptoConfigPath(), orgCryptoPathTemplate)
 		}
-		mspPrivKeyStore, err = NewFileKeyStore(orgCryptoPathTemplate)
+		if len(options.keyStorePassphrase) > 0 {
+			mspPrivKeyStore, err = NewEncryptedFileKeyStore(orgCryptoPathTemplate, options.keyStorePassphrase)
+		} else {
+			mspPrivKeyStore, err = NewFileKeyStore(orgCryptoPathTemplate)
+		}
 		if err != nil {
 			return nil, errors.Wrap(err, "creating a private key store failed")
 		}
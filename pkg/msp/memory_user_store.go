@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"strings"
+
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
 )
 
@@ -40,3 +42,17 @@ func (s *MemoryUserStore) Load(id msp.IdentityIdentifier) (*msp.UserData, error)
 	}
 	return &userData, nil
 }
+
+// List returns all users stored in the store
+func (s *MemoryUserStore) List() ([]*msp.UserData, error) {
+	users := make([]*msp.UserData, 0, len(s.store))
+	for key, cert := range s.store {
+		i := strings.LastIndex(key, "@")
+		users = append(users, &msp.UserData{
+			ID:                    key[:i],
+			MSPID:                 key[i+1:],
+			EnrollmentCertificate: cert,
+		})
+	}
+	return users, nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	http "gitee.com/zhaochuninhefei/gmgo/gmhttp"
+	"github.com/pkg/errors"
+)
+
+// maximum size of a signing request or response body
+const maxBodySize = 64 * 1024
+
+// signRequestNet is the JSON body of a signing request. Binary values are base64 encoded.
+type signRequestNet struct {
+	KeyID  string `json:"key_id"`
+	Digest []byte `json:"digest"`
+}
+
+// signResponseNet is the JSON body of a signing response
+type signResponseNet struct {
+	Signature []byte `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HTTPOption describes a functional parameter for NewHTTPSigner
+type HTTPOption func(*HTTPSigner)
+
+// WithHTTPClient sets the HTTP client used to reach the signing service,
+// e.g. a client configured for (GM) TLS with a client certificate
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(s *HTTPSigner) {
+		s.client = client
+	}
+}
+
+// WithHeader adds a header that is sent with every signing request, e.g. an authorization token
+func WithHeader(key, value string) HTTPOption {
+	return func(s *HTTPSigner) {
+		s.header.Add(key, value)
+	}
+}
+
+// HTTPSigner is a reference Signer that reaches the signing service over HTTP.
+//
+// Each signing request is a POST to the service URL with a JSON body
+// {"key_id": "...", "digest": "<base64>"}. The service responds with 200 and
+// {"signature": "<base64>"}, or with an error status and {"error": "..."}.
+type HTTPSigner struct {
+	url    string
+	client *http.Client
+	header http.Header
+}
+
+// NewHTTPSigner returns a signer for the signing service at the given URL
+func NewHTTPSigner(url string, opts ...HTTPOption) *HTTPSigner {
+	s := &HTTPSigner{
+		url:    url,
+		client: http.DefaultClient,
+		header: make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Sign sends the signing request to the signing service
+func (s *HTTPSigner) Sign(ctx context.Context, request *SignRequest) ([]byte, error) {
+	body, err := json.Marshal(&signRequestNet{KeyID: request.KeyID, Digest: request.Digest})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal sign request")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create sign request")
+	}
+	req = req.WithContext(ctx)
+	for key, values := range s.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "sign request to %s failed", s.url)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := readBody(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read sign response")
+	}
+
+	var signResp signResponseNet
+	if err := json.Unmarshal(respBody, &signResp); err != nil {
+		return nil, errors.Wrapf(err, "invalid sign response from %s (status %d)", s.url, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("signing service returned status %d: %s", resp.StatusCode, signResp.Error)
+	}
+
+	return signResp.Signature, nil
+}
+
+// NewHTTPHandler returns an HTTP handler that serves signing requests sent by HTTPSigner
+// with the given signer. It can be used to expose a LocalSigner in tests or as the
+// starting point of a signing service.
+func NewHTTPHandler(signer Signer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeSignResponse(w, http.StatusMethodNotAllowed, &signResponseNet{Error: "method not allowed"})
+			return
+		}
+
+		body, err := readBody(r.Body)
+		if err != nil {
+			writeSignResponse(w, http.StatusBadRequest, &signResponseNet{Error: err.Error()})
+			return
+		}
+
+		var req signRequestNet
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeSignResponse(w, http.StatusBadRequest, &signResponseNet{Error: "invalid sign request"})
+			return
+		}
+
+		signature, err := signer.Sign(r.Context(), &SignRequest{KeyID: req.KeyID, Digest: req.Digest})
+		if err != nil {
+			writeSignResponse(w, http.StatusInternalServerError, &signResponseNet{Error: err.Error()})
+			return
+		}
+
+		writeSignResponse(w, http.StatusOK, &signResponseNet{Signature: signature})
+	})
+}
+
+func writeSignResponse(w http.ResponseWriter, statusCode int, resp *signResponseNet) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Warnf("failed to write sign response: %s", err)
+	}
+}
+
+// readBody reads at most maxBodySize bytes from the body
+func readBody(body io.Reader) ([]byte, error) {
+	raw, err := ioutil.ReadAll(io.LimitReader(body, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxBodySize {
+		return nil, errors.New("body too large")
+	}
+	return raw, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesigner
+
+import (
+	"context"
+	"sync"
+
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// LocalSigner is a Signer that signs with keys held by a local crypto suite.
+// It stands in for a signing service in tests and development setups.
+type LocalSigner struct {
+	cryptoSuite core.CryptoSuite
+	mutex       sync.RWMutex
+	keys        map[string]core.Key
+}
+
+// NewLocalSigner returns a local signer that signs with the given crypto suite
+func NewLocalSigner(cryptoSuite core.CryptoSuite) *LocalSigner {
+	return &LocalSigner{
+		cryptoSuite: cryptoSuite,
+		keys:        make(map[string]core.Key),
+	}
+}
+
+// AddKey registers a private key under the given key ID
+func (s *LocalSigner) AddKey(keyID string, key core.Key) error {
+	if key == nil || !key.Private() {
+		return errors.New("a private key is required")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keys[keyID] = key
+	return nil
+}
+
+// Sign signs the digest with the key registered under the requested key ID
+func (s *LocalSigner) Sign(ctx context.Context, request *SignRequest) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	key, ok := s.keys[request.KeyID]
+	s.mutex.RUnlock()
+
+	if !ok {
+		return nil, errors.Errorf("key [%s] not found", request.KeyID)
+	}
+	return s.cryptoSuite.Sign(key, request.Digest, nil)
+}
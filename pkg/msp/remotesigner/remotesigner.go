@@ -0,0 +1,241 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package remotesigner provides a signing identity whose private key is held by an
+// external signing service. The enrollment certificate is kept locally while every
+// signature is delegated to a Signer.
+//
+// The identity can be used wherever a msp.SigningIdentity is accepted, for example
+// with fabsdk.WithIdentity or gateway.WithSigningIdentity. Operations that need the
+// raw private key, such as Fabric CA requests, are not supported with a remote key.
+//
+//  Basic Flow:
+//  1) Create a Signer (e.g. NewHTTPSigner) for the signing service
+//  2) Create the identity with NewSigningIdentity using the local enrollment certificate
+//  3) Pass the identity to the SDK context or gateway
+package remotesigner
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	pb_msp "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/logging"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/config/cryptoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/msp")
+
+const defaultSignTimeout = 10 * time.Second
+
+// SignRequest is a request to sign a digest with the key identified by KeyID
+type SignRequest struct {
+	// KeyID identifies the key within the signing service
+	KeyID string
+	// Digest is the hash of the message to be signed
+	Digest []byte
+}
+
+// Signer signs digests with keys that are held by an external signing service
+type Signer interface {
+	Sign(ctx context.Context, request *SignRequest) ([]byte, error)
+}
+
+// Option describes a functional parameter for NewSigningIdentity
+type Option func(*options)
+
+type options struct {
+	keyID       string
+	signTimeout time.Duration
+}
+
+// WithKeyID sets the identifier of the key in the signing service.
+// By default the hex-encoded SKI of the certificate's public key is used.
+func WithKeyID(keyID string) Option {
+	return func(o *options) {
+		o.keyID = keyID
+	}
+}
+
+// WithSignTimeout sets the timeout of a single signing request (default 10s)
+func WithSignTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.signTimeout = timeout
+	}
+}
+
+// Key is a private key that is held by a remote signer. Only the public part
+// of the key is available locally.
+type Key struct {
+	publicKey core.Key
+	keyID     string
+	signer    Signer
+	timeout   time.Duration
+}
+
+// Bytes is not supported since the private key never leaves the signing service
+func (k *Key) Bytes() ([]byte, error) {
+	return nil, errors.New("not supported: private key is held by a remote signer")
+}
+
+// SKI returns the subject key identifier of this key
+func (k *Key) SKI() []byte {
+	return k.publicKey.SKI()
+}
+
+// Symmetric returns false since the key is asymmetric
+func (k *Key) Symmetric() bool {
+	return false
+}
+
+// Private returns true
+func (k *Key) Private() bool {
+	return true
+}
+
+// PublicKey returns the public part of the key
+func (k *Key) PublicKey() (core.Key, error) {
+	return k.publicKey, nil
+}
+
+// KeyID returns the identifier of the key in the signing service
+func (k *Key) KeyID() string {
+	return k.keyID
+}
+
+// Sign sends the digest to the remote signer
+func (k *Key) Sign(digest []byte, opts core.SignerOpts) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), k.timeout)
+	defer cancel()
+
+	signature, err := k.signer.Sign(ctx, &SignRequest{KeyID: k.keyID, Digest: digest})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "remote signing with key [%s] failed", k.keyID)
+	}
+	if len(signature) == 0 {
+		return nil, errors.Errorf("remote signer returned an empty signature for key [%s]", k.keyID)
+	}
+	return signature, nil
+}
+
+// SigningIdentity is a signing identity whose private key is held by a remote signer
+type SigningIdentity struct {
+	id                    string
+	mspID                 string
+	enrollmentCertificate []byte
+	key                   *Key
+	cryptoSuite           core.CryptoSuite
+}
+
+// NewSigningIdentity returns a signing identity for the given enrollment certificate (PEM)
+// which delegates signing to the given signer. The crypto suite is used to hash messages
+// and to verify signatures locally.
+func NewSigningIdentity(cryptoSuite core.CryptoSuite, mspID, id string, cert []byte, signer Signer, opts ...Option) (*SigningIdentity, error) {
+	if cryptoSuite == nil {
+		return nil, errors.New("crypto suite is required")
+	}
+	if mspID == "" || id == "" {
+		return nil, errors.New("MSP ID and ID are required")
+	}
+	if signer == nil {
+		return nil, errors.New("signer is required")
+	}
+
+	publicKey, err := cryptoutil.GetPublicKeyFromCert(cert, cryptoSuite)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get public key from certificate")
+	}
+
+	o := options{
+		keyID:       hex.EncodeToString(publicKey.SKI()),
+		signTimeout: defaultSignTimeout,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.signTimeout <= 0 {
+		return nil, errors.New("sign timeout must be greater than zero")
+	}
+
+	return &SigningIdentity{
+		id:                    id,
+		mspID:                 mspID,
+		enrollmentCertificate: cert,
+		cryptoSuite:           cryptoSuite,
+		key: &Key{
+			publicKey: publicKey,
+			keyID:     o.keyID,
+			signer:    signer,
+			timeout:   o.signTimeout,
+		},
+	}, nil
+}
+
+// Identifier returns the identifier of the identity
+func (i *SigningIdentity) Identifier() *msp.IdentityIdentifier {
+	return &msp.IdentityIdentifier{MSPID: i.mspID, ID: i.id}
+}
+
+// Verify a signature over some message using the public key of the enrollment certificate
+func (i *SigningIdentity) Verify(msg []byte, sig []byte) error {
+	digest, err := i.cryptoSuite.Hash(msg, cryptosuite.GetSignatureHashOpts(i.key.publicKey))
+	if err != nil {
+		return errors.WithMessage(err, "failed to hash message")
+	}
+
+	valid, err := i.cryptoSuite.Verify(i.key.publicKey, sig, digest, nil)
+	if err != nil {
+		return errors.WithMessage(err, "signature verification failed")
+	}
+	if !valid {
+		return errors.New("the signature is invalid")
+	}
+	return nil
+}
+
+// Serialize converts the identity to bytes
+func (i *SigningIdentity) Serialize() ([]byte, error) {
+	serializedIdentity := &pb_msp.SerializedIdentity{
+		Mspid:   i.mspID,
+		IdBytes: i.enrollmentCertificate,
+	}
+	identity, err := proto.Marshal(serializedIdentity)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal serializedIdentity failed")
+	}
+	return identity, nil
+}
+
+// EnrollmentCertificate returns the underlying ECert representing this identity
+func (i *SigningIdentity) EnrollmentCertificate() []byte {
+	return i.enrollmentCertificate
+}
+
+// PrivateKey returns the remote key. Signing with the SDK's signing manager
+// is delegated to the remote signer.
+func (i *SigningIdentity) PrivateKey() core.Key {
+	return i.key
+}
+
+// PublicVersion returns the public parts of this identity
+func (i *SigningIdentity) PublicVersion() msp.Identity {
+	return i
+}
+
+// Sign hashes the message and has the digest signed by the remote signer
+func (i *SigningIdentity) Sign(msg []byte) ([]byte, error) {
+	digest, err := i.cryptoSuite.Hash(msg, cryptosuite.GetSignatureHashOpts(i.key.publicKey))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to hash message")
+	}
+	return i.key.Sign(digest, nil)
+}
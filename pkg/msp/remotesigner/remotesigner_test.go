@@ -0,0 +1,229 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesigner
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	pb_msp "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/core"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/core/cryptosuite/bccsp/sw"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/signingmgr"
+	http "gitee.com/zhaochuninhefei/gmgo/gmhttp"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMSPID = "Org1MSP"
+	testID    = "User1"
+)
+
+var testMsg = []byte("Hello remote signer")
+
+// newTestKey generates a key in the crypto suite and returns it with a certificate for its public key
+func newTestKey(t *testing.T, cs core.CryptoSuite) (core.Key, []byte) {
+	key, err := cs.KeyGen(cryptosuite.GetSM2KeyGenOpts(true))
+	require.NoError(t, err)
+
+	pubKey, err := key.PublicKey()
+	require.NoError(t, err)
+	raw, err := pubKey.Bytes()
+	require.NoError(t, err)
+	pub, err := x509.ParsePKIXPublicKey(raw)
+	require.NoError(t, err)
+
+	caKey, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	now := time.Now()
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca.org1.example.com"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		SignatureAlgorithm:    x509.SM2WithSM3,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	template := &x509.Certificate{
+		SerialNumber:       big.NewInt(2),
+		Subject:            pkix.Name{CommonName: testID},
+		NotBefore:          now.Add(-time.Hour),
+		NotAfter:           now.Add(time.Hour),
+		SignatureAlgorithm: x509.SM2WithSM3,
+		KeyUsage:           x509.KeyUsageDigitalSignature,
+	}
+	certRaw, err := x509.CreateCertificate(rand.Reader, template, caTemplate, pub, caKey)
+	require.NoError(t, err)
+
+	return key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certRaw})
+}
+
+func newTestSuite(t *testing.T) core.CryptoSuite {
+	cs, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+	return cs
+}
+
+func TestSigningIdentity(t *testing.T) {
+	cs := newTestSuite(t)
+	key, cert := newTestKey(t, cs)
+
+	signer := NewLocalSigner(cs)
+	require.NoError(t, signer.AddKey(hex.EncodeToString(key.SKI()), key))
+
+	id, err := NewSigningIdentity(cs, testMSPID, testID, cert, signer)
+	require.NoError(t, err)
+
+	assert.Equal(t, testMSPID, id.Identifier().MSPID)
+	assert.Equal(t, testID, id.Identifier().ID)
+	assert.Equal(t, cert, id.EnrollmentCertificate())
+	assert.Equal(t, id, id.PublicVersion())
+
+	serialized, err := id.Serialize()
+	require.NoError(t, err)
+	sID := &pb_msp.SerializedIdentity{}
+	require.NoError(t, proto.Unmarshal(serialized, sID))
+	assert.Equal(t, testMSPID, sID.Mspid)
+	assert.Equal(t, cert, sID.IdBytes)
+
+	privateKey := id.PrivateKey()
+	assert.True(t, privateKey.Private())
+	assert.False(t, privateKey.Symmetric())
+	assert.Equal(t, key.SKI(), privateKey.SKI())
+	_, err = privateKey.Bytes()
+	assert.Error(t, err, "private key should not be exportable")
+
+	sig, err := id.Sign(testMsg)
+	require.NoError(t, err)
+	assert.NoError(t, id.Verify(testMsg, sig))
+	assert.Error(t, id.Verify([]byte("other message"), sig))
+}
+
+func TestSigningIdentityWithSigningManager(t *testing.T) {
+	cs := newTestSuite(t)
+	key, cert := newTestKey(t, cs)
+
+	signer := NewLocalSigner(cs)
+	require.NoError(t, signer.AddKey("user1-key", key))
+
+	id, err := NewSigningIdentity(cs, testMSPID, testID, cert, signer, WithKeyID("user1-key"))
+	require.NoError(t, err)
+	assert.Equal(t, "user1-key", id.PrivateKey().(*Key).KeyID())
+
+	signingMgr, err := signingmgr.New(cs)
+	require.NoError(t, err)
+
+	sig, err := signingMgr.Sign(testMsg, id.PrivateKey())
+	require.NoError(t, err)
+	assert.NoError(t, id.Verify(testMsg, sig))
+}
+
+func TestSigningIdentityUnknownKey(t *testing.T) {
+	cs := newTestSuite(t)
+	_, cert := newTestKey(t, cs)
+
+	id, err := NewSigningIdentity(cs, testMSPID, testID, cert, NewLocalSigner(cs))
+	require.NoError(t, err)
+
+	_, err = id.Sign(testMsg)
+	assert.Error(t, err)
+}
+
+func TestHTTPSigner(t *testing.T) {
+	cs := newTestSuite(t)
+	key, cert := newTestKey(t, cs)
+
+	local := NewLocalSigner(cs)
+	require.NoError(t, local.AddKey(hex.EncodeToString(key.SKI()), key))
+
+	handler := NewHTTPHandler(local)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"unauthorized"}`))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})}
+	go server.Serve(listener) // nolint: errcheck
+	defer server.Close()
+
+	url := "http://" + listener.Addr().String() + "/sign"
+
+	id, err := NewSigningIdentity(cs, testMSPID, testID, cert, NewHTTPSigner(url, WithHeader("Authorization", "Bearer token")))
+	require.NoError(t, err)
+
+	sig, err := id.Sign(testMsg)
+	require.NoError(t, err)
+	assert.NoError(t, id.Verify(testMsg, sig))
+
+	unauthorized, err := NewSigningIdentity(cs, testMSPID, testID, cert, NewHTTPSigner(url, WithHTTPClient(&http.Client{})))
+	require.NoError(t, err)
+	_, err = unauthorized.Sign(testMsg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+
+	unknownKey, err := NewSigningIdentity(cs, testMSPID, testID, cert, NewHTTPSigner(url, WithHeader("Authorization", "Bearer token")), WithKeyID("unknown"))
+	require.NoError(t, err)
+	_, err = unknownKey.Sign(testMsg)
+	assert.Error(t, err)
+}
+
+func TestLocalSignerCancelled(t *testing.T) {
+	cs := newTestSuite(t)
+	key, _ := newTestKey(t, cs)
+
+	signer := NewLocalSigner(cs)
+	require.NoError(t, signer.AddKey("key", key))
+
+	pubKey, err := key.PublicKey()
+	require.NoError(t, err)
+	assert.Error(t, signer.AddKey("pub", pubKey), "public keys cannot sign")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = signer.Sign(ctx, &SignRequest{KeyID: "key", Digest: []byte("digest")})
+	assert.Error(t, err)
+}
+
+func TestNewSigningIdentityErrors(t *testing.T) {
+	cs := newTestSuite(t)
+	_, cert := newTestKey(t, cs)
+	signer := NewLocalSigner(cs)
+
+	_, err := NewSigningIdentity(nil, testMSPID, testID, cert, signer)
+	assert.Error(t, err)
+
+	_, err = NewSigningIdentity(cs, "", testID, cert, signer)
+	assert.Error(t, err)
+
+	_, err = NewSigningIdentity(cs, testMSPID, testID, cert, nil)
+	assert.Error(t, err)
+
+	_, err = NewSigningIdentity(cs, testMSPID, testID, []byte("invalid"), signer)
+	assert.Error(t, err)
+
+	_, err = NewSigningIdentity(cs, testMSPID, testID, cert, signer, WithSignTimeout(0))
+	assert.Error(t, err)
+}
@@ -7,10 +7,17 @@ SPDX-License-Identifier: Apache-2.0
 package mockmsp
 
 import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
 	"net"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
 	http "gitee.com/zhaochuninhefei/gmgo/gmhttp"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
 
 	cfsslapi "gitee.com/zhaochuninhefei/cfssl-gm/api"
 	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-ca-gm/lib"
@@ -44,6 +51,10 @@ AiaiI2BjxnL3/TetJ8iFJYZyWvK//an13WV/AiARBJd/pI5A7KZgQxJhXmmR8bie
 XdsmTcdRvJ3TS/6HCA==
 -----END CERTIFICATE-----`
 
+const mockCRL = `-----BEGIN X509 CRL-----
+MockCRL
+-----END X509 CRL-----`
+
 var logger = logging.NewLogger("fabsdk/msp")
 
 // The enrollment response from the server
@@ -62,11 +73,30 @@ type serverInfoResponseNet struct {
 	CAChain string
 }
 
+// The response to the POST /gencrl request
+type genCRLResponseNet struct {
+	// Base64 encoding of PEM-encoded CRL
+	CRL string
+}
+
+// The response to the GET /certificates request
+type certificatesResponseNet struct {
+	Certs []certPEM `json:"certs"`
+}
+
+type certPEM struct {
+	PEM string
+}
+
 // MockFabricCAServer is a mock for FabricCAServer
 type MockFabricCAServer struct {
 	address     string
 	cryptoSuite core.CryptoSuite
 	running     bool
+
+	mutex             sync.RWMutex
+	certificatesQuery url.Values
+	reenrollPublicKey []byte
 }
 
 // Start fabric CA mock server
@@ -84,13 +114,15 @@ func (s *MockFabricCAServer) Start(lis net.Listener, cryptoSuite core.CryptoSuit
 
 	http.HandleFunc("/register", s.register)
 	http.HandleFunc("/enroll", s.enroll)
-	http.HandleFunc("/reenroll", s.enroll)
+	http.HandleFunc("/reenroll", s.reenroll)
 	http.HandleFunc("/revoke", s.revoke)
 	http.HandleFunc("/identities", s.identities)
 	http.HandleFunc("/identities/123", s.identity)
 	http.HandleFunc("/affiliations", s.affiliations)
 	http.HandleFunc("/affiliations/123", s.affiliation)
 	http.HandleFunc("/cainfo", s.cainfo)
+	http.HandleFunc("/certificates", s.certificates)
+	http.HandleFunc("/gencrl", s.gencrl)
 
 	server := &http.Server{
 		Addr:      addr,
@@ -115,6 +147,20 @@ func (s *MockFabricCAServer) Running() bool {
 	return s.running
 }
 
+// CertificatesQuery returns the query parameters of the last GET /certificates request
+func (s *MockFabricCAServer) CertificatesQuery() url.Values {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.certificatesQuery
+}
+
+// ReenrollPublicKey returns the DER-encoded public key of the CSR sent with the last POST /reenroll request
+func (s *MockFabricCAServer) ReenrollPublicKey() []byte {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.reenrollPublicKey
+}
+
 func (s *MockFabricCAServer) addKeyToKeyStore(privateKey []byte) error {
 	// Import private key that matches the cert we will return
 	// from this mock service, so it can be looked up by SKI from the cert
@@ -138,6 +184,73 @@ func (s *MockFabricCAServer) revoke(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// Generate CRL
+func (s *MockFabricCAServer) gencrl(w http.ResponseWriter, req *http.Request) {
+	resp := &genCRLResponseNet{CRL: util.B64Encode([]byte(mockCRL))}
+	if err := cfsslapi.SendResponse(w, resp); err != nil {
+		logger.Error(err)
+	}
+}
+
+// List certificates
+// The query parameters are validated like the Fabric CA server does. The mock holds no revoked
+// certificates, so a request for certificates revoked within a time range returns none.
+func (s *MockFabricCAServer) certificates(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	s.mutex.Lock()
+	s.certificatesQuery = query
+	s.mutex.Unlock()
+
+	if err := validateCertificatesQuery(query); err != nil {
+		sendBadRequest(w, err.Error())
+		return
+	}
+
+	resp := &certificatesResponseNet{Certs: []certPEM{{PEM: ecert}}}
+	if query.Get("revoked_start") != "" || query.Get("revoked_end") != "" {
+		resp.Certs = []certPEM{}
+	}
+	if err := cfsslapi.SendResponse(w, resp); err != nil {
+		logger.Error(err)
+	}
+}
+
+func validateCertificatesQuery(query url.Values) error {
+	for _, param := range []string{"notrevoked", "notexpired"} {
+		if v := query.Get(param); v != "" {
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("invalid value for %s: %s", param, v)
+			}
+		}
+	}
+	for _, param := range []string{"revoked_start", "revoked_end", "expired_start", "expired_end"} {
+		if v := query.Get(param); v != "" {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				return fmt.Errorf("invalid time format for %s: %s", param, v)
+			}
+		}
+	}
+	if query.Get("notrevoked") == "true" && (query.Get("revoked_start") != "" || query.Get("revoked_end") != "") {
+		return fmt.Errorf("cannot specify revoked time range and notrevoked")
+	}
+	if query.Get("notexpired") == "true" && (query.Get("expired_start") != "" || query.Get("expired_end") != "") {
+		return fmt.Errorf("cannot specify expired time range and notexpired")
+	}
+	return nil
+}
+
+func sendBadRequest(w http.ResponseWriter, message string) {
+	resp := cfsslapi.Response{
+		Success:  false,
+		Errors:   []cfsslapi.ResponseMessage{{Code: http.StatusBadRequest, Message: message}},
+		Messages: []cfsslapi.ResponseMessage{},
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Error(err)
+	}
+}
+
 // Enroll user
 func (s *MockFabricCAServer) enroll(w http.ResponseWriter, req *http.Request) {
 	if err := s.addKeyToKeyStore([]byte(privateKey)); err != nil {
@@ -150,6 +263,33 @@ func (s *MockFabricCAServer) enroll(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// Reenroll user
+// The public key of the CSR is recorded so that tests can check which key the new certificate was requested for.
+func (s *MockFabricCAServer) reenroll(w http.ResponseWriter, req *http.Request) {
+	var reqNet struct {
+		Request string `json:"certificate_request"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&reqNet); err != nil {
+		sendBadRequest(w, fmt.Sprintf("invalid reenroll request: %s", err))
+		return
+	}
+	block, _ := pem.Decode([]byte(reqNet.Request))
+	if block == nil {
+		sendBadRequest(w, "no PEM data found in certificate request")
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		sendBadRequest(w, fmt.Sprintf("invalid certificate request: %s", err))
+		return
+	}
+	s.mutex.Lock()
+	s.reenrollPublicKey = csr.RawSubjectPublicKeyInfo
+	s.mutex.Unlock()
+
+	s.enroll(w, req)
+}
+
 // Fill the CA info structure appropriately
 func fillCAInfo(info *serverInfoResponseNet) {
 	info.CAName = "MockCAName"
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	msp "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/msp"
 	api "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/msp/api"
 )
 
@@ -78,6 +79,21 @@ func (mr *MockCAClientMockRecorder) Enroll(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockCAClient)(nil).Enroll), arg0)
 }
 
+// GenCRL mocks base method
+func (m *MockCAClient) GenCRL(arg0 *api.GenCRLRequest) (*api.GenCRLResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenCRL", arg0)
+	ret0, _ := ret[0].(*api.GenCRLResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenCRL indicates an expected call of GenCRL
+func (mr *MockCAClientMockRecorder) GenCRL(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenCRL", reflect.TypeOf((*MockCAClient)(nil).GenCRL), arg0)
+}
+
 // GetAffiliation mocks base method
 func (m *MockCAClient) GetAffiliation(arg0, arg1 string) (*api.AffiliationResponse, error) {
 	m.ctrl.T.Helper()
@@ -138,6 +154,21 @@ func (mr *MockCAClientMockRecorder) GetCAInfo() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCAInfo", reflect.TypeOf((*MockCAClient)(nil).GetCAInfo))
 }
 
+// GetCertificates mocks base method
+func (m *MockCAClient) GetCertificates(arg0 *api.GetCertificatesRequest) (*api.GetCertificatesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCertificates", arg0)
+	ret0, _ := ret[0].(*api.GetCertificatesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCertificates indicates an expected call of GetCertificates
+func (mr *MockCAClientMockRecorder) GetCertificates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCertificates", reflect.TypeOf((*MockCAClient)(nil).GetCertificates), arg0)
+}
+
 // GetIdentity mocks base method
 func (m *MockCAClient) GetIdentity(arg0, arg1 string) (*api.IdentityResponse, error) {
 	m.ctrl.T.Helper()
@@ -197,6 +228,21 @@ func (mr *MockCAClientMockRecorder) Reenroll(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reenroll", reflect.TypeOf((*MockCAClient)(nil).Reenroll), arg0)
 }
 
+// ReenrollIdentity mocks base method
+func (m *MockCAClient) ReenrollIdentity(arg0 msp.SigningIdentity, arg1 *api.ReenrollmentRequest) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReenrollIdentity", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReenrollIdentity indicates an expected call of ReenrollIdentity
+func (mr *MockCAClientMockRecorder) ReenrollIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReenrollIdentity", reflect.TypeOf((*MockCAClient)(nil).ReenrollIdentity), arg0, arg1)
+}
+
 // Register mocks base method
 func (m *MockCAClient) Register(arg0 *api.RegistrationRequest) (string, error) {
 	m.ctrl.T.Helper()
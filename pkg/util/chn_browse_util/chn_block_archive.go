@@ -0,0 +1,412 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+/*
+pkg/util/chn_browse_util/chn_block_archive.go 区块离线归档，将区块按长度前缀的protobuf格式写入区块文件，
+同时将交易情报按JSON Lines格式写入交易文件，并提供无需网络的归档读取。
+
+区块文件格式: 每条记录为 uvarint(区块protobuf字节长度) + 区块protobuf字节。
+*/
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/ledger"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"github.com/golang/protobuf/proto"
+)
+
+// 单个区块记录的最大字节数，用于识别损坏的长度前缀
+const maxArchiveRecordSize = 1 << 30
+
+// BlockArchiveWriter 区块归档写入器
+type BlockArchiveWriter struct {
+	blockFile    *os.File
+	blockWriter  *bufio.Writer
+	txFile       *os.File
+	txWriter     *bufio.Writer
+	hasLast      bool
+	lastBlockNum uint64
+	lastHash     []byte
+}
+
+// NewBlockArchiveWriter 创建区块归档写入器
+//  入参: blockFilePath 区块文件路径
+//  入参: txFilePath 交易情报JSON Lines文件路径，为空时不输出交易情报
+//  入参: appendMode 是否追加模式。追加模式下会丢弃文件末尾不完整的记录，并从已归档的最后区块继续写入。
+//  返回: BlockArchiveWriter
+func NewBlockArchiveWriter(blockFilePath string, txFilePath string, appendMode bool) (*BlockArchiveWriter, error) {
+	flag := os.O_CREATE | os.O_RDWR
+	if !appendMode {
+		flag |= os.O_TRUNC
+	}
+	blockFile, err := os.OpenFile(blockFilePath, flag, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open block file: %s", err)
+	}
+	w := &BlockArchiveWriter{
+		blockFile: blockFile,
+	}
+	if appendMode {
+		if err := w.recoverBlockFile(); err != nil {
+			blockFile.Close()
+			return nil, err
+		}
+	}
+	if txFilePath != "" {
+		txFile, err := os.OpenFile(txFilePath, flag, 0640)
+		if err != nil {
+			blockFile.Close()
+			return nil, fmt.Errorf("failed to open tx file: %s", err)
+		}
+		w.txFile = txFile
+		if appendMode {
+			if err := w.recoverTxFile(); err != nil {
+				w.Close()
+				return nil, err
+			}
+		}
+		w.txWriter = bufio.NewWriter(txFile)
+	}
+	w.blockWriter = bufio.NewWriter(blockFile)
+	return w, nil
+}
+
+// 扫描区块文件，定位最后一条完整记录并截断其后的残留数据
+func (w *BlockArchiveWriter) recoverBlockFile() error {
+	if _, err := w.blockFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	reader := NewBlockArchiveReader(w.blockFile)
+	var validSize int64
+	for {
+		block, err := reader.Next()
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// 末尾记录不完整时，以最后一条完整记录为准
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read block file at offset %d: %s", reader.Offset(), err)
+		}
+		validSize = reader.Offset()
+		w.hasLast = true
+		w.lastBlockNum = block.Header.Number
+		w.lastHash = protoutil.BlockHeaderHash(block.Header)
+	}
+	if err := w.blockFile.Truncate(validSize); err != nil {
+		return fmt.Errorf("failed to truncate block file: %s", err)
+	}
+	_, err := w.blockFile.Seek(validSize, io.SeekStart)
+	return err
+}
+
+// 扫描交易情报文件，丢弃不完整的行以及尚未归档区块的交易情报
+func (w *BlockArchiveWriter) recoverTxFile() error {
+	if _, err := w.txFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(w.txFile)
+	var validSize int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		txInfo := &TransactionInfo{}
+		if json.Unmarshal(line, txInfo) != nil {
+			break
+		}
+		if !w.hasLast || txInfo.BlockNum > w.lastBlockNum {
+			break
+		}
+		validSize += int64(len(line))
+	}
+	if err := w.txFile.Truncate(validSize); err != nil {
+		return fmt.Errorf("failed to truncate tx file: %s", err)
+	}
+	_, err := w.txFile.Seek(validSize, io.SeekStart)
+	return err
+}
+
+// LastBlockNum 返回已归档的最后区块编号，ok为false时表示归档为空
+func (w *BlockArchiveWriter) LastBlockNum() (blockNum uint64, ok bool) {
+	return w.lastBlockNum, w.hasLast
+}
+
+// WriteBlock 写入一个区块。
+//  区块编号必须紧接已归档的最后区块，且前区块哈希必须与最后区块的区块头哈希一致。
+//  区块来源可以是`ledger.Client.QueryBlock`，也可以是事件客户端收到的区块事件。
+func (w *BlockArchiveWriter) WriteBlock(block *common.Block) error {
+	if block == nil || block.Header == nil {
+		return fmt.Errorf("block or block header is nil")
+	}
+	if w.hasLast {
+		if block.Header.Number != w.lastBlockNum+1 {
+			return fmt.Errorf("block number %d does not follow the last archived block %d", block.Header.Number, w.lastBlockNum)
+		}
+		if !bytes.Equal(block.Header.PreviousHash, w.lastHash) {
+			return fmt.Errorf("previous hash of block %d does not match the header hash of the last archived block", block.Header.Number)
+		}
+	}
+	blockBytes, err := proto.Marshal(block)
+	if err != nil {
+		return fmt.Errorf("failed to marshal block: %s", err)
+	}
+	headerHash := protoutil.BlockHeaderHash(block.Header)
+	// 先写交易情报，再写区块；追加模式恢复时以区块文件为准截断交易情报文件
+	if w.txWriter != nil {
+		blockInfo, err := UnmarshalBlockData(block, headerHash)
+		if err != nil {
+			return fmt.Errorf("failed to UnmarshalBlockData: %s", err)
+		}
+		for _, txInfo := range blockInfo.TransactionInfos {
+			line, err := json.Marshal(txInfo)
+			if err != nil {
+				return fmt.Errorf("failed to marshal transaction info: %s", err)
+			}
+			if _, err := w.txWriter.Write(append(line, '\n')); err != nil {
+				return err
+			}
+		}
+		if err := w.txWriter.Flush(); err != nil {
+			return err
+		}
+	}
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(blockBytes)))
+	if _, err := w.blockWriter.Write(prefix[:n]); err != nil {
+		return err
+	}
+	if _, err := w.blockWriter.Write(blockBytes); err != nil {
+		return err
+	}
+	if err := w.blockWriter.Flush(); err != nil {
+		return err
+	}
+	w.hasLast = true
+	w.lastBlockNum = block.Header.Number
+	w.lastHash = headerHash
+	return nil
+}
+
+// Close 落盘并关闭归档文件
+func (w *BlockArchiveWriter) Close() error {
+	var firstErr error
+	if w.blockFile != nil {
+		if w.blockWriter != nil {
+			firstErr = w.blockWriter.Flush()
+		}
+		if err := w.blockFile.Sync(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := w.blockFile.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if w.txFile != nil {
+		if w.txWriter != nil {
+			if err := w.txWriter.Flush(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		if err := w.txFile.Sync(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := w.txFile.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ExportBlocks 从账本导出区块到归档。
+//  追加模式下从已归档的最后区块的下一个区块开始导出，直到最新区块。
+//  入参: querier 区块查询接口，通常为`*ledger.Client`
+//  入参: writer 区块归档写入器
+//  返回: 本次导出的区块数量
+func ExportBlocks(querier BlockQuerier, writer *BlockArchiveWriter) (uint64, error) {
+	blockChainInfo, err := querier.QueryInfo()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get blockInfo: %s", err)
+	}
+	height := blockChainInfo.BCI.Height
+	start := firstBlockNumOf(querier)
+	if last, ok := writer.LastBlockNum(); ok {
+		start = last + 1
+	}
+	if start >= height {
+		return 0, nil
+	}
+	return ExportBlockRange(querier, writer, start, height-1)
+}
+
+// ExportBlockRange 从账本导出指定范围(包含两端)的区块到归档。
+//  入参: querier 区块查询接口，通常为`*ledger.Client`
+//  入参: writer 区块归档写入器
+//  入参: start 起始区块编号
+//  入参: end 结束区块编号
+//  返回: 本次导出的区块数量
+func ExportBlockRange(querier BlockQuerier, writer *BlockArchiveWriter, start, end uint64) (uint64, error) {
+	if start > end {
+		return 0, fmt.Errorf("start block number %d is greater than end block number %d", start, end)
+	}
+	var cnt uint64
+	for curBlockNum := start; curBlockNum <= end; curBlockNum++ {
+		block, err := querier.QueryBlock(curBlockNum)
+		if err != nil {
+			return cnt, fmt.Errorf("failed to QueryBlock: %s", err)
+		}
+		if err := writer.WriteBlock(block); err != nil {
+			return cnt, err
+		}
+		cnt++
+	}
+	return cnt, nil
+}
+
+// BlockArchiveReader 区块归档读取器，无需连接网络
+type BlockArchiveReader struct {
+	reader *bufio.Reader
+	offset int64
+}
+
+// NewBlockArchiveReader 创建区块归档读取器
+func NewBlockArchiveReader(r io.Reader) *BlockArchiveReader {
+	return &BlockArchiveReader{
+		reader: bufio.NewReader(r),
+	}
+}
+
+// Offset 返回已读取的完整记录的字节数
+func (r *BlockArchiveReader) Offset() int64 {
+	return r.offset
+}
+
+// Next 读取下一个区块，读取完毕时返回io.EOF，末尾记录不完整时返回io.ErrUnexpectedEOF。
+func (r *BlockArchiveReader) Next() (*common.Block, error) {
+	size, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	if size > maxArchiveRecordSize {
+		return nil, fmt.Errorf("invalid block record size: %d", size)
+	}
+	blockBytes := make([]byte, size)
+	if _, err := io.ReadFull(r.reader, blockBytes); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	block := &common.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal block: %s", err)
+	}
+	if block.Header == nil {
+		return nil, fmt.Errorf("block header is nil")
+	}
+	var prefix [binary.MaxVarintLen64]byte
+	r.offset += int64(binary.PutUvarint(prefix[:], size)) + int64(size)
+	return block, nil
+}
+
+// NextBlockInfo 读取下一个区块并反序列化为区块情报，读取完毕时返回io.EOF。
+func (r *BlockArchiveReader) NextBlockInfo() (*BlockInfoWithTx, error) {
+	block, err := r.Next()
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalBlockData(block, protoutil.BlockHeaderHash(block.Header))
+}
+
+// ArchiveBlockQuerier 基于区块归档文件的区块查询实现，
+// 可以代替`*ledger.Client`传给NewBlockIterator、VerifyBlockChain等函数离线使用。
+// 实现了FirstBlockQuerier，归档不是从0号区块开始时，迭代器与校验器从归档的第一个区块开始。
+type ArchiveBlockQuerier struct {
+	file        *os.File
+	offsets     []int64
+	firstNum    uint64
+	currentHash []byte
+}
+
+// OpenArchiveBlockQuerier 打开区块归档文件并建立区块偏移索引
+func OpenArchiveBlockQuerier(blockFilePath string) (*ArchiveBlockQuerier, error) {
+	file, err := os.Open(blockFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open block file: %s", err)
+	}
+	q := &ArchiveBlockQuerier{file: file}
+	reader := NewBlockArchiveReader(file)
+	for {
+		offset := reader.Offset()
+		block, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read block file at offset %d: %s", offset, err)
+		}
+		if len(q.offsets) == 0 {
+			q.firstNum = block.Header.Number
+		} else if block.Header.Number != q.firstNum+uint64(len(q.offsets)) {
+			file.Close()
+			return nil, fmt.Errorf("block file is not continuous at block %d", block.Header.Number)
+		}
+		q.offsets = append(q.offsets, offset)
+		q.currentHash = protoutil.BlockHeaderHash(block.Header)
+	}
+	return q, nil
+}
+
+// FirstBlockNum 返回归档的第一个区块编号，归档可以从任意区块开始
+func (q *ArchiveBlockQuerier) FirstBlockNum() uint64 {
+	return q.firstNum
+}
+
+// QueryInfo 返回归档的区块高度(最后区块编号+1)与最后区块头哈希，
+// 归档的第一个区块编号通过FirstBlockNum获取。
+func (q *ArchiveBlockQuerier) QueryInfo(options ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error) {
+	var height uint64
+	if len(q.offsets) > 0 {
+		height = q.firstNum + uint64(len(q.offsets))
+	}
+	return &fab.BlockchainInfoResponse{
+		BCI: &common.BlockchainInfo{
+			Height:           height,
+			CurrentBlockHash: q.currentHash,
+		},
+	}, nil
+}
+
+// QueryBlock 从归档读取指定编号的区块
+func (q *ArchiveBlockQuerier) QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*common.Block, error) {
+	if len(q.offsets) == 0 || blockNumber < q.firstNum || blockNumber-q.firstNum >= uint64(len(q.offsets)) {
+		return nil, fmt.Errorf("block %d is not in the archive", blockNumber)
+	}
+	offset := q.offsets[blockNumber-q.firstNum]
+	reader := NewBlockArchiveReader(io.NewSectionReader(q.file, offset, 1<<62))
+	return reader.Next()
+}
+
+// Close 关闭归档文件
+func (q *ArchiveBlockQuerier) Close() error {
+	return q.file.Close()
+}
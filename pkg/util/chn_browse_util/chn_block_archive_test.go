@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockArchiveAppend(t *testing.T) {
+	dir := t.TempDir()
+	blockFilePath := filepath.Join(dir, "mychannel.blocks")
+	txFilePath := filepath.Join(dir, "mychannel.jsonl")
+	q := newMockBlockQuerier(5)
+
+	writer, err := NewBlockArchiveWriter(blockFilePath, txFilePath, false)
+	require.NoError(t, err)
+	cnt, err := ExportBlockRange(q, writer, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), cnt)
+	// 区块编号不连续时拒绝写入
+	assert.Error(t, writer.WriteBlock(q.blocks[4]))
+	require.NoError(t, writer.Close())
+
+	// 模拟写入中断，在文件末尾留下不完整的记录
+	f, err := os.OpenFile(blockFilePath, os.O_APPEND|os.O_WRONLY, 0640)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0x20, 0x01})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	writer, err = NewBlockArchiveWriter(blockFilePath, txFilePath, true)
+	require.NoError(t, err)
+	last, ok := writer.LastBlockNum()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), last)
+	cnt, err = ExportBlocks(q, writer)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), cnt)
+	require.NoError(t, writer.Close())
+
+	f, err = os.Open(blockFilePath)
+	require.NoError(t, err)
+	defer f.Close()
+	reader := NewBlockArchiveReader(f)
+	var nums []uint64
+	for {
+		blockInfo, err := reader.NextBlockInfo()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		nums = append(nums, blockInfo.BlockNum)
+	}
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, nums)
+}
+
+func TestArchiveBlockQuerier(t *testing.T) {
+	blockFilePath := filepath.Join(t.TempDir(), "mychannel.blocks")
+	q := newMockBlockQuerier(4)
+
+	writer, err := NewBlockArchiveWriter(blockFilePath, "", false)
+	require.NoError(t, err)
+	_, err = ExportBlocks(q, writer)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	archive, err := OpenArchiveBlockQuerier(blockFilePath)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	info, err := archive.QueryInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), info.BCI.Height)
+
+	block, err := archive.QueryBlock(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), block.Header.Number)
+	_, err = archive.QueryBlock(4)
+	assert.Error(t, err)
+
+	// 归档可以直接用于离线校验
+	report, err := VerifyBlockChain(archive)
+	require.NoError(t, err)
+	assert.True(t, report.Passed)
+}
+
+func TestArchiveBlockQuerierNotFromGenesis(t *testing.T) {
+	blockFilePath := filepath.Join(t.TempDir(), "mychannel.blocks")
+	q := newMockBlockQuerier(5)
+
+	// 归档从区块2开始
+	writer, err := NewBlockArchiveWriter(blockFilePath, "", false)
+	require.NoError(t, err)
+	_, err = ExportBlockRange(q, writer, 2, 4)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	archive, err := OpenArchiveBlockQuerier(blockFilePath)
+	require.NoError(t, err)
+	defer archive.Close()
+	assert.Equal(t, uint64(2), archive.FirstBlockNum())
+	info, err := archive.QueryInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), info.BCI.Height)
+
+	report, err := VerifyBlockChain(archive)
+	require.NoError(t, err)
+	assert.True(t, report.Passed)
+	assert.Equal(t, uint64(2), report.StartBlockNum)
+	assert.Equal(t, uint64(3), report.VerifiedCnt)
+	_, err = VerifyBlockChain(archive, WithVerifyBlockRange(1, 4))
+	assert.Error(t, err)
+
+	it, err := NewBlockIterator(archive, WithDirection(BrowseForward))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4}, collectBlockNums(t, it))
+	it, err = NewBlockIterator(archive)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{4, 3, 2}, collectBlockNums(t, it))
+}
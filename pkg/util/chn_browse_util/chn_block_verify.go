@@ -0,0 +1,365 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+/*
+pkg/util/chn_browse_util/chn_block_verify.go 区块哈希链完整性校验，重新计算SM3区块头哈希与区块数据哈希，并校验前区块哈希链接与排序节点签名。
+*/
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+)
+
+// 区块校验问题类型
+const (
+	// IssueDataHashMismatch 区块数据哈希与区块头记录的DataHash不一致
+	IssueDataHashMismatch = 1
+	// IssuePreviousHashMismatch 区块头记录的前区块哈希与前区块实际哈希不一致
+	IssuePreviousHashMismatch = 2
+	// IssueSignatureMissing 区块元数据中没有排序节点签名
+	IssueSignatureMissing = 3
+	// IssueSignatureInvalid 区块元数据中的排序节点签名无效
+	IssueSignatureInvalid = 4
+	// IssueCurrentHashMismatch 最新区块哈希与账本信息记录的当前区块哈希不一致
+	IssueCurrentHashMismatch = 5
+	// IssueBlockNumMismatch 查询到的区块编号与请求的区块编号不一致
+	IssueBlockNumMismatch = 6
+	// IssueHeaderMissing 区块缺少区块头
+	IssueHeaderMissing = 7
+	// IssueDataMissing 区块缺少区块数据
+	IssueDataMissing = 8
+)
+
+// SignatureVerifier 签名校验接口，`fab.ChannelMembership`实现了该接口。
+type SignatureVerifier interface {
+	Verify(serializedID []byte, msg []byte, sig []byte) error
+}
+
+// BlockVerifyIssue 区块校验问题
+type BlockVerifyIssue struct {
+	BlockNum  uint64 // 出现问题的区块编号
+	IssueType int    // 问题类型
+	Expected  string // 期望值
+	Actual    string // 实际值
+	Detail    string // 问题说明
+}
+
+func (t *BlockVerifyIssue) ToString() string {
+	return fmt.Sprintf("区块编号: %d, 问题类型: %s, 期望值: %s, 实际值: %s, 说明: %s",
+		t.BlockNum, FormatIssueType(t.IssueType), t.Expected, t.Actual, t.Detail)
+}
+
+// BlockVerifyReport 区块哈希链校验报告
+type BlockVerifyReport struct {
+	StartBlockNum    uint64              // 校验起始区块编号
+	EndBlockNum      uint64              // 校验结束区块编号
+	VerifiedCnt      uint64              // 已校验区块数量
+	SignatureChecked bool                // 是否校验了排序节点签名
+	Passed           bool                // 是否全部校验通过
+	FirstBroken      *BlockVerifyIssue   // 第一个校验问题，校验通过时为nil
+	Issues           []*BlockVerifyIssue // 全部校验问题
+}
+
+func (t *BlockVerifyReport) ToString() string {
+	result := fmt.Sprintf("校验区块范围: [%d, %d], 已校验区块数量: %d, 校验签名: %v, 校验结果: %v",
+		t.StartBlockNum, t.EndBlockNum, t.VerifiedCnt, t.SignatureChecked, t.Passed)
+	if t.FirstBroken != nil {
+		result = result + ",\n第一个断裂点: " + t.FirstBroken.ToString()
+	}
+	for _, issue := range t.Issues {
+		result = result + "\n\t" + issue.ToString()
+	}
+	return result
+}
+
+func (t *BlockVerifyReport) addIssue(issue *BlockVerifyIssue) {
+	if t.FirstBroken == nil {
+		t.FirstBroken = issue
+	}
+	t.Issues = append(t.Issues, issue)
+	t.Passed = false
+}
+
+// 区块校验参数
+type blockVerifyConfig struct {
+	startBlockNum uint64
+	hasEnd        bool
+	endBlockNum   uint64
+	sigVerifier   SignatureVerifier
+	stopOnBroken  bool
+}
+
+// BlockVerifyOption 区块校验选项
+type BlockVerifyOption func(*blockVerifyConfig)
+
+// WithVerifyBlockRange 设置校验的区块范围(包含两端)，未设置时校验全部区块。
+func WithVerifyBlockRange(start, end uint64) BlockVerifyOption {
+	return func(c *blockVerifyConfig) {
+		c.startBlockNum = start
+		c.endBlockNum = end
+		c.hasEnd = true
+	}
+}
+
+// WithSignatureVerifier 设置签名校验器，通常为通道的`fab.ChannelMembership`。
+//  未设置时不校验排序节点签名。
+func WithSignatureVerifier(verifier SignatureVerifier) BlockVerifyOption {
+	return func(c *blockVerifyConfig) {
+		c.sigVerifier = verifier
+	}
+}
+
+// WithStopOnFirstBroken 设置发现第一个校验问题后立即停止校验
+func WithStopOnFirstBroken() BlockVerifyOption {
+	return func(c *blockVerifyConfig) {
+		c.stopOnBroken = true
+	}
+}
+
+// VerifyBlockChain 校验通道区块哈希链的完整性。
+//  逐个区块重新计算SM3区块头哈希与区块数据哈希，校验前区块哈希链接，
+//  设置了签名校验器时还会校验区块元数据中的排序节点签名。
+//  入参: querier 区块查询接口，通常为`*ledger.Client`
+//  入参: opts 区块校验选项
+//  返回: BlockVerifyReport
+func VerifyBlockChain(querier BlockQuerier, opts ...BlockVerifyOption) (*BlockVerifyReport, error) {
+	if querier == nil {
+		return nil, fmt.Errorf("no querier(BlockQuerier)")
+	}
+	config := &blockVerifyConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	blockChainInfo, err := querier.QueryInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockInfo: %s", err)
+	}
+	height := blockChainInfo.BCI.Height
+	if height == 0 {
+		return nil, fmt.Errorf("the ledger has no block")
+	}
+	newest := height - 1
+	first := firstBlockNumOf(querier)
+	start := first
+	end := newest
+	if config.hasEnd {
+		start = config.startBlockNum
+		end = config.endBlockNum
+	}
+	if end > newest {
+		return nil, fmt.Errorf("end block number %d exceeds newest block number %d", end, newest)
+	}
+	if start < first {
+		return nil, fmt.Errorf("start block number %d is before the first block number %d", start, first)
+	}
+	if start > end {
+		return nil, fmt.Errorf("start block number %d is greater than end block number %d", start, end)
+	}
+
+	report := &BlockVerifyReport{
+		StartBlockNum:    start,
+		EndBlockNum:      end,
+		SignatureChecked: config.sigVerifier != nil,
+		Passed:           true,
+	}
+	// 前区块头哈希，起始区块不是第一个区块时需要先计算其前区块的哈希
+	var preBlockHeaderHash []byte
+	if start > first {
+		preBlock, err := querier.QueryBlock(start - 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to QueryBlock: %s", err)
+		}
+		if preBlock == nil || preBlock.Header == nil {
+			// 前区块缺少区块头时无法校验起始区块的前区块哈希链接
+			report.addIssue(&BlockVerifyIssue{
+				BlockNum:  start - 1,
+				IssueType: IssueHeaderMissing,
+				Detail:    "block header is missing",
+			})
+			if config.stopOnBroken {
+				return report, nil
+			}
+		} else {
+			preBlockHeaderHash = protoutil.BlockHeaderHash(preBlock.Header)
+		}
+	}
+	for curBlockNum := start; curBlockNum <= end; curBlockNum++ {
+		block, err := querier.QueryBlock(curBlockNum)
+		if err != nil {
+			return nil, fmt.Errorf("failed to QueryBlock: %s", err)
+		}
+		if curBlockNum == first && first > 0 && block != nil && block.Header != nil {
+			// 区块来源不是从0号区块开始时无法获取第一个区块的前区块，以其记录的前区块哈希作为校验起点
+			preBlockHeaderHash = block.Header.PreviousHash
+		}
+		issues := VerifyBlock(block, curBlockNum, preBlockHeaderHash, config.sigVerifier)
+		report.VerifiedCnt++
+		for _, issue := range issues {
+			report.addIssue(issue)
+		}
+		if len(issues) > 0 && config.stopOnBroken {
+			return report, nil
+		}
+		if block != nil && block.Header != nil {
+			preBlockHeaderHash = protoutil.BlockHeaderHash(block.Header)
+		} else {
+			preBlockHeaderHash = nil
+		}
+	}
+	// 校验到最新区块时，核对账本信息记录的当前区块哈希
+	if end == newest && !bytes.Equal(preBlockHeaderHash, blockChainInfo.BCI.CurrentBlockHash) {
+		report.addIssue(&BlockVerifyIssue{
+			BlockNum:  newest,
+			IssueType: IssueCurrentHashMismatch,
+			Expected:  hex.EncodeToString(blockChainInfo.BCI.CurrentBlockHash),
+			Actual:    hex.EncodeToString(preBlockHeaderHash),
+			Detail:    "the computed header hash of the newest block does not match the current block hash of the ledger",
+		})
+	}
+	return report, nil
+}
+
+// VerifyBlock 校验单个区块。
+//  入参: block 区块数据
+//  入参: expectedBlockNum 期望的区块编号
+//  入参: preBlockHeaderHash 前区块实际的区块头哈希，第一个区块为nil
+//  入参: sigVerifier 签名校验器，为nil时不校验排序节点签名
+//  返回: 校验问题集合，校验通过时为空
+func VerifyBlock(block *common.Block, expectedBlockNum uint64, preBlockHeaderHash []byte, sigVerifier SignatureVerifier) []*BlockVerifyIssue {
+	var issues []*BlockVerifyIssue
+	if block == nil || block.Header == nil {
+		return append(issues, &BlockVerifyIssue{
+			BlockNum:  expectedBlockNum,
+			IssueType: IssueHeaderMissing,
+			Detail:    "block header is missing",
+		})
+	}
+	if block.Data == nil {
+		return append(issues, &BlockVerifyIssue{
+			BlockNum:  expectedBlockNum,
+			IssueType: IssueDataMissing,
+			Detail:    "block data is missing",
+		})
+	}
+	if block.Header.Number != expectedBlockNum {
+		issues = append(issues, &BlockVerifyIssue{
+			BlockNum:  expectedBlockNum,
+			IssueType: IssueBlockNumMismatch,
+			Expected:  fmt.Sprintf("%d", expectedBlockNum),
+			Actual:    fmt.Sprintf("%d", block.Header.Number),
+			Detail:    "the block returned by the peer has an unexpected number",
+		})
+	}
+	dataHash := protoutil.BlockDataHash(block.Data)
+	if !bytes.Equal(dataHash, block.Header.DataHash) {
+		issues = append(issues, &BlockVerifyIssue{
+			BlockNum:  expectedBlockNum,
+			IssueType: IssueDataHashMismatch,
+			Expected:  hex.EncodeToString(block.Header.DataHash),
+			Actual:    hex.EncodeToString(dataHash),
+			Detail:    "the computed data hash does not match the data hash in the block header",
+		})
+	}
+	if !bytes.Equal(preBlockHeaderHash, block.Header.PreviousHash) {
+		issues = append(issues, &BlockVerifyIssue{
+			BlockNum:  expectedBlockNum,
+			IssueType: IssuePreviousHashMismatch,
+			Expected:  hex.EncodeToString(preBlockHeaderHash),
+			Actual:    hex.EncodeToString(block.Header.PreviousHash),
+			Detail:    "the previous hash in the block header does not match the header hash of the previous block",
+		})
+	}
+	// 创世区块没有排序节点签名
+	if sigVerifier != nil && expectedBlockNum > 0 {
+		if issue := verifyBlockSignatures(block, expectedBlockNum, sigVerifier); issue != nil {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// 校验区块元数据中的排序节点签名，至少需要一个有效签名且不能有无效签名。
+func verifyBlockSignatures(block *common.Block, blockNum uint64, sigVerifier SignatureVerifier) *BlockVerifyIssue {
+	md, err := protoutil.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return &BlockVerifyIssue{
+			BlockNum:  blockNum,
+			IssueType: IssueSignatureMissing,
+			Detail:    err.Error(),
+		}
+	}
+	if len(md.Signatures) == 0 {
+		return &BlockVerifyIssue{
+			BlockNum:  blockNum,
+			IssueType: IssueSignatureMissing,
+			Detail:    "no orderer signature in block metadata",
+		}
+	}
+	headerBytes := protoutil.BlockHeaderBytes(block.Header)
+	for i, metadataSignature := range md.Signatures {
+		signatureHeader, err := protoutil.UnmarshalSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			return &BlockVerifyIssue{
+				BlockNum:  blockNum,
+				IssueType: IssueSignatureInvalid,
+				Detail:    fmt.Sprintf("signature %d: %s", i, err),
+			}
+		}
+		signedData := bytes.Join([][]byte{md.Value, metadataSignature.SignatureHeader, headerBytes}, nil)
+		if err := sigVerifier.Verify(signatureHeader.Creator, signedData, metadataSignature.Signature); err != nil {
+			return &BlockVerifyIssue{
+				BlockNum:  blockNum,
+				IssueType: IssueSignatureInvalid,
+				Actual:    callerOfSerializedIdentity(signatureHeader.Creator),
+				Detail:    fmt.Sprintf("signature %d: %s", i, err),
+			}
+		}
+	}
+	return nil
+}
+
+// 返回序列化身份的MSPID，用于问题说明
+func callerOfSerializedIdentity(serializedID []byte) string {
+	sid, err := protoutil.UnmarshalSerializedIdentity(serializedID)
+	if err != nil {
+		return ""
+	}
+	return sid.Mspid
+}
+
+// 格式化区块校验问题类型
+func FormatIssueType(issueType int) string {
+	switch issueType {
+	case IssueDataHashMismatch:
+		return "区块数据哈希不一致"
+	case IssuePreviousHashMismatch:
+		return "前区块哈希链接断裂"
+	case IssueSignatureMissing:
+		return "缺少排序节点签名"
+	case IssueSignatureInvalid:
+		return "排序节点签名无效"
+	case IssueCurrentHashMismatch:
+		return "最新区块哈希不一致"
+	case IssueBlockNumMismatch:
+		return "区块编号不一致"
+	case IssueHeaderMissing:
+		return "缺少区块头"
+	case IssueDataMissing:
+		return "缺少区块数据"
+	default:
+		return "未知"
+	}
+}
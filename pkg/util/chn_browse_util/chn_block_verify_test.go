@@ -0,0 +1,132 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSigVerifier 签名等于"sig-"+签名者时视为有效
+type mockSigVerifier struct{}
+
+func (v *mockSigVerifier) Verify(serializedID []byte, msg []byte, sig []byte) error {
+	if !bytes.Equal(sig, append([]byte("sig-"), serializedID...)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func signBlock(block *common.Block, creator []byte) {
+	sigHeader := protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: creator})
+	md := &common.Metadata{
+		Signatures: []*common.MetadataSignature{{
+			SignatureHeader: sigHeader,
+			Signature:       append([]byte("sig-"), creator...),
+		}},
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(md)
+}
+
+func TestVerifyBlockChain(t *testing.T) {
+	q := newMockBlockQuerier(5)
+	for _, block := range q.blocks[1:] {
+		signBlock(block, []byte("orderer"))
+	}
+
+	report, err := VerifyBlockChain(q, WithSignatureVerifier(&mockSigVerifier{}))
+	require.NoError(t, err)
+	assert.True(t, report.Passed)
+	assert.True(t, report.SignatureChecked)
+	assert.Equal(t, uint64(5), report.VerifiedCnt)
+	assert.Nil(t, report.FirstBroken)
+
+	report, err = VerifyBlockChain(q, WithVerifyBlockRange(2, 3))
+	require.NoError(t, err)
+	assert.True(t, report.Passed)
+	assert.Equal(t, uint64(2), report.VerifiedCnt)
+
+	_, err = VerifyBlockChain(q, WithVerifyBlockRange(3, 9))
+	assert.Error(t, err)
+}
+
+func TestVerifyBlockChainTampered(t *testing.T) {
+	q := newMockBlockQuerier(5)
+	for _, block := range q.blocks[1:] {
+		signBlock(block, []byte("orderer"))
+	}
+	// 篡改区块2的数据
+	q.blocks[2].Data.Data = [][]byte{[]byte("tampered")}
+
+	report, err := VerifyBlockChain(q)
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	require.NotNil(t, report.FirstBroken)
+	assert.Equal(t, uint64(2), report.FirstBroken.BlockNum)
+	assert.Equal(t, IssueDataHashMismatch, report.FirstBroken.IssueType)
+
+	// 修正区块2的数据哈希后，区块3的前区块哈希链接断裂
+	q.blocks[2].Header.DataHash = protoutil.BlockDataHash(q.blocks[2].Data)
+	report, err = VerifyBlockChain(q, WithStopOnFirstBroken())
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, uint64(3), report.FirstBroken.BlockNum)
+	assert.Equal(t, IssuePreviousHashMismatch, report.FirstBroken.IssueType)
+	assert.Len(t, report.Issues, 1)
+
+	// 伪造区块1的签名
+	md := &common.Metadata{Signatures: []*common.MetadataSignature{{
+		SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: []byte("orderer")}),
+		Signature:       []byte("forged"),
+	}}}
+	q.blocks[1].Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(md)
+	report, err = VerifyBlockChain(q, WithSignatureVerifier(&mockSigVerifier{}), WithVerifyBlockRange(0, 2))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), report.FirstBroken.BlockNum)
+	assert.Equal(t, IssueSignatureInvalid, report.FirstBroken.IssueType)
+}
+
+func TestVerifyBlockChainMalformed(t *testing.T) {
+	q := newMockBlockQuerier(5)
+	// 区块2缺少区块头
+	q.blocks[2].Header = nil
+
+	report, err := VerifyBlockChain(q, WithVerifyBlockRange(0, 3))
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, uint64(4), report.VerifiedCnt)
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, uint64(2), report.Issues[0].BlockNum)
+	assert.Equal(t, IssueHeaderMissing, report.Issues[0].IssueType)
+	assert.Equal(t, uint64(3), report.Issues[1].BlockNum)
+	assert.Equal(t, IssuePreviousHashMismatch, report.Issues[1].IssueType)
+
+	// 起始区块的前区块缺少区块头
+	report, err = VerifyBlockChain(q, WithVerifyBlockRange(3, 3))
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, uint64(2), report.FirstBroken.BlockNum)
+	assert.Equal(t, IssueHeaderMissing, report.FirstBroken.IssueType)
+
+	// 区块1缺少区块数据
+	q = newMockBlockQuerier(3)
+	q.blocks[1].Data = nil
+	report, err = VerifyBlockChain(q, WithStopOnFirstBroken())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), report.FirstBroken.BlockNum)
+	assert.Equal(t, IssueDataMissing, report.FirstBroken.IssueType)
+}
@@ -0,0 +1,405 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+/*
+pkg/util/chn_browse_util/chn_browse_stream.go 流式通道浏览，逐个区块返回区块情报，支持正向/反向浏览、断点续浏览与进度回调。
+*/
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/ledger"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+)
+
+// ErrBrowseFinished 区块迭代器已遍历完所有区块
+var ErrBrowseFinished = errors.New("browse finished")
+
+// BlockQuerier 区块查询接口，`*ledger.Client`实现了该接口。
+type BlockQuerier interface {
+	QueryInfo(options ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error)
+	QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*common.Block, error)
+}
+
+// FirstBlockQuerier 可选接口，区块来源不是从0号区块开始时(如从中间区块开始的区块归档)返回其第一个区块编号。
+//  未实现该接口的区块来源视为从0号区块开始。
+type FirstBlockQuerier interface {
+	FirstBlockNum() uint64
+}
+
+// 返回区块来源的第一个区块编号
+func firstBlockNumOf(querier BlockQuerier) uint64 {
+	if fq, ok := querier.(FirstBlockQuerier); ok {
+		return fq.FirstBlockNum()
+	}
+	return 0
+}
+
+// 浏览方向
+const (
+	// BrowseBackward 反向浏览，从新区块向旧区块遍历(默认)
+	BrowseBackward = 0
+	// BrowseForward 正向浏览，从旧区块向新区块遍历
+	BrowseForward = 1
+)
+
+// BrowseCheckpoint 浏览断点(区块编号 + 区块头哈希)
+type BrowseCheckpoint struct {
+	BlockNum        uint64 // 最后处理完毕的区块编号
+	BlockHeaderHash string // 最后处理完毕的区块头哈希(16进制字符串)
+}
+
+func (t *BrowseCheckpoint) ToString() string {
+	return fmt.Sprintf("区块编号: %d, 区块头哈希: %s", t.BlockNum, t.BlockHeaderHash)
+}
+
+// BrowseProgress 浏览进度
+type BrowseProgress struct {
+	BlockNum       uint64 // 刚处理完毕的区块编号
+	ProcessedCnt   uint64 // 已处理区块数量
+	TotalCnt       uint64 // 本次浏览的区块总数
+	ProcessedTxCnt uint64 // 已处理交易数量
+}
+
+// BrowseStreamResult 流式浏览结果
+type BrowseStreamResult struct {
+	BlockInfo  *BlockInfoWithTx  // 区块情报(包含内部交易情报)
+	Checkpoint *BrowseCheckpoint // 处理完该区块后的断点
+	Err        error             // 浏览错误，不为nil时结果通道随即关闭
+}
+
+// 流式浏览参数
+type browseStreamConfig struct {
+	direction     int
+	startBlockNum uint64
+	hasStart      bool
+	endBlockNum   uint64
+	hasEnd        bool
+	checkpoint    *BrowseCheckpoint
+	progress      func(*BrowseProgress)
+	bufferSize    int
+	txFilter      *TxFilter
+	pvtResolver   PrivateDataResolver
+}
+
+// BrowseStreamOption 流式浏览选项
+type BrowseStreamOption func(*browseStreamConfig)
+
+// WithDirection 设置浏览方向(BrowseBackward/BrowseForward)
+func WithDirection(direction int) BrowseStreamOption {
+	return func(c *browseStreamConfig) {
+		c.direction = direction
+	}
+}
+
+// WithBlockRange 设置浏览的区块范围(包含两端)。
+//  正向浏览时start<=end，反向浏览时start>=end。
+//  未设置时，正向浏览为[第一个区块, 最新区块]，反向浏览为[最新区块, 第一个区块]，第一个区块通常为0号区块。
+func WithBlockRange(start, end uint64) BrowseStreamOption {
+	return func(c *browseStreamConfig) {
+		c.startBlockNum = start
+		c.hasStart = true
+		c.endBlockNum = end
+		c.hasEnd = true
+	}
+}
+
+// WithStartBlock 设置浏览的起始区块编号
+func WithStartBlock(start uint64) BrowseStreamOption {
+	return func(c *browseStreamConfig) {
+		c.startBlockNum = start
+		c.hasStart = true
+	}
+}
+
+// WithEndBlock 设置浏览的结束区块编号(包含)
+func WithEndBlock(end uint64) BrowseStreamOption {
+	return func(c *browseStreamConfig) {
+		c.endBlockNum = end
+		c.hasEnd = true
+	}
+}
+
+// WithCheckpoint 从断点继续浏览。
+//  断点对应区块本身不再返回，浏览前会校验断点区块头哈希是否与账本一致。
+//  设置断点后忽略起始区块编号。
+func WithCheckpoint(checkpoint *BrowseCheckpoint) BrowseStreamOption {
+	return func(c *browseStreamConfig) {
+		c.checkpoint = checkpoint
+	}
+}
+
+// WithProgressHandler 设置进度回调，每处理完一个区块调用一次
+func WithProgressHandler(handler func(*BrowseProgress)) BrowseStreamOption {
+	return func(c *browseStreamConfig) {
+		c.progress = handler
+	}
+}
+
+// WithTxFilter 设置交易过滤条件，返回的区块情报中只保留满足条件的交易情报
+func WithTxFilter(filter *TxFilter) BrowseStreamOption {
+	return func(c *browseStreamConfig) {
+		c.txFilter = filter
+	}
+}
+
+// WithPrivateDataResolver 设置私有数据获取接口，浏览时使用私有数据还原交易的私有数据读写集
+func WithPrivateDataResolver(resolver PrivateDataResolver) BrowseStreamOption {
+	return func(c *browseStreamConfig) {
+		c.pvtResolver = resolver
+	}
+}
+
+// WithBufferSize 设置流式浏览结果通道的缓冲大小，默认为0
+func WithBufferSize(size int) BrowseStreamOption {
+	return func(c *browseStreamConfig) {
+		c.bufferSize = size
+	}
+}
+
+// BlockIterator 区块迭代器，每次调用Next返回一个区块情报，不在内存中保留已返回的区块。
+type BlockIterator struct {
+	querier    BlockQuerier
+	config     *browseStreamConfig
+	nextNum    uint64
+	endNum     uint64
+	finished   bool
+	totalCnt   uint64
+	processed  uint64
+	txCnt      uint64
+	checkpoint *BrowseCheckpoint
+}
+
+// NewBlockIterator 创建区块迭代器
+//  入参: querier 区块查询接口，通常为`*ledger.Client`
+//  入参: opts 流式浏览选项
+//  返回: BlockIterator
+func NewBlockIterator(querier BlockQuerier, opts ...BrowseStreamOption) (*BlockIterator, error) {
+	if querier == nil {
+		return nil, fmt.Errorf("no querier(BlockQuerier)")
+	}
+	config := &browseStreamConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.direction != BrowseBackward && config.direction != BrowseForward {
+		return nil, fmt.Errorf("not supported browse direction: %d", config.direction)
+	}
+	if config.bufferSize < 0 {
+		return nil, fmt.Errorf("invalid buffer size: %d", config.bufferSize)
+	}
+	// 查询当前最新区块链信息
+	blockChainInfo, err := querier.QueryInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockInfo: %s", err)
+	}
+	it := &BlockIterator{
+		querier: querier,
+		config:  config,
+	}
+	height := blockChainInfo.BCI.Height
+	if height == 0 {
+		it.finished = true
+		return it, nil
+	}
+	newest := height - 1
+	first := firstBlockNumOf(querier)
+
+	// 确定起始区块编号
+	var start uint64
+	if config.direction == BrowseForward {
+		start = first
+	} else {
+		start = newest
+	}
+	if config.hasStart {
+		start = config.startBlockNum
+	}
+	if config.checkpoint != nil {
+		if err := it.verifyCheckpoint(config.checkpoint); err != nil {
+			return nil, err
+		}
+		it.checkpoint = config.checkpoint
+		if config.direction == BrowseForward {
+			start = config.checkpoint.BlockNum + 1
+		} else {
+			if config.checkpoint.BlockNum <= first {
+				// 反向浏览已到达第一个区块
+				it.finished = true
+				return it, nil
+			}
+			start = config.checkpoint.BlockNum - 1
+		}
+	}
+	// 确定结束区块编号
+	var end uint64
+	if config.direction == BrowseForward {
+		end = newest
+	} else {
+		end = first
+	}
+	if config.hasEnd {
+		end = config.endBlockNum
+	}
+	if end > newest {
+		return nil, fmt.Errorf("end block number %d exceeds newest block number %d", end, newest)
+	}
+	if start < first || end < first {
+		return nil, fmt.Errorf("block range [%d, %d] is before the first block number %d", start, end, first)
+	}
+
+	if config.direction == BrowseForward {
+		if start > end {
+			// 断点已是结束区块时，不再有需要浏览的区块
+			if config.checkpoint != nil && start == end+1 {
+				it.finished = true
+				return it, nil
+			}
+			return nil, fmt.Errorf("start block number %d is greater than end block number %d", start, end)
+		}
+		it.totalCnt = end - start + 1
+	} else {
+		if start > newest {
+			return nil, fmt.Errorf("start block number %d exceeds newest block number %d", start, newest)
+		}
+		if start < end {
+			if config.checkpoint != nil && start+1 == end {
+				it.finished = true
+				return it, nil
+			}
+			return nil, fmt.Errorf("start block number %d is less than end block number %d", start, end)
+		}
+		it.totalCnt = start - end + 1
+	}
+	it.nextNum = start
+	it.endNum = end
+	return it, nil
+}
+
+// 校验断点区块头哈希是否与账本一致
+func (it *BlockIterator) verifyCheckpoint(checkpoint *BrowseCheckpoint) error {
+	block, err := it.querier.QueryBlock(checkpoint.BlockNum)
+	if err != nil {
+		return fmt.Errorf("failed to QueryBlock for checkpoint: %s", err)
+	}
+	hash := hex.EncodeToString(protoutil.BlockHeaderHash(block.Header))
+	if hash != checkpoint.BlockHeaderHash {
+		return fmt.Errorf("checkpoint mismatch, block %d header hash on ledger is %s, checkpoint is %s",
+			checkpoint.BlockNum, hash, checkpoint.BlockHeaderHash)
+	}
+	return nil
+}
+
+// Next 返回下一个区块情报，遍历结束时返回ErrBrowseFinished。
+func (it *BlockIterator) Next() (*BlockInfoWithTx, error) {
+	if it.finished {
+		return nil, ErrBrowseFinished
+	}
+	curBlockNum := it.nextNum
+	block, err := it.querier.QueryBlock(curBlockNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to QueryBlock: %s", err)
+	}
+	curBlockHeaderHash := protoutil.BlockHeaderHash(block.Header)
+	blockInfo, err := UnmarshalBlockDataWithFilter(block, curBlockHeaderHash, it.config.txFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to UnmarshalBlockData: %s", err)
+	}
+	if err := ResolvePrivateData(blockInfo, it.config.pvtResolver); err != nil {
+		return nil, err
+	}
+	it.checkpoint = &BrowseCheckpoint{
+		BlockNum:        blockInfo.BlockNum,
+		BlockHeaderHash: blockInfo.BlockHeaderHash,
+	}
+	it.processed++
+	it.txCnt += blockInfo.TransCnt
+	// 计算下一个区块编号
+	if curBlockNum == it.endNum {
+		it.finished = true
+	} else if it.config.direction == BrowseForward {
+		it.nextNum++
+	} else {
+		it.nextNum--
+	}
+	if it.config.progress != nil {
+		it.config.progress(it.Progress())
+	}
+	return blockInfo, nil
+}
+
+// Checkpoint 返回最后处理完毕的区块断点，尚未处理任何区块且未设置断点时返回nil。
+func (it *BlockIterator) Checkpoint() *BrowseCheckpoint {
+	if it.checkpoint == nil {
+		return nil
+	}
+	cp := *it.checkpoint
+	return &cp
+}
+
+// Progress 返回当前浏览进度
+func (it *BlockIterator) Progress() *BrowseProgress {
+	progress := &BrowseProgress{
+		ProcessedCnt:   it.processed,
+		TotalCnt:       it.totalCnt,
+		ProcessedTxCnt: it.txCnt,
+	}
+	if it.checkpoint != nil {
+		progress.BlockNum = it.checkpoint.BlockNum
+	}
+	return progress
+}
+
+// BrowseChannelStream 流式浏览通道数据，通过返回的通道逐个输出区块情报。
+//  遍历结束、发生错误或ctx取消时关闭结果通道。
+//  入参: ctx 上下文，用于取消浏览
+//  入参: querier 区块查询接口，通常为`*ledger.Client`
+//  入参: opts 流式浏览选项
+//  返回: 结果通道
+func BrowseChannelStream(ctx context.Context, querier BlockQuerier, opts ...BrowseStreamOption) (<-chan *BrowseStreamResult, error) {
+	it, err := NewBlockIterator(querier, opts...)
+	if err != nil {
+		return nil, err
+	}
+	results := make(chan *BrowseStreamResult, it.config.bufferSize)
+	go func() {
+		defer close(results)
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			blockInfo, err := it.Next()
+			if err == ErrBrowseFinished {
+				return
+			}
+			result := &BrowseStreamResult{
+				BlockInfo:  blockInfo,
+				Checkpoint: it.Checkpoint(),
+				Err:        err,
+			}
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return results, nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/client/ledger"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBlockQuerier 基于内存区块链的区块查询实现
+type mockBlockQuerier struct {
+	blocks []*common.Block
+}
+
+func newMockBlockQuerier(height int) *mockBlockQuerier {
+	q := &mockBlockQuerier{}
+	var preHash []byte
+	for i := 0; i < height; i++ {
+		block := protoutil.NewBlock(uint64(i), preHash)
+		block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+		q.blocks = append(q.blocks, block)
+		preHash = protoutil.BlockHeaderHash(block.Header)
+	}
+	return q
+}
+
+func (q *mockBlockQuerier) QueryInfo(options ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error) {
+	bci := &common.BlockchainInfo{Height: uint64(len(q.blocks))}
+	if len(q.blocks) > 0 {
+		bci.CurrentBlockHash = protoutil.BlockHeaderHash(q.blocks[len(q.blocks)-1].Header)
+	}
+	return &fab.BlockchainInfoResponse{BCI: bci}, nil
+}
+
+func (q *mockBlockQuerier) QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*common.Block, error) {
+	if blockNumber >= uint64(len(q.blocks)) {
+		return nil, fmt.Errorf("block %d not found", blockNumber)
+	}
+	return q.blocks[blockNumber], nil
+}
+
+func collectBlockNums(t *testing.T, it *BlockIterator) []uint64 {
+	var nums []uint64
+	for {
+		blockInfo, err := it.Next()
+		if err == ErrBrowseFinished {
+			return nums
+		}
+		require.NoError(t, err)
+		nums = append(nums, blockInfo.BlockNum)
+	}
+}
+
+func TestBlockIteratorDirection(t *testing.T) {
+	q := newMockBlockQuerier(5)
+
+	it, err := NewBlockIterator(q)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{4, 3, 2, 1, 0}, collectBlockNums(t, it))
+
+	it, err = NewBlockIterator(q, WithDirection(BrowseForward))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, collectBlockNums(t, it))
+
+	it, err = NewBlockIterator(q, WithDirection(BrowseForward), WithBlockRange(1, 3))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, collectBlockNums(t, it))
+
+	it, err = NewBlockIterator(q, WithBlockRange(3, 2))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{3, 2}, collectBlockNums(t, it))
+
+	_, err = NewBlockIterator(q, WithDirection(BrowseForward), WithBlockRange(3, 1))
+	assert.Error(t, err)
+	_, err = NewBlockIterator(q, WithEndBlock(9), WithDirection(BrowseForward))
+	assert.Error(t, err)
+	_, err = NewBlockIterator(q, WithDirection(2))
+	assert.Error(t, err)
+}
+
+func TestBlockIteratorCheckpoint(t *testing.T) {
+	q := newMockBlockQuerier(5)
+
+	it, err := NewBlockIterator(q, WithDirection(BrowseForward), WithEndBlock(1))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0, 1}, collectBlockNums(t, it))
+	cp := it.Checkpoint()
+	require.NotNil(t, cp)
+	assert.Equal(t, uint64(1), cp.BlockNum)
+	assert.Equal(t, hex.EncodeToString(protoutil.BlockHeaderHash(q.blocks[1].Header)), cp.BlockHeaderHash)
+
+	it, err = NewBlockIterator(q, WithDirection(BrowseForward), WithCheckpoint(cp))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4}, collectBlockNums(t, it))
+
+	it, err = NewBlockIterator(q, WithCheckpoint(cp))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0}, collectBlockNums(t, it))
+
+	it, err = NewBlockIterator(q, WithDirection(BrowseForward), WithCheckpoint(it.Checkpoint()))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, collectBlockNums(t, it))
+	// 断点已是最新区块
+	it, err = NewBlockIterator(q, WithDirection(BrowseForward), WithCheckpoint(it.Checkpoint()))
+	require.NoError(t, err)
+	assert.Empty(t, collectBlockNums(t, it))
+
+	_, err = NewBlockIterator(q, WithCheckpoint(&BrowseCheckpoint{BlockNum: 2, BlockHeaderHash: "00"}))
+	assert.Error(t, err)
+}
+
+func TestBrowseChannelStream(t *testing.T) {
+	q := newMockBlockQuerier(4)
+
+	var progresses []*BrowseProgress
+	results, err := BrowseChannelStream(context.Background(), q,
+		WithDirection(BrowseForward),
+		WithProgressHandler(func(p *BrowseProgress) {
+			progresses = append(progresses, p)
+		}))
+	require.NoError(t, err)
+
+	var nums []uint64
+	for r := range results {
+		require.NoError(t, r.Err)
+		assert.Equal(t, r.BlockInfo.BlockNum, r.Checkpoint.BlockNum)
+		nums = append(nums, r.BlockInfo.BlockNum)
+	}
+	assert.Equal(t, []uint64{0, 1, 2, 3}, nums)
+	require.Len(t, progresses, 4)
+	assert.Equal(t, uint64(4), progresses[3].ProcessedCnt)
+	assert.Equal(t, uint64(4), progresses[3].TotalCnt)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err = BrowseChannelStream(ctx, q)
+	require.NoError(t, err)
+	r := <-results
+	require.NoError(t, r.Err)
+	assert.Equal(t, uint64(3), r.BlockInfo.BlockNum)
+	cancel()
+	for range results {
+	}
+}
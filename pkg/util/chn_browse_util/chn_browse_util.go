@@ -96,8 +96,22 @@ type TransactionInfo struct {
 	TxDesc       string                  // 交易说明
 	ErrorMsg     string                  // 交易解析错误消息
 	TxType       int                     // 交易类型(0:未知; 1:业务合约交易数据; 2:系统合约交易数据; 3:通道创建或配置交易数据)
+	// 交易验证码(peer.TxValidationCode, 0:VALID)，区块元数据中没有交易验证码时为ValidationCodeUnknown
+	ValidationCode    int32
+	TxEndorsers       []*TransactionEndorserInfo // 交易背书者情报集合
+	CcEventName       string                     // 链码事件名称
+	CcEventPayload    string                     // 链码事件数据
+	CcResponseStatus  int32                      // 链码响应状态码
+	CcResponseMessage string                     // 链码响应消息
+	CcResponsePayload string                     // 链码响应数据
+	TxSeqInBlock      uint64                     // 交易在区块内的序号
+	TxPvtReads        []*TransactionPvtReadInfo  // 交易读取私有数据情报集合
+	TxPvtWrites       []*TransactionPvtWriteInfo // 交易写入私有数据情报集合
 }
 
+// ValidationCodeUnknown 区块元数据中没有交易验证码
+const ValidationCodeUnknown int32 = -1
+
 func (t *TransactionInfo) ToString() string {
 	readSeys := []string{}
 	for _, r := range t.TxReads {
@@ -107,8 +121,36 @@ func (t *TransactionInfo) ToString() string {
 	for _, w := range t.TxWrites {
 		writeSeys = append(writeSeys, w.ToString())
 	}
-	return fmt.Sprintf("TxID: %s, TxType: %s, TxCreateTime: %s, BlockNum: %d, TxCcID: %s, TxArgs: %q, TxReads: %q, TxWrites: %q, CallerMspID: %s, CallerName: %s, CallerOU: %s, TxDesc: %s, ErrorMsg: %s",
-		t.TxID, FormatTxType(t.TxType), t.TxCreateTime, t.BlockNum, t.TxCcID, t.TxArgs, readSeys, writeSeys, t.CallerMspID, t.CallerName, t.CallerOU, t.TxDesc, t.ErrorMsg)
+	endorserSeys := []string{}
+	for _, e := range t.TxEndorsers {
+		endorserSeys = append(endorserSeys, e.ToString())
+	}
+	pvtReadSeys := []string{}
+	for _, r := range t.TxPvtReads {
+		pvtReadSeys = append(pvtReadSeys, r.ToString())
+	}
+	pvtWriteSeys := []string{}
+	for _, w := range t.TxPvtWrites {
+		pvtWriteSeys = append(pvtWriteSeys, w.ToString())
+	}
+	return fmt.Sprintf("TxID: %s, TxType: %s, ValidationCode: %s, TxCreateTime: %s, BlockNum: %d, TxSeqInBlock: %d, TxCcID: %s, TxArgs: %q, TxReads: %q, TxWrites: %q, TxPvtReads: %q, TxPvtWrites: %q, CallerMspID: %s, CallerName: %s, CallerOU: %s, TxEndorsers: %q, CcEventName: %s, CcEventPayload: %s, CcResponseStatus: %d, CcResponseMessage: %s, CcResponsePayload: %s, TxDesc: %s, ErrorMsg: %s",
+		t.TxID, FormatTxType(t.TxType), FormatValidationCode(t.ValidationCode), t.TxCreateTime, t.BlockNum, t.TxSeqInBlock, t.TxCcID, t.TxArgs, readSeys, writeSeys, pvtReadSeys, pvtWriteSeys, t.CallerMspID, t.CallerName, t.CallerOU,
+		endorserSeys, t.CcEventName, t.CcEventPayload, t.CcResponseStatus, t.CcResponseMessage, t.CcResponsePayload, t.TxDesc, t.ErrorMsg)
+}
+
+// IsValid 判断交易是否为有效交易
+func (t *TransactionInfo) IsValid() bool {
+	return t.ValidationCode == int32(peer.TxValidationCode_VALID)
+}
+
+// 交易背书者情报
+type TransactionEndorserInfo struct {
+	MspID   string // 背书者MSPID
+	Subject string // 背书者证书主题
+}
+
+func (t *TransactionEndorserInfo) ToString() string {
+	return fmt.Sprintf("MspID: %s, Subject: %s", t.MspID, t.Subject)
 }
 
 // 交易读取数据情报
@@ -153,12 +195,10 @@ type BrowseChannelConfig struct {
 	//  LastBlockNum默认值为0。
 	LastBlockNum uint64
 
-	// 暂时不考虑浏览通道数据时对交易做过滤
-	// 交易数据浏览级别
-	//  0:默认值，无限制
-	//  1:只浏览合约交易数据，包括业务合约与系统合约(如`_lifecycle`)，不包括通道的创建交易、配置交易等
-	//  2:只浏览业务合约数据
-	// TxBrowseLevel int
+	// 交易过滤条件
+	//  TxFilter默认值为nil，此时不对交易做过滤。
+	//  设置后ChannelInfo与BlockInfoWithTx中只保留满足条件的交易情报，TransTotal仍统计本次浏览区块内的全部交易。
+	TxFilter *TxFilter
 }
 
 type BrowseOption func(*BrowseChannelConfig)
@@ -281,7 +321,7 @@ func BrowseChannelWithConfig(ledgerClient *ledger.Client, config *BrowseChannelC
 			return nil, fmt.Errorf("failed to QueryBlockByHash: %s", err)
 		}
 		// 反序列化当前区块
-		blockInfo, err := UnmarshalBlockData(block, curBlockHeaderHash)
+		blockInfo, err := UnmarshalBlockDataWithFilter(block, curBlockHeaderHash, config.TxFilter)
 		if err != nil {
 			return nil, fmt.Errorf("failed to UnmarshalBlockData: %s", err)
 		}
@@ -326,12 +366,19 @@ func UnmarshalBlockData(block *common.Block, curBlockHash []byte) (*BlockInfoWit
 		},
 	}
 	// zclog.Debugf("区块编号: %d, 交易数量: %d", blockInfo.BlockNum, blockInfo.TransCnt)
+	// 区块元数据中的交易验证码集合
+	txValidationFlags := getTxValidationFlags(block)
 	transactionInfos := []*TransactionInfo{}
 	// 遍历区块内所有交易
 	for i := 0; i < transCnt; i++ {
 		// 创建交易情报
 		transactionInfo := &TransactionInfo{
-			BlockNum: blockInfo.BlockNum,
+			BlockNum:       blockInfo.BlockNum,
+			TxSeqInBlock:   uint64(i),
+			ValidationCode: ValidationCodeUnknown,
+		}
+		if i < len(txValidationFlags) {
+			transactionInfo.ValidationCode = int32(txValidationFlags[i])
 		}
 		transactionInfos = append(transactionInfos, transactionInfo)
 		// zclog.Debugf("第 %d 条交易数据.", i+1)
@@ -461,6 +508,8 @@ func UnmarshalBlockData(block *common.Block, curBlockHash []byte) (*BlockInfoWit
 			transactionInfo.TxDesc = fmt.Sprintf("区块编号: %d, 第 %d 条交易不是业务合约或系统合约的交易数据。", blockInfo.BlockNum, i+1)
 			continue
 		}
+		// 获取本次交易的背书者
+		transactionInfo.TxEndorsers = unmarshalEndorsers(chaincodeActionPayload.Action.Endorsements)
 		// 反序列化 chaincodeActionPayload.Action 数据
 		proposalResponsePayload := &peer.ProposalResponsePayload{}
 		err = proto.Unmarshal(chaincodeActionPayload.Action.ProposalResponsePayload, proposalResponsePayload)
@@ -477,17 +526,35 @@ func UnmarshalBlockData(block *common.Block, curBlockHash []byte) (*BlockInfoWit
 			continue
 		}
 		// zclog.Debugf("chaincodeAction: %s", chaincodeAction.String())
+		// 获取本次合约调用的响应
+		if chaincodeAction.Response != nil {
+			transactionInfo.CcResponseStatus = chaincodeAction.Response.Status
+			transactionInfo.CcResponseMessage = chaincodeAction.Response.Message
+			transactionInfo.CcResponsePayload = string(chaincodeAction.Response.Payload)
+		}
+		// 交易解析过程中不影响读写集的错误
+		errInTx := []string{}
+		// 反序列化 chaincodeAction.Events 获取链码事件，失败时记录错误并继续解析读写集
+		if len(chaincodeAction.Events) > 0 {
+			chaincodeEvent := &peer.ChaincodeEvent{}
+			err = proto.Unmarshal(chaincodeAction.Events, chaincodeEvent)
+			if err != nil {
+				errInTx = append(errInTx, fmt.Sprintf("failed to unmarshal chaincode event: %s", err))
+			} else {
+				transactionInfo.CcEventName = chaincodeEvent.EventName
+				transactionInfo.CcEventPayload = string(chaincodeEvent.Payload)
+			}
+		}
 		// 反序列化 chaincodeAction.Results
 		txReadWriteSet := &rwset.TxReadWriteSet{}
 		err = proto.Unmarshal(chaincodeAction.Results, txReadWriteSet)
 		if err != nil {
-			transactionInfo.ErrorMsg = err.Error()
+			transactionInfo.ErrorMsg = strings.Join(append(errInTx, err.Error()), ";")
 			continue
 		}
 		// zclog.Debugf("txReadWriteSet: %s", txReadWriteSet.String())
 		transactionReadInfos := []*TransactionReadInfo{}
 		transactionWriteInfos := []*TransactionWriteInfo{}
-		errInRwset := []string{}
 		// 遍历 txReadWriteSet.NsRwset
 		for _, v := range txReadWriteSet.NsRwset {
 			// 不处理 _lifecycle 等系统合约
@@ -498,7 +565,7 @@ func UnmarshalBlockData(block *common.Block, curBlockHash []byte) (*BlockInfoWit
 			readWriteSet := &kvrwset.KVRWSet{}
 			err = proto.Unmarshal(v.Rwset, readWriteSet)
 			if err != nil {
-				errInRwset = append(errInRwset, err.Error())
+				errInTx = append(errInTx, err.Error())
 				continue
 			}
 			for _, r := range readWriteSet.Reads {
@@ -523,17 +590,43 @@ func UnmarshalBlockData(block *common.Block, curBlockHash []byte) (*BlockInfoWit
 				transactionWriteInfos = append(transactionWriteInfos, transactionWriteInfo)
 			}
 		}
-		if len(errInRwset) > 0 {
-			transactionInfo.ErrorMsg = strings.Join(errInRwset, ";")
+		// 解析私有数据集合的哈希读写集
+		pvtReadInfos, pvtWriteInfos, err := unmarshalCollHashedRwSets(txReadWriteSet)
+		if err != nil {
+			errInTx = append(errInTx, err.Error())
+		}
+		if len(errInTx) > 0 {
+			transactionInfo.ErrorMsg = strings.Join(errInTx, ";")
 		}
 		transactionInfo.TxReads = transactionReadInfos
 		transactionInfo.TxWrites = transactionWriteInfos
+		transactionInfo.TxPvtReads = pvtReadInfos
+		transactionInfo.TxPvtWrites = pvtWriteInfos
 
 	}
 	blockInfo.TransactionInfos = transactionInfos
 	return blockInfo, nil
 }
 
+// 反序列化交易背书集合，获取背书者的MSPID与证书主题
+func unmarshalEndorsers(endorsements []*peer.Endorsement) []*TransactionEndorserInfo {
+	endorsers := []*TransactionEndorserInfo{}
+	for _, endorsement := range endorsements {
+		if endorserInfo := unmarshalIdentityInfo(endorsement.Endorser); endorserInfo != nil {
+			endorsers = append(endorsers, endorserInfo)
+		}
+	}
+	return endorsers
+}
+
+// 获取区块元数据中的交易验证码集合，每个字节对应一条交易的peer.TxValidationCode
+func getTxValidationFlags(block *common.Block) []byte {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil
+	}
+	return block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]
+}
+
 func TrimHiddenCharacter(originStr string) string {
 	srcRunes := []rune(originStr)
 	dstRunes := make([]rune, 0, len(srcRunes))
@@ -572,6 +665,14 @@ func IsBussinessCC(name string) bool {
 	return len(name) > 0 && !IsSysCC(name)
 }
 
+// 格式化交易验证码
+func FormatValidationCode(code int32) string {
+	if code == ValidationCodeUnknown {
+		return "UNKNOWN"
+	}
+	return peer.TxValidationCode(code).String()
+}
+
 // 格式化交易类型
 //  0:未知; 1:业务合约交易数据; 2:系统合约交易数据; 3:通道创建或配置交易数据
 func FormatTxType(txType int) string {
@@ -0,0 +1,167 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset/kvrwset"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"gitee.com/zhaochuninhefei/gmgo/sm2"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 生成带OU的SM2自签名证书，返回序列化身份
+func newMockSerializedIdentity(t *testing.T, mspID, cn, ou string) []byte {
+	key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:       big.NewInt(1),
+		Subject:            pkix.Name{CommonName: cn, OrganizationalUnit: []string{ou}},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           time.Now().Add(time.Hour),
+		SignatureAlgorithm: x509.SM2WithSM3,
+		KeyUsage:           x509.KeyUsageDigitalSignature,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})
+	return protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: certPem})
+}
+
+// 构建一条链码调用交易，events为链码事件字节
+func newMockTxEnvelope(t *testing.T, txID, ccName string, events []byte, endorsers ...[]byte) []byte {
+	chdr := protoutil.MakeChannelHeader(common.HeaderType_ENDORSER_TRANSACTION, 0, "mychannel", 0)
+	chdr.TxId = txID
+	shdr := protoutil.MakeSignatureHeader(newMockSerializedIdentity(t, "Org1MSP", "user1", "client"), nil)
+
+	results := protoutil.MarshalOrPanic(&rwset.TxReadWriteSet{
+		NsRwset: []*rwset.NsReadWriteSet{{
+			Namespace: ccName,
+			Rwset: protoutil.MarshalOrPanic(&kvrwset.KVRWSet{
+				Writes: []*kvrwset.KVWrite{{Key: "k1", Value: []byte("v1")}},
+			}),
+		}},
+	})
+	chaincodeAction := &peer.ChaincodeAction{
+		Results:  results,
+		Events:   events,
+		Response: &peer.Response{Status: 200, Message: "OK"},
+	}
+	var endorsements []*peer.Endorsement
+	for _, endorser := range endorsers {
+		endorsements = append(endorsements, &peer.Endorsement{Endorser: endorser})
+	}
+	cis := &peer.ChaincodeInvocationSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			ChaincodeId: &peer.ChaincodeID{Name: ccName},
+			Input:       &peer.ChaincodeInput{Args: [][]byte{[]byte("put"), []byte("k1"), []byte("v1")}},
+		},
+	}
+	actionPayload := &peer.ChaincodeActionPayload{
+		ChaincodeProposalPayload: protoutil.MarshalOrPanic(&peer.ChaincodeProposalPayload{Input: protoutil.MarshalOrPanic(cis)}),
+		Action: &peer.ChaincodeEndorsedAction{
+			ProposalResponsePayload: protoutil.MarshalOrPanic(&peer.ProposalResponsePayload{Extension: protoutil.MarshalOrPanic(chaincodeAction)}),
+			Endorsements:            endorsements,
+		},
+	}
+	tx := &peer.Transaction{
+		Actions: []*peer.TransactionAction{{Payload: protoutil.MarshalOrPanic(actionPayload)}},
+	}
+	payload := &common.Payload{
+		Header: protoutil.MakePayloadHeader(chdr, shdr),
+		Data:   protoutil.MarshalOrPanic(tx),
+	}
+	return protoutil.MarshalOrPanic(&common.Envelope{Payload: protoutil.MarshalOrPanic(payload)})
+}
+
+func TestFormatValidationCode(t *testing.T) {
+	assert.Equal(t, "VALID", FormatValidationCode(int32(peer.TxValidationCode_VALID)))
+	assert.Equal(t, "MVCC_READ_CONFLICT", FormatValidationCode(int32(peer.TxValidationCode_MVCC_READ_CONFLICT)))
+	assert.Equal(t, "UNKNOWN", FormatValidationCode(ValidationCodeUnknown))
+
+	assert.True(t, (&TransactionInfo{ValidationCode: 0}).IsValid())
+	assert.False(t, (&TransactionInfo{ValidationCode: ValidationCodeUnknown}).IsValid())
+}
+
+func TestUnmarshalEndorsers(t *testing.T) {
+	endorsements := []*peer.Endorsement{
+		{Endorser: protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("not a cert")})},
+		{Endorser: []byte("garbage")},
+	}
+	endorsers := unmarshalEndorsers(endorsements)
+	assert.Len(t, endorsers, 1)
+	assert.Equal(t, "Org1MSP", endorsers[0].MspID)
+	assert.Empty(t, endorsers[0].Subject)
+}
+
+func TestGetTxValidationFlags(t *testing.T) {
+	peer0 := newMockSerializedIdentity(t, "Org1MSP", "peer0.org1.example.com", "peer")
+	peer1 := newMockSerializedIdentity(t, "Org2MSP", "peer0.org2.example.com", "peer")
+	event := protoutil.MarshalOrPanic(&peer.ChaincodeEvent{EventName: "put", Payload: []byte("k1")})
+
+	block := protoutil.NewBlock(1, nil)
+	block.Data.Data = [][]byte{
+		newMockTxEnvelope(t, "tx1", "mycc", event, peer0, peer1),
+		// 链码事件无法反序列化
+		newMockTxEnvelope(t, "tx2", "mycc", []byte{0xff}, peer0),
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{
+		byte(peer.TxValidationCode_VALID), byte(peer.TxValidationCode_MVCC_READ_CONFLICT),
+	}
+	assert.Equal(t, []byte{0, 11}, getTxValidationFlags(block))
+
+	blockInfo, err := UnmarshalBlockData(block, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), blockInfo.TransCnt)
+	require.Len(t, blockInfo.TransactionInfos, 2)
+
+	tx1 := blockInfo.TransactionInfos[0]
+	assert.Empty(t, tx1.ErrorMsg)
+	assert.Equal(t, "tx1", tx1.TxID)
+	assert.True(t, tx1.IsValid())
+	assert.Equal(t, "mycc", tx1.TxCcID)
+	assert.Equal(t, "user1", tx1.CallerName)
+	assert.Equal(t, "client", tx1.CallerOU)
+	assert.Equal(t, "put", tx1.CcEventName)
+	assert.Equal(t, "k1", tx1.CcEventPayload)
+	assert.Equal(t, int32(200), tx1.CcResponseStatus)
+	require.Len(t, tx1.TxEndorsers, 2)
+	assert.Equal(t, "Org1MSP", tx1.TxEndorsers[0].MspID)
+	assert.Contains(t, tx1.TxEndorsers[0].Subject, "CN=peer0.org1.example.com")
+	assert.Contains(t, tx1.TxEndorsers[0].Subject, "OU=peer")
+	assert.Equal(t, "Org2MSP", tx1.TxEndorsers[1].MspID)
+	assert.Contains(t, tx1.TxEndorsers[1].Subject, "CN=peer0.org2.example.com")
+	require.Len(t, tx1.TxWrites, 1)
+
+	// 链码事件反序列化失败时记录错误，但保留读写集
+	tx2 := blockInfo.TransactionInfos[1]
+	assert.Equal(t, int32(peer.TxValidationCode_MVCC_READ_CONFLICT), tx2.ValidationCode)
+	assert.False(t, tx2.IsValid())
+	assert.Contains(t, tx2.ErrorMsg, "chaincode event")
+	assert.Empty(t, tx2.CcEventName)
+	require.Len(t, tx2.TxWrites, 1)
+	assert.Equal(t, "k1", tx2.TxWrites[0].WriteKey)
+	assert.Equal(t, "v1", tx2.TxWrites[0].WriteValue)
+	require.Len(t, tx2.TxEndorsers, 1)
+	assert.Contains(t, tx2.TxEndorsers[0].Subject, "CN=peer0.org1.example.com")
+}
@@ -0,0 +1,542 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+/*
+pkg/util/chn_browse_util/chn_config_history.go 通道配置历史，根据区块元数据中的LastConfig回溯通道的全部配置区块，
+并对相邻两个版本的通道配置做语义比较。
+*/
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	mb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	ab "gitee.com/zhaochuninhefei/fabric-protos-go-gm/orderer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/common/channelconfig"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/chconfig"
+	"gitee.com/zhaochuninhefei/gmgo/x509"
+	"github.com/golang/protobuf/proto"
+)
+
+// 通道配置差异的分类
+const (
+	ConfigDiffMSP             = "MSP"
+	ConfigDiffAnchorPeer      = "AnchorPeer"
+	ConfigDiffOrdererEndpoint = "OrdererEndpoint"
+	ConfigDiffBatchSize       = "BatchSize"
+	ConfigDiffBatchTimeout    = "BatchTimeout"
+	ConfigDiffCapability      = "Capability"
+	ConfigDiffPolicy          = "Policy"
+)
+
+// 通道配置差异的操作
+const (
+	ConfigDiffAdded    = "Added"
+	ConfigDiffRemoved  = "Removed"
+	ConfigDiffModified = "Modified"
+)
+
+// ChannelConfigVersion 通道配置版本
+type ChannelConfigVersion struct {
+	BlockNum   uint64                     // 配置区块编号
+	Sequence   uint64                     // 配置序号
+	UpdateTime string                     // 配置更新时间
+	Submitter  *TransactionEndorserInfo   // 配置更新提交者，创世区块为nil
+	Signers    []*TransactionEndorserInfo // 配置更新签名者集合
+	Config     fab.ChannelCfg             // 通道配置
+	Snapshot   *ChannelConfigSnapshot     // 用于比较的通道配置摘要
+}
+
+// ChannelConfigSnapshot 通道配置摘要
+type ChannelConfigSnapshot struct {
+	MSPs             map[string]string   // MSPID -> MSP配置的哈希
+	AnchorPeers      []string            // 锚节点集合，格式为"组织 host:port"
+	OrdererEndpoints []string            // 排序节点地址集合，组织级地址格式为"组织 host:port"
+	BatchSize        string              // 出块大小配置
+	BatchTimeout     string              // 出块超时配置
+	Capabilities     map[string][]string // 配置组 -> 能力集合
+	Policies         map[string]string   // 策略路径 -> 策略说明
+}
+
+// ChannelConfigDiffItem 通道配置差异项
+type ChannelConfigDiffItem struct {
+	Category string // 差异分类
+	Action   string // 差异操作
+	Key      string // 差异对象
+	Before   string // 变更前的值
+	After    string // 变更后的值
+}
+
+func (t *ChannelConfigDiffItem) ToString() string {
+	return fmt.Sprintf("[%s] %s %s: %s -> %s", t.Category, t.Action, t.Key, t.Before, t.After)
+}
+
+// ChannelConfigDiff 相邻两个版本的通道配置差异
+type ChannelConfigDiff struct {
+	FromBlockNum uint64                     // 变更前的配置区块编号
+	ToBlockNum   uint64                     // 变更后的配置区块编号
+	UpdateTime   string                     // 配置更新时间
+	Submitter    *TransactionEndorserInfo   // 配置更新提交者
+	Signers      []*TransactionEndorserInfo // 配置更新签名者集合
+	Items        []*ChannelConfigDiffItem   // 差异项集合
+}
+
+func (t *ChannelConfigDiff) ToString() string {
+	submitter := ""
+	if t.Submitter != nil {
+		submitter = t.Submitter.ToString()
+	}
+	signers := []string{}
+	for _, s := range t.Signers {
+		signers = append(signers, s.ToString())
+	}
+	result := fmt.Sprintf("配置区块: %d -> %d, 更新时间: %s, 提交者: %s, 签名者: %q, 差异项:",
+		t.FromBlockNum, t.ToBlockNum, t.UpdateTime, submitter, signers)
+	for _, item := range t.Items {
+		result = result + "\n\t" + item.ToString()
+	}
+	return result
+}
+
+// QueryChannelConfigHistory 查询通道的全部配置版本，按配置区块编号从旧到新排列。
+//  从最新区块的LastConfig开始，依次通过前一个区块的LastConfig回溯到创世区块。
+//  入参: querier 区块查询接口，通常为`*ledger.Client`
+//  入参: channelID 通道ID
+//  返回: 通道配置版本集合
+func QueryChannelConfigHistory(querier BlockQuerier, channelID string) ([]*ChannelConfigVersion, error) {
+	blockChainInfo, err := querier.QueryInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockInfo: %s", err)
+	}
+	if blockChainInfo.BCI.Height == 0 {
+		return nil, fmt.Errorf("the ledger has no block")
+	}
+	block, err := querier.QueryBlock(blockChainInfo.BCI.Height - 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to QueryBlock: %s", err)
+	}
+	configIndex, err := getLastConfigIndex(block)
+	if err != nil {
+		return nil, err
+	}
+	versions := []*ChannelConfigVersion{}
+	for {
+		configBlock, err := querier.QueryBlock(configIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to QueryBlock: %s", err)
+		}
+		version, err := UnmarshalConfigBlock(channelID, configBlock)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+		if configIndex == 0 {
+			break
+		}
+		// 配置区块的前一个区块记录了上一个配置区块的编号
+		preBlock, err := querier.QueryBlock(configIndex - 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to QueryBlock: %s", err)
+		}
+		preConfigIndex, err := getLastConfigIndex(preBlock)
+		if err != nil {
+			return nil, err
+		}
+		if preConfigIndex >= configIndex {
+			return nil, fmt.Errorf("invalid last config index %d in block %d", preConfigIndex, configIndex-1)
+		}
+		configIndex = preConfigIndex
+	}
+	// 反转为从旧到新
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions, nil
+}
+
+// QueryChannelConfigDiffs 查询通道配置历史，并返回相邻两个版本之间的差异，按时间从旧到新排列。
+//  入参: querier 区块查询接口，通常为`*ledger.Client`
+//  入参: channelID 通道ID
+//  返回: 通道配置差异集合
+func QueryChannelConfigDiffs(querier BlockQuerier, channelID string) ([]*ChannelConfigDiff, error) {
+	versions, err := QueryChannelConfigHistory(querier, channelID)
+	if err != nil {
+		return nil, err
+	}
+	diffs := []*ChannelConfigDiff{}
+	for i := 1; i < len(versions); i++ {
+		diffs = append(diffs, DiffChannelConfig(versions[i-1], versions[i]))
+	}
+	return diffs, nil
+}
+
+// 获取区块元数据中记录的最后配置区块编号
+func getLastConfigIndex(block *common.Block) (uint64, error) {
+	if protoutil.IsConfigBlock(block) {
+		return block.Header.Number, nil
+	}
+	index, err := protoutil.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last config index from block %d: %s", block.Header.Number, err)
+	}
+	return index, nil
+}
+
+// UnmarshalConfigBlock 反序列化配置区块，获取通道配置以及配置更新的提交者、签名者与更新时间。
+//  入参: channelID 通道ID
+//  入参: block 配置区块
+//  返回: ChannelConfigVersion
+func UnmarshalConfigBlock(channelID string, block *common.Block) (*ChannelConfigVersion, error) {
+	cfg, err := chconfig.ExtractConfigFromBlock(channelID, block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract config from block %d: %s", block.Header.Number, err)
+	}
+	version := &ChannelConfigVersion{
+		BlockNum: block.Header.Number,
+		Config:   cfg,
+		Snapshot: NewChannelConfigSnapshot(cfg),
+		Signers:  []*TransactionEndorserInfo{},
+	}
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	configEnvelope := &common.ConfigEnvelope{}
+	channelHeader, err := protoutil.UnmarshalEnvelopeOfType(envelope, common.HeaderType_CONFIG, configEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	if channelHeader.Timestamp != nil {
+		version.UpdateTime = time.Unix(channelHeader.Timestamp.Seconds, 0).Format(txCreateTimeLayout)
+	}
+	if configEnvelope.Config != nil {
+		version.Sequence = configEnvelope.Config.Sequence
+	}
+	// 创世区块没有配置更新交易
+	if configEnvelope.LastUpdate == nil {
+		return version, nil
+	}
+	payload, err := protoutil.UnmarshalPayload(configEnvelope.LastUpdate.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header != nil {
+		signatureHeader, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+		if err == nil {
+			version.Submitter = unmarshalIdentityInfo(signatureHeader.Creator)
+		}
+	}
+	configUpdateEnvelope := &common.ConfigUpdateEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configUpdateEnvelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config update envelope: %s", err)
+	}
+	for _, configSignature := range configUpdateEnvelope.Signatures {
+		signatureHeader, err := protoutil.UnmarshalSignatureHeader(configSignature.SignatureHeader)
+		if err != nil {
+			continue
+		}
+		if signer := unmarshalIdentityInfo(signatureHeader.Creator); signer != nil {
+			version.Signers = append(version.Signers, signer)
+		}
+	}
+	return version, nil
+}
+
+// 反序列化身份，获取MSPID与证书主题
+func unmarshalIdentityInfo(serializedID []byte) *TransactionEndorserInfo {
+	sid := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sid); err != nil {
+		return nil
+	}
+	info := &TransactionEndorserInfo{
+		MspID: sid.GetMspid(),
+	}
+	cert, err := x509.ReadCertificateFromPem(sid.IdBytes)
+	if err == nil {
+		info.Subject = cert.Subject.String()
+	}
+	return info
+}
+
+// NewChannelConfigSnapshot 根据通道配置生成用于比较的配置摘要
+func NewChannelConfigSnapshot(cfg fab.ChannelCfg) *ChannelConfigSnapshot {
+	snapshot := &ChannelConfigSnapshot{
+		MSPs:         map[string]string{},
+		Capabilities: map[string][]string{},
+		Policies:     map[string]string{},
+	}
+	for _, mspConfig := range cfg.MSPs() {
+		fabricMSPConfig := &mb.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+			continue
+		}
+		snapshot.MSPs[fabricMSPConfig.Name] = hex.EncodeToString(computeSM3(mspConfig.Config))
+	}
+	for _, anchorPeer := range cfg.AnchorPeers() {
+		snapshot.AnchorPeers = append(snapshot.AnchorPeers, fmt.Sprintf("%s %s:%d", anchorPeer.Org, anchorPeer.Host, anchorPeer.Port))
+	}
+	snapshot.OrdererEndpoints = append(snapshot.OrdererEndpoints, cfg.Orderers()...)
+	var channelGroup *common.ConfigGroup
+	if cfg.Versions() != nil {
+		channelGroup = cfg.Versions().Channel
+	}
+	if channelGroup != nil {
+		snapshot.Capabilities[channelconfig.ChannelGroupKey] = unmarshalCapabilities(channelGroup)
+		if ordererGroup, ok := channelGroup.Groups[channelconfig.OrdererGroupKey]; ok {
+			snapshot.Capabilities[channelconfig.OrdererGroupKey] = unmarshalCapabilities(ordererGroup)
+			snapshot.BatchSize = unmarshalBatchSize(ordererGroup)
+			snapshot.BatchTimeout = unmarshalBatchTimeout(ordererGroup)
+			// 组织级的排序节点地址
+			for org, orgGroup := range ordererGroup.Groups {
+				if v, ok := orgGroup.Values[channelconfig.EndpointsKey]; ok {
+					addresses := &common.OrdererAddresses{}
+					if proto.Unmarshal(v.Value, addresses) == nil {
+						for _, addr := range addresses.Addresses {
+							snapshot.OrdererEndpoints = append(snapshot.OrdererEndpoints, org+" "+addr)
+						}
+					}
+				}
+			}
+		}
+		if applicationGroup, ok := channelGroup.Groups[channelconfig.ApplicationGroupKey]; ok {
+			snapshot.Capabilities[channelconfig.ApplicationGroupKey] = unmarshalCapabilities(applicationGroup)
+		}
+		collectPolicies(channelconfig.ChannelGroupKey, channelGroup, snapshot.Policies)
+	}
+	sort.Strings(snapshot.AnchorPeers)
+	sort.Strings(snapshot.OrdererEndpoints)
+	return snapshot
+}
+
+func unmarshalCapabilities(group *common.ConfigGroup) []string {
+	result := []string{}
+	v, ok := group.Values[channelconfig.CapabilitiesKey]
+	if !ok {
+		return result
+	}
+	capabilities := &common.Capabilities{}
+	if err := proto.Unmarshal(v.Value, capabilities); err != nil {
+		return result
+	}
+	for c := range capabilities.Capabilities {
+		result = append(result, c)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func unmarshalBatchSize(ordererGroup *common.ConfigGroup) string {
+	v, ok := ordererGroup.Values[channelconfig.BatchSizeKey]
+	if !ok {
+		return ""
+	}
+	batchSize := &ab.BatchSize{}
+	if err := proto.Unmarshal(v.Value, batchSize); err != nil {
+		return ""
+	}
+	return fmt.Sprintf("MaxMessageCount: %d, AbsoluteMaxBytes: %d, PreferredMaxBytes: %d",
+		batchSize.MaxMessageCount, batchSize.AbsoluteMaxBytes, batchSize.PreferredMaxBytes)
+}
+
+func unmarshalBatchTimeout(ordererGroup *common.ConfigGroup) string {
+	v, ok := ordererGroup.Values[channelconfig.BatchTimeoutKey]
+	if !ok {
+		return ""
+	}
+	batchTimeout := &ab.BatchTimeout{}
+	if err := proto.Unmarshal(v.Value, batchTimeout); err != nil {
+		return ""
+	}
+	return batchTimeout.Timeout
+}
+
+// 递归收集配置组内的全部策略，策略路径格式为"Channel/Application/Org1MSP/Readers"
+func collectPolicies(path string, group *common.ConfigGroup, policies map[string]string) {
+	for name, configPolicy := range group.Policies {
+		policies[path+"/"+name] = fmt.Sprintf("%s, mod_policy: %s", DescribePolicy(configPolicy.Policy), configPolicy.ModPolicy)
+	}
+	for name, subGroup := range group.Groups {
+		collectPolicies(path+"/"+name, subGroup, policies)
+	}
+}
+
+// DescribePolicy 将通道配置中的策略转为可读的说明
+func DescribePolicy(policy *common.Policy) string {
+	if policy == nil {
+		return ""
+	}
+	switch common.Policy_PolicyType(policy.Type) {
+	case common.Policy_IMPLICIT_META:
+		implicitMetaPolicy := &common.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Value, implicitMetaPolicy); err != nil {
+			break
+		}
+		return fmt.Sprintf("ImplicitMeta(%s %s)", implicitMetaPolicy.Rule, implicitMetaPolicy.SubPolicy)
+	case common.Policy_SIGNATURE:
+		envelope := &common.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(policy.Value, envelope); err != nil || envelope.Rule == nil {
+			break
+		}
+		return "Signature(" + DescribeSignaturePolicy(envelope) + ")"
+	}
+	return fmt.Sprintf("%s(%s)", common.Policy_PolicyType(policy.Type), hex.EncodeToString(computeSM3(policy.Value)))
+}
+
+// DescribeSignaturePolicy 将签名策略转为与policydsl相近的可读形式，如"OutOf(1, 'Org1MSP.member', 'Org2MSP.member')"
+func DescribeSignaturePolicy(envelope *common.SignaturePolicyEnvelope) string {
+	principals := make([]string, len(envelope.Identities))
+	for i, principal := range envelope.Identities {
+		principals[i] = describePrincipal(principal)
+	}
+	return describeSignaturePolicyRule(envelope.Rule, principals)
+}
+
+func describeSignaturePolicyRule(rule *common.SignaturePolicy, principals []string) string {
+	switch t := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(principals) {
+			return fmt.Sprintf("'<invalid principal %d>'", t.SignedBy)
+		}
+		return "'" + principals[t.SignedBy] + "'"
+	case *common.SignaturePolicy_NOutOf_:
+		subRules := make([]string, len(t.NOutOf.Rules))
+		for i, r := range t.NOutOf.Rules {
+			subRules[i] = describeSignaturePolicyRule(r, principals)
+		}
+		return fmt.Sprintf("OutOf(%d, %s)", t.NOutOf.N, strings.Join(subRules, ", "))
+	}
+	return "<unknown rule>"
+}
+
+func describePrincipal(principal *mb.MSPPrincipal) string {
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		role := &mb.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			break
+		}
+		return role.MspIdentifier + "." + strings.ToLower(role.Role.String())
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mb.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			break
+		}
+		return ou.MspIdentifier + ".ou:" + ou.OrganizationalUnitIdentifier
+	case mb.MSPPrincipal_IDENTITY:
+		if identity := unmarshalIdentityInfo(principal.Principal); identity != nil {
+			return identity.MspID + ".identity:" + identity.Subject
+		}
+	}
+	return principal.PrincipalClassification.String()
+}
+
+// DiffChannelConfig 比较两个版本的通道配置
+//  入参: before 变更前的通道配置版本
+//  入参: after 变更后的通道配置版本
+//  返回: ChannelConfigDiff
+func DiffChannelConfig(before, after *ChannelConfigVersion) *ChannelConfigDiff {
+	diff := &ChannelConfigDiff{
+		FromBlockNum: before.BlockNum,
+		ToBlockNum:   after.BlockNum,
+		UpdateTime:   after.UpdateTime,
+		Submitter:    after.Submitter,
+		Signers:      after.Signers,
+		Items:        []*ChannelConfigDiffItem{},
+	}
+	b, a := before.Snapshot, after.Snapshot
+	diff.Items = append(diff.Items, diffStringMap(ConfigDiffMSP, b.MSPs, a.MSPs)...)
+	diff.Items = append(diff.Items, diffStringSet(ConfigDiffAnchorPeer, b.AnchorPeers, a.AnchorPeers)...)
+	diff.Items = append(diff.Items, diffStringSet(ConfigDiffOrdererEndpoint, b.OrdererEndpoints, a.OrdererEndpoints)...)
+	if b.BatchSize != a.BatchSize {
+		diff.Items = append(diff.Items, &ChannelConfigDiffItem{Category: ConfigDiffBatchSize, Action: ConfigDiffModified, Key: channelconfig.BatchSizeKey, Before: b.BatchSize, After: a.BatchSize})
+	}
+	if b.BatchTimeout != a.BatchTimeout {
+		diff.Items = append(diff.Items, &ChannelConfigDiffItem{Category: ConfigDiffBatchTimeout, Action: ConfigDiffModified, Key: channelconfig.BatchTimeoutKey, Before: b.BatchTimeout, After: a.BatchTimeout})
+	}
+	groups := map[string]struct{}{}
+	for g := range b.Capabilities {
+		groups[g] = struct{}{}
+	}
+	for g := range a.Capabilities {
+		groups[g] = struct{}{}
+	}
+	for _, g := range sortedKeys(groups) {
+		for _, item := range diffStringSet(ConfigDiffCapability, b.Capabilities[g], a.Capabilities[g]) {
+			item.Key = g + "/" + item.Key
+			diff.Items = append(diff.Items, item)
+		}
+	}
+	diff.Items = append(diff.Items, diffStringMap(ConfigDiffPolicy, b.Policies, a.Policies)...)
+	return diff
+}
+
+func diffStringSet(category string, before, after []string) []*ChannelConfigDiffItem {
+	items := []*ChannelConfigDiffItem{}
+	beforeSet := map[string]struct{}{}
+	for _, v := range before {
+		beforeSet[v] = struct{}{}
+	}
+	afterSet := map[string]struct{}{}
+	for _, v := range after {
+		afterSet[v] = struct{}{}
+	}
+	for _, v := range sortedKeys(beforeSet) {
+		if _, ok := afterSet[v]; !ok {
+			items = append(items, &ChannelConfigDiffItem{Category: category, Action: ConfigDiffRemoved, Key: v, Before: v})
+		}
+	}
+	for _, v := range sortedKeys(afterSet) {
+		if _, ok := beforeSet[v]; !ok {
+			items = append(items, &ChannelConfigDiffItem{Category: category, Action: ConfigDiffAdded, Key: v, After: v})
+		}
+	}
+	return items
+}
+
+func diffStringMap(category string, before, after map[string]string) []*ChannelConfigDiffItem {
+	items := []*ChannelConfigDiffItem{}
+	keys := map[string]struct{}{}
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+	for _, k := range sortedKeys(keys) {
+		b, inBefore := before[k]
+		a, inAfter := after[k]
+		switch {
+		case !inAfter:
+			items = append(items, &ChannelConfigDiffItem{Category: category, Action: ConfigDiffRemoved, Key: k, Before: b})
+		case !inBefore:
+			items = append(items, &ChannelConfigDiffItem{Category: category, Action: ConfigDiffAdded, Key: k, After: a})
+		case a != b:
+			items = append(items, &ChannelConfigDiffItem{Category: category, Action: ConfigDiffModified, Key: k, Before: b, After: a})
+		}
+	}
+	return items
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+import (
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	mb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/msp"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/mocks"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 构建配置区块，submitter不为空时附带配置更新交易
+func newMockConfigBlock(t *testing.T, num uint64, mspNames []string, ordererAddress string, submitter string) *common.Block {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       mspNames,
+			OrdererAddress: ordererAddress,
+		},
+		Index:           num,
+		LastConfigIndex: num,
+	}
+	block := builder.Build()
+	if submitter == "" {
+		return block
+	}
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	require.NoError(t, err)
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	require.NoError(t, err)
+	configEnvelope := &common.ConfigEnvelope{}
+	require.NoError(t, proto.Unmarshal(payload.Data, configEnvelope))
+
+	creator := protoutil.MarshalOrPanic(&mb.SerializedIdentity{Mspid: submitter})
+	signatureHeader := protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: creator})
+	configUpdateEnvelope := &common.ConfigUpdateEnvelope{
+		Signatures: []*common.ConfigSignature{{SignatureHeader: signatureHeader}},
+	}
+	configEnvelope.LastUpdate = &common.Envelope{
+		Payload: protoutil.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{SignatureHeader: signatureHeader},
+			Data:   protoutil.MarshalOrPanic(configUpdateEnvelope),
+		}),
+	}
+	payload.Data = protoutil.MarshalOrPanic(configEnvelope)
+	envelope.Payload = protoutil.MarshalOrPanic(payload)
+	block.Data.Data[0] = protoutil.MarshalOrPanic(envelope)
+	return block
+}
+
+// 构建普通区块，元数据中记录最后配置区块编号
+func newMockBlockWithLastConfig(num, lastConfig uint64) *common.Block {
+	block := protoutil.NewBlock(num, nil)
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&common.Metadata{
+		Value: protoutil.MarshalOrPanic(&common.OrdererBlockMetadata{
+			LastConfig: &common.LastConfig{Index: lastConfig},
+		}),
+	})
+	return block
+}
+
+func TestQueryChannelConfigHistory(t *testing.T) {
+	q := &mockBlockQuerier{}
+	q.blocks = append(q.blocks, newMockConfigBlock(t, 0, []string{"Org1MSP"}, "orderer1:7050", ""))
+	q.blocks = append(q.blocks, newMockBlockWithLastConfig(1, 0))
+	q.blocks = append(q.blocks, newMockConfigBlock(t, 2, []string{"Org1MSP", "Org2MSP"}, "orderer1:7050", "Org1MSP"))
+	q.blocks = append(q.blocks, newMockBlockWithLastConfig(3, 2))
+	q.blocks = append(q.blocks, newMockConfigBlock(t, 4, []string{"Org1MSP", "Org2MSP"}, "orderer2:7050", "Org2MSP"))
+	q.blocks = append(q.blocks, newMockBlockWithLastConfig(5, 4))
+
+	versions, err := QueryChannelConfigHistory(q, "mychannel")
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, uint64(0), versions[0].BlockNum)
+	assert.Equal(t, uint64(2), versions[1].BlockNum)
+	assert.Equal(t, uint64(4), versions[2].BlockNum)
+	assert.Nil(t, versions[0].Submitter)
+	require.NotNil(t, versions[1].Submitter)
+	assert.Equal(t, "Org1MSP", versions[1].Submitter.MspID)
+	require.Len(t, versions[2].Signers, 1)
+	assert.Equal(t, "Org2MSP", versions[2].Signers[0].MspID)
+
+	diffs, err := QueryChannelConfigDiffs(q, "mychannel")
+	require.NoError(t, err)
+	require.Len(t, diffs, 2)
+
+	// 第一次配置更新新增了Org2MSP，及其下的策略
+	assert.Contains(t, diffs[0].Items, &ChannelConfigDiffItem{
+		Category: ConfigDiffMSP, Action: ConfigDiffAdded, Key: "Org2MSP", After: versions[1].Snapshot.MSPs["Org2MSP"],
+	})
+	for _, item := range diffs[0].Items {
+		assert.NotEqual(t, ConfigDiffOrdererEndpoint, item.Category)
+		assert.NotEqual(t, ConfigDiffRemoved, item.Action)
+	}
+
+	// 第二次配置更新只替换了排序节点地址
+	assert.Equal(t, []*ChannelConfigDiffItem{
+		{Category: ConfigDiffOrdererEndpoint, Action: ConfigDiffRemoved, Key: "orderer1:7050", Before: "orderer1:7050"},
+		{Category: ConfigDiffOrdererEndpoint, Action: ConfigDiffAdded, Key: "orderer2:7050", After: "orderer2:7050"},
+	}, diffs[1].Items)
+}
+
+func TestQueryChannelConfigHistoryInvalidLastConfig(t *testing.T) {
+	q := &mockBlockQuerier{}
+	q.blocks = append(q.blocks, newMockConfigBlock(t, 0, []string{"Org1MSP"}, "orderer1:7050", ""))
+	q.blocks = append(q.blocks, newMockBlockWithLastConfig(1, 2))
+	q.blocks = append(q.blocks, newMockConfigBlock(t, 2, []string{"Org1MSP"}, "orderer1:7050", "Org1MSP"))
+
+	_, err := QueryChannelConfigHistory(q, "mychannel")
+	assert.Error(t, err)
+}
+
+func TestDescribeSignaturePolicy(t *testing.T) {
+	principal := func(mspID string, role mb.MSPRole_MSPRoleType) *mb.MSPPrincipal {
+		return &mb.MSPPrincipal{
+			PrincipalClassification: mb.MSPPrincipal_ROLE,
+			Principal:               protoutil.MarshalOrPanic(&mb.MSPRole{MspIdentifier: mspID, Role: role}),
+		}
+	}
+	envelope := &common.SignaturePolicyEnvelope{
+		Rule: &common.SignaturePolicy{
+			Type: &common.SignaturePolicy_NOutOf_{
+				NOutOf: &common.SignaturePolicy_NOutOf{
+					N: 1,
+					Rules: []*common.SignaturePolicy{
+						{Type: &common.SignaturePolicy_SignedBy{SignedBy: 0}},
+						{Type: &common.SignaturePolicy_SignedBy{SignedBy: 1}},
+					},
+				},
+			},
+		},
+		Identities: []*mb.MSPPrincipal{principal("Org1MSP", mb.MSPRole_MEMBER), principal("Org2MSP", mb.MSPRole_ADMIN)},
+	}
+	assert.Equal(t, "OutOf(1, 'Org1MSP.member', 'Org2MSP.admin')", DescribeSignaturePolicy(envelope))
+
+	policy := &common.Policy{
+		Type:  int32(common.Policy_IMPLICIT_META),
+		Value: protoutil.MarshalOrPanic(&common.ImplicitMetaPolicy{Rule: common.ImplicitMetaPolicy_MAJORITY, SubPolicy: "Admins"}),
+	}
+	assert.Equal(t, "ImplicitMeta(MAJORITY Admins)", DescribePolicy(policy))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+/*
+pkg/util/chn_browse_util/chn_pvt_resolver.go 基于peer的DeliverWithPrivateData服务获取区块私有数据。
+peer只返回调用者所属组织有权访问的私有数据集合，其余集合的哈希读写集保持未还原状态。
+*/
+
+import (
+	"fmt"
+	"time"
+
+	cb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset"
+	ab "gitee.com/zhaochuninhefei/fabric-protos-go-gm/orderer"
+	pb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/context"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/common/providers/fab"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/comm"
+	clientdisp "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/client/dispatcher"
+	deliverconn "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/deliverclient/connection"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/deliverclient/seek"
+)
+
+// deliver连接接口，`*connection.DeliverConnection`实现了该接口
+type pvtDataConnection interface {
+	Send(seekInfo *ab.SeekInfo) error
+	Receive(eventch chan<- interface{})
+	Close()
+}
+
+// DeliverPrivateDataResolver 基于peer的DeliverWithPrivateData服务的私有数据获取实现。
+//  每次获取私有数据时建立一次deliver连接，只请求对应的一个区块。
+type DeliverPrivateDataResolver struct {
+	connect func() (pvtDataConnection, error)
+	timeout time.Duration
+}
+
+// NewDeliverPrivateDataResolver 创建基于peer DeliverWithPrivateData服务的私有数据获取实现
+//  入参: channelProvider 通道上下文，如`sdk.ChannelContext(channelID, fabsdk.WithUser(user), fabsdk.WithOrg(org))`，
+//        用户所属组织需要是私有数据集合的成员
+//  入参: peerNameOrURL 目标peer的名称或地址，需要在SDK配置中存在
+//  返回: DeliverPrivateDataResolver
+func NewDeliverPrivateDataResolver(channelProvider context.ChannelProvider, peerNameOrURL string) (*DeliverPrivateDataResolver, error) {
+	ctx, err := channelProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create channel context: %s", err)
+	}
+	chConfig, err := ctx.ChannelService().ChannelConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel config: %s", err)
+	}
+	peerCfg, ok := ctx.EndpointConfig().PeerConfig(peerNameOrURL)
+	if !ok {
+		return nil, fmt.Errorf("peer %s not found in config", peerNameOrURL)
+	}
+	opts := comm.OptsFromPeerConfig(peerCfg)
+	opts = append(opts, comm.WithConnectTimeout(ctx.EndpointConfig().Timeout(fab.PeerConnection)))
+	return &DeliverPrivateDataResolver{
+		connect: func() (pvtDataConnection, error) {
+			return deliverconn.New(ctx, chConfig, deliverconn.DeliverWithPrivateData, peerCfg.URL, opts...)
+		},
+		timeout: ctx.EndpointConfig().Timeout(fab.PeerResponse),
+	}, nil
+}
+
+// GetPrivateData 从peer获取指定区块的私有数据
+func (r *DeliverPrivateDataResolver) GetPrivateData(blockNum uint64) (map[uint64]*rwset.TxPvtReadWriteSet, error) {
+	conn, err := r.connect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to deliver service: %s", err)
+	}
+	defer conn.Close()
+
+	// 一个区块响应加一个状态响应
+	eventch := make(chan interface{}, 2)
+	go conn.Receive(eventch)
+	if err := conn.Send(seek.InfoRange(blockNum, blockNum)); err != nil {
+		return nil, fmt.Errorf("failed to send seek request: %s", err)
+	}
+
+	var pvtDataMap map[uint64]*rwset.TxPvtReadWriteSet
+	timer := time.NewTimer(r.timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case e := <-eventch:
+			switch evt := e.(type) {
+			case *deliverconn.Event:
+				resp, ok := evt.Event.(*pb.DeliverResponse)
+				if !ok {
+					return nil, fmt.Errorf("unexpected deliver event type: %T", evt.Event)
+				}
+				switch t := resp.Type.(type) {
+				case *pb.DeliverResponse_BlockAndPrivateData:
+					if t.BlockAndPrivateData.Block == nil || t.BlockAndPrivateData.Block.Header == nil ||
+						t.BlockAndPrivateData.Block.Header.Number != blockNum {
+						return nil, fmt.Errorf("unexpected block in deliver response, expected block %d", blockNum)
+					}
+					pvtDataMap = t.BlockAndPrivateData.PrivateDataMap
+				case *pb.DeliverResponse_Status:
+					if t.Status != cb.Status_SUCCESS {
+						return nil, fmt.Errorf("deliver service returned status: %s", t.Status)
+					}
+					return pvtDataMap, nil
+				default:
+					return nil, fmt.Errorf("unexpected deliver response type: %T", resp.Type)
+				}
+			case *clientdisp.DisconnectedEvent:
+				return nil, fmt.Errorf("deliver stream disconnected: %s", evt.Err)
+			default:
+				return nil, fmt.Errorf("unexpected deliver event type: %T", e)
+			}
+		case <-timer.C:
+			return nil, fmt.Errorf("timed out waiting for private data of block %d", blockNum)
+		}
+	}
+}
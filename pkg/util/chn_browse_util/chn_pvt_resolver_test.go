@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+import (
+	"testing"
+	"time"
+
+	cb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset"
+	ab "gitee.com/zhaochuninhefei/fabric-protos-go-gm/orderer"
+	pb "gitee.com/zhaochuninhefei/fabric-protos-go-gm/peer"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	deliverconn "gitee.com/zhaochuninhefei/fabric-sdk-go-gm/pkg/fab/events/deliverclient/connection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPvtDataConnection 收到seek请求后依次返回预设的deliver响应
+type mockPvtDataConnection struct {
+	responses []*pb.DeliverResponse
+	seekInfo  *ab.SeekInfo
+	sent      chan struct{}
+	closed    bool
+}
+
+func (c *mockPvtDataConnection) Send(seekInfo *ab.SeekInfo) error {
+	c.seekInfo = seekInfo
+	close(c.sent)
+	return nil
+}
+
+func (c *mockPvtDataConnection) Receive(eventch chan<- interface{}) {
+	<-c.sent
+	for _, resp := range c.responses {
+		eventch <- deliverconn.NewEvent(resp, "peer0.org1.example.com:7051")
+	}
+}
+
+func (c *mockPvtDataConnection) Close() {
+	c.closed = true
+}
+
+func newMockPvtDataResolver(conn *mockPvtDataConnection) *DeliverPrivateDataResolver {
+	return &DeliverPrivateDataResolver{
+		connect: func() (pvtDataConnection, error) {
+			return conn, nil
+		},
+		timeout: time.Second,
+	}
+}
+
+func TestDeliverPrivateDataResolver(t *testing.T) {
+	pvtData := map[uint64]*rwset.TxPvtReadWriteSet{
+		1: {NsPvtRwset: []*rwset.NsPvtReadWriteSet{{Namespace: "mycc"}}},
+	}
+	conn := &mockPvtDataConnection{
+		sent: make(chan struct{}),
+		responses: []*pb.DeliverResponse{
+			{Type: &pb.DeliverResponse_BlockAndPrivateData{BlockAndPrivateData: &pb.BlockAndPrivateData{
+				Block:          protoutil.NewBlock(7, nil),
+				PrivateDataMap: pvtData,
+			}}},
+			{Type: &pb.DeliverResponse_Status{Status: cb.Status_SUCCESS}},
+		},
+	}
+	result, err := newMockPvtDataResolver(conn).GetPrivateData(7)
+	require.NoError(t, err)
+	assert.Equal(t, pvtData, result)
+	assert.True(t, conn.closed)
+	assert.Equal(t, uint64(7), conn.seekInfo.Start.GetSpecified().Number)
+	assert.Equal(t, uint64(7), conn.seekInfo.Stop.GetSpecified().Number)
+
+	// 无权访问通道时peer返回FORBIDDEN
+	conn = &mockPvtDataConnection{
+		sent:      make(chan struct{}),
+		responses: []*pb.DeliverResponse{{Type: &pb.DeliverResponse_Status{Status: cb.Status_FORBIDDEN}}},
+	}
+	_, err = newMockPvtDataResolver(conn).GetPrivateData(7)
+	assert.Error(t, err)
+
+	// 返回的区块编号与请求不一致
+	conn = &mockPvtDataConnection{
+		sent: make(chan struct{}),
+		responses: []*pb.DeliverResponse{
+			{Type: &pb.DeliverResponse_BlockAndPrivateData{BlockAndPrivateData: &pb.BlockAndPrivateData{
+				Block: protoutil.NewBlock(8, nil),
+			}}},
+		},
+	}
+	_, err = newMockPvtDataResolver(conn).GetPrivateData(7)
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+/*
+pkg/util/chn_browse_util/chn_pvt_rwset.go 私有数据集合读写集解析。
+区块中只保存私有数据集合的哈希读写集，解析出的Key与Value均为SM3哈希；
+提供私有数据后，可以将哈希读写集还原为明文Key与Value。
+*/
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/core/ledger/kvledger/txmgmt/rwsetutil"
+	"gitee.com/zhaochuninhefei/gmgo/sm3"
+)
+
+// 交易读取私有数据情报
+type TransactionPvtReadInfo struct {
+	NameSpace        string // 所属链码
+	Collection       string // 私有数据集合名称
+	ReadKeyHash      string // 交易读取Key的哈希(16进制字符串)
+	ReadBlockNum     uint64 // 交易读取区块编号
+	ReadTxNumInBlock uint64 // 交易读取交易编号(区块内部)
+	ReadKey          string // 交易读取Key，还原私有数据后才有值
+	Resolved         bool   // 是否已还原私有数据
+}
+
+func (t *TransactionPvtReadInfo) ToString() string {
+	return fmt.Sprintf("NameSpace: %s, Collection: %s, ReadKeyHash: %s, ReadBlockNum: %d, ReadTxNumInBlock: %d, ReadKey: %s, Resolved: %v",
+		t.NameSpace, t.Collection, t.ReadKeyHash, t.ReadBlockNum, t.ReadTxNumInBlock, t.ReadKey, t.Resolved)
+}
+
+// 交易写入私有数据情报
+type TransactionPvtWriteInfo struct {
+	NameSpace      string // 所属链码
+	Collection     string // 私有数据集合名称
+	WriteKeyHash   string // 交易写入Key的哈希(16进制字符串)
+	WriteValueHash string // 交易写入数据的哈希(16进制字符串)
+	IsDelete       bool   // 是否删除
+	WriteKey       string // 交易写入Key，还原私有数据后才有值
+	WriteValue     string // 交易写入数据，还原私有数据后才有值
+	Resolved       bool   // 是否已还原私有数据
+}
+
+func (t *TransactionPvtWriteInfo) ToString() string {
+	return fmt.Sprintf("NameSpace: %s, Collection: %s, WriteKeyHash: %s, WriteValueHash: %s, IsDelete: %v, WriteKey: %s, WriteValue: %s, Resolved: %v",
+		t.NameSpace, t.Collection, t.WriteKeyHash, t.WriteValueHash, t.IsDelete, t.WriteKey, t.WriteValue, t.Resolved)
+}
+
+// TouchedCollections 返回交易读写过的私有数据集合，格式为"链码/集合"
+func (t *TransactionInfo) TouchedCollections() []string {
+	collSet := map[string]struct{}{}
+	for _, r := range t.TxPvtReads {
+		collSet[r.NameSpace+"/"+r.Collection] = struct{}{}
+	}
+	for _, w := range t.TxPvtWrites {
+		collSet[w.NameSpace+"/"+w.Collection] = struct{}{}
+	}
+	colls := make([]string, 0, len(collSet))
+	for c := range collSet {
+		colls = append(colls, c)
+	}
+	sort.Strings(colls)
+	return colls
+}
+
+// 解析私有数据集合的哈希读写集
+func unmarshalCollHashedRwSets(txReadWriteSet *rwset.TxReadWriteSet) ([]*TransactionPvtReadInfo, []*TransactionPvtWriteInfo, error) {
+	pvtReadInfos := []*TransactionPvtReadInfo{}
+	pvtWriteInfos := []*TransactionPvtWriteInfo{}
+	txRwSet, err := rwsetutil.TxRwSetFromProtoMsg(txReadWriteSet)
+	if err != nil {
+		return pvtReadInfos, pvtWriteInfos, err
+	}
+	for _, nsRwSet := range txRwSet.NsRwSets {
+		// 不处理 _lifecycle 等系统合约的隐式私有数据集合
+		if IsSysCC(nsRwSet.NameSpace) {
+			continue
+		}
+		for _, collHashedRwSet := range nsRwSet.CollHashedRwSets {
+			for _, r := range collHashedRwSet.HashedRwSet.HashedReads {
+				pvtReadInfo := &TransactionPvtReadInfo{
+					NameSpace:   nsRwSet.NameSpace,
+					Collection:  collHashedRwSet.CollectionName,
+					ReadKeyHash: hex.EncodeToString(r.KeyHash),
+				}
+				if r.Version != nil {
+					pvtReadInfo.ReadBlockNum = r.Version.BlockNum
+					pvtReadInfo.ReadTxNumInBlock = r.Version.TxNum
+				}
+				pvtReadInfos = append(pvtReadInfos, pvtReadInfo)
+			}
+			for _, w := range collHashedRwSet.HashedRwSet.HashedWrites {
+				pvtWriteInfos = append(pvtWriteInfos, &TransactionPvtWriteInfo{
+					NameSpace:      nsRwSet.NameSpace,
+					Collection:     collHashedRwSet.CollectionName,
+					WriteKeyHash:   hex.EncodeToString(w.KeyHash),
+					WriteValueHash: hex.EncodeToString(w.ValueHash),
+					IsDelete:       w.IsDelete,
+				})
+			}
+		}
+	}
+	return pvtReadInfos, pvtWriteInfos, nil
+}
+
+// PrivateDataResolver 私有数据获取接口
+//  ledger.Client(qscc)不提供私有数据查询，私有数据需要由具备集合访问权限的组织从peer的
+//  DeliverWithPrivateData服务(DeliverPrivateDataResolver)或应用自身的存储(PrivateDataMap)中获取。
+type PrivateDataResolver interface {
+	// GetPrivateData 返回指定区块的私有数据，key为交易在区块内的序号，与peer.BlockAndPrivateData.PrivateDataMap一致。
+	GetPrivateData(blockNum uint64) (map[uint64]*rwset.TxPvtReadWriteSet, error)
+}
+
+// PrivateDataMap 基于内存的私有数据获取实现，key为区块编号
+type PrivateDataMap map[uint64]map[uint64]*rwset.TxPvtReadWriteSet
+
+// GetPrivateData 返回指定区块的私有数据
+func (m PrivateDataMap) GetPrivateData(blockNum uint64) (map[uint64]*rwset.TxPvtReadWriteSet, error) {
+	return m[blockNum], nil
+}
+
+// ResolvePrivateData 使用私有数据还原区块情报中各交易的私有数据读写集。
+//  只有Key的哈希一致(写入时Value的哈希也一致)的条目才会被还原，未获取到私有数据的条目保持不变。
+//  入参: blockInfo 区块情报
+//  入参: resolver 私有数据获取接口
+func ResolvePrivateData(blockInfo *BlockInfoWithTx, resolver PrivateDataResolver) error {
+	if resolver == nil {
+		return nil
+	}
+	pvtDataMap, err := resolver.GetPrivateData(blockInfo.BlockNum)
+	if err != nil {
+		return fmt.Errorf("failed to get private data of block %d: %s", blockInfo.BlockNum, err)
+	}
+	for _, txInfo := range blockInfo.TransactionInfos {
+		pvtData, ok := pvtDataMap[txInfo.TxSeqInBlock]
+		if !ok || pvtData == nil {
+			continue
+		}
+		if err := resolveTxPrivateData(txInfo, pvtData); err != nil {
+			return fmt.Errorf("failed to resolve private data of tx %s: %s", txInfo.TxID, err)
+		}
+	}
+	return nil
+}
+
+func resolveTxPrivateData(txInfo *TransactionInfo, pvtData *rwset.TxPvtReadWriteSet) error {
+	txPvtRwSet, err := rwsetutil.TxPvtRwSetFromProtoMsg(pvtData)
+	if err != nil {
+		return err
+	}
+	for _, nsPvtRwSet := range txPvtRwSet.NsPvtRwSet {
+		for _, collPvtRwSet := range nsPvtRwSet.CollPvtRwSets {
+			for _, r := range collPvtRwSet.KvRwSet.Reads {
+				keyHash := hex.EncodeToString(computeSM3([]byte(r.Key)))
+				for _, pvtRead := range txInfo.TxPvtReads {
+					if pvtRead.NameSpace == nsPvtRwSet.NameSpace && pvtRead.Collection == collPvtRwSet.CollectionName && pvtRead.ReadKeyHash == keyHash {
+						pvtRead.ReadKey = TrimUnknownHeader(r.Key)
+						pvtRead.Resolved = true
+					}
+				}
+			}
+			for _, w := range collPvtRwSet.KvRwSet.Writes {
+				keyHash := hex.EncodeToString(computeSM3([]byte(w.Key)))
+				for _, pvtWrite := range txInfo.TxPvtWrites {
+					if pvtWrite.NameSpace != nsPvtRwSet.NameSpace || pvtWrite.Collection != collPvtRwSet.CollectionName || pvtWrite.WriteKeyHash != keyHash {
+						continue
+					}
+					// 删除操作没有写入数据
+					if !pvtWrite.IsDelete {
+						valueHash, _ := hex.DecodeString(pvtWrite.WriteValueHash)
+						if !bytes.Equal(computeSM3(w.Value), valueHash) {
+							continue
+						}
+						pvtWrite.WriteValue = string(w.Value)
+					}
+					pvtWrite.WriteKey = TrimUnknownHeader(w.Key)
+					pvtWrite.Resolved = true
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func computeSM3(data []byte) []byte {
+	sum := sm3.Sm3Sum(data)
+	return sum[:]
+}
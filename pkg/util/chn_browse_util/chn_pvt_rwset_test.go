@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset"
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/ledger/rwset/kvrwset"
+	"gitee.com/zhaochuninhefei/fabric-sdk-go-gm/internal/gitee.com/zhaochuninhefei/fabric-gm/protoutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalAndResolvePvtRwSet(t *testing.T) {
+	hashedRwSet := &kvrwset.HashedRWSet{
+		HashedReads: []*kvrwset.KVReadHash{
+			{KeyHash: computeSM3([]byte("k1")), Version: &kvrwset.Version{BlockNum: 3, TxNum: 1}},
+		},
+		HashedWrites: []*kvrwset.KVWriteHash{
+			{KeyHash: computeSM3([]byte("k2")), ValueHash: computeSM3([]byte("v2"))},
+			{KeyHash: computeSM3([]byte("k3")), ValueHash: computeSM3([]byte("v3"))},
+		},
+	}
+	txReadWriteSet := &rwset.TxReadWriteSet{
+		NsRwset: []*rwset.NsReadWriteSet{{
+			Namespace: "mycc",
+			Rwset:     protoutil.MarshalOrPanic(&kvrwset.KVRWSet{}),
+			CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{{
+				CollectionName: "collA",
+				HashedRwset:    protoutil.MarshalOrPanic(hashedRwSet),
+			}},
+		}},
+	}
+	pvtReads, pvtWrites, err := unmarshalCollHashedRwSets(txReadWriteSet)
+	require.NoError(t, err)
+	require.Len(t, pvtReads, 1)
+	require.Len(t, pvtWrites, 2)
+	assert.Equal(t, hex.EncodeToString(computeSM3([]byte("k1"))), pvtReads[0].ReadKeyHash)
+	assert.Equal(t, uint64(3), pvtReads[0].ReadBlockNum)
+
+	txInfo := &TransactionInfo{TxID: "tx1", TxSeqInBlock: 1, TxPvtReads: pvtReads, TxPvtWrites: pvtWrites}
+	assert.Equal(t, []string{"mycc/collA"}, txInfo.TouchedCollections())
+
+	pvtKVRWSet := &kvrwset.KVRWSet{
+		Reads: []*kvrwset.KVRead{{Key: "k1"}},
+		Writes: []*kvrwset.KVWrite{
+			{Key: "k2", Value: []byte("v2")},
+			// Value与哈希不一致，不应还原
+			{Key: "k3", Value: []byte("tampered")},
+		},
+	}
+	resolver := PrivateDataMap{
+		7: {1: &rwset.TxPvtReadWriteSet{
+			NsPvtRwset: []*rwset.NsPvtReadWriteSet{{
+				Namespace: "mycc",
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{
+					CollectionName: "collA",
+					Rwset:          protoutil.MarshalOrPanic(pvtKVRWSet),
+				}},
+			}},
+		}},
+	}
+	blockInfo := &BlockInfoWithTx{
+		BlockInfoBasic:   BlockInfoBasic{BlockNum: 7},
+		TransactionInfos: []*TransactionInfo{txInfo},
+	}
+	require.NoError(t, ResolvePrivateData(blockInfo, resolver))
+	assert.True(t, pvtReads[0].Resolved)
+	assert.Equal(t, "k1", pvtReads[0].ReadKey)
+	assert.True(t, pvtWrites[0].Resolved)
+	assert.Equal(t, "k2", pvtWrites[0].WriteKey)
+	assert.Equal(t, "v2", pvtWrites[0].WriteValue)
+	assert.False(t, pvtWrites[1].Resolved)
+	assert.Empty(t, pvtWrites[1].WriteValue)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+/*
+pkg/util/chn_browse_util/chn_tx_filter.go 交易过滤，在反序列化区块时按条件筛选交易情报。
+*/
+
+import (
+	"strings"
+	"time"
+
+	"gitee.com/zhaochuninhefei/fabric-protos-go-gm/common"
+)
+
+// TxCreateTime的格式
+const txCreateTimeLayout = "2006-01-02 15:04:05"
+
+// TxFilter 交易过滤条件
+//  各条件之间为"与"关系，集合类条件内部为"或"关系，未设置的条件不参与过滤。
+type TxFilter struct {
+	ChaincodeIDs    []string  // 交易调用链码ID
+	CallerMspIDs    []string  // 交易发起者MSPID
+	CallerOUs       []string  // 交易发起者OU分组
+	TxTypes         []int     // 交易类型
+	StartTime       time.Time // 交易创建时间下限(包含)，零值时不限制
+	EndTime         time.Time // 交易创建时间上限(不包含)，零值时不限制
+	WriteKeyPrefix  string    // 交易写入Key前缀，交易至少有一个写入Key匹配该前缀
+	ValidationCodes []int32   // 交易验证码(peer.TxValidationCode)
+}
+
+// Match 判断交易情报是否满足过滤条件，filter为nil时总是返回true。
+func (f *TxFilter) Match(t *TransactionInfo) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.ChaincodeIDs) > 0 && !containsString(f.ChaincodeIDs, t.TxCcID) {
+		return false
+	}
+	if len(f.CallerMspIDs) > 0 && !containsString(f.CallerMspIDs, t.CallerMspID) {
+		return false
+	}
+	if len(f.CallerOUs) > 0 && !containsString(f.CallerOUs, t.CallerOU) {
+		return false
+	}
+	if len(f.TxTypes) > 0 && !containsInt(f.TxTypes, t.TxType) {
+		return false
+	}
+	if len(f.ValidationCodes) > 0 && !containsInt32(f.ValidationCodes, t.ValidationCode) {
+		return false
+	}
+	if !f.StartTime.IsZero() || !f.EndTime.IsZero() {
+		createTime, err := time.ParseInLocation(txCreateTimeLayout, t.TxCreateTime, time.Local)
+		if err != nil {
+			return false
+		}
+		if !f.StartTime.IsZero() && createTime.Before(f.StartTime) {
+			return false
+		}
+		if !f.EndTime.IsZero() && !createTime.Before(f.EndTime) {
+			return false
+		}
+	}
+	if f.WriteKeyPrefix != "" {
+		matched := false
+		for _, w := range t.TxWrites {
+			if strings.HasPrefix(w.WriteKey, f.WriteKeyPrefix) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterTransactions 返回满足过滤条件的交易情报集合
+func (f *TxFilter) FilterTransactions(txInfos []*TransactionInfo) []*TransactionInfo {
+	if f == nil {
+		return txInfos
+	}
+	result := []*TransactionInfo{}
+	for _, t := range txInfos {
+		if f.Match(t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// UnmarshalBlockDataWithFilter 反序列化Block区块数据，区块情报中只保留满足过滤条件的交易情报。
+//  区块情报的TransCnt仍为区块内的交易总数。
+//  入参: block 区块数据
+//  入参: curBlockHash 当前区块头哈希
+//  入参: filter 交易过滤条件，为nil时不过滤
+//  返回: BlockInfo
+func UnmarshalBlockDataWithFilter(block *common.Block, curBlockHash []byte, filter *TxFilter) (*BlockInfoWithTx, error) {
+	blockInfo, err := UnmarshalBlockData(block, curBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	blockInfo.TransactionInfos = filter.FilterTransactions(blockInfo.TransactionInfos)
+	return blockInfo, nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, target int) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt32(values []int32, target int32) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (c) 2022 zhaochun
+gitee.com/zhaochuninhefei/fabric-sdk-go-gm is licensed under Mulan PSL v2.
+You can use this software according to the terms and conditions of the Mulan PSL v2.
+You may obtain a copy of Mulan PSL v2 at:
+		 http://license.coscl.org.cn/MulanPSL2
+THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND, EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT, MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+See the Mulan PSL v2 for more details.
+*/
+
+package chn_browse_util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxFilterMatch(t *testing.T) {
+	tx := &TransactionInfo{
+		TxID:         "tx1",
+		TxCreateTime: "2022-05-10 12:00:00",
+		TxCcID:       "mycc",
+		CallerMspID:  "Org2MSP",
+		CallerOU:     "client",
+		TxType:       1,
+		TxWrites: []*TransactionWriteInfo{
+			{NameSpace: "mycc", WriteKey: "acct_001", WriteValue: "100"},
+		},
+	}
+
+	var nilFilter *TxFilter
+	assert.True(t, nilFilter.Match(tx))
+	assert.True(t, (&TxFilter{}).Match(tx))
+
+	filter := &TxFilter{
+		ChaincodeIDs:    []string{"othercc", "mycc"},
+		CallerMspIDs:    []string{"Org2MSP"},
+		CallerOUs:       []string{"client"},
+		TxTypes:         []int{1},
+		StartTime:       time.Date(2022, 5, 9, 0, 0, 0, 0, time.Local),
+		EndTime:         time.Date(2022, 5, 16, 0, 0, 0, 0, time.Local),
+		WriteKeyPrefix:  "acct_",
+		ValidationCodes: []int32{0},
+	}
+	assert.True(t, filter.Match(tx))
+
+	assert.False(t, (&TxFilter{ChaincodeIDs: []string{"othercc"}}).Match(tx))
+	assert.False(t, (&TxFilter{CallerMspIDs: []string{"Org1MSP"}}).Match(tx))
+	assert.False(t, (&TxFilter{CallerOUs: []string{"peer"}}).Match(tx))
+	assert.False(t, (&TxFilter{TxTypes: []int{2, 3}}).Match(tx))
+	assert.False(t, (&TxFilter{WriteKeyPrefix: "user_"}).Match(tx))
+	assert.False(t, (&TxFilter{ValidationCodes: []int32{11}}).Match(tx))
+	assert.False(t, (&TxFilter{StartTime: time.Date(2022, 5, 10, 12, 0, 1, 0, time.Local)}).Match(tx))
+	assert.False(t, (&TxFilter{EndTime: time.Date(2022, 5, 10, 12, 0, 0, 0, time.Local)}).Match(tx))
+
+	txs := []*TransactionInfo{tx, {TxID: "tx2", TxCcID: "othercc"}}
+	result := (&TxFilter{ChaincodeIDs: []string{"othercc"}}).FilterTransactions(txs)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "tx2", result[0].TxID)
+}